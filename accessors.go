@@ -0,0 +1,119 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// eface is the layout of an interface value holding a pointer.
+type eface struct {
+	typ  unsafe.Pointer
+	data unsafe.Pointer
+}
+
+// Accessors compiles getters and setters for the fields of a built type that
+// read and write the field at its offset in the instance, without going
+// through reflect.Value on each call. Create them with CompileAccessors and
+// Getter or Setter.
+type Accessors struct {
+	structType reflect.Type
+	// ptrType is the type word of an interface holding a pointer to an
+	// instance, to check instances without reflection
+	ptrType unsafe.Pointer
+}
+
+// CompileAccessors returns Accessors for the built type. It is meant for hot
+// paths where reflection dominates; GetField and SetFieldValue are the safer
+// choice elsewhere.
+func (b *Builder) CompileAccessors() (*Accessors, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+	instancePtr := reflect.New(structType).Interface()
+
+	return &Accessors{
+		structType: structType,
+		ptrType:    (*eface)(unsafe.Pointer(&instancePtr)).typ,
+	}, nil
+}
+
+// Getter returns a function that reads the named field of an instance, given
+// a pointer to it. T must be exactly the type of the field. Fields promoted
+// from embedded structs are supported unless they are reached through an
+// embedded pointer.
+//
+// The returned function panics when called with anything but a non-nil
+// pointer to an instance of the built type.
+func Getter[T any](a *Accessors, name string) (func(instancePtr any) T, error) {
+	offset, err := a.offset(name, reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(instancePtr any) T {
+		return *(*T)(a.field(instancePtr, offset))
+	}, nil
+}
+
+// Setter returns a function that writes the named field of an instance, like
+// Getter reads it.
+func Setter[T any](a *Accessors, name string) (func(instancePtr any, value T), error) {
+	offset, err := a.offset(name, reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(instancePtr any, value T) {
+		*(*T)(a.field(instancePtr, offset)) = value
+	}, nil
+}
+
+// offset returns the offset of the named field in the struct, after checking
+// that its type is fieldType.
+func (a *Accessors) offset(name string, fieldType reflect.Type) (uintptr, error) {
+	field, ok := a.structType.FieldByName(name)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrFieldNotFound, name)
+	}
+
+	if field.Type != fieldType {
+		return 0, fmt.Errorf(
+			"%w: field type: %s, value type: %s",
+			ErrIncompatibleTypes,
+			field.Type.String(),
+			fieldType.String(),
+		)
+	}
+
+	var offset uintptr
+
+	structType := a.structType
+
+	for depth, i := range field.Index {
+		step := structType.Field(i)
+		if step.Type.Kind() == reflect.Ptr && depth < len(field.Index)-1 {
+			return 0, fmt.Errorf("%w: field %s is promoted through embedded pointer %s", ErrIncompatibleTypes, name, step.Name)
+		}
+
+		offset += step.Offset
+		structType = step.Type
+	}
+
+	return offset, nil
+}
+
+func (a *Accessors) field(instancePtr any, offset uintptr) unsafe.Pointer {
+	e := (*eface)(unsafe.Pointer(&instancePtr))
+	if e.typ != a.ptrType || e.data == nil {
+		panic(fmt.Sprintf("dynamicstruct: accessor called with %T, want non-nil *%s", instancePtr, a.structType))
+	}
+
+	return unsafe.Add(e.data, offset)
+}
@@ -0,0 +1,146 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newAccessorsBuilder(t *testing.T) (*dynamicstruct.Builder, *dynamicstruct.Accessors) {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Age", 0)
+	_ = builder.AddField("Score", float64(0))
+	_ = builder.AddField("Active", false)
+	_ = builder.AddField("Tags", []string{})
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	accessors, err := builder.CompileAccessors()
+	if err != nil {
+		t.Fatalf("CompileAccessors() error = %v", err)
+	}
+
+	return builder, accessors
+}
+
+func TestCompileAccessors(t *testing.T) {
+	builder, accessors := newAccessorsBuilder(t)
+
+	getName, err := dynamicstruct.Getter[string](accessors, "Name")
+	if err != nil {
+		t.Fatalf("Getter() error = %v", err)
+	}
+
+	setName, _ := dynamicstruct.Setter[string](accessors, "Name")
+	getAge, _ := dynamicstruct.Getter[int](accessors, "Age")
+	setAge, _ := dynamicstruct.Setter[int](accessors, "Age")
+	setScore, _ := dynamicstruct.Setter[float64](accessors, "Score")
+	setActive, _ := dynamicstruct.Setter[bool](accessors, "Active")
+	setTags, _ := dynamicstruct.Setter[[]string](accessors, "Tags")
+
+	getEmail, err := dynamicstruct.Getter[string](accessors, "Email")
+	if err != nil {
+		t.Fatalf("Getter() promoted error = %v", err)
+	}
+
+	setEmail, _ := dynamicstruct.Setter[string](accessors, "Email")
+
+	instancePtr, _ := builder.NewInstance()
+
+	setName(instancePtr, "Alice")
+	setAge(instancePtr, 30)
+	setScore(instancePtr, 9.5)
+	setActive(instancePtr, true)
+	setTags(instancePtr, []string{"a"})
+	setEmail(instancePtr, "alice@example.com")
+
+	value := reflect.ValueOf(instancePtr).Elem()
+
+	want := map[string]any{
+		"Name":   "Alice",
+		"Age":    30,
+		"Score":  9.5,
+		"Active": true,
+		"Tags":   []string{"a"},
+		"Email":  "alice@example.com",
+	}
+
+	for name, wantValue := range want {
+		if got := value.FieldByName(name).Interface(); !reflect.DeepEqual(got, wantValue) {
+			t.Errorf("Setter() %s = %v, want %v", name, got, wantValue)
+		}
+	}
+
+	value.FieldByName("Age").SetInt(41)
+
+	if got := getAge(instancePtr); got != 41 {
+		t.Errorf("Getter() Age = %d, want 41", got)
+	}
+
+	if got := getName(instancePtr); got != "Alice" {
+		t.Errorf("Getter() Name = %q, want %q", got, "Alice")
+	}
+
+	if got := getEmail(instancePtr); got != "alice@example.com" {
+		t.Errorf("Getter() Email = %q, want %q", got, "alice@example.com")
+	}
+
+	t.Run("wrong_instance_panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Getter() with wrong instance did not panic")
+			}
+		}()
+
+		getName(&PersonTest{})
+	})
+
+	t.Run("value_instance_panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Getter() with instance value did not panic")
+			}
+		}()
+
+		getName(value.Interface())
+	})
+}
+
+func TestCompileAccessorsErrors(t *testing.T) {
+	if _, err := dynamicstruct.New().CompileAccessors(); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("CompileAccessors() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	_, accessors := newAccessorsBuilder(t)
+
+	if _, err := dynamicstruct.Getter[string](accessors, "Missing"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+		t.Errorf("Getter() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+	}
+
+	if _, err := dynamicstruct.Getter[int64](accessors, "Age"); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Getter() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	if _, err := dynamicstruct.Setter[int](accessors, "Name"); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Setter() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(&PersonTest{})
+	_ = builder.AddField("ID", 0)
+	_, _ = builder.Build()
+
+	pointerAccessors, _ := builder.CompileAccessors()
+
+	if _, err := dynamicstruct.Getter[string](pointerAccessors, "Name"); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Getter() through embedded pointer error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+}
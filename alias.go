@@ -0,0 +1,123 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AddFieldAlias adds alternate names for the named field, such as the key
+// names of different upstream feeds. GetField, GetFieldValue, SetFieldValue,
+// SetFieldFromString and FromMap resolve an alias to its field:
+//
+//	_ = builder.AddFieldAlias("Email", "EmailAddress", "mail")
+//
+// An alias can't be the name of a field or an alias of another field, which
+// returns ErrFieldAlreadyExists. Aliases don't change the built type, so they
+// can be added after Build. They follow the field when it is renamed, and
+// are removed with it.
+func (b *Builder) AddFieldAlias(field string, aliases ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if _, ok := b.fields[field]; !ok {
+		return fieldError("alias", field, ErrFieldNotFound)
+	}
+
+	for _, alias := range aliases {
+		if _, ok := b.fields[alias]; ok {
+			return fieldError("alias", field, fmt.Errorf("%w: %s", ErrFieldAlreadyExists, alias))
+		}
+
+		if canonical, ok := b.aliases[alias]; ok && canonical != field {
+			return fieldError("alias", field, fmt.Errorf("%w: %s is an alias of %s", ErrFieldAlreadyExists, alias, canonical))
+		}
+	}
+
+	if b.aliases == nil {
+		b.aliases = make(map[string]string, len(aliases))
+	}
+
+	for _, alias := range aliases {
+		b.aliases[alias] = field
+	}
+
+	return nil
+}
+
+// FieldAliases returns the aliases of the named field, sorted.
+func (b *Builder) FieldAliases(field string) []string {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	return b.fieldAliases(field)
+}
+
+func (b *Builder) fieldAliases(field string) []string {
+	var aliases []string
+
+	for alias, canonical := range b.aliases {
+		if canonical == field {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	sort.Strings(aliases)
+
+	return aliases
+}
+
+// FromMap sets the fields of the builder's instance from values, keyed by
+// field name or alias, as SetFieldValue sets them. It sets all fields or none:
+// when any entry is invalid, the instance is left unchanged and the returned
+// FieldErrors lists every invalid entry, sorted by key.
+func (b *Builder) FromMap(values map[string]any) error {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.Lock()
+	defer b.values.Unlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return ErrInstanceNotBuilt
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	// Fields are set on a copy, so that the instance is only changed when
+	// every entry is valid
+	updated := reflect.New(b.instance.Type()).Elem()
+	updated.Set(*b.instance)
+
+	var errs FieldErrors
+
+	for _, key := range keys {
+		// Embedded pointers are shared with the instance, so the copy gets
+		// its own before a field promoted through them is set
+		name := key
+		if canonical, ok := b.aliases[key]; ok {
+			name = canonical
+		}
+
+		if field, ok := updated.Type().FieldByName(name); ok && len(field.Index) > 1 {
+			ownedField(updated, field.Index)
+		}
+
+		if err := b.setStructField(updated, key, values[key]); err != nil {
+			errs = append(errs, &FieldError{Field: key, Op: "set", Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	b.instance.Set(updated)
+
+	return nil
+}
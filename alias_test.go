@@ -0,0 +1,216 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newAliasedBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Email", "", `json:"email"`)
+	_ = builder.AddField("Age", 0, `json:"age"`)
+
+	if err := builder.AddFieldAlias("Email", "EmailAddress", "mail"); err != nil {
+		t.Fatalf("AddFieldAlias() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestAddFieldAlias(t *testing.T) {
+	builder := newAliasedBuilder(t)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	t.Run("set_and_get", func(t *testing.T) {
+		if err := builder.SetFieldValue("mail", "alice@example.com"); err != nil {
+			t.Fatalf("SetFieldValue() error = %v", err)
+		}
+
+		var email string
+		if err := builder.GetFieldValue("EmailAddress", &email); err != nil || email != "alice@example.com" {
+			t.Errorf("GetFieldValue() = %q, %v, want alice@example.com", email, err)
+		}
+
+		if got, err := builder.GetField("Email"); err != nil || got != "alice@example.com" {
+			t.Errorf("GetField() = %v, %v, want alice@example.com", got, err)
+		}
+	})
+
+	t.Run("set_from_string", func(t *testing.T) {
+		_ = builder.AddFieldAlias("Age", "years")
+
+		if err := builder.SetFieldFromString("years", "42"); err != nil {
+			t.Fatalf("SetFieldFromString() error = %v", err)
+		}
+
+		if got, _ := builder.GetField("Age"); got != 42 {
+			t.Errorf("Age = %v, want 42", got)
+		}
+	})
+
+	t.Run("aliases", func(t *testing.T) {
+		want := []string{"EmailAddress", "mail"}
+		if got := builder.FieldAliases("Email"); !reflect.DeepEqual(got, want) {
+			t.Errorf("FieldAliases() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestAddFieldAliasErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		alias   string
+		wantErr error
+	}{
+		{"missing_field", "Missing", "other", dynamicstruct.ErrFieldNotFound},
+		{"alias_is_field", "Email", "Age", dynamicstruct.ErrFieldAlreadyExists},
+		{"alias_of_other_field", "Age", "mail", dynamicstruct.ErrFieldAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newAliasedBuilder(t)
+
+			if err := builder.AddFieldAlias(tt.field, tt.alias); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddFieldAlias() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("field_named_like_alias", func(t *testing.T) {
+		builder := newAliasedBuilder(t)
+
+		if err := builder.AddField("mail", ""); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Errorf("AddField() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+
+		if err := builder.RenameField("Age", "mail"); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Errorf("RenameField() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+	})
+}
+
+func TestFieldAliasEdits(t *testing.T) {
+	t.Run("rename", func(t *testing.T) {
+		builder := newAliasedBuilder(t)
+		_ = builder.RenameField("Email", "Mail")
+
+		if got := builder.FieldAliases("Mail"); len(got) != 2 {
+			t.Errorf("FieldAliases(Mail) = %v, want 2 aliases", got)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		builder := newAliasedBuilder(t)
+		_ = builder.ReplaceField("Email", []string{})
+
+		if got := builder.FieldAliases("Email"); len(got) != 2 {
+			t.Errorf("FieldAliases(Email) = %v, want 2 aliases", got)
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		builder := newAliasedBuilder(t)
+		_ = builder.RemoveField("Email")
+
+		if err := builder.AddField("mail", ""); err != nil {
+			t.Errorf("AddField() error = %v, want the alias removed", err)
+		}
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		builder := newAliasedBuilder(t)
+		clone := builder.Clone()
+		_ = clone.RemoveField("Email")
+
+		if got := builder.FieldAliases("Email"); len(got) != 2 {
+			t.Errorf("FieldAliases(Email) = %v, want the original's aliases kept", got)
+		}
+	})
+}
+
+func TestFromMap(t *testing.T) {
+	builder := newAliasedBuilder(t)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if err := builder.FromMap(map[string]any{"mail": "bob@example.com", "Age": 30}); err != nil {
+		t.Fatalf("FromMap() error = %v", err)
+	}
+
+	if got, _ := builder.GetField("Email"); got != "bob@example.com" {
+		t.Errorf("Email = %v, want bob@example.com", got)
+	}
+
+	t.Run("all_or_none", func(t *testing.T) {
+		err := builder.FromMap(map[string]any{"mail": "carol@example.com", "Age": "old", "Unknown": 1})
+
+		var errs dynamicstruct.FieldErrors
+		if !errors.As(err, &errs) || len(errs) != 2 {
+			t.Fatalf("FromMap() error = %v, want 2 FieldErrors", err)
+		}
+
+		if errs[0].Field != "Age" || !errors.Is(errs[0], dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("FieldErrors[0] = %v, want Age: %v", errs[0], dynamicstruct.ErrIncompatibleTypes)
+		}
+
+		if errs[1].Field != "Unknown" || !errors.Is(errs[1], dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("FieldErrors[1] = %v, want Unknown: %v", errs[1], dynamicstruct.ErrFieldNotFound)
+		}
+
+		if got, _ := builder.GetField("Email"); got != "bob@example.com" {
+			t.Errorf("Email = %v, want it unchanged", got)
+		}
+	})
+
+	t.Run("embedded_pointer", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		if err := builder.AddAnonymousField(&PersonTest{}); err != nil {
+			t.Fatalf("AddAnonymousField() error = %v", err)
+		}
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		person := &PersonTest{Name: "alice"}
+		if err := builder.SetFieldValue("PersonTest", person); err != nil {
+			t.Fatalf("SetFieldValue() error = %v", err)
+		}
+
+		// The promoted Name lives in the struct the embedded pointer shares
+		if err := builder.FromMap(map[string]any{"Name": "changed", "Zzz": 1}); err == nil {
+			t.Fatal("FromMap() error = nil, want Zzz reported")
+		}
+
+		if got, _ := builder.GetField("Name"); got != "alice" || person.Name != "alice" {
+			t.Errorf("Name = %v, embedded Name = %v, want both unchanged", got, person.Name)
+		}
+
+		if err := builder.FromMap(map[string]any{"Name": "bob"}); err != nil {
+			t.Fatalf("FromMap() error = %v", err)
+		}
+
+		if got, _ := builder.GetField("Name"); got != "bob" {
+			t.Errorf("Name = %v, want bob", got)
+		}
+	})
+
+	t.Run("not_built", func(t *testing.T) {
+		err := dynamicstruct.New().FromMap(map[string]any{})
+		if !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("FromMap() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AddArrayField adds a field of type [length]T, where T is the type of elem,
+// so fixed-width fields such as [16]byte can be declared without an array
+// value to take the type from.
+func (b *Builder) AddArrayField(name string, length int, elem any, tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	elemType := reflect.TypeOf(elem)
+	if elemType == nil {
+		return fieldError("add", name, fmt.Errorf("%w: element of array field", ErrValueCannotBeNil))
+	}
+
+	if length < 0 {
+		return fieldError("add", name, fmt.Errorf("%w: negative array length %d", ErrIncompatibleTypes, length))
+	}
+
+	return b.addField(name, reflect.ArrayOf(length, elemType), tags)
+}
@@ -0,0 +1,73 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestAddArrayField(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   int
+		elem     any
+		wantType reflect.Type
+		wantErr  error
+	}{
+		{name: "bytes", length: 16, elem: byte(0), wantType: reflect.TypeOf([16]byte{})},
+		{name: "structs", length: 2, elem: PersonTest{}, wantType: reflect.TypeOf([2]PersonTest{})},
+		{name: "empty", length: 0, elem: "", wantType: reflect.TypeOf([0]string{})},
+		{name: "negative_length", length: -1, elem: byte(0), wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "nil_elem", length: 4, elem: nil, wantErr: dynamicstruct.ErrValueCannotBeNil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+
+			err := builder.AddArrayField("Data", tt.length, tt.elem, `json:"data"`)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddArrayField() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			field, _ := reflect.TypeOf(instance).FieldByName("Data")
+			if field.Type != tt.wantType {
+				t.Errorf("field type = %s, want %s", field.Type, tt.wantType)
+			}
+
+			if field.Tag != `json:"data"` {
+				t.Errorf("field tag = %q, want %q", field.Tag, `json:"data"`)
+			}
+		})
+	}
+}
+
+func TestAddArrayFieldValue(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddArrayField("ID", 4, byte(0))
+	_, _ = builder.Build()
+
+	if err := builder.SetFieldValue("ID", [4]byte{1, 2, 3, 4}); err != nil {
+		t.Fatalf("SetFieldValue() error = %v", err)
+	}
+
+	var id [4]byte
+	if err := builder.GetFieldValue("ID", &id); err != nil || id != [4]byte{1, 2, 3, 4} {
+		t.Errorf("GetFieldValue() = %v, %v, want [1 2 3 4]", id, err)
+	}
+
+	if err := builder.SetFieldValue("ID", []byte{1, 2, 3, 4}); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("SetFieldValue() with a slice error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+}
@@ -0,0 +1,274 @@
+// Package arrowstruct builds dynamic structs from Arrow schemas and writes
+// their instances to Arrow records and Parquet files.
+package arrowstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+// TagKey is the struct tag holding the Arrow field name.
+const TagKey = "arrow"
+
+var (
+	ErrUnsupportedType = errors.New("type has no arrow counterpart")
+	ErrFieldMismatch   = errors.New("struct field doesn't match the arrow field")
+)
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// FromArrowSchema returns a builder with one field per field of the schema.
+// Every field carries its Arrow name in an `arrow:"name"` tag and a `json`
+// tag. Nullable fields become pointers, except lists and binaries, whose nil
+// slices are the nulls; lists become slices and structs nested dynamic
+// structs.
+//
+// Fields are named after their Arrow names ("user_id" -> "UserId"). Names
+// that map to the same Go name, such as "user-id" and "user_id", are resolved
+// by the policy of dynamicstruct.WithCollisionPolicy, and return
+// dynamicstruct.ErrFieldAlreadyExists by default.
+func FromArrowSchema(schema *arrow.Schema, opts ...dynamicstruct.ImportOption) (*dynamicstruct.Builder, error) {
+	return fromFields(schema.Fields(), opts)
+}
+
+func fromFields(fields []arrow.Field, opts []dynamicstruct.ImportOption) (*dynamicstruct.Builder, error) {
+	builder := dynamicstruct.New()
+
+	for _, field := range fields {
+		fieldType, err := goType(field, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, field.Name)
+		}
+
+		name, err := builder.ImportedFieldName(dynamicstruct.ExportedName(field.Name), field.Name, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, field.Name)
+		}
+
+		err = builder.AddField(
+			name,
+			reflect.Zero(fieldType).Interface(),
+			fmt.Sprintf(`%s:%q`, TagKey, field.Name),
+			fmt.Sprintf(`json:%q`, field.Name),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, field.Name)
+		}
+	}
+
+	return builder, nil
+}
+
+func goType(field arrow.Field, opts []dynamicstruct.ImportOption) (reflect.Type, error) {
+	fieldType, err := singularType(field.Type, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nil slices already stand for nulls
+	if field.Nullable && fieldType.Kind() != reflect.Slice {
+		return reflect.PtrTo(fieldType), nil
+	}
+
+	return fieldType, nil
+}
+
+func singularType(dataType arrow.DataType, opts []dynamicstruct.ImportOption) (reflect.Type, error) {
+	switch dataType.ID() {
+	case arrow.BOOL:
+		return reflect.TypeOf(false), nil
+	case arrow.INT8:
+		return reflect.TypeOf(int8(0)), nil
+	case arrow.INT16:
+		return reflect.TypeOf(int16(0)), nil
+	case arrow.INT32:
+		return reflect.TypeOf(int32(0)), nil
+	case arrow.INT64:
+		return reflect.TypeOf(int64(0)), nil
+	case arrow.UINT8:
+		return reflect.TypeOf(uint8(0)), nil
+	case arrow.UINT16:
+		return reflect.TypeOf(uint16(0)), nil
+	case arrow.UINT32:
+		return reflect.TypeOf(uint32(0)), nil
+	case arrow.UINT64:
+		return reflect.TypeOf(uint64(0)), nil
+	case arrow.FLOAT32:
+		return reflect.TypeOf(float32(0)), nil
+	case arrow.FLOAT64:
+		return reflect.TypeOf(float64(0)), nil
+	case arrow.STRING, arrow.LARGE_STRING:
+		return reflect.TypeOf(""), nil
+	case arrow.BINARY, arrow.LARGE_BINARY:
+		return bytesType, nil
+	case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		return timeType, nil
+	case arrow.LIST, arrow.LARGE_LIST:
+		elemType, err := singularType(dataType.(arrow.ListLikeType).Elem(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.SliceOf(elemType), nil
+	case arrow.STRUCT:
+		builder, err := fromFields(dataType.(*arrow.StructType).Fields(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		instance, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.TypeOf(instance), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, dataType)
+	}
+}
+
+// SchemaOf returns the Arrow schema of instance, a struct or pointer to
+// struct, so that instances of a struct that wasn't built from a schema can
+// be written too. Fields are named by their arrow tag, or their Go name
+// without one, and `arrow:"-"` and unexported fields are left out. Pointer
+// fields and slices are nullable, and times are UTC timestamps in
+// microseconds.
+func SchemaOf(instance any) (*arrow.Schema, error) {
+	structType := reflect.TypeOf(instance)
+	for structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, dynamicstruct.ErrInvalidInstance
+	}
+
+	fields, err := arrowFields(structType)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrow.NewSchema(fields, nil), nil
+}
+
+func arrowFields(structType reflect.Type) ([]arrow.Field, error) {
+	var fields []arrow.Field
+
+	for _, field := range structFields(structType) {
+		fieldType := field.Type
+		nullable := false
+
+		switch fieldType.Kind() {
+		case reflect.Ptr:
+			fieldType = fieldType.Elem()
+			nullable = true
+		case reflect.Slice:
+			nullable = true
+		}
+
+		dataType, err := arrowType(fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, field.Name)
+		}
+
+		fields = append(fields, arrow.Field{Name: field.arrowName, Type: dataType, Nullable: nullable})
+	}
+
+	return fields, nil
+}
+
+func arrowType(goType reflect.Type) (arrow.DataType, error) {
+	switch goType {
+	case timeType:
+		return arrow.FixedWidthTypes.Timestamp_us, nil
+	case bytesType:
+		return arrow.BinaryTypes.Binary, nil
+	}
+
+	switch goType.Kind() {
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case reflect.Int8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case reflect.Int16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case reflect.Int32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case reflect.Int, reflect.Int64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case reflect.Uint8:
+		return arrow.PrimitiveTypes.Uint8, nil
+	case reflect.Uint16:
+		return arrow.PrimitiveTypes.Uint16, nil
+	case reflect.Uint32:
+		return arrow.PrimitiveTypes.Uint32, nil
+	case reflect.Uint, reflect.Uint64:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case reflect.Float32:
+		return arrow.PrimitiveTypes.Float32, nil
+	case reflect.Float64:
+		return arrow.PrimitiveTypes.Float64, nil
+	case reflect.String:
+		return arrow.BinaryTypes.String, nil
+	case reflect.Slice, reflect.Array:
+		elemType, err := arrowType(goType.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return arrow.ListOf(elemType), nil
+	case reflect.Struct:
+		fields, err := arrowFields(goType)
+		if err != nil {
+			return nil, err
+		}
+
+		return arrow.StructOf(fields...), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, goType)
+	}
+}
+
+// structField is an exported struct field with the name of its Arrow field.
+type structField struct {
+	reflect.StructField
+	arrowName string
+}
+
+// structFields returns the exported fields of structType that aren't skipped
+// with `arrow:"-"`.
+func structFields(structType reflect.Type) []structField {
+	var fields []structField
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+
+		if tag, ok := field.Tag.Lookup(TagKey); ok {
+			if tag == "-" {
+				continue
+			}
+
+			if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+				name = tagName
+			}
+		}
+
+		fields = append(fields, structField{StructField: field, arrowName: name})
+	}
+
+	return fields
+}
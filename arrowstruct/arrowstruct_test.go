@@ -0,0 +1,293 @@
+package arrowstruct_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/gosmos-space/dynamicstruct/arrowstruct"
+)
+
+func testSchema() *arrow.Schema {
+	return arrow.NewSchema([]arrow.Field{
+		{Name: "user_id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float32, Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "seen_at", Type: arrow.FixedWidthTypes.Timestamp_us},
+		{Name: "address", Type: arrow.StructOf(
+			arrow.Field{Name: "city", Type: arrow.BinaryTypes.String},
+		)},
+	}, nil)
+}
+
+func TestFromArrowSchema(t *testing.T) {
+	builder, err := arrowstruct.FromArrowSchema(testSchema())
+	if err != nil {
+		t.Fatalf("FromArrowSchema() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"UserId", "int64"},
+		{"Name", "string"},
+		{"Score", "*float32"},
+		{"Tags", "[]string"},
+		{"SeenAt", "time.Time"},
+		{"Address", "struct { City string \"arrow:\\\"city\\\" json:\\\"city\\\"\" }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+
+			if got := field.Type.String(); got != tt.want {
+				t.Errorf("%s type = %s, want %s", tt.field, got, tt.want)
+			}
+		})
+	}
+
+	if field, _ := structType.FieldByName("UserId"); field.Tag.Get(arrowstruct.TagKey) != "user_id" {
+		t.Errorf("UserId tag = %q, want arrow name", field.Tag)
+	}
+}
+
+func TestFromArrowSchemaUnsupported(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "counts", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64)},
+	}, nil)
+
+	if _, err := arrowstruct.FromArrowSchema(schema); !errors.Is(err, arrowstruct.ErrUnsupportedType) {
+		t.Errorf("FromArrowSchema() error = %v, want %v", err, arrowstruct.ErrUnsupportedType)
+	}
+}
+
+func TestFromArrowSchemaCollisions(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "user-id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "user_id", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	if _, err := arrowstruct.FromArrowSchema(schema); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+		t.Errorf("FromArrowSchema() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+	}
+
+	builder, err := arrowstruct.FromArrowSchema(schema, dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionSuffix))
+	if err != nil {
+		t.Fatalf("FromArrowSchema() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	field, ok := reflect.TypeOf(instance).FieldByName("UserId2")
+	if !ok || field.Tag.Get(arrowstruct.TagKey) != "user_id" {
+		t.Errorf("FromArrowSchema() = %T, want UserId2 tagged user_id", instance)
+	}
+}
+
+func newRows(t *testing.T) []any {
+	t.Helper()
+
+	builder, err := arrowstruct.FromArrowSchema(testSchema())
+	if err != nil {
+		t.Fatalf("FromArrowSchema() error = %v", err)
+	}
+
+	_, _ = builder.Build()
+
+	rows := make([]any, 2)
+	for i := range rows {
+		instance, _ := builder.NewInstance()
+		rows[i] = instance
+	}
+
+	seenAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	score := float32(9.5)
+
+	row := reflect.ValueOf(rows[0]).Elem()
+	row.FieldByName("UserId").SetInt(1)
+	row.FieldByName("Name").SetString("ada")
+	row.FieldByName("Score").Set(reflect.ValueOf(&score))
+	row.FieldByName("Tags").Set(reflect.ValueOf([]string{"admin", "ops"}))
+	row.FieldByName("SeenAt").Set(reflect.ValueOf(seenAt))
+	row.FieldByName("Address").Field(0).SetString("London")
+
+	row = reflect.ValueOf(rows[1]).Elem()
+	row.FieldByName("UserId").SetInt(2)
+	row.FieldByName("Name").SetString("grace")
+	row.FieldByName("SeenAt").Set(reflect.ValueOf(seenAt))
+
+	return rows
+}
+
+func TestAppendToRecordBuilder(t *testing.T) {
+	rows := newRows(t)
+
+	rb := array.NewRecordBuilder(memory.DefaultAllocator, testSchema())
+	defer rb.Release()
+
+	if err := arrowstruct.AppendToRecordBuilder(rows, rb); err != nil {
+		t.Fatalf("AppendToRecordBuilder() error = %v", err)
+	}
+
+	record := rb.NewRecord()
+	defer record.Release()
+
+	if record.NumRows() != 2 {
+		t.Fatalf("NumRows() = %d, want 2", record.NumRows())
+	}
+
+	if got := record.Column(1).(*array.String).Value(1); got != "grace" {
+		t.Errorf("name[1] = %q, want grace", got)
+	}
+
+	if !record.Column(2).IsNull(1) || !record.Column(3).IsNull(1) {
+		t.Error("score[1] and tags[1] aren't null, want nil pointers and slices as nulls")
+	}
+
+	if got := record.Column(3).(*array.List).ListValues().(*array.String).Value(1); got != "ops" {
+		t.Errorf("tags[0][1] = %q, want ops", got)
+	}
+
+	city := record.Column(5).(*array.Struct).Field(0).(*array.String)
+	if got := city.Value(0); got != "London" {
+		t.Errorf("address[0].city = %q, want London", got)
+	}
+}
+
+func TestAppendToRecordBuilderMismatch(t *testing.T) {
+	type row struct {
+		UserID string `arrow:"user_id"`
+		Name   string `arrow:"name"`
+	}
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "user_id", Type: arrow.PrimitiveTypes.Int8},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	tests := []struct {
+		name     string
+		instance any
+	}{
+		{"wrong_kind", row{UserID: "1", Name: "ada"}},
+		{"overflow", struct {
+			UserID int    `arrow:"user_id"`
+			Name   string `arrow:"name"`
+		}{UserID: 300, Name: "ada"}},
+		{"missing_field", struct {
+			UserID int8 `arrow:"user_id"`
+		}{UserID: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rb := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+			defer rb.Release()
+
+			valid := struct {
+				UserID int8
+				Name   string
+			}{UserID: 1, Name: "ada"}
+
+			// The valid row comes first, to check that it isn't appended
+			err := arrowstruct.AppendToRecordBuilder([]any{valid, tt.instance}, rb)
+			if !errors.Is(err, arrowstruct.ErrFieldMismatch) {
+				t.Fatalf("AppendToRecordBuilder() error = %v, want %v", err, arrowstruct.ErrFieldMismatch)
+			}
+
+			if n := rb.Field(0).Len(); n != 0 {
+				t.Errorf("builder has %d rows, want 0", n)
+			}
+		})
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	type event struct {
+		ID      int64     `arrow:"id"`
+		Kind    string    `arrow:"kind"`
+		Weight  *float64  `arrow:"weight"`
+		At      time.Time `arrow:"at"`
+		Skipped string    `arrow:"-"`
+	}
+
+	weight := 0.5
+	events := []any{
+		event{ID: 1, Kind: "click", Weight: &weight, At: time.Unix(0, 0).UTC()},
+		&event{ID: 2, Kind: "view", At: time.Unix(60, 0).UTC()},
+	}
+
+	schema, err := arrowstruct.SchemaOf(event{})
+	if err != nil {
+		t.Fatalf("SchemaOf() error = %v", err)
+	}
+
+	if schema.NumFields() != 4 || !schema.Field(2).Nullable {
+		t.Fatalf("SchemaOf() = %s, want 4 fields with a nullable weight", schema)
+	}
+
+	var buf bytes.Buffer
+	if err := arrowstruct.WriteParquet(&buf, schema, events); err != nil {
+		t.Fatalf("WriteParquet() error = %v", err)
+	}
+
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewParquetReader() error = %v", err)
+	}
+	defer reader.Close()
+
+	parquetSchema := reader.MetaData().Schema
+	if got := parquetSchema.Column(0).PhysicalType().String(); got != "INT64" {
+		t.Errorf("id physical type = %s, want INT64", got)
+	}
+
+	if got := parquetSchema.Column(1).PhysicalType().String(); got != "BYTE_ARRAY" {
+		t.Errorf("kind physical type = %s, want BYTE_ARRAY", got)
+	}
+
+	fileReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("NewFileReader() error = %v", err)
+	}
+
+	table, err := fileReader.ReadTable(context.Background())
+	if err != nil {
+		t.Fatalf("ReadTable() error = %v", err)
+	}
+	defer table.Release()
+
+	if table.NumRows() != 2 {
+		t.Fatalf("NumRows() = %d, want 2", table.NumRows())
+	}
+
+	weights := table.Column(2).Data().Chunk(0).(*array.Float64)
+	if weights.Value(0) != 0.5 || !weights.IsNull(1) {
+		t.Errorf("weight = %v, want [0.5 null]", weights)
+	}
+}
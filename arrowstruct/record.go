@@ -0,0 +1,351 @@
+package arrowstruct
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+// AppendToRecordBuilder appends one row per instance, a struct or pointer to
+// struct, to rb. Struct fields are matched to the schema's fields by their
+// arrow tag, or their Go name without one. Schema fields without a struct
+// field, and nil pointers and slices, are appended as nulls, which fields
+// that aren't nullable refuse with ErrFieldMismatch.
+//
+// The instances are checked against the schema before anything is appended,
+// so that a mismatch leaves rb unchanged.
+func AppendToRecordBuilder(instances []any, rb *array.RecordBuilder) error {
+	rows := make([]reflect.Value, len(instances))
+
+	for i, instance := range instances {
+		value := reflect.ValueOf(instance)
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return dynamicstruct.ErrValueCannotBeNil
+			}
+
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return dynamicstruct.ErrInvalidInstance
+		}
+
+		rows[i] = value
+	}
+
+	schema := rb.Schema()
+
+	// A first pass without builders only checks the values
+	for _, builders := range [][]array.Builder{make([]array.Builder, schema.NumFields()), rb.Fields()} {
+		for i, row := range rows {
+			if err := appendRow(builders, schema.Fields(), row); err != nil {
+				return fmt.Errorf("%w: row %d", err, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteParquet writes the instances to w as a Parquet file with the given
+// schema, which SchemaOf returns for structs that weren't built from one.
+// Column types follow the Arrow types of the schema: integers and floats are
+// written with their physical width, strings as UTF-8 byte arrays and times
+// as timestamps.
+func WriteParquet(w io.Writer, schema *arrow.Schema, instances []any) error {
+	rb := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer rb.Release()
+
+	if err := AppendToRecordBuilder(instances, rb); err != nil {
+		return err
+	}
+
+	record := rb.NewRecord()
+	defer record.Release()
+
+	writer, err := pqarrow.NewFileWriter(schema, w, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Write(record); err != nil {
+		_ = writer.Close()
+
+		return err
+	}
+
+	return writer.Close()
+}
+
+// appendRow appends the struct value's fields to the builders, one per field.
+// Nil builders only check the values.
+func appendRow(builders []array.Builder, fields []arrow.Field, value reflect.Value) error {
+	byName := make(map[string]reflect.Value, len(fields))
+	for _, field := range structFields(value.Type()) {
+		byName[field.arrowName] = value.FieldByIndex(field.Index)
+	}
+
+	for i, field := range fields {
+		fieldValue, ok := byName[field.Name]
+		if !ok || isNull(fieldValue) {
+			if !field.Nullable {
+				return fmt.Errorf("%w: %s is null but not nullable", ErrFieldMismatch, field.Name)
+			}
+
+			if builders[i] != nil {
+				builders[i].AppendNull()
+			}
+
+			continue
+		}
+
+		if err := appendValue(builders[i], field.Type, reflect.Indirect(fieldValue)); err != nil {
+			return fmt.Errorf("%w: field %s", err, field.Name)
+		}
+	}
+
+	return nil
+}
+
+func isNull(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// appendValue appends value to builder as a value of dataType. A nil builder
+// only checks the value.
+func appendValue(builder array.Builder, dataType arrow.DataType, value reflect.Value) error {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	switch dataType.ID() {
+	case arrow.BOOL:
+		if value.Kind() != reflect.Bool {
+			return mismatchError(value, dataType)
+		}
+
+		if builder != nil {
+			builder.(*array.BooleanBuilder).Append(value.Bool())
+		}
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		n, ok := intValue(value, dataType.(arrow.FixedWidthDataType).BitWidth())
+		if !ok {
+			return mismatchError(value, dataType)
+		}
+
+		if builder != nil {
+			appendInt(builder, n)
+		}
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		n, ok := uintValue(value, dataType.(arrow.FixedWidthDataType).BitWidth())
+		if !ok {
+			return mismatchError(value, dataType)
+		}
+
+		if builder != nil {
+			appendUint(builder, n)
+		}
+	case arrow.FLOAT32, arrow.FLOAT64:
+		if value.Kind() != reflect.Float32 && value.Kind() != reflect.Float64 {
+			return mismatchError(value, dataType)
+		}
+
+		switch builder := builder.(type) {
+		case *array.Float32Builder:
+			builder.Append(float32(value.Float()))
+		case *array.Float64Builder:
+			builder.Append(value.Float())
+		}
+	case arrow.STRING, arrow.LARGE_STRING:
+		if value.Kind() != reflect.String {
+			return mismatchError(value, dataType)
+		}
+
+		if builder != nil {
+			builder.(interface{ Append(string) }).Append(value.String())
+		}
+	case arrow.BINARY, arrow.LARGE_BINARY:
+		if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.Uint8 {
+			return mismatchError(value, dataType)
+		}
+
+		if builder != nil {
+			builder.(*array.BinaryBuilder).Append(value.Bytes())
+		}
+	case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		if value.Type() != timeType {
+			return mismatchError(value, dataType)
+		}
+
+		if builder != nil {
+			appendTime(builder, value.Interface().(time.Time))
+		}
+	case arrow.LIST, arrow.LARGE_LIST:
+		return appendList(builder, dataType.(arrow.ListLikeType), value)
+	case arrow.STRUCT:
+		return appendStruct(builder, dataType.(*arrow.StructType), value)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, dataType)
+	}
+
+	return nil
+}
+
+func appendList(builder array.Builder, listType arrow.ListLikeType, value reflect.Value) error {
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return mismatchError(value, listType)
+	}
+
+	var elemBuilder array.Builder
+
+	if builder != nil {
+		listBuilder := builder.(interface {
+			Append(bool)
+			ValueBuilder() array.Builder
+		})
+		listBuilder.Append(true)
+		elemBuilder = listBuilder.ValueBuilder()
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		elem := value.Index(i)
+		if isNull(elem) {
+			if elemBuilder != nil {
+				elemBuilder.AppendNull()
+			}
+
+			continue
+		}
+
+		if err := appendValue(elemBuilder, listType.Elem(), reflect.Indirect(elem)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appendStruct(builder array.Builder, structType *arrow.StructType, value reflect.Value) error {
+	if value.Kind() != reflect.Struct {
+		return mismatchError(value, structType)
+	}
+
+	builders := make([]array.Builder, structType.NumFields())
+
+	if builder != nil {
+		structBuilder := builder.(*array.StructBuilder)
+		structBuilder.Append(true)
+
+		for i := range builders {
+			builders[i] = structBuilder.FieldBuilder(i)
+		}
+	}
+
+	return appendRow(builders, structType.Fields(), value)
+}
+
+func mismatchError(value reflect.Value, dataType arrow.DataType) error {
+	return fmt.Errorf("%w: can't append %s as %s", ErrFieldMismatch, value.Type(), dataType)
+}
+
+// intValue returns the integer value holds, and false when it isn't one or
+// overflows bits.
+func intValue(value reflect.Value, bits int) (int64, bool) {
+	var n int64
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+
+		n = int64(value.Uint())
+	default:
+		return 0, false
+	}
+
+	if bits < 64 && (n < -(1<<(bits-1)) || n >= 1<<(bits-1)) {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// uintValue returns the unsigned integer value holds, and false when it isn't
+// one, is negative or overflows bits.
+func uintValue(value reflect.Value, bits int) (uint64, bool) {
+	var n uint64
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 {
+			return 0, false
+		}
+
+		n = uint64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = value.Uint()
+	default:
+		return 0, false
+	}
+
+	if bits < 64 && n >= 1<<bits {
+		return 0, false
+	}
+
+	return n, true
+}
+
+func appendInt(builder array.Builder, n int64) {
+	switch builder := builder.(type) {
+	case *array.Int8Builder:
+		builder.Append(int8(n))
+	case *array.Int16Builder:
+		builder.Append(int16(n))
+	case *array.Int32Builder:
+		builder.Append(int32(n))
+	case *array.Int64Builder:
+		builder.Append(n)
+	}
+}
+
+func appendUint(builder array.Builder, n uint64) {
+	switch builder := builder.(type) {
+	case *array.Uint8Builder:
+		builder.Append(uint8(n))
+	case *array.Uint16Builder:
+		builder.Append(uint16(n))
+	case *array.Uint32Builder:
+		builder.Append(uint32(n))
+	case *array.Uint64Builder:
+		builder.Append(n)
+	}
+}
+
+func appendTime(builder array.Builder, t time.Time) {
+	switch builder := builder.(type) {
+	case *array.TimestampBuilder:
+		builder.AppendTime(t)
+	case *array.Date32Builder:
+		builder.Append(arrow.Date32FromTime(t))
+	case *array.Date64Builder:
+		builder.Append(arrow.Date64FromTime(t))
+	}
+}
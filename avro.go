@@ -0,0 +1,601 @@
+package dynamicstruct
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// avroSchema is a parsed Avro schema together with the Go type its values
+// decode into.
+type avroSchema struct {
+	kind     string
+	logical  string
+	fields   []*avroSchema
+	items    *avroSchema
+	branches []*avroSchema
+	symbols  []string
+	size     int
+	goType   reflect.Type
+
+	// builder defines the struct of a record
+	builder *Builder
+}
+
+type avroParser struct {
+	named    map[string]*avroSchema
+	defining map[string]bool
+	cfg      *importConfig
+}
+
+// parseAvroSchema parses an Avro schema in its JSON form. Records become
+// structs with fields tagged with their Avro name in avro and json tags,
+// enums strings, fixed types byte arrays, arrays slices and maps maps with
+// string keys. A union of null and one other type becomes a pointer to that
+// type, other unions any. The timestamp-millis, timestamp-micros and date
+// logical types decode into time.Time.
+func parseAvroSchema(schema []byte, opts ...ImportOption) (*avroSchema, error) {
+	var node any
+	if err := json.Unmarshal(schema, &node); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+	}
+
+	p := &avroParser{
+		named:    map[string]*avroSchema{},
+		defining: map[string]bool{},
+		cfg:      newImportConfig(opts),
+	}
+
+	return p.parse(node, "")
+}
+
+func (p *avroParser) parse(node any, namespace string) (*avroSchema, error) {
+	switch n := node.(type) {
+	case string:
+		return p.primitive(n, namespace)
+	case []any:
+		return p.union(n, namespace)
+	case map[string]any:
+		kind, _ := n["type"].(string)
+
+		switch kind {
+		case "record", "error":
+			return p.record(n, namespace)
+		case "enum", "fixed":
+			return p.namedType(kind, n, namespace)
+		case "array":
+			return p.container(kind, n["items"], namespace)
+		case "map":
+			return p.container(kind, n["values"], namespace)
+		}
+
+		if kind == "" {
+			// {"type": {...}} wraps a complex type
+			return p.parse(n["type"], namespace)
+		}
+
+		s, err := p.primitive(kind, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		logical, _ := n["logicalType"].(string)
+
+		return avroLogical(s, logical), nil
+	default:
+		return nil, fmt.Errorf("%w: invalid Avro schema %v", ErrInvalidSchema, node)
+	}
+}
+
+func (p *avroParser) primitive(name, namespace string) (*avroSchema, error) {
+	var goType reflect.Type
+
+	switch name {
+	case "null":
+		goType = anyType
+	case "boolean":
+		goType = reflect.TypeOf(false)
+	case "int":
+		goType = reflect.TypeOf(int32(0))
+	case "long":
+		goType = reflect.TypeOf(int64(0))
+	case "float":
+		goType = reflect.TypeOf(float32(0))
+	case "double":
+		goType = reflect.TypeOf(float64(0))
+	case "bytes":
+		goType = reflect.TypeOf([]byte(nil))
+	case "string":
+		goType = reflect.TypeOf("")
+	default:
+		return p.reference(name, namespace)
+	}
+
+	return &avroSchema{kind: name, goType: goType}, nil
+}
+
+// reference resolves the name of a type defined earlier in the schema.
+func (p *avroParser) reference(name, namespace string) (*avroSchema, error) {
+	candidates := []string{name}
+	if namespace != "" && !strings.Contains(name, ".") {
+		candidates = []string{namespace + "." + name, name}
+	}
+
+	for _, candidate := range candidates {
+		if p.defining[candidate] {
+			return nil, fmt.Errorf("%w: circular reference to %q", ErrInvalidSchema, candidate)
+		}
+
+		if s, ok := p.named[candidate]; ok {
+			return s, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown Avro type %q", ErrInvalidSchema, name)
+}
+
+// avroFullName returns the full name of a named type and the namespace its
+// members are resolved in.
+func avroFullName(n map[string]any, namespace string) (string, string, error) {
+	name, _ := n["name"].(string)
+	if name == "" {
+		return "", "", fmt.Errorf("%w: named Avro type without a name", ErrInvalidSchema)
+	}
+
+	if ns, ok := n["namespace"].(string); ok {
+		namespace = ns
+	}
+
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name, name[:i], nil
+	}
+
+	if namespace == "" {
+		return name, "", nil
+	}
+
+	return namespace + "." + name, namespace, nil
+}
+
+func (p *avroParser) record(n map[string]any, namespace string) (*avroSchema, error) {
+	fullName, namespace, err := avroFullName(n, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := n["fields"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: record %q without fields", ErrInvalidSchema, fullName)
+	}
+
+	p.defining[fullName] = true
+	defer delete(p.defining, fullName)
+
+	s := &avroSchema{kind: "record", builder: New()}
+
+	for _, item := range fields {
+		field, _ := item.(map[string]any)
+		name, _ := field["name"].(string)
+
+		if name == "" {
+			return nil, fmt.Errorf("%w: field of record %q without a name", ErrInvalidSchema, fullName)
+		}
+
+		fieldSchema, err := p.parse(field["type"], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %q", err, name)
+		}
+
+		tag := fmt.Sprintf(`avro:%q json:%q`, name, name)
+		if err := s.builder.addImportedField(name, fieldSchema.goType, tag, p.cfg); err != nil {
+			return nil, fmt.Errorf("%w: field %q", err, name)
+		}
+
+		s.fields = append(s.fields, fieldSchema)
+	}
+
+	if s.goType, err = structOf(s.builder.buildStructFields()); err != nil {
+		return nil, err
+	}
+
+	p.named[fullName] = s
+
+	return s, nil
+}
+
+func (p *avroParser) namedType(kind string, n map[string]any, namespace string) (*avroSchema, error) {
+	fullName, _, err := avroFullName(n, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &avroSchema{kind: kind}
+
+	if kind == "enum" {
+		symbols, _ := n["symbols"].([]any)
+		for _, symbol := range symbols {
+			name, _ := symbol.(string)
+			s.symbols = append(s.symbols, name)
+		}
+
+		s.goType = reflect.TypeOf("")
+	} else {
+		size, ok := n["size"].(float64)
+		if !ok || size < 0 || size > math.MaxInt32 || size != math.Trunc(size) {
+			return nil, fmt.Errorf("%w: fixed %q without a valid size", ErrInvalidSchema, fullName)
+		}
+
+		s.size = int(size)
+		s.goType = reflect.ArrayOf(s.size, reflect.TypeOf(byte(0)))
+	}
+
+	p.named[fullName] = s
+
+	return s, nil
+}
+
+func (p *avroParser) container(kind string, items any, namespace string) (*avroSchema, error) {
+	itemSchema, err := p.parse(items, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &avroSchema{kind: kind, items: itemSchema}
+	if kind == "array" {
+		s.goType = reflect.SliceOf(itemSchema.goType)
+	} else {
+		s.goType = reflect.MapOf(reflect.TypeOf(""), itemSchema.goType)
+	}
+
+	return s, nil
+}
+
+func (p *avroParser) union(branches []any, namespace string) (*avroSchema, error) {
+	s := &avroSchema{kind: "union", goType: anyType}
+
+	var nonNull []*avroSchema
+
+	for _, branch := range branches {
+		branchSchema, err := p.parse(branch, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		s.branches = append(s.branches, branchSchema)
+
+		if branchSchema.kind != "null" {
+			nonNull = append(nonNull, branchSchema)
+		}
+	}
+
+	// A null and one other type is an optional value of that type
+	if len(nonNull) == 1 && len(s.branches) == 2 {
+		switch goType := nonNull[0].goType; goType.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface:
+			s.goType = goType
+		default:
+			s.goType = reflect.PtrTo(goType)
+		}
+	}
+
+	return s, nil
+}
+
+func avroLogical(s *avroSchema, logical string) *avroSchema {
+	switch {
+	case logical == "timestamp-millis" && s.kind == "long",
+		logical == "timestamp-micros" && s.kind == "long",
+		logical == "date" && s.kind == "int":
+		return &avroSchema{kind: s.kind, logical: logical, goType: timeType}
+	default:
+		return s
+	}
+}
+
+// avroDecoder decodes values in the Avro binary encoding.
+type avroDecoder struct {
+	data []byte
+	pos  int
+}
+
+// decodeAvro decodes data, written with schema s, into the struct instancePtr
+// points to, whose type must be the one s was parsed into.
+func decodeAvro(s *avroSchema, data []byte, instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	if value.Type() != s.goType {
+		return fmt.Errorf("%w: instance type: %s, built type: %s", ErrIncompatibleTypes, value.Type(), s.goType)
+	}
+
+	d := &avroDecoder{data: data}
+
+	return d.decode(s, value)
+}
+
+func (d *avroDecoder) truncated() error {
+	return fmt.Errorf("%w: Avro data truncated at byte %d", ErrInvalidMessage, d.pos)
+}
+
+func (d *avroDecoder) long() (int64, error) {
+	u, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, d.truncated()
+	}
+
+	d.pos += n
+
+	// Zig-zag decoding
+	return int64(u>>1) ^ -int64(u&1), nil
+}
+
+func (d *avroDecoder) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, d.truncated()
+	}
+
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+
+	return b, nil
+}
+
+// length reads the length prefix of bytes and strings.
+func (d *avroDecoder) length() (int, error) {
+	n, err := d.long()
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 || n > int64(len(d.data)-d.pos) {
+		return 0, d.truncated()
+	}
+
+	return int(n), nil
+}
+
+func (d *avroDecoder) decode(s *avroSchema, v reflect.Value) error {
+	switch s.kind {
+	case "null":
+		return nil
+	case "boolean":
+		b, err := d.bytes(1)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b[0] != 0)
+	case "int", "long":
+		n, err := d.long()
+		if err != nil {
+			return err
+		}
+
+		if s.kind == "int" && (n < math.MinInt32 || n > math.MaxInt32) {
+			return fmt.Errorf("%w: int %d out of range", ErrInvalidMessage, n)
+		}
+
+		switch s.logical {
+		case "timestamp-millis":
+			v.Set(reflect.ValueOf(time.UnixMilli(n).UTC()))
+		case "timestamp-micros":
+			v.Set(reflect.ValueOf(time.UnixMicro(n).UTC()))
+		case "date":
+			v.Set(reflect.ValueOf(time.Unix(n*24*60*60, 0).UTC()))
+		default:
+			if v.OverflowInt(n) {
+				return fmt.Errorf("%w: %d overflows %s", ErrInvalidMessage, n, v.Type())
+			}
+
+			v.SetInt(n)
+		}
+	case "float":
+		b, err := d.bytes(4)
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+	case "double":
+		b, err := d.bytes(8)
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	case "bytes", "string":
+		n, err := d.length()
+		if err != nil {
+			return err
+		}
+
+		b, _ := d.bytes(n)
+		if s.kind == "string" {
+			v.SetString(string(b))
+		} else {
+			v.SetBytes(append([]byte(nil), b...))
+		}
+	case "fixed":
+		b, err := d.bytes(s.size)
+		if err != nil {
+			return err
+		}
+
+		reflect.Copy(v, reflect.ValueOf(b))
+	case "enum":
+		i, err := d.long()
+		if err != nil {
+			return err
+		}
+
+		if i < 0 || i >= int64(len(s.symbols)) {
+			return fmt.Errorf("%w: enum index %d out of range", ErrInvalidMessage, i)
+		}
+
+		v.SetString(s.symbols[i])
+	case "record":
+		for i, field := range s.fields {
+			if err := d.decode(field, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case "array", "map":
+		return d.blocks(s, v)
+	case "union":
+		return d.union(s, v)
+	}
+
+	return nil
+}
+
+// avroMaxEmptyItems bounds the items of an array whose items take no bytes,
+// such as nulls, which the size of the data doesn't bound.
+const avroMaxEmptyItems = 1 << 16
+
+// blocks decodes the blocks arrays and maps are encoded in.
+func (d *avroDecoder) blocks(s *avroSchema, v reflect.Value) error {
+	if s.kind == "map" {
+		v.Set(reflect.MakeMap(v.Type()))
+	} else {
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	}
+
+	// Map entries start with the length of their key
+	itemSize := avroMinSize(s.items)
+	if s.kind == "map" {
+		itemSize++
+	}
+
+	total := int64(0)
+
+	for {
+		count, err := d.long()
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return nil
+		}
+
+		// A negative count is followed by the size of the block in bytes
+		if count < 0 {
+			count = -count
+
+			if _, err := d.long(); err != nil {
+				return err
+			}
+		}
+
+		// Counts are checked against what the rest of the data can hold, so
+		// that a few bytes can't claim billions of items
+		total += count
+
+		switch {
+		case count < 0:
+			return fmt.Errorf("%w: invalid block count", ErrInvalidMessage)
+		case itemSize > 0 && count > int64(len(d.data)-d.pos)/int64(itemSize):
+			return d.truncated()
+		case itemSize == 0 && total > avroMaxEmptyItems:
+			return fmt.Errorf("%w: more than %d empty items", ErrInvalidMessage, avroMaxEmptyItems)
+		}
+
+		for i := int64(0); i < count; i++ {
+			item := reflect.New(s.items.goType).Elem()
+
+			if s.kind == "array" {
+				if err := d.decode(s.items, item); err != nil {
+					return err
+				}
+
+				v.Set(reflect.Append(v, item))
+
+				continue
+			}
+
+			n, err := d.length()
+			if err != nil {
+				return err
+			}
+
+			key, _ := d.bytes(n)
+
+			if err := d.decode(s.items, item); err != nil {
+				return err
+			}
+
+			v.SetMapIndex(reflect.ValueOf(string(key)), item)
+		}
+	}
+}
+
+// avroMinSize returns the fewest bytes a value of s is encoded in.
+func avroMinSize(s *avroSchema) int {
+	switch s.kind {
+	case "null":
+		return 0
+	case "float":
+		return 4
+	case "double":
+		return 8
+	case "fixed":
+		return s.size
+	case "record":
+		size := 0
+		for _, field := range s.fields {
+			size += avroMinSize(field)
+		}
+
+		return size
+	default:
+		// Varints, length prefixes, block counts and union indexes take a
+		// byte at least
+		return 1
+	}
+}
+
+func (d *avroDecoder) union(s *avroSchema, v reflect.Value) error {
+	i, err := d.long()
+	if err != nil {
+		return err
+	}
+
+	if i < 0 || i >= int64(len(s.branches)) {
+		return fmt.Errorf("%w: union index %d out of range", ErrInvalidMessage, i)
+	}
+
+	branch := s.branches[i]
+
+	switch {
+	case branch.kind == "null":
+		v.Set(reflect.Zero(v.Type()))
+
+		return nil
+	case v.Type() == branch.goType:
+		return d.decode(branch, v)
+	case v.Kind() == reflect.Ptr:
+		target := reflect.New(branch.goType)
+		if err := d.decode(branch, target.Elem()); err != nil {
+			return err
+		}
+
+		v.Set(target)
+
+		return nil
+	default:
+		target := reflect.New(branch.goType).Elem()
+		if err := d.decode(branch, target); err != nil {
+			return err
+		}
+
+		v.Set(target)
+
+		return nil
+	}
+}
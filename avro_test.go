@@ -0,0 +1,173 @@
+package dynamicstruct_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+// avroLong encodes n as an Avro long.
+func avroLong(n int64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+
+	return buf[:binary.PutUvarint(buf, uint64((n<<1)^(n>>63)))]
+}
+
+func avroString(s string) []byte {
+	return append(avroLong(int64(len(s))), s...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var data []byte
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+
+	return data
+}
+
+const avroUserSchema = `{
+	"type": "record",
+	"name": "User",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": "string"},
+		{"name": "active", "type": "boolean"},
+		{"name": "score", "type": "double"},
+		{"name": "nickname", "type": ["null", "string"]},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "limits", "type": {"type": "map", "values": "int"}},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["ACTIVE", "BANNED"]}},
+		{"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "address", "type": {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}]}},
+		{"name": "previous", "type": ["null", "Address"]}
+	]
+}`
+
+func TestRegistryCodecAvro(t *testing.T) {
+	client := &registryClientTest{schemas: map[int]dynamicstruct.RegistrySchema{
+		1: {Schema: avroUserSchema},
+	}}
+
+	score := make([]byte, 8)
+	binary.LittleEndian.PutUint64(score, math.Float64bits(7.5))
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	payload := concat(
+		avroLong(42),
+		avroString("Alice"),
+		[]byte{1},
+		score,
+		avroLong(1), avroString("ali"),
+		avroLong(2), avroString("a"), avroString("b"), avroLong(0),
+		avroLong(1), avroString("daily"), avroLong(3), avroLong(0),
+		avroLong(1),
+		avroLong(created.UnixMilli()),
+		avroString("Berlin"),
+		avroLong(0),
+	)
+
+	codec := dynamicstruct.NewRegistryCodec(client)
+
+	instancePtr, err := codec.Deserialize("users", wireMessage(1, payload))
+	if err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	value := reflect.ValueOf(instancePtr).Elem()
+
+	tests := []struct {
+		field string
+		want  any
+	}{
+		{field: "Id", want: int64(42)},
+		{field: "Name", want: "Alice"},
+		{field: "Active", want: true},
+		{field: "Score", want: 7.5},
+		{field: "Tags", want: []string{"a", "b"}},
+		{field: "Limits", want: map[string]int32{"daily": 3}},
+		{field: "Status", want: "BANNED"},
+		{field: "Created", want: created},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := value.FieldByName(tt.field).Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+
+	if got := value.FieldByName("Nickname").Elem().String(); got != "ali" {
+		t.Errorf("Nickname = %q, want %q", got, "ali")
+	}
+
+	if got := value.FieldByName("Address").FieldByName("City").String(); got != "Berlin" {
+		t.Errorf("Address.City = %q, want %q", got, "Berlin")
+	}
+
+	if !value.FieldByName("Previous").IsNil() {
+		t.Errorf("Previous = %v, want nil", value.FieldByName("Previous"))
+	}
+
+	field, _ := value.Type().FieldByName("Id")
+	if got := field.Tag.Get("avro"); got != "id" {
+		t.Errorf("Id avro tag = %q, want %q", got, "id")
+	}
+}
+
+func TestRegistryCodecAvroErrors(t *testing.T) {
+	client := &registryClientTest{schemas: map[int]dynamicstruct.RegistrySchema{
+		1: {Type: "AVRO", Schema: avroUserSchema},
+		2: {Schema: `{"type": "record", "name": "Node", "fields": [{"name": "next", "type": ["null", "Node"]}]}`},
+		3: {Schema: `{"type": "record", "name": "Bad", "fields": [{"name": "x", "type": "Unknown"}]}`},
+		4: {Schema: `"string"`},
+		5: {Schema: `{"type": "record", "name": "Nulls", "fields": [{"name": "x", "type": {"type": "array", "items": "null"}}]}`},
+		6: {Schema: `{"type": "record", "name": "Longs", "fields": [{"name": "x", "type": {"type": "array", "items": "long"}}]}`},
+		7: {Schema: `{"type": "record", "name": "Int", "fields": [{"name": "f", "type": "int"}]}`},
+		8: {Schema: `{"type": "record", "name": "Huge", "fields": [{"name": "f", "type": {"type": "fixed", "name": "H", "size": 1e20}}]}`},
+	}}
+
+	codec := dynamicstruct.NewRegistryCodec(client)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "truncated", data: wireMessage(1, concat(avroLong(42), avroLong(10), []byte("Al"))), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "recursive_record", data: wireMessage(2, nil), wantErr: dynamicstruct.ErrInvalidSchema},
+		{name: "unknown_type", data: wireMessage(3, nil), wantErr: dynamicstruct.ErrInvalidSchema},
+		{name: "not_a_record", data: wireMessage(4, nil), wantErr: dynamicstruct.ErrInvalidSchema},
+		{name: "too_many_empty_items", data: wireMessage(5, concat(avroLong(1<<62), avroLong(0))), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "count_past_data", data: wireMessage(6, concat(avroLong(1<<40), avroLong(1))), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "int_out_of_range", data: wireMessage(7, avroLong(1<<40)), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "huge_fixed", data: wireMessage(8, nil), wantErr: dynamicstruct.ErrInvalidSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Deserialize("users", tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Deserialize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("empty_items", func(t *testing.T) {
+		instancePtr, err := codec.Deserialize("users", wireMessage(5, concat(avroLong(3), avroLong(0))))
+		if err != nil {
+			t.Fatalf("Deserialize() error = %v", err)
+		}
+
+		if got := reflect.ValueOf(instancePtr).Elem().Field(0).Len(); got != 3 {
+			t.Errorf("len(X) = %d, want 3", got)
+		}
+	})
+}
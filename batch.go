@@ -0,0 +1,87 @@
+package dynamicstruct
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldSpec describes a field for AddFields. Kind and Tags are as for
+// AddField, and Options as for AddFieldWithOptions.
+type FieldSpec struct {
+	Name    string
+	Kind    any
+	Tags    []string
+	Options []FieldOption
+}
+
+// FieldErrors lists the errors of the invalid entries of a batch, in the
+// order of the batch. errors.Is reports whether any of them matches.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strconv.Itoa(len(e)) + " invalid fields: " + strings.Join(messages, "; ")
+}
+
+func (e FieldErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddFields adds the fields of specs in order, all or none: when any entry is
+// invalid, the builder is left unchanged and the returned FieldErrors lists
+// every invalid entry, including names repeated within the batch.
+func (b *Builder) AddFields(specs []FieldSpec) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.frozen() {
+		return ErrInstanceAlreadyBuilt
+	}
+
+	state := b.saveState()
+	instance := b.instance
+
+	var errs FieldErrors
+
+	for _, spec := range specs {
+		if err := b.addSpec(spec); err != nil {
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				fieldErr = &FieldError{Field: spec.Name, Op: "add", Err: err}
+			}
+
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	if len(errs) > 0 {
+		b.restoreState(state)
+		b.restoreInstance(instance)
+
+		return errs
+	}
+
+	return nil
+}
+
+func (b *Builder) addSpec(spec FieldSpec) error {
+	if spec.Kind == nil {
+		return fieldError("add", spec.Name, fmt.Errorf("%w: kind", ErrValueCannotBeNil))
+	}
+
+	opts := append([]FieldOption{Tags(spec.Tags...)}, spec.Options...)
+
+	return b.addFieldWithOptions(spec.Name, spec.Kind, opts)
+}
@@ -0,0 +1,101 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestAddFields(t *testing.T) {
+	address := dynamicstruct.New()
+	_ = address.AddField("City", "")
+
+	builder := dynamicstruct.New()
+
+	err := builder.AddFields([]dynamicstruct.FieldSpec{
+		{Name: "Name", Kind: "", Tags: []string{`json:"name"`}},
+		{Name: "Port", Kind: 0, Options: []dynamicstruct.FieldOption{dynamicstruct.Default(8080)}},
+		{Name: "Address", Kind: address},
+	})
+	if err != nil {
+		t.Fatalf("AddFields() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, []string{"Name", "Port", "Address"}) {
+		t.Errorf("fields = %v, want [Name Port Address]", got)
+	}
+
+	if got, _ := builder.GetField("Port"); got != 8080 {
+		t.Errorf("GetField() Port = %v, want 8080", got)
+	}
+}
+
+func TestAddFieldsAllOrNone(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Id", 0)
+
+	err := builder.AddFields([]dynamicstruct.FieldSpec{
+		{Name: "Name", Kind: ""},
+		{Name: "Id", Kind: ""},
+		{Name: "Email", Kind: "", Tags: []string{`json:"email`}},
+		{Name: "Name", Kind: 0},
+		{Name: "Age", Kind: nil},
+		{Name: "Port", Kind: 0, Options: []dynamicstruct.FieldOption{dynamicstruct.Default("x")}},
+	})
+
+	var fieldErrs dynamicstruct.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("AddFields() error = %v, want FieldErrors", err)
+	}
+
+	var got []string
+	for _, fieldErr := range fieldErrs {
+		got = append(got, fieldErr.Field)
+	}
+
+	if want := []string{"Id", "Email", "Name", "Age", "Port"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldErrors fields = %v, want %v", got, want)
+	}
+
+	for _, target := range []error{
+		dynamicstruct.ErrFieldAlreadyExists,
+		dynamicstruct.ErrInvalidTag,
+		dynamicstruct.ErrValueCannotBeNil,
+		dynamicstruct.ErrIncompatibleTypes,
+	} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(err, %v) = false, want true", target)
+		}
+	}
+
+	if !strings.HasPrefix(err.Error(), "5 invalid fields: add Id: field already exists") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, []string{"Id"}) {
+		t.Errorf("fields = %v, want [Id]", got)
+	}
+}
+
+func TestAddFieldsBuilt(t *testing.T) {
+	builder := dynamicstruct.New()
+	_, _ = builder.Build()
+
+	err := builder.AddFields([]dynamicstruct.FieldSpec{{Name: "Name", Kind: ""}})
+	if !errors.Is(err, dynamicstruct.ErrInstanceAlreadyBuilt) {
+		t.Errorf("AddFields() error = %v, want %v", err, dynamicstruct.ErrInstanceAlreadyBuilt)
+	}
+}
@@ -0,0 +1,370 @@
+// Package benchmarks provides reusable benchmarks for dynamicstruct schemas.
+//
+// The functions in this package take a *testing.B and a Schema, so they can be
+// called from benchmarks in any module to measure a real-world shape:
+//
+//	func BenchmarkOrderBuild(b *testing.B) {
+//		benchmarks.Build(b, orderSchema)
+//	}
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+type Field struct {
+	Name string
+	Kind any
+	Tags []string
+}
+
+type Schema []Field
+
+// Flat returns a schema of n scalar fields with json tags, cycling through
+// string, int, float64 and bool kinds.
+func Flat(n int) Schema {
+	kinds := []any{"", int(0), float64(0), false}
+	schema := make(Schema, 0, n)
+
+	for i := 0; i < n; i++ {
+		schema = append(schema, Field{
+			Name: fmt.Sprintf("Field%d", i),
+			Kind: kinds[i%len(kinds)],
+			Tags: []string{fmt.Sprintf(`json:"field_%d"`, i)},
+		})
+	}
+
+	return schema
+}
+
+// NewBuilder returns a builder with all fields of the schema added.
+func (s Schema) NewBuilder() (*dynamicstruct.Builder, error) {
+	builder := dynamicstruct.New()
+
+	for _, field := range s {
+		if err := builder.AddField(field.Name, field.Kind, field.Tags...); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+
+	return builder, nil
+}
+
+// Type builds the schema and returns the resulting struct type.
+func (s Schema) Type() (reflect.Type, error) {
+	builder, err := s.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return reflect.TypeOf(instance), nil
+}
+
+// SampleJSON returns a JSON document with a non-zero value for every field.
+func (s Schema) SampleJSON() ([]byte, error) {
+	typ, err := s.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	instance := reflect.New(typ).Elem()
+
+	for i := 0; i < instance.NumField(); i++ {
+		field := instance.Field(i)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(fmt.Sprintf("value %d", i))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(int64(i))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(uint64(i))
+		case reflect.Float32, reflect.Float64:
+			field.SetFloat(float64(i) + 0.5)
+		case reflect.Bool:
+			field.SetBool(i%2 == 0)
+		default:
+			// Leave composite fields at their zero value
+		}
+	}
+
+	return json.Marshal(instance.Interface())
+}
+
+func AddField(b *testing.B, s Schema) {
+	b.Helper()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.NewBuilder(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Build(b *testing.B, s Schema) {
+	b.Helper()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+
+		builder, err := s.NewBuilder()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.StartTimer()
+
+		if _, err := builder.Build(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func GetField(b *testing.B, s Schema) {
+	b.Helper()
+
+	builder := mustBuild(b, s)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := builder.GetField(s[i%len(s)].Name); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func GetFieldValue(b *testing.B, s Schema) {
+	b.Helper()
+
+	builder := mustBuild(b, s)
+
+	// Allocate one destination per field up front, so only the access is measured
+	values := make([]any, len(s))
+	for i, field := range s {
+		values[i] = reflect.New(reflect.TypeOf(field.Kind)).Interface()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		j := i % len(s)
+
+		if err := builder.GetFieldValue(s[j].Name, values[j]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func JSONRoundTrip(b *testing.B, s Schema) {
+	b.Helper()
+
+	typ := mustType(b, s)
+
+	data, err := s.SampleJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		instance := reflect.New(typ).Interface()
+
+		if err := json.Unmarshal(data, instance); err != nil {
+			b.Fatal(err)
+		}
+
+		if _, err := json.Marshal(instance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BatchDecode measures decoding a JSON array of size documents into a slice
+// of the dynamic type.
+func BatchDecode(b *testing.B, s Schema, size int) {
+	b.Helper()
+
+	typ := mustType(b, s)
+
+	doc, err := s.SampleJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	docs := make([]json.RawMessage, size)
+	for i := range docs {
+		docs[i] = doc
+	}
+
+	data, err := json.Marshal(docs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	sliceType := reflect.SliceOf(typ)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		batch := reflect.New(sliceType).Interface()
+
+		if err := json.Unmarshal(data, batch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// CompiledGetField measures reading fields through getters compiled with
+// CompileAccessors, for the same access pattern as GetField. Only string, int,
+// float64 and bool fields are read; others are skipped.
+func CompiledGetField(b *testing.B, s Schema) {
+	b.Helper()
+
+	builder := mustBuild(b, s)
+
+	accessors, err := builder.CompileAccessors()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	instance, err := builder.NewInstance()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	getters := make([]func(instancePtr any), 0, len(s))
+
+	for _, field := range s {
+		var getter func(instancePtr any)
+
+		switch field.Kind.(type) {
+		case string:
+			get, err := dynamicstruct.Getter[string](accessors, field.Name)
+			mustCompile(b, err)
+			getter = func(instancePtr any) { stringSink = get(instancePtr) }
+		case int:
+			get, err := dynamicstruct.Getter[int](accessors, field.Name)
+			mustCompile(b, err)
+			getter = func(instancePtr any) { intSink = get(instancePtr) }
+		case float64:
+			get, err := dynamicstruct.Getter[float64](accessors, field.Name)
+			mustCompile(b, err)
+			getter = func(instancePtr any) { floatSink = get(instancePtr) }
+		case bool:
+			get, err := dynamicstruct.Getter[bool](accessors, field.Name)
+			mustCompile(b, err)
+			getter = func(instancePtr any) { boolSink = get(instancePtr) }
+		default:
+			continue
+		}
+
+		getters = append(getters, getter)
+	}
+
+	if len(getters) == 0 {
+		b.Skip("schema has no string, int, float64 or bool fields")
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		getters[i%len(getters)](instance)
+	}
+}
+
+// Sinks keep compiled getter results alive so the calls aren't optimized away
+var (
+	stringSink string
+	intSink    int
+	floatSink  float64
+	boolSink   bool
+)
+
+func mustCompile(b *testing.B, err error) {
+	b.Helper()
+
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+// PooledDecode measures decoding a JSON document into instances taken from
+// and returned to the builder's Pool.
+func PooledDecode(b *testing.B, s Schema) {
+	b.Helper()
+
+	builder := mustBuild(b, s)
+
+	pool, err := builder.Pool()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	data, err := s.SampleJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		instance := pool.Get()
+
+		if err := json.Unmarshal(data, instance); err != nil {
+			b.Fatal(err)
+		}
+
+		if err := pool.Put(instance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func mustBuild(b *testing.B, s Schema) *dynamicstruct.Builder {
+	b.Helper()
+
+	builder, err := s.NewBuilder()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if _, err := builder.Build(); err != nil {
+		b.Fatal(err)
+	}
+
+	return builder
+}
+
+func mustType(b *testing.B, s Schema) reflect.Type {
+	b.Helper()
+
+	typ, err := s.Type()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return typ
+}
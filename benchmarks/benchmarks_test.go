@@ -0,0 +1,96 @@
+package benchmarks_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct/benchmarks"
+)
+
+var sizes = []int{8, 64}
+
+func BenchmarkAddField(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d", size), func(b *testing.B) {
+			benchmarks.AddField(b, benchmarks.Flat(size))
+		})
+	}
+}
+
+func BenchmarkBuild(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d", size), func(b *testing.B) {
+			benchmarks.Build(b, benchmarks.Flat(size))
+		})
+	}
+}
+
+func BenchmarkGetField(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d", size), func(b *testing.B) {
+			benchmarks.GetField(b, benchmarks.Flat(size))
+		})
+	}
+}
+
+func BenchmarkGetFieldValue(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d", size), func(b *testing.B) {
+			benchmarks.GetFieldValue(b, benchmarks.Flat(size))
+		})
+	}
+}
+
+func BenchmarkCompiledGetField(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d", size), func(b *testing.B) {
+			benchmarks.CompiledGetField(b, benchmarks.Flat(size))
+		})
+	}
+}
+
+func BenchmarkJSONRoundTrip(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d", size), func(b *testing.B) {
+			benchmarks.JSONRoundTrip(b, benchmarks.Flat(size))
+		})
+	}
+}
+
+func BenchmarkBatchDecode(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d_docs_100", size), func(b *testing.B) {
+			benchmarks.BatchDecode(b, benchmarks.Flat(size), 100)
+		})
+	}
+}
+
+func BenchmarkPooledDecode(b *testing.B) {
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("fields_%d", size), func(b *testing.B) {
+			benchmarks.PooledDecode(b, benchmarks.Flat(size))
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := benchmarks.Flat(6)
+
+	typ, err := schema.Type()
+	if err != nil {
+		t.Fatalf("Type() error = %v", err)
+	}
+
+	if typ.NumField() != 6 {
+		t.Errorf("Type() fields = %d, want 6", typ.NumField())
+	}
+
+	data, err := schema.SampleJSON()
+	if err != nil {
+		t.Fatalf("SampleJSON() error = %v", err)
+	}
+
+	if len(data) == 0 {
+		t.Error("SampleJSON() returned empty document")
+	}
+}
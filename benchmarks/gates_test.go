@@ -0,0 +1,57 @@
+package benchmarks_test
+
+import (
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct/benchmarks"
+)
+
+// Allocation budgets for hot accessors. Timing is too noisy to gate on in CI,
+// but allocation counts are stable, so a regression here fails the test suite.
+func TestAllocationGates(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful under the race detector")
+	}
+
+	schema := benchmarks.Flat(16)
+
+	builder, err := schema.NewBuilder()
+	if err != nil {
+		t.Fatalf("NewBuilder() error = %v", err)
+	}
+
+	if _, err = builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var intValue int
+
+	tests := []struct {
+		name   string
+		budget float64
+		fn     func()
+	}{
+		{
+			name:   "get_field_value",
+			budget: 0,
+			fn: func() {
+				_ = builder.GetFieldValue("Field1", &intValue)
+			},
+		},
+		{
+			name:   "get_field",
+			budget: 1,
+			fn: func() {
+				_, _ = builder.GetField("Field0")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.fn); allocs > tt.budget {
+				t.Errorf("allocations per op = %v, budget %v", allocs, tt.budget)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+)
+
+// binaryMagic starts every payload written by EncodeBinary. The last byte is
+// the format version.
+var binaryMagic = []byte("DSB\x01")
+
+// binaryDefinition is the builder definition EncodeBinary writes ahead of the
+// values.
+type binaryDefinition struct {
+	Fields []binaryField
+}
+
+type binaryField struct {
+	Name      string
+	Tag       string
+	Anonymous bool
+	Required  bool
+	Default   bool
+	Meta      map[string]any
+	Group     string
+	Type      binaryType
+}
+
+// binaryType describes a field type by its structure. Named types are
+// described by their underlying type, except for the types in namedTypes,
+// which are referred to by name.
+type binaryType struct {
+	Kind   uint
+	Named  string
+	Len    int
+	Key    *binaryType
+	Elem   *binaryType
+	Fields []binaryField
+}
+
+var namedTypes = map[string]reflect.Type{
+	"time.Time":     timeType,
+	"time.Duration": durationType,
+}
+
+// EncodeBinary serializes instance, a value of the built type or a pointer to
+// one, together with the builder's definition, so that DecodeBinary can
+// recreate both in a process that doesn't know the type. Values are encoded
+// with encoding/gob.
+//
+// Field types are recorded by structure: named types other than time.Time
+// and time.Duration are decoded as their underlying type, without methods.
+// Interface, channel and function fields can't be encoded and return
+// ErrIncompatibleTypes. Field metadata is encoded with the definition; values
+// of types other than gob's basic types must be registered with gob.Register.
+func (b *Builder) EncodeBinary(instance any) ([]byte, error) {
+	b.m.RLock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		b.m.RUnlock()
+
+		return nil, ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+	fields := typeFields(structType)
+
+	defaults := reflect.New(structType).Elem()
+	b.applyDefaults(defaults)
+
+	def := binaryDefinition{}
+
+	for _, field := range fields {
+		fieldType, err := describeType(field.Type, map[reflect.Type]bool{})
+		if err != nil {
+			b.m.RUnlock()
+
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		meta := b.meta[field.Name]
+		def.Fields = append(def.Fields, binaryField{
+			Name:      field.Name,
+			Tag:       string(field.Tag),
+			Anonymous: field.Anonymous,
+			Required:  !field.Anonymous && meta.required,
+			Default:   !field.Anonymous && meta.defaultValue.IsValid(),
+			Meta:      meta.values,
+			Group:     meta.group,
+			Type:      *fieldType,
+		})
+	}
+	b.m.RUnlock()
+
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Type() != structType {
+		return nil, fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			structType.String(),
+		)
+	}
+
+	var buf bytes.Buffer
+
+	buf.Write(binaryMagic)
+
+	encoder := gob.NewEncoder(&buf)
+	for _, v := range []any{def, defaults.Interface(), value.Interface()} {
+		if err := encoder.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeBinary recreates a builder and an instance from data written by
+// EncodeBinary. The builder is built, and the instance is a pointer to the
+// instance it holds, so GetField and the other accessors read the decoded
+// values.
+func DecodeBinary(data []byte) (*Builder, any, error) {
+	if !bytes.HasPrefix(data, binaryMagic) {
+		return nil, nil, fmt.Errorf("%w: not a dynamicstruct binary payload", ErrInvalidSchema)
+	}
+
+	decoder := gob.NewDecoder(bytes.NewReader(data[len(binaryMagic):]))
+
+	var def binaryDefinition
+	if err := decoder.Decode(&def); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+	}
+
+	builder := New()
+
+	for _, field := range def.Fields {
+		fieldType, err := field.Type.reflectType()
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		tag, err := parseTags([]string{field.Tag})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if field.Anonymous {
+			if err := builder.addAnonymous(field.Name, fieldType, []string{string(tag)}); err != nil {
+				return nil, nil, fmt.Errorf("%w: field %s: %s", ErrInvalidSchema, field.Name, err.Error())
+			}
+
+			continue
+		}
+
+		if err := builder.addField(field.Name, fieldType, []string{string(tag)}); err != nil {
+			return nil, nil, fmt.Errorf("%w: field %s: %s", ErrInvalidSchema, field.Name, err.Error())
+		}
+	}
+
+	// Defaults are set after Build, so that values left zero in the encoded
+	// instance stay zero when it is decoded over the built one
+	if _, err := builder.Build(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+	}
+
+	defaults := reflect.New(builder.instance.Type())
+	if err := decoder.Decode(defaults.Interface()); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+	}
+
+	for _, field := range def.Fields {
+		if !field.Required && !field.Default && len(field.Meta) == 0 && field.Group == "" {
+			continue
+		}
+
+		meta := fieldMeta{required: field.Required, group: field.Group, values: field.Meta}
+		if field.Default {
+			meta.defaultValue = defaults.Elem().FieldByName(field.Name)
+		}
+
+		if builder.meta == nil {
+			builder.meta = make(map[string]fieldMeta)
+		}
+
+		builder.meta[field.Name] = meta
+	}
+
+	instance := builder.instance.Addr()
+	if err := decoder.Decode(instance.Interface()); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+	}
+
+	return builder, instance.Interface(), nil
+}
+
+func describeType(t reflect.Type, visiting map[reflect.Type]bool) (*binaryType, error) {
+	for name, namedType := range namedTypes {
+		if t == namedType {
+			return &binaryType{Named: name}, nil
+		}
+	}
+
+	if visiting[t] {
+		return nil, fmt.Errorf("%w: recursive type %s can't be encoded", ErrIncompatibleTypes, t.String())
+	}
+
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	described := &binaryType{Kind: uint(t.Kind())}
+
+	var err error
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+	case reflect.Array:
+		described.Len = t.Len()
+		described.Elem, err = describeType(t.Elem(), visiting)
+	case reflect.Slice, reflect.Ptr:
+		described.Elem, err = describeType(t.Elem(), visiting)
+	case reflect.Map:
+		if described.Key, err = describeType(t.Key(), visiting); err == nil {
+			described.Elem, err = describeType(t.Elem(), visiting)
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				return nil, fmt.Errorf("%w: %s has unexported fields", ErrIncompatibleTypes, t.String())
+			}
+
+			fieldType, err := describeType(field.Type, visiting)
+			if err != nil {
+				return nil, err
+			}
+
+			described.Fields = append(described.Fields, binaryField{
+				Name:      field.Name,
+				Tag:       string(field.Tag),
+				Anonymous: field.Anonymous,
+				Type:      *fieldType,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s can't be encoded", ErrIncompatibleTypes, t.String())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return described, nil
+}
+
+// maxBinaryArrayLen bounds the length and the size in bytes of the arrays
+// DecodeBinary recreates, so that a crafted payload can't describe arrays too
+// large to create.
+const maxBinaryArrayLen = 1 << 24
+
+func (d *binaryType) reflectType() (reflect.Type, error) {
+	if d.Named != "" {
+		t, ok := namedTypes[d.Named]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidSchema, d.Named)
+		}
+
+		return t, nil
+	}
+
+	kind := reflect.Kind(d.Kind)
+
+	switch kind {
+	case reflect.Array, reflect.Slice, reflect.Ptr, reflect.Map:
+		if d.Elem == nil || (kind == reflect.Map && d.Key == nil) {
+			return nil, fmt.Errorf("%w: %s type without element type", ErrInvalidSchema, kind)
+		}
+
+		elem, err := d.Elem.reflectType()
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case reflect.Array:
+			if d.Len < 0 || d.Len > maxBinaryArrayLen || elem.Size() > 0 && uintptr(d.Len) > maxBinaryArrayLen/elem.Size() {
+				return nil, fmt.Errorf("%w: array length %d out of range", ErrInvalidSchema, d.Len)
+			}
+
+			return reflect.ArrayOf(d.Len, elem), nil
+		case reflect.Slice:
+			return reflect.SliceOf(elem), nil
+		case reflect.Ptr:
+			return reflect.PtrTo(elem), nil
+		default:
+			key, err := d.Key.reflectType()
+			if err != nil {
+				return nil, err
+			}
+
+			if !key.Comparable() {
+				return nil, fmt.Errorf("%w: map key type %s isn't comparable", ErrInvalidSchema, key.String())
+			}
+
+			return reflect.MapOf(key, elem), nil
+		}
+	case reflect.Struct:
+		fields := make([]reflect.StructField, 0, len(d.Fields))
+
+		for _, field := range d.Fields {
+			fieldType, err := field.Type.reflectType()
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, reflect.StructField{
+				Name:      field.Name,
+				Type:      fieldType,
+				Tag:       reflect.StructTag(field.Tag),
+				Anonymous: field.Anonymous,
+			})
+		}
+
+		t, err := structOf(fields)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+		}
+
+		return t, nil
+	default:
+		t, ok := basicTypes[kind]
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported kind %s", ErrInvalidSchema, kind)
+		}
+
+		return t, nil
+	}
+}
+
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:       reflect.TypeOf(false),
+	reflect.String:     reflect.TypeOf(""),
+	reflect.Int:        reflect.TypeOf(int(0)),
+	reflect.Int8:       reflect.TypeOf(int8(0)),
+	reflect.Int16:      reflect.TypeOf(int16(0)),
+	reflect.Int32:      reflect.TypeOf(int32(0)),
+	reflect.Int64:      reflect.TypeOf(int64(0)),
+	reflect.Uint:       reflect.TypeOf(uint(0)),
+	reflect.Uint8:      reflect.TypeOf(uint8(0)),
+	reflect.Uint16:     reflect.TypeOf(uint16(0)),
+	reflect.Uint32:     reflect.TypeOf(uint32(0)),
+	reflect.Uint64:     reflect.TypeOf(uint64(0)),
+	reflect.Uintptr:    reflect.TypeOf(uintptr(0)),
+	reflect.Float32:    reflect.TypeOf(float32(0)),
+	reflect.Float64:    reflect.TypeOf(float64(0)),
+	reflect.Complex64:  reflect.TypeOf(complex64(0)),
+	reflect.Complex128: reflect.TypeOf(complex128(0)),
+}
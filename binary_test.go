@@ -0,0 +1,247 @@
+package dynamicstruct_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestEncodeBinary(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddFieldWithOptions("Port", 0, dynamicstruct.Default(8080), dynamicstruct.Required())
+	_ = builder.AddField("Tags", []string{})
+	_ = builder.AddField("Scores", map[string]float64{})
+	_ = builder.AddField("Address", &AddressTest{})
+	_ = builder.AddField("Checksum", [4]byte{})
+	_ = builder.AddField("CreatedAt", time.Time{})
+	_ = builder.AddFieldWithOptions("Timeout", time.Duration(0), dynamicstruct.Group("Network"))
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	value := reflect.New(reflect.TypeOf(instance)).Elem()
+	value.FieldByName("Email").SetString("alice@example.com")
+	value.FieldByName("Name").SetString("Alice")
+	value.FieldByName("Tags").Set(reflect.ValueOf([]string{"a", "b"}))
+	value.FieldByName("Scores").Set(reflect.ValueOf(map[string]float64{"go": 9.5}))
+	value.FieldByName("Address").Set(reflect.ValueOf(&AddressTest{City: "Oslo"}))
+	value.FieldByName("Checksum").Set(reflect.ValueOf([4]byte{1, 2, 3, 4}))
+	value.FieldByName("CreatedAt").Set(reflect.ValueOf(createdAt))
+	value.FieldByName("Timeout").Set(reflect.ValueOf(time.Second))
+
+	data, err := builder.EncodeBinary(value.Interface())
+	if err != nil {
+		t.Fatalf("EncodeBinary() error = %v", err)
+	}
+
+	decodedBuilder, decoded, err := dynamicstruct.DecodeBinary(data)
+	if err != nil {
+		t.Fatalf("DecodeBinary() error = %v", err)
+	}
+
+	// Named types such as AddressTest are decoded as their underlying struct
+	// type, so the instances are compared by their JSON encoding
+	got, _ := json.Marshal(decoded)
+	want, _ := json.Marshal(value.Interface())
+
+	if string(got) != string(want) {
+		t.Errorf("DecodeBinary() instance = %s, want %s", got, want)
+	}
+
+	t.Run("definition", func(t *testing.T) {
+		decodedType := reflect.TypeOf(decoded).Elem()
+
+		name, _ := decodedType.FieldByName("Name")
+		if name.Tag != `json:"name"` {
+			t.Errorf("DecodeBinary() Name tag = %q, want %q", name.Tag, `json:"name"`)
+		}
+
+		if email, ok := decodedType.FieldByName("Email"); !ok || len(email.Index) != 2 {
+			t.Errorf("DecodeBinary() Email = %+v, want a promoted field", email)
+		}
+
+		if createdAt, _ := decodedType.FieldByName("CreatedAt"); createdAt.Type != reflect.TypeOf(time.Time{}) {
+			t.Errorf("DecodeBinary() CreatedAt type = %s, want time.Time", createdAt.Type)
+		}
+
+		var port int
+		if err := decodedBuilder.GetFieldValue("Port", &port); err != nil || port != 0 {
+			t.Errorf("GetFieldValue() = %d, %v, want 0, nil", port, err)
+		}
+
+		fresh, err := decodedBuilder.NewInstance()
+		if err != nil {
+			t.Fatalf("NewInstance() error = %v", err)
+		}
+
+		if got := reflect.ValueOf(fresh).Elem().FieldByName("Port").Int(); got != 8080 {
+			t.Errorf("NewInstance() Port = %d, want 8080", got)
+		}
+
+		err = decodedBuilder.CheckRequired(reflect.New(decodedType).Interface())
+		if !errors.Is(err, dynamicstruct.ErrValidation) {
+			t.Errorf("CheckRequired() error = %v, want %v", err, dynamicstruct.ErrValidation)
+		}
+
+		if got := decodedBuilder.FieldGroup("Timeout"); got != "Network" {
+			t.Errorf("FieldGroup(Timeout) = %q, want Network", got)
+		}
+	})
+}
+
+func TestEncodeBinaryErrors(t *testing.T) {
+	newBuilder := func(kind any) *dynamicstruct.Builder {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Value", kind)
+
+		return builder
+	}
+
+	tests := []struct {
+		name     string
+		builder  *dynamicstruct.Builder
+		build    bool
+		instance any
+		wantErr  error
+	}{
+		{name: "not_built", builder: newBuilder(0), instance: struct{}{}, wantErr: dynamicstruct.ErrInstanceNotBuilt},
+		{name: "wrong_instance_type", builder: newBuilder(0), build: true, instance: PersonTest{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "interface_field", builder: newBuilder([]any{}), build: true, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "channel_field", builder: newBuilder(make(chan int)), build: true, wantErr: dynamicstruct.ErrIncompatibleTypes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := tt.instance
+
+			if tt.build {
+				built, err := tt.builder.Build()
+				if err != nil {
+					t.Fatalf("Build() error = %v", err)
+				}
+
+				if instance == nil {
+					instance = built
+				}
+			}
+
+			_, err := tt.builder.EncodeBinary(instance)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EncodeBinary() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeBinaryErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "wrong_magic", data: []byte("gob data")},
+		{name: "truncated", data: []byte("DSB\x01\x00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := dynamicstruct.DecodeBinary(tt.data)
+			if !errors.Is(err, dynamicstruct.ErrInvalidSchema) {
+				t.Errorf("DecodeBinary() error = %v, want %v", err, dynamicstruct.ErrInvalidSchema)
+			}
+		})
+	}
+}
+
+// binaryFieldTest and binaryTypeTest mirror the definition EncodeBinary
+// writes, so that tests can craft payloads gob decodes into it.
+type binaryFieldTest struct {
+	Name      string
+	Anonymous bool
+	Type      binaryTypeTest
+}
+
+type binaryTypeTest struct {
+	Kind   uint
+	Len    int
+	Elem   *binaryTypeTest
+	Fields []binaryFieldTest
+}
+
+func craftBinary(t *testing.T, fields ...binaryFieldTest) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.WriteString("DSB\x01")
+
+	if err := gob.NewEncoder(&buf).Encode(struct{ Fields []binaryFieldTest }{fields}); err != nil {
+		t.Fatalf("gob.Encode() error = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDecodeBinaryCraftedDefinitions(t *testing.T) {
+	byteType := &binaryTypeTest{Kind: uint(reflect.Uint8)}
+	embedded := binaryTypeTest{Kind: uint(reflect.Struct), Fields: []binaryFieldTest{{Name: "X", Type: *byteType}}}
+
+	tests := []struct {
+		name   string
+		fields []binaryFieldTest
+	}{
+		{
+			name:   "huge_array",
+			fields: []binaryFieldTest{{Name: "A", Type: binaryTypeTest{Kind: uint(reflect.Array), Len: 1 << 40, Elem: byteType}}},
+		},
+		{
+			name: "huge_nested_array",
+			fields: []binaryFieldTest{{Name: "A", Type: binaryTypeTest{
+				Kind: uint(reflect.Array),
+				Len:  1 << 20,
+				Elem: &binaryTypeTest{Kind: uint(reflect.Array), Len: 1 << 20, Elem: byteType},
+			}}},
+		},
+		{
+			name: "invalid_nested_name",
+			fields: []binaryFieldTest{{Name: "S", Type: binaryTypeTest{
+				Kind:   uint(reflect.Struct),
+				Fields: []binaryFieldTest{{Name: "not exported", Type: *byteType}},
+			}}},
+		},
+		{
+			name: "duplicate_embedded",
+			fields: []binaryFieldTest{
+				{Name: "Base", Anonymous: true, Type: embedded},
+				{Name: "Base", Anonymous: true, Type: embedded},
+			},
+		},
+		{
+			name: "embedded_pointer_to_pointer",
+			fields: []binaryFieldTest{{Name: "Base", Anonymous: true, Type: binaryTypeTest{
+				Kind: uint(reflect.Ptr),
+				Elem: &binaryTypeTest{Kind: uint(reflect.Ptr), Elem: &embedded},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := dynamicstruct.DecodeBinary(craftBinary(t, tt.fields...))
+			if !errors.Is(err, dynamicstruct.ErrInvalidSchema) {
+				t.Errorf("DecodeBinary() error = %v, want %v", err, dynamicstruct.ErrInvalidSchema)
+			}
+		})
+	}
+}
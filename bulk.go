@@ -0,0 +1,38 @@
+package dynamicstruct
+
+import "reflect"
+
+// NewBulk allocates n instances of the built type in one backing array and
+// returns them as a slice, []T for the built type T, along with a function
+// returning a pointer to the i-th instance. Defaults are applied to every
+// instance, as NewInstance does. Like make, NewBulk panics when n is
+// negative.
+//
+// The instances share one allocation, so the whole array stays in memory as
+// long as a pointer to any of them is held.
+func (b *Builder) NewBulk(n int) (any, func(i int) any, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, nil, ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+	defaults := b.defaultValues()
+
+	slice := reflect.MakeSlice(reflect.SliceOf(structType), n, n)
+
+	if len(defaults.fields) > 0 {
+		for i := 0; i < n; i++ {
+			defaults.apply(slice.Index(i))
+		}
+	}
+
+	index := func(i int) any {
+		return slice.Index(i).Addr().Interface()
+	}
+
+	return slice.Interface(), index, nil
+}
@@ -0,0 +1,59 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestNewBulk(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddFieldWithOptions("Tags", []string{}, dynamicstruct.Tags(`json:"tags"`), dynamicstruct.Default([]string{"new"}))
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	slice, index, err := builder.NewBulk(3)
+	if err != nil {
+		t.Fatalf("NewBulk() error = %v", err)
+	}
+
+	if got, want := reflect.TypeOf(slice), reflect.SliceOf(reflect.TypeOf(instance)); got != want {
+		t.Fatalf("NewBulk() slice type = %s, want %s", got, want)
+	}
+
+	for i, name := range []string{"a", "b", "c"} {
+		if err := json.Unmarshal([]byte(`{"name":"`+name+`"}`), index(i)); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+	}
+
+	// Defaults are copied, not shared between instances
+	reflect.ValueOf(index(0)).Elem().FieldByName("Tags").Index(0).SetString("changed")
+
+	data, _ := json.Marshal(slice)
+
+	want := `[{"name":"a","tags":["changed"]},{"name":"b","tags":["new"]},{"name":"c","tags":["new"]}]`
+	if string(data) != want {
+		t.Errorf("NewBulk() = %s, want %s", data, want)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		slice, _, err := builder.NewBulk(0)
+		if err != nil || reflect.ValueOf(slice).Len() != 0 {
+			t.Errorf("NewBulk(0) = %v, %v, want empty slice", slice, err)
+		}
+	})
+
+	t.Run("not_built", func(t *testing.T) {
+		if _, _, err := dynamicstruct.New().NewBulk(1); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("NewBulk() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const cborTagKey = "cbor"
+
+// parseCBORTag checks the keyasint option of a cbor tag, which requires the
+// tag name to be an integer key.
+func parseCBORTag(tag string) error {
+	name, options, _ := strings.Cut(tag, ",")
+
+	for _, option := range strings.Split(options, ",") {
+		if option != "keyasint" {
+			continue
+		}
+
+		if _, err := strconv.ParseInt(name, 10, 64); err != nil {
+			return fmt.Errorf("%w: cbor key %q must be an integer with keyasint", ErrInvalidTag, name)
+		}
+	}
+
+	return nil
+}
+
+// WithCBORTags adds a cbor tag to fields that declare a json tag but no cbor
+// tag, with the same value, so that instances encode to the same keys in CBOR
+// as in JSON.
+func WithCBORTags() Option {
+	return func(b *Builder) {
+		b.autoTags = append(b.autoTags, autoTag{key: cborTagKey, mirror: "json"})
+	}
+}
+
+// WithCBORIntKeys adds a `cbor:"N,keyasint"` tag to fields that don't declare
+// a cbor tag, numbering them in the order they are added after the highest
+// integer key already used. Instances then encode as maps keyed by integers,
+// as COSE and other compact CBOR protocols expect.
+func WithCBORIntKeys() Option {
+	return func(b *Builder) {
+		b.autoTags = append(b.autoTags, autoTag{key: cborTagKey, mapper: func(string) string {
+			return strconv.FormatInt(b.maxCBORIntKey()+1, 10) + ",keyasint"
+		}})
+	}
+}
+
+// maxCBORIntKey returns the highest integer key of the fields' cbor tags, or
+// 0 if no field has one.
+func (b *Builder) maxCBORIntKey() int64 {
+	var maxKey int64
+
+	for _, field := range b.fields {
+		tag, ok := field.Tag.Lookup(cborTagKey)
+		if !ok {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if !strings.Contains(","+options+",", ",keyasint,") {
+			continue
+		}
+
+		if key, err := strconv.ParseInt(name, 10, 64); err == nil && key > maxKey {
+			maxKey = key
+		}
+	}
+
+	return maxKey
+}
+
+func MarshalCBOR(instance any) ([]byte, error) {
+	return cbor.Marshal(instance)
+}
+
+func UnmarshalCBOR(data []byte, instancePtr any) error {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return ErrValueCannotBeNil
+	}
+
+	if err := cbor.Unmarshal(data, instancePtr); err != nil {
+		return err
+	}
+
+	return transformDecoded(valueReflect)
+}
@@ -0,0 +1,119 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestCBORRoundTrip(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithCBORTags())
+	_ = builder.AddField("Name", "", `json:"name"`, `transform:"trim"`)
+	_ = builder.AddField("Note", "", `json:"note"`, `cbor:"n"`)
+	_ = builder.AddField("Raw", "")
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tags := map[string]string{"Name": "name", "Note": "n", "Raw": ""}
+	for name, want := range tags {
+		field, _ := structType.FieldByName(name)
+		if got := field.Tag.Get("cbor"); got != want {
+			t.Errorf("field %s cbor tag = %q, want %q", name, got, want)
+		}
+	}
+
+	value := reflect.New(structType)
+	value.Elem().FieldByName("Name").SetString(" Alice ")
+	value.Elem().FieldByName("Note").SetString("hi")
+
+	data, err := dynamicstruct.MarshalCBOR(value.Interface())
+	if err != nil {
+		t.Fatalf("MarshalCBOR() error = %v", err)
+	}
+
+	var keys map[string]any
+	if err := cbor.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("cbor.Unmarshal() error = %v", err)
+	}
+
+	want := map[string]any{"name": " Alice ", "n": "hi", "Raw": ""}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("MarshalCBOR() = %v, want %v", keys, want)
+	}
+
+	decoded := reflect.New(structType)
+	if err := dynamicstruct.UnmarshalCBOR(data, decoded.Interface()); err != nil {
+		t.Fatalf("UnmarshalCBOR() error = %v", err)
+	}
+
+	if got := decoded.Elem().FieldByName("Name").String(); got != "Alice" {
+		t.Errorf("UnmarshalCBOR() Name = %q, want %q", got, "Alice")
+	}
+
+	t.Run("not_a_pointer", func(t *testing.T) {
+		err := dynamicstruct.UnmarshalCBOR(data, decoded.Elem().Interface())
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("UnmarshalCBOR() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
+
+func TestCBORIntKeys(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithCBORIntKeys())
+	_ = builder.AddField("Alg", 0)
+	_ = builder.AddField("Kid", []byte(nil), `cbor:"4,keyasint"`)
+	_ = builder.AddField("IV", []byte(nil))
+	_ = builder.AddField("Label", "", `cbor:"label"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tags := map[string]string{"Alg": "1,keyasint", "Kid": "4,keyasint", "IV": "5,keyasint", "Label": "label"}
+	for name, want := range tags {
+		field, _ := structType.FieldByName(name)
+		if got := field.Tag.Get("cbor"); got != want {
+			t.Errorf("field %s cbor tag = %q, want %q", name, got, want)
+		}
+	}
+
+	value := reflect.New(structType)
+	value.Elem().FieldByName("Alg").SetInt(-7)
+	value.Elem().FieldByName("IV").SetBytes([]byte{1})
+
+	data, err := dynamicstruct.MarshalCBOR(value.Interface())
+	if err != nil {
+		t.Fatalf("MarshalCBOR() error = %v", err)
+	}
+
+	var keys map[any]any
+	if err := cbor.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("cbor.Unmarshal() error = %v", err)
+	}
+
+	if got := keys[uint64(1)]; got != int64(-7) {
+		t.Errorf("MarshalCBOR() key 1 = %v, want -7", got)
+	}
+
+	if got, ok := keys[uint64(5)].([]byte); !ok || len(got) != 1 {
+		t.Errorf("MarshalCBOR() key 5 = %v, want [1]", keys[uint64(5)])
+	}
+
+	t.Run("invalid_key", func(t *testing.T) {
+		err := dynamicstruct.New().AddField("Alg", 0, `cbor:"alg,keyasint"`)
+		if !errors.Is(err, dynamicstruct.ErrInvalidTag) {
+			t.Errorf("AddField() error = %v, want %v", err, dynamicstruct.ErrInvalidTag)
+		}
+	})
+}
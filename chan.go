@@ -0,0 +1,34 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AddChanField adds a channel field of element type elem and direction dir:
+// reflect.RecvDir for <-chan T, reflect.SendDir for chan<- T, and
+// reflect.BothDir for chan T. A sample channel passed to AddField can only
+// express the last.
+func (b *Builder) AddChanField(name string, elem any, dir reflect.ChanDir, tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	elemType := reflect.TypeOf(elem)
+	if elemType == nil {
+		return fieldError("add", name, fmt.Errorf("%w: element of channel field", ErrValueCannotBeNil))
+	}
+
+	switch dir {
+	case reflect.RecvDir, reflect.SendDir, reflect.BothDir:
+	default:
+		return fieldError("add", name, fmt.Errorf("%w: channel direction %d", ErrIncompatibleTypes, dir))
+	}
+
+	// reflect.ChanOf panics on elements of 64KiB and more, which Go rejects
+	// at compile time
+	if elemType.Size() >= 1<<16 {
+		return fieldError("add", name, fmt.Errorf("%w: channel element %s is too large", ErrIncompatibleTypes, elemType))
+	}
+
+	return b.addField(name, reflect.ChanOf(dir, elemType), tags)
+}
@@ -0,0 +1,75 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestAddChanField(t *testing.T) {
+	tests := []struct {
+		name     string
+		elem     any
+		dir      reflect.ChanDir
+		wantType reflect.Type
+		wantErr  error
+	}{
+		{name: "receive", elem: 0, dir: reflect.RecvDir, wantType: reflect.TypeOf((<-chan int)(nil))},
+		{name: "send", elem: "", dir: reflect.SendDir, wantType: reflect.TypeOf((chan<- string)(nil))},
+		{name: "both", elem: PersonTest{}, dir: reflect.BothDir, wantType: reflect.TypeOf((chan PersonTest)(nil))},
+		{name: "invalid_direction", elem: 0, dir: 0, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "large_element", elem: [1 << 16]byte{}, dir: reflect.BothDir, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "nil_elem", elem: nil, dir: reflect.BothDir, wantErr: dynamicstruct.ErrValueCannotBeNil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+
+			err := builder.AddChanField("Events", tt.elem, tt.dir, `json:"-"`)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddChanField() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			field, _ := reflect.TypeOf(instance).FieldByName("Events")
+			if field.Type != tt.wantType {
+				t.Errorf("field type = %s, want %s", field.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestAddChanFieldValue(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddChanField("Done", struct{}{}, reflect.RecvDir)
+	_, _ = builder.Build()
+
+	done := make(chan struct{})
+
+	// A bidirectional channel is assignable to a receive-only field
+	if err := builder.SetFieldValue("Done", done); err != nil {
+		t.Fatalf("SetFieldValue() error = %v", err)
+	}
+
+	close(done)
+
+	var got <-chan struct{}
+	if err := builder.GetFieldValue("Done", &got); err != nil {
+		t.Fatalf("GetFieldValue() error = %v", err)
+	}
+
+	if _, ok := <-got; ok {
+		t.Error("received from closed channel")
+	}
+}
@@ -0,0 +1,61 @@
+package dynamicstruct
+
+import "reflect"
+
+// Clone returns an unbuilt builder with the same definition as b: its fields,
+// tags, options, metadata and automatic tags. Nested builders are cloned as
+// well, so the clone can be edited and built without affecting b. A builder
+// nested in several fields, or in itself through a cycle, is cloned once, so
+// the clone nests its copy the same way.
+func (b *Builder) Clone() *Builder {
+	return b.clone(map[*Builder]*Builder{})
+}
+
+// clone clones b, reusing the clones already made of the builders in clones.
+func (b *Builder) clone(clones map[*Builder]*Builder) *Builder {
+	if clone, ok := clones[b]; ok {
+		return clone
+	}
+
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	state := b.saveState()
+
+	clone := &Builder{
+		fields:          state.fields,
+		order:           state.order,
+		meta:            state.meta,
+		anonymousFields: state.anonymousFields,
+		selfFields:      state.selfFields,
+		unknownField:    state.unknownField,
+		aliases:         state.aliases,
+		positions:       state.positions,
+		group:           b.group,
+		autoTags:        append([]autoTag(nil), b.autoTags...),
+		interfaces:      append([]reflect.Type(nil), b.interfaces...),
+		stringer:        b.stringer,
+		jsonMarshaler:   b.jsonMarshaler,
+		caseInsensitive: b.caseInsensitive,
+		allowRebuild:    b.allowRebuild,
+		keepValues:      b.keepValues,
+		optimizeLayout:  b.optimizeLayout,
+		coerce:          b.coerce,
+	}
+
+	clones[b] = clone
+
+	if clone.fields == nil {
+		clone.fields = make(map[string]reflect.StructField)
+	}
+
+	for name, child := range state.nested {
+		if clone.nested == nil {
+			clone.nested = make(map[string]*Builder, len(state.nested))
+		}
+
+		clone.nested[name] = child.clone(clones)
+	}
+
+	return clone
+}
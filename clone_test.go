@@ -0,0 +1,107 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestClone(t *testing.T) {
+	address := dynamicstruct.New()
+	_ = address.AddField("City", "")
+
+	builder := dynamicstruct.New(dynamicstruct.WithAutoTags(dynamicstruct.SnakeCase, "json"))
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddFieldWithOptions("Port", 0, dynamicstruct.Default(8080))
+	_ = builder.AddField("Address", address)
+
+	clone := builder.Clone()
+	_ = clone.AddField("UserName", "")
+	_ = address.AddField("Zip", "")
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instance, err := clone.Build()
+	if err != nil {
+		t.Fatalf("clone Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Email", ""},
+		{"Port", `json:"port"`},
+		{"UserName", `json:"user_name"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.name)
+			if !ok {
+				t.Fatalf("clone has no field %s", tt.name)
+			}
+
+			if string(field.Tag) != tt.want {
+				t.Errorf("tag = %q, want %q", field.Tag, tt.want)
+			}
+		})
+	}
+
+	if got, _ := clone.GetField("Port"); got != 8080 {
+		t.Errorf("clone GetField() Port = %v, want 8080", got)
+	}
+
+	addressField, _ := structType.FieldByName("Address")
+	if _, ok := addressField.Type.FieldByName("Zip"); ok {
+		t.Error("clone saw a field added to the original nested builder")
+	}
+
+	if _, err := builder.GetField("UserName"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+		t.Errorf("GetField() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+	}
+}
+
+func TestCloneSharedAndCyclic(t *testing.T) {
+	address := dynamicstruct.New()
+	_ = address.AddField("City", "")
+
+	customer := dynamicstruct.New()
+	order := dynamicstruct.New()
+	_ = customer.AddField("Billing", address)
+	_ = customer.AddField("Shipping", address)
+	_ = customer.AddField("Order", order)
+	_ = order.AddField("Customer", customer)
+
+	clone := customer.Clone()
+
+	if _, err := clone.Build(); !errors.Is(err, dynamicstruct.ErrCircularReference) {
+		t.Fatalf("clone Build() error = %v, want %v", err, dynamicstruct.ErrCircularReference)
+	}
+
+	// Breaking the cycle in the clone leaves the original cyclic
+	_ = clone.RemoveField("Order")
+
+	instance, err := clone.Build()
+	if err != nil {
+		t.Fatalf("clone Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+	billing, _ := structType.FieldByName("Billing")
+	shipping, _ := structType.FieldByName("Shipping")
+
+	if billing.Type != shipping.Type {
+		t.Errorf("Billing type = %v, Shipping type = %v, want the same type", billing.Type, shipping.Type)
+	}
+
+	if _, err := customer.Build(); !errors.Is(err, dynamicstruct.ErrCircularReference) {
+		t.Errorf("Build() error = %v, want %v", err, dynamicstruct.ErrCircularReference)
+	}
+}
@@ -0,0 +1,207 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// TimeLayouts are the layouts strings are parsed with into time.Time fields,
+// tried in order, by SetFieldValue, Loader, DecodeForm and DecodeQuery. The
+// defaults are RFC 3339, with or without fractional seconds, and the formats
+// of HTML datetime-local and date inputs. Strings of digits are read as Unix
+// times in seconds whatever the layouts. Change TimeLayouts before decoding
+// starts, as it isn't guarded against concurrent use.
+var TimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// parseTime parses raw with TimeLayouts, or as Unix seconds.
+func parseTime(raw string) (time.Time, error) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+
+	if sec, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(sec, 0).UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("can't parse %q as a time", raw)
+}
+
+// convertTime converts value to fieldType when fieldType is time.Time,
+// time.Duration or a pointer to either: strings are parsed as times or
+// durations, integers are Unix seconds for times and nanoseconds for
+// durations, and floats are fractional Unix seconds for times. It reports
+// false for other types and values.
+func convertTime(fieldType reflect.Type, value reflect.Value) (reflect.Value, bool, error) {
+	if fieldType.Kind() == reflect.Ptr {
+		converted, ok, err := convertTime(fieldType.Elem(), value)
+		if !ok || err != nil {
+			return reflect.Value{}, ok, err
+		}
+
+		ptr := reflect.New(fieldType.Elem())
+		ptr.Elem().Set(converted)
+
+		return ptr, true, nil
+	}
+
+	switch fieldType {
+	case timeType:
+		t, ok, err := timeOf(value)
+
+		return reflect.ValueOf(t), ok, err
+	case durationType:
+		d, ok, err := durationOf(value)
+
+		return reflect.ValueOf(d), ok, err
+	default:
+		return reflect.Value{}, false, nil
+	}
+}
+
+func timeOf(value reflect.Value) (time.Time, bool, error) {
+	switch value.Kind() {
+	case reflect.String:
+		t, err := parseTime(value.String())
+
+		return t, true, err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Unix(value.Int(), 0).UTC(), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return time.Time{}, true, fmt.Errorf("unix time %d out of range", value.Uint())
+		}
+
+		return time.Unix(int64(value.Uint()), 0).UTC(), true, nil
+	case reflect.Float32, reflect.Float64:
+		sec, frac := math.Modf(value.Float())
+		if math.IsNaN(sec) || math.IsInf(sec, 0) || sec > math.MaxInt64 || sec < math.MinInt64 {
+			return time.Time{}, true, fmt.Errorf("unix time %v out of range", value.Float())
+		}
+
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), true, nil
+	default:
+		return time.Time{}, false, nil
+	}
+}
+
+func durationOf(value reflect.Value) (time.Duration, bool, error) {
+	switch value.Kind() {
+	case reflect.String:
+		d, err := time.ParseDuration(value.String())
+
+		return d, true, err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(value.Int()), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return 0, true, fmt.Errorf("duration %d out of range", value.Uint())
+		}
+
+		return time.Duration(value.Uint()), true, nil
+	default:
+		return 0, false, nil
+	}
+}
+
+// convertNumber converts value to fieldType when both are numeric, or
+// fieldType is a pointer to a number, failing instead of overflowing or
+// dropping the fraction of a float converted to an integer. It reports false
+// for other types.
+func convertNumber(fieldType reflect.Type, value reflect.Value) (reflect.Value, bool, error) {
+	if fieldType.Kind() == reflect.Ptr {
+		converted, ok, err := convertNumber(fieldType.Elem(), value)
+		if !ok || err != nil {
+			return reflect.Value{}, ok, err
+		}
+
+		ptr := reflect.New(fieldType.Elem())
+		ptr.Elem().Set(converted)
+
+		return ptr, true, nil
+	}
+
+	if !isNumberKind(value.Kind()) || !isNumberKind(fieldType.Kind()) {
+		return reflect.Value{}, false, nil
+	}
+
+	converted := reflect.New(fieldType).Elem()
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := numberInt(value)
+		if err == nil && converted.OverflowInt(i) {
+			err = fmt.Errorf("%d overflows %s", i, fieldType)
+		}
+
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		converted.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := numberUint(value)
+		if err == nil && converted.OverflowUint(u) {
+			err = fmt.Errorf("%d overflows %s", u, fieldType)
+		}
+
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		converted.SetUint(u)
+	default:
+		f := value.Convert(reflect.TypeOf(float64(0))).Float()
+		if converted.OverflowFloat(f) {
+			return reflect.Value{}, true, fmt.Errorf("%v overflows %s", f, fieldType)
+		}
+
+		converted.SetFloat(f)
+	}
+
+	return converted, true, nil
+}
+
+func numberInt(value reflect.Value) (int64, error) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%v isn't an integer", f)
+		}
+
+		return int64(f), nil
+	default:
+		if value.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", value.Uint())
+		}
+
+		return int64(value.Uint()), nil
+	}
+}
+
+func numberUint(value reflect.Value) (uint64, error) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 {
+			return 0, fmt.Errorf("%d is negative", value.Int())
+		}
+
+		return uint64(value.Int()), nil
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("%v isn't an unsigned integer", f)
+		}
+
+		return uint64(f), nil
+	default:
+		return value.Uint(), nil
+	}
+}
@@ -0,0 +1,155 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestSetFieldValueTime(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("At", time.Time{})
+	_ = builder.AddOptionalField("Until", time.Time{})
+	_ = builder.AddField("Timeout", time.Duration(0))
+	_, _ = builder.Build()
+
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		field   string
+		value   any
+		want    any
+		wantErr error
+	}{
+		{name: "rfc3339", field: "At", value: "2024-03-01T12:30:00Z", want: at},
+		{name: "date", field: "At", value: "2024-03-01", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "unix_seconds", field: "At", value: at.Unix(), want: at},
+		{name: "unix_string", field: "At", value: "1709296200", want: at},
+		{name: "unix_float", field: "At", value: float64(at.Unix()) + 0.5, want: at.Add(500 * time.Millisecond)},
+		{name: "time", field: "At", value: at, want: at},
+		{name: "optional", field: "Until", value: "2024-03-01T12:30:00Z", want: &at},
+		{name: "duration_string", field: "Timeout", value: "5m", want: 5 * time.Minute},
+		{name: "duration_nanoseconds", field: "Timeout", value: int64(time.Second), want: time.Second},
+		{name: "invalid_time", field: "At", value: "yesterday", wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "invalid_duration", field: "Timeout", value: "5 minutes", wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "unrelated_type", field: "At", value: true, wantErr: dynamicstruct.ErrIncompatibleTypes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := builder.SetFieldValue(tt.field, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, _ := builder.GetField(tt.field)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeLayouts(t *testing.T) {
+	defer func(layouts []string) { dynamicstruct.TimeLayouts = layouts }(dynamicstruct.TimeLayouts)
+
+	dynamicstruct.TimeLayouts = []string{"02/01/2006"}
+
+	instancePtr := newConfigInstance(t)
+	loader, _ := dynamicstruct.NewLoader(instancePtr)
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("At", time.Time{})
+	_, _ = builder.Build()
+
+	if err := builder.SetFieldValue("At", "01/03/2024"); err != nil {
+		t.Fatalf("SetFieldValue() error = %v", err)
+	}
+
+	got, _ := builder.GetField("At")
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); got != want {
+		t.Errorf("GetField() = %v, want %v", got, want)
+	}
+
+	if err := builder.SetFieldValue("At", "2024-03-01"); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("SetFieldValue() with a default layout error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	// Loader defaults are converted too
+	if err := loader.Defaults(map[string]any{"Timeout": "90s"}); err != nil {
+		t.Fatalf("Defaults() error = %v", err)
+	}
+
+	timeout := reflect.ValueOf(instancePtr).Elem().FieldByName("Timeout").Interface()
+	if timeout != 90*time.Second {
+		t.Errorf("Timeout = %v, want %v", timeout, 90*time.Second)
+	}
+}
+
+func TestWithCoercion(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithCoercion())
+	_ = builder.AddField("Count", 0)
+	_ = builder.AddField("Small", int8(0))
+	_ = builder.AddField("Size", uint(0))
+	_ = builder.AddField("Ratio", float32(0))
+	_ = builder.AddOptionalField("Limit", int64(0))
+	_ = builder.AddField("Name", "")
+	_, _ = builder.Build()
+
+	limit := int64(10)
+
+	tests := []struct {
+		name    string
+		field   string
+		value   any
+		want    any
+		wantErr error
+	}{
+		{name: "float_to_int", field: "Count", value: float64(42), want: 42},
+		{name: "int64_to_int", field: "Count", value: int64(7), want: 7},
+		{name: "int_to_uint", field: "Size", value: 3, want: uint(3)},
+		{name: "int_to_float", field: "Ratio", value: 2, want: float32(2)},
+		{name: "optional", field: "Limit", value: float64(10), want: &limit},
+		{name: "fraction", field: "Count", value: 1.5, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "overflow", field: "Small", value: 300, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "negative_uint", field: "Size", value: -1, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "float32_overflow", field: "Ratio", value: 1e300, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "number_to_string", field: "Name", value: 1, wantErr: dynamicstruct.ErrIncompatibleTypes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := builder.SetFieldValue(tt.field, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, _ := builder.GetField(tt.field)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("without_coercion", func(t *testing.T) {
+		strict := dynamicstruct.New()
+		_ = strict.AddField("Count", 0)
+		_, _ = strict.Build()
+
+		if err := strict.SetFieldValue("Count", float64(42)); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("SetFieldValue() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	})
+}
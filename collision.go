@@ -0,0 +1,124 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CollisionPolicy decides the field name for a source key whose Go name, name,
+// is already taken by another field, as when "user-id" and "user_id" both map
+// to UserId. taken reports whether a candidate name is in use. The source key
+// is always kept in the field's tag, so only the Go name changes.
+type CollisionPolicy func(name, key string, taken func(name string) bool) (string, error)
+
+// CollisionError rejects colliding keys with ErrFieldAlreadyExists. It is the
+// default policy of the importers.
+func CollisionError(name, _ string, _ func(string) bool) (string, error) {
+	return "", fmt.Errorf("%w: %s", ErrFieldAlreadyExists, name)
+}
+
+// CollisionSuffix numbers colliding names: UserId, UserId2, UserId3.
+func CollisionSuffix(name, _ string, taken func(string) bool) (string, error) {
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if !taken(candidate) {
+			return candidate, nil
+		}
+	}
+}
+
+// CollisionEscape derives the name from the key with its separators spelled
+// out instead of dropped, so "user-id" becomes User_2Did next to the UserId of
+// "user_id". Names that are still taken are numbered as by CollisionSuffix.
+func CollisionEscape(name, key string, taken func(string) bool) (string, error) {
+	var sb strings.Builder
+
+	for i, r := range key {
+		switch {
+		case i == 0 && unicode.IsLetter(r):
+			sb.WriteRune(unicode.ToUpper(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			sb.WriteRune(r)
+		default:
+			fmt.Fprintf(&sb, "_%X", r)
+		}
+	}
+
+	escaped := sb.String()
+
+	// Identifiers must start with an upper case letter to be exported
+	if escaped == "" || !unicode.IsUpper([]rune(escaped)[0]) {
+		escaped = "X" + escaped
+	}
+
+	if escaped != name && !taken(escaped) {
+		return escaped, nil
+	}
+
+	return CollisionSuffix(escaped, key, taken)
+}
+
+type importConfig struct {
+	collisions CollisionPolicy
+	inference  InferenceOptions
+}
+
+// ImportOption configures the importers that create builders from samples and
+// schemas.
+type ImportOption func(*importConfig)
+
+// WithCollisionPolicy sets how importers name fields whose keys map to the
+// same Go name.
+func WithCollisionPolicy(policy CollisionPolicy) ImportOption {
+	return func(c *importConfig) {
+		c.collisions = policy
+	}
+}
+
+func newImportConfig(opts []ImportOption) *importConfig {
+	cfg := &importConfig{collisions: CollisionError}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// ImportedFieldName returns the name for a field of b imported from the source
+// key: name, or the name the collision policy of opts picks when b already has
+// a field called name. Importers outside this package use it, with
+// ExportedName, to name fields as the importers of this package do.
+func (b *Builder) ImportedFieldName(name, key string, opts ...ImportOption) (string, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	if !b.hasField(name) {
+		return name, nil
+	}
+
+	return newImportConfig(opts).collisions(name, key, b.hasField)
+}
+
+// addImportedField adds a field for the source key, named after the key and
+// renamed by the collision policy when that name is taken.
+func (b *Builder) addImportedField(key string, fieldType reflect.Type, tag string, cfg *importConfig) error {
+	return b.addImportedFieldAs(ExportedName(key), key, fieldType, tag, cfg)
+}
+
+// addImportedFieldAs is addImportedField for a field named name instead of
+// after the key.
+func (b *Builder) addImportedFieldAs(name, key string, fieldType reflect.Type, tag string, cfg *importConfig) error {
+	if b.hasField(name) {
+		var err error
+
+		name, err = cfg.collisions(name, key, b.hasField)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.addField(name, fieldType, []string{tag})
+}
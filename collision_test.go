@@ -0,0 +1,238 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestCollisionPolicy(t *testing.T) {
+	sample := []byte(`{"user-id": 1, "user_id": 2, "userId": 3}`)
+
+	tests := []struct {
+		name    string
+		opts    []dynamicstruct.ImportOption
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "default_error",
+			wantErr: dynamicstruct.ErrFieldAlreadyExists,
+		},
+		{
+			name:    "explicit_error",
+			opts:    []dynamicstruct.ImportOption{dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionError)},
+			wantErr: dynamicstruct.ErrFieldAlreadyExists,
+		},
+		{
+			name: "suffix",
+			opts: []dynamicstruct.ImportOption{dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionSuffix)},
+			want: map[string]string{"UserId": "user-id", "UserId2": "userId", "UserId3": "user_id"},
+		},
+		{
+			name: "escape",
+			opts: []dynamicstruct.ImportOption{dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionEscape)},
+			want: map[string]string{"UserId": "user-id", "UserId2": "userId", "User_id": "user_id"},
+		},
+		{
+			name: "custom",
+			opts: []dynamicstruct.ImportOption{dynamicstruct.WithCollisionPolicy(
+				func(name, key string, taken func(string) bool) (string, error) {
+					return map[string]string{"userId": "UserIdCamel", "user_id": "UserIdSnake"}[key], nil
+				},
+			)},
+			want: map[string]string{"UserId": "user-id", "UserIdCamel": "userId", "UserIdSnake": "user_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder, err := dynamicstruct.FromJSONSample(sample, tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FromJSONSample() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("FromJSONSample() error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			structType := reflect.TypeOf(instance)
+			if structType.NumField() != len(tt.want) {
+				t.Errorf("FromJSONSample() fields = %d, want %d", structType.NumField(), len(tt.want))
+			}
+
+			for name, key := range tt.want {
+				field, ok := structType.FieldByName(name)
+				if !ok {
+					t.Errorf("field %s not found in %s", name, structType)
+
+					continue
+				}
+
+				if got := field.Tag.Get("json"); got != key {
+					t.Errorf("field %s json tag = %q, want %q", name, got, key)
+				}
+			}
+		})
+	}
+}
+
+func TestCollisionPolicyImporters(t *testing.T) {
+	suffix := dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionSuffix)
+
+	tests := []struct {
+		name  string
+		build func() (*dynamicstruct.Builder, error)
+		want  []string
+	}{
+		{
+			name: "yaml",
+			build: func() (*dynamicstruct.Builder, error) {
+				return dynamicstruct.FromYAMLSample([]byte("first-name: a\nfirst_name: b\n"), suffix)
+			},
+			want: []string{"FirstName", "FirstName2"},
+		},
+		{
+			name: "xml",
+			build: func() (*dynamicstruct.Builder, error) {
+				return dynamicstruct.FromXMLSample([]byte(`<user first-name="a"><first_name>b</first_name></user>`), suffix)
+			},
+			want: []string{"XMLName", "FirstName", "FirstName2"},
+		},
+		{
+			name: "nested_json",
+			build: func() (*dynamicstruct.Builder, error) {
+				return dynamicstruct.FromJSONSample([]byte(`{"user": {"a-b": 1, "a_b": 2}}`), suffix)
+			},
+			want: []string{"User"},
+		},
+		{
+			name: "openapi",
+			build: func() (*dynamicstruct.Builder, error) {
+				spec := []byte(`
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        user-id: {type: integer}
+        user_id: {type: string}
+`)
+
+				return dynamicstruct.FromOpenAPISchema(spec, "User", suffix)
+			},
+			want: []string{"UserId", "UserId2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder, err := tt.build()
+			if err != nil {
+				t.Fatalf("import error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			structType := reflect.TypeOf(instance)
+
+			var got []string
+			for i := 0; i < structType.NumField(); i++ {
+				got = append(got, structType.Field(i).Name)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type collisionStructTest struct {
+	id string
+	Id string //nolint:revive,stylecheck // collides with the mirror of id
+}
+
+func TestStructCollisionPolicy(t *testing.T) {
+	var skipped []dynamicstruct.SkippedField
+
+	builder, err := dynamicstruct.FromStruct(collisionStructTest{id: "a"}, dynamicstruct.WithExportedMirrors(),
+		dynamicstruct.WithSkipReport(func(f dynamicstruct.SkippedField) { skipped = append(skipped, f) }))
+	if err != nil {
+		t.Fatalf("FromStruct() error = %v", err)
+	}
+
+	if len(skipped) != 1 || skipped[0].Path != "Id" {
+		t.Errorf("FromStruct() skipped = %v, want Id", skipped)
+	}
+
+	builder, err = dynamicstruct.FromStruct(collisionStructTest{}, dynamicstruct.WithExportedMirrors(),
+		dynamicstruct.WithStructCollisionPolicy(dynamicstruct.CollisionSuffix))
+	if err != nil {
+		t.Fatalf("FromStruct() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if _, ok := reflect.TypeOf(instance).FieldByName("Id2"); !ok {
+		t.Errorf("FromStruct() = %T, want an Id2 field", instance)
+	}
+
+	_, err = dynamicstruct.FromStruct(collisionStructTest{}, dynamicstruct.WithExportedMirrors(),
+		dynamicstruct.WithStructCollisionPolicy(dynamicstruct.CollisionError))
+	if !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+		t.Errorf("FromStruct() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+	}
+}
+
+func TestImportedFieldName(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("UserId", 0)
+
+	tests := []struct {
+		name    string
+		key     string
+		opts    []dynamicstruct.ImportOption
+		want    string
+		wantErr error
+	}{
+		{name: "free", key: "email", want: "Email"},
+		{name: "taken", key: "user-id", wantErr: dynamicstruct.ErrFieldAlreadyExists},
+		{
+			name: "suffix",
+			key:  "user-id",
+			opts: []dynamicstruct.ImportOption{dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionSuffix)},
+			want: "UserId2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := builder.ImportedFieldName(dynamicstruct.ExportedName(tt.key), tt.key, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportedFieldName() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ImportedFieldName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package dynamicstruct
+
+import (
+	"strconv"
+	"strings"
+)
+
+// WithComment returns a description tag documenting a field, for use as one
+// of the tags of AddField:
+//
+//	builder.AddField("Email", "", `json:"email"`, dynamicstruct.WithComment("primary email address"))
+//
+// The description is emitted as a comment by GoString, as the property
+// description by ToJSONSchema and ToOpenAPISchema, and in the description
+// column by MarkdownDoc.
+func WithComment(text string) string {
+	return descriptionTagKey + ":" + strconv.Quote(text)
+}
+
+// writeComment writes the description of a field as line comments.
+func writeComment(w *strings.Builder, description string) {
+	for _, line := range strings.Split(description, "\n") {
+		w.WriteString("//")
+
+		if line != "" {
+			w.WriteString(" ")
+			w.WriteString(line)
+		}
+
+		w.WriteString("\n")
+	}
+}
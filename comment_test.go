@@ -0,0 +1,86 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newCommentBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	if err := builder.AddField("Email", "", `json:"email"`, dynamicstruct.WithComment("primary email address")); err != nil {
+		t.Fatalf("AddField() error = %v", err)
+	}
+
+	_ = builder.AddField("Notes", "", `json:"notes"`, dynamicstruct.WithComment("free-form notes,\nnot \"validated\""))
+	_ = builder.AddField("Age", 0, `json:"age"`)
+
+	return builder
+}
+
+func TestWithComment(t *testing.T) {
+	t.Run("tag", func(t *testing.T) {
+		if got := dynamicstruct.WithComment("primary email address"); got != `description:"primary email address"` {
+			t.Errorf("WithComment() = %s, want description tag", got)
+		}
+	})
+
+	t.Run("go_string", func(t *testing.T) {
+		src, err := newCommentBuilder(t).GoString("Contact", "models")
+		if err != nil {
+			t.Fatalf("GoString() error = %v", err)
+		}
+
+		for _, want := range []string{
+			"\t// primary email address\n\tEmail string",
+			"\t// free-form notes,\n\t// not \"validated\"\n\tNotes string",
+		} {
+			if !strings.Contains(src, want) {
+				t.Errorf("GoString() = %s, want it to contain %q", src, want)
+			}
+		}
+
+		if strings.Contains(src, "//\n\tAge") {
+			t.Errorf("GoString() = %s, want no comment for Age", src)
+		}
+	})
+
+	schemas := []struct {
+		name   string
+		export func(b *dynamicstruct.Builder) ([]byte, error)
+	}{
+		{"json_schema", func(b *dynamicstruct.Builder) ([]byte, error) { return b.ToJSONSchema() }},
+		{"openapi", func(b *dynamicstruct.Builder) ([]byte, error) { return b.ToOpenAPISchema() }},
+	}
+
+	for _, tt := range schemas {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.export(newCommentBuilder(t))
+			if err != nil {
+				t.Fatalf("export error = %v", err)
+			}
+
+			var doc struct {
+				Properties map[string]struct {
+					Description string `json:"description"`
+				} `json:"properties"`
+			}
+
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			if got := doc.Properties["email"].Description; got != "primary email address" {
+				t.Errorf("email description = %q, want %q", got, "primary email address")
+			}
+
+			if got := doc.Properties["age"].Description; got != "" {
+				t.Errorf("age description = %q, want empty", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package dynamicstruct
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// cursorPayload is the signed content of a cursor: the fields it carries and
+// the fingerprint of the struct type they were read from.
+type cursorPayload struct {
+	Type   string `json:"t"`
+	Fields any    `json:"f"`
+}
+
+// EncodeCursor serializes the named fields of instance into an opaque,
+// URL-safe token signed with HMAC-SHA256 using key. The token is bound to the
+// struct type of instance.
+func EncodeCursor(instance any, key []byte, fields ...string) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("%w: empty key", ErrInvalidCursor)
+	}
+
+	value, err := structValue(instance)
+	if err != nil {
+		return "", err
+	}
+
+	values := make(map[string]any, len(fields))
+
+	for _, name := range fields {
+		// Fields promoted through a nil embedded pointer have no value
+		field, ok := fieldByName(value, name)
+		if !ok || !field.CanInterface() {
+			return "", fmt.Errorf("%w: %s", missingField(value.Type(), name), name)
+		}
+
+		values[name] = field.Interface()
+	}
+
+	payload, err := json.Marshal(cursorPayload{Type: cursorType(value.Type()), Fields: values})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signCursor(payload, key)), nil
+}
+
+// DecodeCursor verifies a token created by EncodeCursor and sets the fields it
+// carries on the struct instancePtr points to. A token encoded from another
+// struct type returns ErrInvalidCursor. The instance is left unchanged when a
+// field can't be set.
+func DecodeCursor(token string, key []byte, instancePtr any) error {
+	if len(key) == 0 {
+		return fmt.Errorf("%w: empty key", ErrInvalidCursor)
+	}
+
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return fmt.Errorf("%w: malformed payload", ErrInvalidCursor)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidCursor)
+	}
+
+	if !hmac.Equal(signature, signCursor(payload, key)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+	}
+
+	var values map[string]json.RawMessage
+
+	decoded := cursorPayload{Fields: &values}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return fmt.Errorf("%w: malformed payload", ErrInvalidCursor)
+	}
+
+	if decoded.Type != cursorType(value.Type()) {
+		return fmt.Errorf("%w: encoded for another type than %s", ErrInvalidCursor, value.Type())
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	// Fields are set on a copy, so that the instance is only written once
+	// all of them are
+	target := reflect.New(value.Type()).Elem()
+	target.Set(deepCopy(value))
+
+	for _, name := range names {
+		// Nil embedded pointers on the way to the field are allocated
+		field, ok := fieldByNameAlloc(target, name)
+		if !ok || !field.CanSet() {
+			return fmt.Errorf("%w: %s", missingField(value.Type(), name), name)
+		}
+
+		if err := json.Unmarshal(values[name], field.Addr().Interface()); err != nil {
+			return fmt.Errorf("%w: field %s: %s", ErrIncompatibleTypes, name, err.Error())
+		}
+	}
+
+	value.Set(target)
+
+	return nil
+}
+
+// cursorType fingerprints the struct type a cursor is encoded from, by its
+// package and definition, which for a built struct lists its fields and tags.
+func cursorType(t reflect.Type) string {
+	sum := sha256.Sum256([]byte(t.PkgPath() + " " + t.String()))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func signCursor(payload, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,248 @@
+package dynamicstruct_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newCursorInstance(t *testing.T) reflect.Type {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0))
+	_ = builder.AddField("CreatedAt", time.Time{})
+	_ = builder.AddField("Title", "")
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	key := []byte("secret")
+	structType := newCursorInstance(t)
+
+	source := reflect.New(structType)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	source.Elem().FieldByName("ID").SetInt(42)
+	source.Elem().FieldByName("CreatedAt").Set(reflect.ValueOf(createdAt))
+	source.Elem().FieldByName("Title").SetString("not part of the cursor")
+
+	token, err := dynamicstruct.EncodeCursor(source.Interface(), key, "CreatedAt", "ID")
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("EncodeCursor() = %q, want URL-safe token", token)
+	}
+
+	target := reflect.New(structType)
+	if err = dynamicstruct.DecodeCursor(token, key, target.Interface()); err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	if got := target.Elem().FieldByName("ID").Int(); got != 42 {
+		t.Errorf("ID = %d, want 42", got)
+	}
+
+	if got := target.Elem().FieldByName("CreatedAt").Interface().(time.Time); !got.Equal(createdAt) {
+		t.Errorf("CreatedAt = %v, want %v", got, createdAt)
+	}
+
+	if got := target.Elem().FieldByName("Title").String(); got != "" {
+		t.Errorf("Title = %q, want empty", got)
+	}
+}
+
+func TestCursorErrors(t *testing.T) {
+	key := []byte("secret")
+	structType := newCursorInstance(t)
+	instance := reflect.New(structType).Elem().Interface()
+
+	token, err := dynamicstruct.EncodeCursor(instance, key, "ID")
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	t.Run("unknown_field", func(t *testing.T) {
+		_, err := dynamicstruct.EncodeCursor(instance, key, "Missing")
+		if !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("EncodeCursor() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+	})
+
+	t.Run("empty_key", func(t *testing.T) {
+		_, err := dynamicstruct.EncodeCursor(instance, nil, "ID")
+		if !errors.Is(err, dynamicstruct.ErrInvalidCursor) {
+			t.Errorf("EncodeCursor() error = %v, want %v", err, dynamicstruct.ErrInvalidCursor)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		token   string
+		key     []byte
+		wantErr error
+	}{
+		{name: "wrong_key", token: token, key: []byte("other"), wantErr: dynamicstruct.ErrInvalidCursor},
+		{name: "tampered_payload", token: "x" + token, key: key, wantErr: dynamicstruct.ErrInvalidCursor},
+		{name: "missing_signature", token: strings.Split(token, ".")[0], key: key, wantErr: dynamicstruct.ErrInvalidCursor},
+		{name: "garbage", token: "!!.!!", key: key, wantErr: dynamicstruct.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.DecodeCursor(tt.token, tt.key, reflect.New(structType).Interface())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeCursor() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("decode_into_non_pointer", func(t *testing.T) {
+		err := dynamicstruct.DecodeCursor(token, key, instance)
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("DecodeCursor() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
+
+func TestCursorEmbeddedPointer(t *testing.T) {
+	key := []byte("secret")
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0))
+	_ = builder.AddAnonymousField(&ContactTest{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	t.Run("nil_embedded_pointer", func(t *testing.T) {
+		_, err := dynamicstruct.EncodeCursor(reflect.New(structType).Interface(), key, "Email")
+		if !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("EncodeCursor() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+	})
+
+	source := reflect.New(structType)
+	source.Elem().FieldByName("ContactTest").Set(reflect.ValueOf(&ContactTest{Email: "ops@example.com"}))
+
+	token, err := dynamicstruct.EncodeCursor(source.Interface(), key, "Email")
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	// The embedded pointer of the target is allocated
+	target := reflect.New(structType)
+	if err := dynamicstruct.DecodeCursor(token, key, target.Interface()); err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	if got := target.Elem().FieldByName("Email").String(); got != "ops@example.com" {
+		t.Errorf("Email = %q, want ops@example.com", got)
+	}
+}
+
+// resignCursor returns token with the fields of its payload replaced by fields,
+// signed with key like EncodeCursor signs tokens.
+func resignCursor(t *testing.T, token string, key []byte, fields map[string]any) string {
+	t.Helper()
+
+	encodedPayload, _, _ := strings.Cut(token, ".")
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	decoded["f"] = fields
+
+	if payload, err = json.Marshal(decoded); err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestCursorTypeAndAtomicity(t *testing.T) {
+	key := []byte("secret")
+	structType := newCursorInstance(t)
+
+	source := reflect.New(structType)
+	source.Elem().FieldByName("ID").SetInt(42)
+
+	token, err := dynamicstruct.EncodeCursor(source.Interface(), key, "ID")
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	other := dynamicstruct.New()
+	_ = other.AddField("ID", int64(0))
+
+	otherInstance, err := other.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		targetType reflect.Type
+		wantErr    error
+	}{
+		{name: "other_type", token: token, targetType: reflect.TypeOf(otherInstance), wantErr: dynamicstruct.ErrInvalidCursor},
+		{
+			name:       "invalid_second_field",
+			token:      resignCursor(t, token, key, map[string]any{"ID": 7, "Title": 1}),
+			targetType: structType,
+			wantErr:    dynamicstruct.ErrIncompatibleTypes,
+		},
+		{
+			name:       "unknown_second_field",
+			token:      resignCursor(t, token, key, map[string]any{"ID": 7, "Missing": 1}),
+			targetType: structType,
+			wantErr:    dynamicstruct.ErrFieldNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := reflect.New(tt.targetType)
+			target.Elem().FieldByName("ID").SetInt(1)
+
+			err := dynamicstruct.DecodeCursor(tt.token, key, target.Interface())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeCursor() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if id := target.Elem().FieldByName("ID").Int(); id != 1 {
+				t.Errorf("ID = %d, want 1 unchanged", id)
+			}
+		})
+	}
+}
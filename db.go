@@ -0,0 +1,172 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	dbTagKey   = "db"
+	gormTagKey = "gorm"
+)
+
+// WithDBTags names the database column of every field with mapper, such as
+// SnakeCase, so that the built type works with sqlx and GORM without
+// hand-written tags: fields without a db tag get one, for sqlx, and the gorm
+// tag of fields that don't name a column gets a column setting, which is
+// added to settings such as those of PrimaryKey. A nil mapper keeps the field
+// name.
+func WithDBTags(mapper NameMapper) Option {
+	if mapper == nil {
+		mapper = func(name string) string { return name }
+	}
+
+	return func(b *Builder) {
+		b.autoTags = append(b.autoTags,
+			autoTag{key: dbTagKey, mapper: mapper},
+			autoTag{
+				key: gormTagKey,
+				mapper: func(name string) string {
+					return "column:" + mapper(name)
+				},
+				merge: func(existing, name string) string {
+					if hasGormSetting(existing, "column") {
+						return existing
+					}
+
+					return "column:" + mapper(name) + ";" + existing
+				},
+			},
+		)
+	}
+}
+
+// PrimaryKey returns a `gorm:"primaryKey"` tag marking the field as the
+// primary key.
+func PrimaryKey() string {
+	return `gorm:"primaryKey"`
+}
+
+// AutoIncrementKey returns a `gorm:"primaryKey;autoIncrement"` tag marking the
+// field as a primary key the database assigns.
+func AutoIncrementKey() string {
+	return `gorm:"primaryKey;autoIncrement"`
+}
+
+// hasGormSetting reports whether the gorm tag value has the setting key, in
+// either case as GORM reads them.
+func hasGormSetting(value, key string) bool {
+	for _, setting := range strings.Split(value, ";") {
+		name, _, _ := strings.Cut(setting, ":")
+		if strings.EqualFold(strings.TrimSpace(name), key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Columns returns the database columns of the builder's struct in field
+// order, as sqlx maps them: the name in a field's db tag, or its lower-cased
+// name without one. Fields tagged db:"-" are skipped, and the fields of
+// embedded structs are promoted.
+func (b *Builder) Columns() ([]string, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	structType, err := b.resolveStructType()
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+
+	for _, column := range dbColumns(structType, nil) {
+		columns = append(columns, column.name)
+	}
+
+	return columns, nil
+}
+
+// Values returns the values of the columns Columns returns, in the same
+// order, from instance, an instance of the built type or a pointer to one,
+// for hand-written SQL such as an INSERT. Columns of structs embedded through
+// a nil pointer are nil.
+func (b *Builder) Values(instance any) ([]any, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Type() != b.instance.Type() {
+		return nil, fmt.Errorf("%w: instance type: %s, built type: %s", ErrIncompatibleTypes, value.Type(), b.instance.Type())
+	}
+
+	columns := dbColumns(value.Type(), nil)
+	values := make([]any, len(columns))
+
+	for i, column := range columns {
+		field, err := value.FieldByIndexErr(column.index)
+		if err != nil {
+			continue
+		}
+
+		values[i] = field.Interface()
+	}
+
+	return values, nil
+}
+
+type dbColumn struct {
+	name  string
+	index []int
+}
+
+// dbColumns returns the columns of structType, with the index of their field
+// below the field at index.
+func dbColumns(structType reflect.Type, index []int) []dbColumn {
+	var columns []dbColumn
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		name, ok := field.Tag.Lookup(dbTagKey)
+		if name == "-" {
+			continue
+		}
+
+		name, _, _ = strings.Cut(name, ",")
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+
+		if field.Anonymous && !ok && embedded.Kind() == reflect.Struct {
+			columns = append(columns, dbColumns(embedded, fieldIndex)...)
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		columns = append(columns, dbColumn{name: name, index: fieldIndex})
+	}
+
+	return columns
+}
@@ -0,0 +1,81 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestWithDBTags(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithDBTags(dynamicstruct.SnakeCase))
+	_ = builder.AddField("UserID", int64(0), dynamicstruct.AutoIncrementKey())
+	_ = builder.AddField("DisplayName", "")
+	_ = builder.AddField("Email", "", `db:"mail" gorm:"column:mail;unique"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		field string
+		want  reflect.StructTag
+	}{
+		{field: "UserID", want: `gorm:"column:user_id;primaryKey;autoIncrement" db:"user_id"`},
+		{field: "DisplayName", want: `db:"display_name" gorm:"column:display_name"`},
+		{field: "Email", want: `db:"mail" gorm:"column:mail;unique"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, _ := reflect.TypeOf(instance).FieldByName(tt.field)
+			if field.Tag != tt.want {
+				t.Errorf("%s tag = %s, want %s", tt.field, field.Tag, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnsAndValues(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithDBTags(dynamicstruct.SnakeCase))
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("UserID", int64(0), dynamicstruct.PrimaryKey())
+	_ = builder.AddField("Secret", "", `db:"-"`)
+
+	columns, err := builder.Columns()
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+
+	if want := []string{"name", "age", "user_id"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("Columns() = %v, want %v", columns, want)
+	}
+
+	if _, err := builder.Values(PersonTest{}); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("Values() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Name", "Alice")
+	_ = builder.SetFieldValue("UserID", int64(7))
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	values, err := builder.Values(instancePtr)
+	if err != nil {
+		t.Fatalf("Values() error = %v", err)
+	}
+
+	if want := []any{"Alice", 0, int64(7)}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Values() = %v, want %v", values, want)
+	}
+
+	if _, err := builder.Values(PersonTest{}); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Values() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+}
@@ -0,0 +1,168 @@
+package dynamicstruct
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// Decoder reads instances of a built type one at a time from a stream of JSON
+// documents, such as newline-delimited JSON, or from the elements of a JSON
+// array, without reading the whole stream into memory. Instances come from a
+// Pool: each call to Next recycles the instance of the previous call, unless
+// it was kept with Keep. Use it like bufio.Scanner:
+//
+//	for decoder.Next() {
+//		instancePtr := decoder.Instance()
+//		// use instancePtr
+//	}
+//
+//	if err := decoder.Err(); err != nil {
+//		// handle the error
+//	}
+//
+// A Decoder isn't safe for concurrent use.
+type Decoder struct {
+	builder *Builder
+	pool    *Pool
+	reader  *bufio.Reader
+	json    *json.Decoder
+	// array is set while reading the elements of a top-level array
+	array    bool
+	started  bool
+	current  any
+	kept     bool
+	document int
+	err      error
+}
+
+// NewDecoder returns a Decoder reading instances of the built type from r.
+// A stream starting with "[" is read as an array, one instance per element,
+// and any other as a sequence of JSON objects. Each document is decoded as
+// DecodeJSON decodes it, starting from the default values.
+func (b *Builder) NewDecoder(r io.Reader) (*Decoder, error) {
+	pool, err := b.Pool()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decoder{builder: b, pool: pool, reader: bufio.NewReader(r)}, nil
+}
+
+// Next decodes the next document or array element, and reports whether there
+// was one. It returns false at the end of the stream or on the first error,
+// which Err returns.
+func (d *Decoder) Next() bool {
+	d.recycle()
+
+	if d.err != nil {
+		return false
+	}
+
+	if !d.started {
+		d.started = true
+
+		if d.err = d.start(); d.err != nil {
+			return false
+		}
+	}
+
+	if d.array && !d.json.More() {
+		// Read the closing bracket; what follows the array is left unread
+		if _, d.err = d.json.Token(); d.err != nil {
+			return false
+		}
+
+		d.array = false
+		d.err = io.EOF
+
+		return false
+	}
+
+	var raw json.RawMessage
+	if d.err = d.json.Decode(&raw); d.err != nil {
+		return false
+	}
+
+	d.document++
+	d.current = d.pool.Get()
+
+	if err := d.builder.DecodeJSON(raw, d.current); err != nil {
+		d.err = fmt.Errorf("document %d: %w", d.document, err)
+		d.recycle()
+
+		return false
+	}
+
+	return true
+}
+
+// start detects whether the stream is an array, and consumes its opening
+// bracket if so.
+func (d *Decoder) start() error {
+	for {
+		r, _, err := d.reader.ReadRune()
+		if err != nil {
+			return err
+		}
+
+		if unicode.IsSpace(r) {
+			continue
+		}
+
+		if err := d.reader.UnreadRune(); err != nil {
+			return err
+		}
+
+		d.json = json.NewDecoder(d.reader)
+
+		if r == '[' {
+			d.array = true
+			_, err = d.json.Token()
+		}
+
+		return err
+	}
+}
+
+// recycle returns the current instance to the pool, unless it was kept.
+func (d *Decoder) recycle() {
+	if d.current != nil && !d.kept {
+		_ = d.pool.Put(d.current)
+	}
+
+	d.current = nil
+	d.kept = false
+}
+
+// Instance returns a pointer to the instance decoded by the last call to
+// Next. It is valid until the next call to Next, unless kept with Keep.
+func (d *Decoder) Instance() any {
+	return d.current
+}
+
+// Keep returns the instance decoded by the last call to Next and hands it
+// over to the caller: Next no longer recycles it. Give it back with Release
+// when done with it.
+func (d *Decoder) Keep() any {
+	d.kept = d.current != nil
+
+	return d.current
+}
+
+// Release returns an instance kept with Keep to the decoder's pool.
+func (d *Decoder) Release(instancePtr any) error {
+	return d.pool.Put(instancePtr)
+}
+
+// Err returns the error that stopped Next, or nil at the end of the stream.
+func (d *Decoder) Err() error {
+	if errors.Is(d.err, io.EOF) {
+		return nil
+	}
+
+	return d.err
+}
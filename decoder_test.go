@@ -0,0 +1,139 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestNewDecoder(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+	_ = builder.AddFieldWithOptions("Status", "", dynamicstruct.Default("new"), dynamicstruct.Tags(`json:"status"`))
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stream string
+	}{
+		{name: "ndjson", stream: "{\"id\": 1, \"status\": \"done\"}\n{\"id\": 2}\n{\"id\": 3}\n"},
+		{name: "array", stream: "  [{\"id\": 1, \"status\": \"done\"}, {\"id\": 2}, {\"id\": 3}]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := builder.NewDecoder(strings.NewReader(tt.stream))
+			if err != nil {
+				t.Fatalf("NewDecoder() error = %v", err)
+			}
+
+			var (
+				ids      []int64
+				statuses []string
+			)
+
+			for decoder.Next() {
+				value := reflect.ValueOf(decoder.Instance()).Elem()
+				ids = append(ids, value.FieldByName("ID").Int())
+				statuses = append(statuses, value.FieldByName("Status").String())
+			}
+
+			if err := decoder.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+
+			if !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+				t.Errorf("ids = %v, want [1 2 3]", ids)
+			}
+
+			// Recycled instances start from the defaults again
+			if !reflect.DeepEqual(statuses, []string{"done", "new", "new"}) {
+				t.Errorf("statuses = %v, want [done new new]", statuses)
+			}
+		})
+	}
+
+	t.Run("keep", func(t *testing.T) {
+		decoder, _ := builder.NewDecoder(strings.NewReader(`{"id": 1} {"id": 2}`))
+
+		var kept []any
+
+		for decoder.Next() {
+			kept = append(kept, decoder.Keep())
+		}
+
+		if len(kept) != 2 || kept[0] == kept[1] {
+			t.Fatalf("kept = %v, want two distinct instances", kept)
+		}
+
+		if id := reflect.ValueOf(kept[0]).Elem().FieldByName("ID").Int(); id != 1 {
+			t.Errorf("kept[0].ID = %d, want 1", id)
+		}
+
+		for _, instancePtr := range kept {
+			if err := decoder.Release(instancePtr); err != nil {
+				t.Errorf("Release() error = %v", err)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		for _, stream := range []string{"", "  \n", "[]"} {
+			decoder, _ := builder.NewDecoder(strings.NewReader(stream))
+
+			if decoder.Next() {
+				t.Errorf("Next() = true for %q, want false", stream)
+			}
+
+			if err := decoder.Err(); err != nil {
+				t.Errorf("Err() = %v for %q, want nil", err, stream)
+			}
+		}
+	})
+}
+
+func TestNewDecoderErrors(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+
+	if _, err := builder.NewDecoder(strings.NewReader("")); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("NewDecoder() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	_, _ = builder.Build()
+
+	tests := []struct {
+		name   string
+		stream string
+		want   int
+	}{
+		{name: "truncated", stream: "{\"id\": 1}\n{\"id\":", want: 1},
+		{name: "type_mismatch", stream: "{\"id\": 1}\n{\"id\": \"two\"}", want: 1},
+		{name: "unterminated_array", stream: `[{"id": 1},`, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, _ := builder.NewDecoder(strings.NewReader(tt.stream))
+
+			n := 0
+			for decoder.Next() {
+				n++
+			}
+
+			if n != tt.want {
+				t.Errorf("Next() returned true %d times, want %d", n, tt.want)
+			}
+
+			if decoder.Err() == nil {
+				t.Error("Err() = nil, want error")
+			}
+		})
+	}
+}
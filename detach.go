@@ -0,0 +1,95 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Detach copies the string and []byte fields of the struct instancePtr points
+// to into memory of their own. Values decoded from a large buffer often share
+// its backing array, which keeps the whole buffer alive for as long as the
+// instance is; detaching them lets the buffer be freed.
+//
+// With no field names, every string and []byte field is detached, including
+// those of nested structs. Named fields must be strings or byte slices.
+func Detach(instancePtr any, fields ...string) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
+		detachStruct(value)
+
+		return nil
+	}
+
+	for _, name := range fields {
+		field, ok := fieldByName(value, name)
+		if !ok || !field.CanSet() {
+			return fmt.Errorf("%w: %s", ErrFieldNotFound, name)
+		}
+
+		if !detachValue(field) {
+			return fmt.Errorf(
+				"%w: field %s type: %s, want string or []byte",
+				ErrIncompatibleTypes,
+				name,
+				field.Type().String(),
+			)
+		}
+	}
+
+	return nil
+}
+
+func detachStruct(value reflect.Value) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).PkgPath != "" {
+			continue
+		}
+
+		field := reflect.Indirect(value.Field(i))
+		if !field.IsValid() || !field.CanSet() {
+			continue
+		}
+
+		if field.Kind() == reflect.Struct {
+			detachStruct(field)
+
+			continue
+		}
+
+		detachValue(field)
+	}
+}
+
+// detachValue copies a string or []byte value, and reports whether value was
+// one.
+func detachValue(value reflect.Value) bool {
+	switch {
+	case value.Kind() == reflect.String:
+		// strings.Clone is not available in Go 1.18
+		var clone strings.Builder
+
+		clone.WriteString(value.String())
+		value.SetString(clone.String())
+
+		return true
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		if value.IsNil() {
+			return true
+		}
+
+		clone := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(clone, value)
+		value.Set(clone)
+
+		return true
+	default:
+		return false
+	}
+}
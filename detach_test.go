@@ -0,0 +1,121 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newDetachInstance(t *testing.T) reflect.Value {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Payload", []byte{})
+	_ = builder.AddField("Raw", []byte{})
+	_ = builder.AddField("Count", 0)
+	_ = builder.AddField("Address", AddressTest{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.New(reflect.TypeOf(instance))
+}
+
+func TestDetach(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		want     map[string]string
+		detached []string
+	}{
+		{
+			name:     "named_fields",
+			fields:   []string{"Payload"},
+			want:     map[string]string{"Payload": "payload", "Raw": "XXX"},
+			detached: []string{"Payload"},
+		},
+		{
+			name:     "all_fields",
+			want:     map[string]string{"Payload": "payload", "Raw": "raw"},
+			detached: []string{"Payload", "Raw"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := []byte("payload-raw-and-more")
+
+			value := newDetachInstance(t)
+			value.Elem().FieldByName("Name").SetString("Alice")
+			value.Elem().FieldByName("Payload").SetBytes(buffer[0:7])
+			value.Elem().FieldByName("Raw").SetBytes(buffer[8:11])
+			value.Elem().FieldByName("Address").Set(reflect.ValueOf(AddressTest{City: "Oslo"}))
+
+			if err := dynamicstruct.Detach(value.Interface(), tt.fields...); err != nil {
+				t.Fatalf("Detach() error = %v", err)
+			}
+
+			// Changes to the buffer are only visible through fields still
+			// sharing it
+			copy(buffer, "XXXXXXXXXXXX")
+
+			for name, wantValue := range tt.want {
+				field := value.Elem().FieldByName(name)
+				if got := string(field.Bytes()); got != wantValue {
+					t.Errorf("Detach() %s = %q, want %q", name, got, wantValue)
+				}
+			}
+
+			for _, name := range tt.detached {
+				if field := value.Elem().FieldByName(name); field.Cap() != field.Len() {
+					t.Errorf("Detach() %s cap = %d, want %d", name, field.Cap(), field.Len())
+				}
+			}
+
+			if got := value.Elem().FieldByName("Address").Interface(); got != (AddressTest{City: "Oslo"}) {
+				t.Errorf("Detach() Address = %v, want %v", got, AddressTest{City: "Oslo"})
+			}
+		})
+	}
+}
+
+func TestDetachErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance func(t *testing.T) any
+		fields   []string
+		wantErr  error
+	}{
+		{
+			name:     "unknown_field",
+			instance: func(t *testing.T) any { return newDetachInstance(t).Interface() },
+			fields:   []string{"Missing"},
+			wantErr:  dynamicstruct.ErrFieldNotFound,
+		},
+		{
+			name:     "not_string_or_bytes",
+			instance: func(t *testing.T) any { return newDetachInstance(t).Interface() },
+			fields:   []string{"Count"},
+			wantErr:  dynamicstruct.ErrIncompatibleTypes,
+		},
+		{
+			name:     "not_a_pointer",
+			instance: func(t *testing.T) any { return newDetachInstance(t).Elem().Interface() },
+			wantErr:  dynamicstruct.ErrValueMustBePointer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.Detach(tt.instance(t), tt.fields...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Detach() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldChange is a field whose value differs between two instances.
+type FieldChange struct {
+	// Field is the field name. Fields of nested structs are named by their
+	// path, such as "Address.City", and fields of embedded structs by their
+	// promoted name.
+	Field string
+	Old   any
+	New   any
+}
+
+// Diff returns the fields whose values differ between a and b, in field
+// order. a and b must be instances of the same type, or pointers to them.
+// Nested structs are compared field by field; other values, including
+// slices, maps and structs with unexported fields such as time.Time, are
+// compared as a whole with reflect.DeepEqual.
+func Diff(a, b any) ([]FieldChange, error) {
+	aValue, err := structValue(a)
+	if err != nil {
+		return nil, err
+	}
+
+	bValue, err := structValue(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if aValue.Type() != bValue.Type() {
+		return nil, fmt.Errorf(
+			"%w: old type: %s, new type: %s",
+			ErrIncompatibleTypes,
+			aValue.Type().String(),
+			bValue.Type().String(),
+		)
+	}
+
+	var changes []FieldChange
+	diffStruct(aValue, bValue, "", &changes)
+
+	return changes, nil
+}
+
+func diffStruct(a, b reflect.Value, prefix string, changes *[]FieldChange) {
+	structType := a.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		path := prefix + field.Name
+		aField, bField := a.Field(i), b.Field(i)
+
+		// Fields of embedded structs are reported under their promoted names
+		if field.Anonymous {
+			aEmbedded, bEmbedded := reflect.Indirect(aField), reflect.Indirect(bField)
+			if aEmbedded.IsValid() && bEmbedded.IsValid() && isDiffableStruct(aEmbedded.Type()) {
+				diffStruct(aEmbedded, bEmbedded, prefix, changes)
+
+				continue
+			}
+		}
+
+		diffValue(aField, bField, path, changes)
+	}
+}
+
+func diffValue(a, b reflect.Value, path string, changes *[]FieldChange) {
+	aStruct, bStruct := a, b
+	if a.Kind() == reflect.Ptr && !a.IsNil() && !b.IsNil() {
+		aStruct, bStruct = a.Elem(), b.Elem()
+	}
+
+	if aStruct.Kind() == reflect.Struct && isDiffableStruct(aStruct.Type()) {
+		diffStruct(aStruct, bStruct, path+".", changes)
+
+		return
+	}
+
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		*changes = append(*changes, FieldChange{Field: path, Old: a.Interface(), New: b.Interface()})
+	}
+}
+
+// isDiffableStruct reports whether t is a struct whose fields are all
+// exported, so that comparing it field by field sees all of its state.
+func isDiffableStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			return false
+		}
+	}
+
+	return true
+}
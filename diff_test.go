@@ -0,0 +1,134 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newDiffType(t *testing.T) reflect.Type {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Age", 0)
+	_ = builder.AddField("Tags", []string{})
+	_ = builder.AddField("Address", AddressTest{})
+	_ = builder.AddField("Billing", (*AddressTest)(nil))
+	_ = builder.AddField("UpdatedAt", time.Time{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestDiff(t *testing.T) {
+	structType := newDiffType(t)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		modify func(v reflect.Value)
+		want   []dynamicstruct.FieldChange
+	}{
+		{
+			name:   "no_changes",
+			modify: func(v reflect.Value) {},
+			want:   nil,
+		},
+		{
+			name: "top_level_fields",
+			modify: func(v reflect.Value) {
+				v.FieldByName("Name").SetString("Bob")
+				v.FieldByName("Tags").Set(reflect.ValueOf([]string{"a", "c"}))
+			},
+			want: []dynamicstruct.FieldChange{
+				{Field: "Name", Old: "Alice", New: "Bob"},
+				{Field: "Tags", Old: []string{"a", "b"}, New: []string{"a", "c"}},
+			},
+		},
+		{
+			name: "nested_and_embedded_fields",
+			modify: func(v reflect.Value) {
+				v.FieldByName("Email").SetString("bob@example.com")
+				v.FieldByName("Address").FieldByName("City").SetString("Bergen")
+				v.FieldByName("Billing").Elem().FieldByName("Street").SetString("Main St")
+			},
+			want: []dynamicstruct.FieldChange{
+				{Field: "Email", Old: "alice@example.com", New: "bob@example.com"},
+				{Field: "Address.City", Old: "Oslo", New: "Bergen"},
+				{Field: "Billing.Street", Old: "", New: "Main St"},
+			},
+		},
+		{
+			name: "nil_pointer_and_time",
+			modify: func(v reflect.Value) {
+				v.FieldByName("Billing").Set(reflect.Zero(v.FieldByName("Billing").Type()))
+				v.FieldByName("UpdatedAt").Set(reflect.ValueOf(now.Add(time.Hour)))
+			},
+			want: []dynamicstruct.FieldChange{
+				{Field: "Billing", Old: &AddressTest{City: "Oslo"}, New: (*AddressTest)(nil)},
+				{Field: "UpdatedAt", Old: now, New: now.Add(time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newInstance := func() reflect.Value {
+				v := reflect.New(structType).Elem()
+				v.FieldByName("Email").SetString("alice@example.com")
+				v.FieldByName("Name").SetString("Alice")
+				v.FieldByName("Tags").Set(reflect.ValueOf([]string{"a", "b"}))
+				v.FieldByName("Address").Set(reflect.ValueOf(AddressTest{City: "Oslo"}))
+				v.FieldByName("Billing").Set(reflect.ValueOf(&AddressTest{City: "Oslo"}))
+				v.FieldByName("UpdatedAt").Set(reflect.ValueOf(now))
+
+				return v
+			}
+
+			a, b := newInstance(), newInstance()
+			tt.modify(b)
+
+			got, err := dynamicstruct.Diff(a.Interface(), b.Addr().Interface())
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	structType := newDiffType(t)
+	instance := reflect.New(structType).Interface()
+
+	tests := []struct {
+		name    string
+		a, b    any
+		wantErr error
+	}{
+		{name: "different_types", a: instance, b: PersonTest{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "not_a_struct", a: instance, b: 1, wantErr: dynamicstruct.ErrInvalidInstance},
+		{name: "nil_pointer", a: (*PersonTest)(nil), b: instance, wantErr: dynamicstruct.ErrValueCannotBeNil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamicstruct.Diff(tt.a, tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Diff() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
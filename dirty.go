@@ -0,0 +1,93 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"sync"
+)
+
+// DirtyTracker wraps an instance and records which fields are set through
+// its SetFieldValue, so that only those need to be written back, as columns
+// of an UPDATE or keys of a PATCH body. Fields set directly on the instance
+// are not recorded. It is safe for concurrent use.
+type DirtyTracker struct {
+	value   reflect.Value
+	changed []string
+	dirty   map[string]bool
+	m       sync.Mutex
+}
+
+// TrackChanges returns a DirtyTracker for the struct instancePtr points to,
+// with no field changed.
+func TrackChanges(instancePtr any) (*DirtyTracker, error) {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DirtyTracker{value: value, dirty: make(map[string]bool)}, nil
+}
+
+// SetFieldValue sets the named field like Builder.SetFieldValue and records
+// it as changed, even when the value is the one the field had.
+func (d *DirtyTracker) SetFieldValue(name string, value any) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if _, err := setFieldValue(d.value, name, value); err != nil {
+		return fieldError("set", name, err)
+	}
+
+	if !d.dirty[name] {
+		d.dirty[name] = true
+		d.changed = append(d.changed, name)
+	}
+
+	return nil
+}
+
+// GetField returns the current value of the named field. Reading a field
+// doesn't record it as changed, and fields promoted through a nil embedded
+// pointer return ErrFieldNotFound.
+func (d *DirtyTracker) GetField(name string) (any, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	field, ok := fieldByName(d.value, name)
+	if !ok {
+		return nil, fieldError("get", name, missingField(d.value.Type(), name))
+	}
+
+	return field.Interface(), nil
+}
+
+// ChangedFields returns the names of the fields set since the tracker was
+// created or last reset, in the order they were first set.
+func (d *DirtyTracker) ChangedFields() []string {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	return append([]string(nil), d.changed...)
+}
+
+// IsDirty reports whether the named field was set since the tracker was
+// created or last reset.
+func (d *DirtyTracker) IsDirty(name string) bool {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	return d.dirty[name]
+}
+
+// Reset forgets the changed fields, as after the changes were saved.
+func (d *DirtyTracker) Reset() {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	d.changed = nil
+	d.dirty = make(map[string]bool)
+}
+
+// Instance returns the pointer the tracker was created with.
+func (d *DirtyTracker) Instance() any {
+	return d.value.Addr().Interface()
+}
@@ -0,0 +1,96 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestDirtyTracker(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("Email", "")
+	_ = builder.AddField("Active", false)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instancePtr := reflect.New(reflect.TypeOf(instance)).Interface()
+
+	tracker, err := dynamicstruct.TrackChanges(instancePtr)
+	if err != nil {
+		t.Fatalf("TrackChanges() error = %v", err)
+	}
+
+	if changed := tracker.ChangedFields(); len(changed) != 0 {
+		t.Errorf("ChangedFields() = %v, want none", changed)
+	}
+
+	_ = tracker.SetFieldValue("Email", "alice@example.com")
+	_ = tracker.SetFieldValue("Name", "Alice")
+	_ = tracker.SetFieldValue("Email", "a@example.com")
+	_ = tracker.SetFieldValue("Active", false) // unchanged value still counts
+
+	if got, want := tracker.ChangedFields(), []string{"Email", "Name", "Active"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedFields() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{field: "Email", want: true},
+		{field: "Name", want: true},
+		{field: "Age", want: false},
+		{field: "Missing", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tracker.IsDirty(tt.field); got != tt.want {
+			t.Errorf("IsDirty(%s) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+
+	if email, _ := tracker.GetField("Email"); email != "a@example.com" {
+		t.Errorf("GetField(Email) = %v, want %q", email, "a@example.com")
+	}
+
+	if tracker.Instance() != instancePtr {
+		t.Errorf("Instance() = %p, want %p", tracker.Instance(), instancePtr)
+	}
+
+	tracker.Reset()
+
+	if changed := tracker.ChangedFields(); len(changed) != 0 || tracker.IsDirty("Email") {
+		t.Errorf("ChangedFields() after Reset() = %v, want none", changed)
+	}
+}
+
+func TestDirtyTrackerErrors(t *testing.T) {
+	if _, err := dynamicstruct.TrackChanges(PersonTest{}); !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+		t.Errorf("TrackChanges() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+	}
+
+	tracker, _ := dynamicstruct.TrackChanges(&PersonTest{})
+
+	if err := tracker.SetFieldValue("Age", "thirty"); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("SetFieldValue() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	if err := tracker.SetFieldValue("Email", ""); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+		t.Errorf("SetFieldValue() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+	}
+
+	if _, err := tracker.GetField("Email"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+		t.Errorf("GetField() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+	}
+
+	// Failed sets aren't recorded
+	if changed := tracker.ChangedFields(); len(changed) != 0 {
+		t.Errorf("ChangedFields() = %v, want none", changed)
+	}
+}
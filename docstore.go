@@ -0,0 +1,416 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const documentTagKey = "docstore"
+
+type documentConfig struct {
+	tagKey    string
+	timestamp func() any
+}
+
+// DocumentOption configures ToDocument and FromDocument.
+type DocumentOption func(*documentConfig)
+
+// WithDocumentTagKey sets the tag fields are named and configured by, such
+// as "firestore", instead of "docstore".
+func WithDocumentTagKey(key string) DocumentOption {
+	return func(c *documentConfig) {
+		c.tagKey = key
+	}
+}
+
+// WithServerTimestamp sets the value ToDocument writes for zero
+// serverTimestamp fields, such as firestore.ServerTimestamp, so the store
+// sets the time. By default it writes the current time.
+func WithServerTimestamp(sentinel any) DocumentOption {
+	return func(c *documentConfig) {
+		c.timestamp = func() any { return sentinel }
+	}
+}
+
+func newDocumentConfig(opts []DocumentOption) *documentConfig {
+	cfg := &documentConfig{
+		tagKey:    documentTagKey,
+		timestamp: func() any { return time.Now().UTC() },
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+type documentField struct {
+	name            string
+	omitempty       bool
+	serverTimestamp bool
+}
+
+// documentFieldOf reads the name and options of field from its tag, as in
+// `docstore:"name,omitempty,serverTimestamp"`. It reports false for fields
+// left out of documents.
+func (c *documentConfig) documentFieldOf(field reflect.StructField) (documentField, bool) {
+	tag, _ := field.Tag.Lookup(c.tagKey)
+	if tag == "-" {
+		return documentField{}, false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	f := documentField{name: name}
+
+	for _, option := range strings.Split(options, ",") {
+		switch option {
+		case "omitempty":
+			f.omitempty = true
+		case "serverTimestamp":
+			f.serverTimestamp = true
+		}
+	}
+
+	return f, true
+}
+
+// isPromoted reports whether the fields of field's struct are stored in the
+// document of the struct embedding it: field is embedded and doesn't name
+// itself in its tag.
+func (c *documentConfig) isPromoted(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+
+	if tag, _ := field.Tag.Lookup(c.tagKey); tag != "" {
+		return false
+	}
+
+	embedded := field.Type
+	if embedded.Kind() == reflect.Ptr {
+		embedded = embedded.Elem()
+	}
+
+	return embedded.Kind() == reflect.Struct
+}
+
+// ToDocument converts instance, a struct or a pointer to one, into a document
+// for stores such as Firestore or Go CDK docstore. Fields are keyed by the
+// name in their docstore tag, or by their own name. Nested structs become
+// nested maps, slices []any and maps with string keys map[string]any, while
+// times and byte slices are kept as they are. Fields of embedded structs are
+// promoted.
+//
+// Tag options control how fields are written: omitempty leaves out zero
+// fields, and serverTimestamp writes a zero time field as the current time,
+// or as the sentinel set with WithServerTimestamp.
+func ToDocument(instance any, opts ...DocumentOption) (map[string]any, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := newDocumentConfig(opts)
+	doc := make(map[string]any)
+	cfg.encodeStruct(value, doc)
+
+	return doc, nil
+}
+
+func (c *documentConfig) encodeStruct(value reflect.Value, doc map[string]any) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldValue := value.Field(i)
+
+		if c.isPromoted(field) {
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					continue
+				}
+
+				fieldValue = fieldValue.Elem()
+			}
+
+			c.encodeStruct(fieldValue, doc)
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		f, ok := c.documentFieldOf(field)
+		if !ok {
+			continue
+		}
+
+		if fieldValue.IsZero() || isEmptyCollection(fieldValue) {
+			if f.serverTimestamp {
+				doc[f.name] = c.timestamp()
+
+				continue
+			}
+
+			if f.omitempty {
+				continue
+			}
+		}
+
+		doc[f.name] = c.encodeValue(fieldValue)
+	}
+}
+
+func isEmptyCollection(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	default:
+		return false
+	}
+}
+
+func (c *documentConfig) encodeValue(value reflect.Value) any {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+
+		return c.encodeValue(value.Elem())
+	case reflect.Struct:
+		if value.Type() == timeType || !isDiffableStruct(value.Type()) {
+			return value.Interface()
+		}
+
+		doc := make(map[string]any)
+		c.encodeStruct(value, doc)
+
+		return doc
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+
+		items := make([]any, value.Len())
+		for i := range items {
+			items[i] = c.encodeValue(value.Index(i))
+		}
+
+		return items
+	case reflect.Map:
+		if value.IsNil() || value.Type().Key().Kind() != reflect.String {
+			return value.Interface()
+		}
+
+		entries := make(map[string]any, value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			entries[iter.Key().String()] = c.encodeValue(iter.Value())
+		}
+
+		return entries
+	default:
+		if !value.CanInterface() {
+			return nil
+		}
+
+		return value.Interface()
+	}
+}
+
+// FromDocument sets the fields of the struct instancePtr points to from doc,
+// a document read from a store, reversing ToDocument. Keys are matched to the
+// names ToDocument uses, or case-insensitively, and keys without a field are
+// ignored. Nested maps and slices are converted into the struct, slice and map
+// fields they are stored for, numbers convert between numeric types without
+// overflowing, and strings and numbers convert into time fields, so documents
+// decoded from JSON can be read too.
+//
+// Fields without a key in doc are left as they are. Values that don't fit
+// their field return a *FieldError wrapping ErrIncompatibleTypes, and leave
+// the instance unchanged.
+func FromDocument(doc map[string]any, instancePtr any, opts ...DocumentOption) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	cfg := newDocumentConfig(opts)
+
+	decoded := reflect.New(value.Type()).Elem()
+	decoded.Set(value)
+
+	if err := cfg.decodeStruct(doc, decoded, ""); err != nil {
+		return err
+	}
+
+	value.Set(decoded)
+
+	return nil
+}
+
+func (c *documentConfig) decodeStruct(doc map[string]any, value reflect.Value, path string) error {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldValue := value.Field(i)
+
+		if c.isPromoted(field) {
+			if fieldValue.Kind() == reflect.Ptr {
+				switch {
+				case fieldValue.CanSet():
+					// The embedded struct is shared with the instance, so it
+					// is decoded into a copy
+					fieldValue = ownPointer(fieldValue)
+				case fieldValue.IsNil():
+					continue
+				default:
+					fieldValue = fieldValue.Elem()
+				}
+			}
+
+			if err := c.decodeStruct(doc, fieldValue, path); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		f, ok := c.documentFieldOf(field)
+		if !ok {
+			continue
+		}
+
+		raw, ok := documentValue(doc, f.name)
+		if !ok {
+			continue
+		}
+
+		if err := c.decodeValue(raw, fieldValue, path+f.name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// documentValue looks name up in doc, falling back to a case-insensitive
+// match.
+func documentValue(doc map[string]any, name string) (any, bool) {
+	if raw, ok := doc[name]; ok {
+		return raw, true
+	}
+
+	for key, raw := range doc {
+		if strings.EqualFold(key, name) {
+			return raw, true
+		}
+	}
+
+	return nil, false
+}
+
+func (c *documentConfig) decodeValue(raw any, target reflect.Value, path string) error {
+	if raw == nil {
+		target.Set(reflect.Zero(target.Type()))
+
+		return nil
+	}
+
+	value := reflect.ValueOf(raw)
+	targetType := target.Type()
+
+	switch {
+	case value.Type().AssignableTo(targetType):
+		target.Set(value)
+
+		return nil
+	case targetType.Kind() == reflect.Ptr:
+		elem := reflect.New(targetType.Elem())
+		if err := c.decodeValue(raw, elem.Elem(), path); err != nil {
+			return err
+		}
+
+		target.Set(elem)
+
+		return nil
+	case targetType == timeType:
+		converted, ok, err := convertTime(targetType, value)
+		if err != nil {
+			return fieldError("decode", path, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error()))
+		}
+
+		if ok {
+			target.Set(converted)
+
+			return nil
+		}
+	case targetType.Kind() == reflect.Struct && value.Kind() == reflect.Map:
+		if doc, ok := raw.(map[string]any); ok {
+			return c.decodeStruct(doc, target, path+".")
+		}
+	case targetType.Kind() == reflect.Slice && (value.Kind() == reflect.Slice || value.Kind() == reflect.Array):
+		items := reflect.MakeSlice(targetType, value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if err := c.decodeValue(value.Index(i).Interface(), items.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+		target.Set(items)
+
+		return nil
+	case targetType.Kind() == reflect.Map && value.Kind() == reflect.Map &&
+		targetType.Key().Kind() == reflect.String && value.Type().Key().Kind() == reflect.String:
+		entries := reflect.MakeMapWithSize(targetType, value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			entry := reflect.New(targetType.Elem()).Elem()
+			if err := c.decodeValue(iter.Value().Interface(), entry, path+"."+iter.Key().String()); err != nil {
+				return err
+			}
+
+			entries.SetMapIndex(iter.Key().Convert(targetType.Key()), entry)
+		}
+
+		target.Set(entries)
+
+		return nil
+	case isNumberKind(value.Kind()) && isNumberKind(targetType.Kind()):
+		converted, _, err := convertNumber(targetType, value)
+		if err != nil {
+			return fieldError("decode", path, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error()))
+		}
+
+		target.Set(converted)
+
+		return nil
+	case value.Kind() == targetType.Kind() && value.Type().ConvertibleTo(targetType):
+		target.Set(value.Convert(targetType))
+
+		return nil
+	}
+
+	return fieldError("decode", path, fmt.Errorf("%w: field type: %s, value type: %s", ErrIncompatibleTypes, targetType, value.Type()))
+}
@@ -0,0 +1,174 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newDocumentBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("Email", "", `docstore:"email,omitempty"`)
+	_ = builder.AddField("Address", &AddressTest{}, `docstore:"address"`)
+	_ = builder.AddField("Previous", []AddressTest{}, `docstore:"previous"`)
+	_ = builder.AddField("Scores", map[string]int{}, `docstore:"scores,omitempty"`)
+	_ = builder.AddField("Updated", time.Time{}, `docstore:"updated,serverTimestamp"`)
+	_ = builder.AddField("Internal", "", `docstore:"-"`)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestToDocument(t *testing.T) {
+	builder := newDocumentBuilder(t)
+	_ = builder.SetFieldValue("Name", "Alice")
+	_ = builder.SetFieldValue("Address", &AddressTest{City: "Berlin"})
+	_ = builder.SetFieldValue("Previous", []AddressTest{{City: "Paris"}})
+	_ = builder.SetFieldValue("Internal", "secret")
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	doc, err := dynamicstruct.ToDocument(instancePtr, dynamicstruct.WithServerTimestamp("REQUEST_TIME"))
+	if err != nil {
+		t.Fatalf("ToDocument() error = %v", err)
+	}
+
+	want := map[string]any{
+		"Name":     "Alice",
+		"Age":      0,
+		"address":  map[string]any{"Street": "", "City": "Berlin"},
+		"previous": []any{map[string]any{"Street": "", "City": "Paris"}},
+		"updated":  "REQUEST_TIME",
+	}
+
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("ToDocument() = %v, want %v", doc, want)
+	}
+
+	if _, err := dynamicstruct.ToDocument(42); !errors.Is(err, dynamicstruct.ErrInvalidInstance) {
+		t.Errorf("ToDocument() error = %v, want %v", err, dynamicstruct.ErrInvalidInstance)
+	}
+}
+
+func TestToDocumentTagKey(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Created", time.Time{}, `firestore:"created,serverTimestamp"`)
+
+	instance, _ := builder.Build()
+
+	doc, err := dynamicstruct.ToDocument(instance, dynamicstruct.WithDocumentTagKey("firestore"))
+	if err != nil {
+		t.Fatalf("ToDocument() error = %v", err)
+	}
+
+	created, ok := doc["created"].(time.Time)
+	if !ok || created.IsZero() {
+		t.Errorf("ToDocument() created = %v, want the current time", doc["created"])
+	}
+}
+
+func TestFromDocument(t *testing.T) {
+	builder := newDocumentBuilder(t)
+	instancePtr, _ := builder.InstanceAddr()
+
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Numbers as decoded from JSON, and keys in another case
+	doc := map[string]any{
+		"name":     "Alice",
+		"Age":      float64(30),
+		"address":  map[string]any{"city": "Berlin"},
+		"previous": []any{map[string]any{"City": "Paris"}},
+		"scores":   map[string]any{"math": int64(90)},
+		"updated":  updated.Format(time.RFC3339),
+		"unknown":  true,
+	}
+
+	if err := dynamicstruct.FromDocument(doc, instancePtr); err != nil {
+		t.Fatalf("FromDocument() error = %v", err)
+	}
+
+	value := reflect.ValueOf(instancePtr).Elem()
+
+	tests := []struct {
+		field string
+		want  any
+	}{
+		{field: "Name", want: "Alice"},
+		{field: "Age", want: 30},
+		{field: "Address", want: &AddressTest{City: "Berlin"}},
+		{field: "Previous", want: []AddressTest{{City: "Paris"}}},
+		{field: "Scores", want: map[string]int{"math": 90}},
+		{field: "Updated", want: updated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := value.FieldByName(tt.field).Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromDocumentErrors(t *testing.T) {
+	builder := newDocumentBuilder(t)
+	instancePtr, _ := builder.InstanceAddr()
+
+	tests := []struct {
+		name      string
+		doc       map[string]any
+		wantField string
+	}{
+		{name: "wrong_type", doc: map[string]any{"Name": "Alice", "Age": "old"}, wantField: "Age"},
+		{name: "fraction", doc: map[string]any{"Age": 1.5}, wantField: "Age"},
+		{name: "nested", doc: map[string]any{"address": map[string]any{"City": 1}}, wantField: "address.City"},
+		{name: "slice_element", doc: map[string]any{"previous": []any{"Paris"}}, wantField: "previous[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.FromDocument(tt.doc, instancePtr)
+			if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+				t.Fatalf("FromDocument() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+			}
+
+			var fieldErr *dynamicstruct.FieldError
+			if !errors.As(err, &fieldErr) || fieldErr.Field != tt.wantField {
+				t.Errorf("FromDocument() error = %v, want field %s", err, tt.wantField)
+			}
+
+			if name, _ := builder.GetField("Name"); name != "" {
+				t.Errorf("FromDocument() changed Name to %v", name)
+			}
+		})
+	}
+
+	t.Run("embedded_pointer", func(t *testing.T) {
+		type Entry struct {
+			*PersonTest
+			Email string `docstore:"email"`
+		}
+
+		person := &PersonTest{Name: "Alice"}
+		entry := &Entry{PersonTest: person}
+
+		err := dynamicstruct.FromDocument(map[string]any{"Name": "Bob", "email": 1}, entry)
+		if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Fatalf("FromDocument() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+
+		if person.Name != "Alice" || entry.Name != "Alice" {
+			t.Errorf("Name = %q, embedded Name = %q, want both unchanged", entry.Name, person.Name)
+		}
+	})
+}
@@ -5,127 +5,335 @@ import (
 	"reflect"
 	"strings"
 	"sync"
-
-	"github.com/fatih/structtag"
 )
 
 type Builder struct {
 	fields          map[string]reflect.StructField
+	order           []string
+	meta            map[string]fieldMeta
 	anonymousFields []reflect.StructField
+	nested          map[string]*Builder
+	selfFields      map[string]SelfKind
+	unknownField    string
+	group           string
+	aliases         map[string]string
+	positions       map[string]int
 	instance        *reflect.Value
-	m               sync.Mutex
+	dropped         *reflect.Value
+	index           map[string]int
+	autoTags        []autoTag
+	interfaces      []reflect.Type
+	stringer        func(inst any) string
+	jsonMarshaler   func(inst any) ([]byte, error)
+	chainErrs       FieldErrors
+	foldIndex       map[string]int
+	caseInsensitive bool
+	allowRebuild    bool
+	keepValues      bool
+	coerce          bool
+	optimizeLayout  bool
+
+	// m guards the definition and which instance is held; values guards the
+	// fields of the held instance, so that instances created from the builder
+	// never wait on each other.
+	m      sync.RWMutex
+	values sync.RWMutex
 }
 
-func New() *Builder {
-	return &Builder{
+func New(opts ...Option) *Builder {
+	b := &Builder{
 		fields: make(map[string]reflect.StructField),
 	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
 }
 
+// AddField adds a field of the type of kind. A *Builder kind adds a nested
+// struct field of the type the builder defines, resolved when this builder is
+// built, so the nested builder can still be edited until then.
 func (b *Builder) AddField(name string, kind any, tags ...string) error {
 	b.m.Lock()
 	defer b.m.Unlock()
 
-	if b.instance != nil {
-		return ErrInstanceAlreadyBuilt
+	return b.addKind(name, kind, tags)
+}
+
+// addKind adds a field of the type of kind, or a nested struct field for a
+// *Builder kind.
+func (b *Builder) addKind(name string, kind any, tags []string) error {
+	if child, ok := kind.(*Builder); ok {
+		return b.addBuilderField(name, child, tags)
+	}
+
+	return b.addField(name, reflect.TypeOf(kind), tags)
+}
+
+func (b *Builder) addField(name string, fieldType reflect.Type, tags []string) error {
+	if b.frozen() {
+		return fieldError("add", name, ErrInstanceAlreadyBuilt)
 	}
 
 	if _, ok := b.fields[name]; ok {
-		return ErrFieldAlreadyExists
+		return fieldError("add", name, ErrFieldAlreadyExists)
+	}
+
+	if canonical, ok := b.aliases[name]; ok {
+		return fieldError("add", name, fmt.Errorf("%w: alias of %s", ErrFieldAlreadyExists, canonical))
+	}
+
+	if err := b.checkAnonymousName(name); err != nil {
+		return fieldError("add", name, err)
+	}
+
+	tag, err := parseTags(tags)
+	if err != nil {
+		return fieldError("add", name, err)
+	}
+
+	tag = b.applyAutoTags(name, tag)
+
+	b.fields[name] = reflect.StructField{
+		Name: name,
+		Type: fieldType,
+		Tag:  tag,
+	}
+	b.order = append(b.order, name)
+	b.invalidate()
+
+	if len(b.positions) > 0 {
+		b.arrangeFields()
 	}
 
+	if b.group != "" {
+		b.setGroup(name, b.group)
+	}
+
+	return nil
+}
+
+func parseTags(tags []string) (reflect.StructTag, error) {
 	// Build tag string from variadic tags
 	var tag reflect.StructTag
 
 	if len(tags) > 0 {
 		tagString := strings.Join(tags, " ")
 
-		// Validate tag format using structtag library, but only if not empty
+		// Validate tag format, but only if not empty
 		if tagString != "" {
-			if _, err := structtag.Parse(tagString); err != nil {
-				return ErrInvalidTag
+			if err := checkTagSyntax(tagString); err != nil {
+				return "", err
 			}
 		}
 
 		tag = reflect.StructTag(tagString)
+
+		if err := validateTagValues(tag); err != nil {
+			return "", err
+		}
 	}
 
-	b.fields[name] = reflect.StructField{
-		Name: name,
-		Type: reflect.TypeOf(kind),
-		Tag:  tag,
+	return tag, nil
+}
+
+func (b *Builder) applyAutoTags(name string, tag reflect.StructTag) reflect.StructTag {
+	for _, auto := range b.autoTags {
+		if existing, ok := tag.Lookup(auto.key); ok {
+			if auto.merge != nil {
+				tag = replaceTagValue(tag, auto.key, auto.merge(existing, name))
+			}
+
+			continue
+		}
+
+		value := ""
+
+		if auto.mirror != "" {
+			mirrored, ok := tag.Lookup(auto.mirror)
+			if !ok {
+				continue
+			}
+
+			value = mirrored
+		} else {
+			value = auto.mapper(name)
+		}
+
+		autoTag := fmt.Sprintf("%s:%q", auto.key, value)
+		if tag != "" {
+			autoTag = " " + autoTag
+		}
+
+		tag += reflect.StructTag(autoTag)
 	}
 
-	return nil
+	return tag
+}
+
+// replaceTagValue returns tag with the value of key replaced by value.
+func replaceTagValue(tag reflect.StructTag, key, value string) reflect.StructTag {
+	parts := splitTag(tag)
+	raws := make([]string, len(parts))
+
+	for i, part := range parts {
+		raws[i] = part.raw
+		if part.key == key {
+			raws[i] = fmt.Sprintf("%s:%q", key, value)
+		}
+	}
+
+	return reflect.StructTag(strings.Join(raws, " "))
 }
 
 func (b *Builder) AddAnonymousField(fieldType any, tags ...string) error {
 	b.m.Lock()
 	defer b.m.Unlock()
 
-	if b.instance != nil {
-		return ErrInstanceAlreadyBuilt
+	fieldTypeReflect := reflect.TypeOf(fieldType)
+
+	if b.frozen() {
+		return fieldError("embed", fmt.Sprint(fieldTypeReflect), ErrInstanceAlreadyBuilt)
 	}
 
-	fieldTypeReflect := reflect.TypeOf(fieldType)
+	if fieldTypeReflect == nil {
+		return fieldError("embed", fmt.Sprint(fieldTypeReflect), fmt.Errorf("%w: nil type", ErrIncompatibleTypes))
+	}
+
+	// Embedded fields are named after their type, so different types of the
+	// same name, like pkg1.Config and pkg2.Config, can't both be embedded
+	return b.addAnonymous(anonymousFieldName(fieldTypeReflect), fieldTypeReflect, tags)
+}
+
+// addAnonymous embeds fieldTypeReflect as the field called name.
+func (b *Builder) addAnonymous(name string, fieldTypeReflect reflect.Type, tags []string) error {
+	// Like Go, only pointers to types that aren't pointers or interfaces can
+	// be embedded
+	if fieldTypeReflect != nil && fieldTypeReflect.Kind() == reflect.Ptr {
+		if kind := fieldTypeReflect.Elem().Kind(); kind == reflect.Ptr || kind == reflect.Interface {
+			return fieldError("embed", fieldTypeReflect.String(), fmt.Errorf("%w: embedded pointer to %s", ErrIncompatibleTypes, kind))
+		}
+	}
 
 	// Check if anonymous field of this type already exists
 	for _, field := range b.anonymousFields {
 		if field.Type == fieldTypeReflect {
-			return ErrAnonymousFieldAlreadyExists
+			return fieldError("embed", fmt.Sprint(fieldTypeReflect), ErrAnonymousFieldAlreadyExists)
 		}
 	}
 
-	// Build tag string from variadic tags
-	var tag reflect.StructTag
+	if err := b.checkAnonymousName(name); err != nil {
+		return fieldError("embed", fmt.Sprint(fieldTypeReflect), err)
+	}
 
-	if len(tags) > 0 {
-		tagString := strings.Join(tags, " ")
+	tag, err := parseTags(tags)
+	if err != nil {
+		return fieldError("embed", fmt.Sprint(fieldTypeReflect), err)
+	}
 
-		// Validate tag format using structtag library, but only if not empty
-		if tagString != "" {
-			if _, err := structtag.Parse(tagString); err != nil {
-				return ErrInvalidTag
-			}
+	b.anonymousFields = append(b.anonymousFields, reflect.StructField{
+		Name:      name,
+		Type:      fieldTypeReflect,
+		Tag:       tag,
+		Anonymous: true,
+	})
+	b.invalidate()
+
+	return nil
+}
+
+// checkAnonymousName returns ErrFieldNameConflict when an anonymous field or
+// a field is named name. Embedded fields are named after their type, and the
+// names have to be unique across both.
+func (b *Builder) checkAnonymousName(name string) error {
+	for _, field := range b.anonymousFields {
+		if field.Name == name {
+			return fmt.Errorf("%w: %s is embedded as %s", ErrFieldNameConflict, field.Type, name)
 		}
+	}
 
-		tag = reflect.StructTag(tagString)
+	if _, ok := b.fields[name]; ok {
+		return fmt.Errorf("%w: field %s", ErrFieldNameConflict, name)
+	}
+
+	return nil
+}
+
+func anonymousFieldName(fieldType reflect.Type) string {
+	// An embedded pointer is named after the type it points to
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
 	}
 
 	// Generate a unique name for the anonymous field
-	fieldName := fieldTypeReflect.Name()
+	fieldName := fieldType.Name()
 	if fieldName == "" {
 		// For built-in types like string, int, etc., use the type kind
-		fieldName = fieldTypeReflect.Kind().String()
+		fieldName = fieldType.Kind().String()
 	}
+
+	// Instantiated generic types are named like "List[int]", but an embedded
+	// field is named after the generic type itself, without type arguments
+	if i := strings.IndexByte(fieldName, '['); i > 0 {
+		fieldName = fieldName[:i]
+	}
+
 	// Ensure the name is exported (starts with uppercase)
 	if len(fieldName) > 0 && fieldName[0] >= 'a' && fieldName[0] <= 'z' {
 		fieldName = strings.ToUpper(fieldName[:1]) + fieldName[1:]
 	}
 
-	b.anonymousFields = append(b.anonymousFields, reflect.StructField{
-		Name:      fieldName,
-		Type:      fieldTypeReflect,
-		Tag:       tag,
-		Anonymous: true,
-	})
-
-	return nil
+	return fieldName
 }
 
 func (b *Builder) RemoveField(name string) error {
 	b.m.Lock()
 	defer b.m.Unlock()
 
-	if b.instance != nil {
-		return ErrInstanceAlreadyBuilt
+	if b.frozen() {
+		return fieldError("remove", name, ErrInstanceAlreadyBuilt)
 	}
 
-	delete(b.fields, name)
+	if _, ok := b.fields[name]; !ok {
+		return nil
+	}
+
+	b.removeField(name)
+	b.invalidate()
 
 	return nil
 }
 
+// removeField deletes the named field and everything recorded about it.
+func (b *Builder) removeField(name string) {
+	delete(b.fields, name)
+	delete(b.meta, name)
+	delete(b.nested, name)
+	delete(b.selfFields, name)
+
+	if b.unknownField == name {
+		b.unknownField = ""
+	}
+
+	for alias, canonical := range b.aliases {
+		if canonical == name {
+			delete(b.aliases, alias)
+		}
+	}
+
+	delete(b.positions, name)
+
+	for i, fieldName := range b.order {
+		if fieldName == name {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+
+			break
+		}
+	}
+}
+
 func (b *Builder) buildStructFields() []reflect.StructField {
 	totalFields := len(b.anonymousFields) + len(b.fields)
 	fields := make([]reflect.StructField, 0, totalFields)
@@ -133,15 +341,15 @@ func (b *Builder) buildStructFields() []reflect.StructField {
 	// Add anonymous fields first (as specified)
 	fields = append(fields, b.anonymousFields...)
 
-	// Add regular fields
-	for _, field := range b.fields {
-		fields = append(fields, field)
+	// Add regular fields in the order they were added
+	for _, name := range b.order {
+		fields = append(fields, b.fields[name])
 	}
 
 	return fields
 }
 
-func (b *Builder) Build() (any, error) {
+func (b *Builder) Build(opts ...BuildOption) (any, error) {
 	b.m.Lock()
 	defer b.m.Unlock()
 
@@ -149,59 +357,144 @@ func (b *Builder) Build() (any, error) {
 		return nil, ErrInstanceAlreadyBuilt
 	}
 
-	instance := reflect.New(
-		reflect.StructOf(b.buildStructFields()),
-	).Elem()
+	cfg := buildConfig{optimizeLayout: b.optimizeLayout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
-	b.instance = &instance
+	b.optimizeLayout = cfg.optimizeLayout
+
+	if err := b.build(); err != nil {
+		return nil, err
+	}
 
 	return b.instance.Interface(), nil
 }
 
+// build creates the instance from the definition. b.m must be held.
+func (b *Builder) build() error {
+	// Errors of chained calls fail the build
+	if err := b.chainErr(); err != nil {
+		return err
+	}
+
+	fields, err := b.resolveStructFields(nil)
+	if err != nil {
+		return err
+	}
+
+	if b.optimizeLayout {
+		optimizeLayout(fields)
+	}
+
+	structType, err := structOf(fields)
+	if err != nil {
+		return err
+	}
+
+	if err := b.checkInterfaces(structType); err != nil {
+		return err
+	}
+
+	instance := reflect.New(structType).Elem()
+
+	b.applyDefaults(instance)
+	b.applyNestedDefaults(instance)
+
+	if b.dropped != nil && b.keepValues {
+		migrateStruct(*b.dropped, instance)
+	}
+
+	b.dropped = nil
+
+	b.setInstance(instance)
+
+	return nil
+}
+
 func (b *Builder) Reset() {
 	b.m.Lock()
 	defer b.m.Unlock()
 
 	b.instance = nil
+	b.dropped = nil
+	b.index = nil
 	b.anonymousFields = nil
+	b.chainErrs = nil
+	b.foldIndex = nil
+}
+
+// InstanceValue returns the reflect.Value of the instance held by the builder.
+//
+// The value is addressable and settable, and it is the same instance that
+// GetField and GetFieldValue read from, so changes made through it are visible
+// to them. It stays valid until Reset is called. Access through the returned
+// value is not synchronized by the builder; callers sharing it between
+// goroutines must synchronize themselves.
+func (b *Builder) InstanceValue() (reflect.Value, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return reflect.Value{}, ErrInstanceNotBuilt
+	}
+
+	return *b.instance, nil
+}
+
+// InstanceAddr returns a pointer to the instance held by the builder, for use
+// with APIs such as json.Unmarshal that need a pointer. The same invariants as
+// for InstanceValue apply.
+func (b *Builder) InstanceAddr() (any, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	return b.instance.Addr().Interface(), nil
 }
 
 func (b *Builder) GetFieldValue(name string, value any) error {
-	b.m.Lock()
-	defer b.m.Unlock()
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.RLock()
+	defer b.values.RUnlock()
 
 	// Check if instance is built
 	if b.instance == nil {
-		return ErrInstanceNotBuilt
+		return fieldError("get", name, ErrInstanceNotBuilt)
 	}
 
 	valueReflect := reflect.ValueOf(value)
 
 	// Check if value is a pointer and not nil
 	if valueReflect.Kind() != reflect.Ptr {
-		return ErrValueMustBePointer
+		return fieldError("get", name, ErrValueMustBePointer)
 	}
 
 	// Check if value is not nil
 	if valueReflect.IsNil() {
-		return ErrValueCannotBeNil
+		return fieldError("get", name, ErrValueCannotBeNil)
 	}
 
 	// Get the field by name
-	field := b.instance.FieldByName(name)
-
-	if !field.IsValid() {
-		return ErrFieldNotFound
+	field, ok := b.instanceField(name)
+	if !ok {
+		return fieldError("get", name, missingField(b.instance.Type(), name))
 	}
 
 	// Check if the types are compatible
 	if field.Type() != valueReflect.Elem().Type() {
-		return fmt.Errorf(
+		return fieldError("get", name, fmt.Errorf(
 			"%w: field type: %s, value type: %s",
 			ErrIncompatibleTypes,
 			field.Type().String(),
 			valueReflect.Elem().Type().String(),
-		)
+		))
 	}
 
 	// Set the value
@@ -211,16 +504,18 @@ func (b *Builder) GetFieldValue(name string, value any) error {
 }
 
 func (b *Builder) GetAnonymousField(fieldType any) (any, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.RLock()
+	defer b.values.RUnlock()
+
+	fieldTypeReflect := reflect.TypeOf(fieldType)
 
 	// Check if instance is built
 	if b.instance == nil {
-		return nil, ErrInstanceNotBuilt
+		return nil, fieldError("get", fmt.Sprint(fieldTypeReflect), ErrInstanceNotBuilt)
 	}
 
-	fieldTypeReflect := reflect.TypeOf(fieldType)
-
 	// Find the anonymous field by type
 	structType := b.instance.Type()
 
@@ -232,32 +527,34 @@ func (b *Builder) GetAnonymousField(fieldType any) (any, error) {
 		}
 	}
 
-	return nil, ErrAnonymousFieldNotFound
+	return nil, fieldError("get", fmt.Sprint(fieldTypeReflect), ErrAnonymousFieldNotFound)
 }
 
 func (b *Builder) GetAnonymousFieldValue(fieldType any, value any) error {
-	b.m.Lock()
-	defer b.m.Unlock()
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.RLock()
+	defer b.values.RUnlock()
+
+	fieldTypeReflect := reflect.TypeOf(fieldType)
 
 	// Check if instance is built
 	if b.instance == nil {
-		return ErrInstanceNotBuilt
+		return fieldError("get", fmt.Sprint(fieldTypeReflect), ErrInstanceNotBuilt)
 	}
 
 	valueReflect := reflect.ValueOf(value)
 
 	// Check if value is a pointer and not nil
 	if valueReflect.Kind() != reflect.Ptr {
-		return ErrValueMustBePointer
+		return fieldError("get", fmt.Sprint(fieldTypeReflect), ErrValueMustBePointer)
 	}
 
 	// Check if value is not nil
 	if valueReflect.IsNil() {
-		return ErrValueCannotBeNil
+		return fieldError("get", fmt.Sprint(fieldTypeReflect), ErrValueCannotBeNil)
 	}
 
-	fieldTypeReflect := reflect.TypeOf(fieldType)
-
 	// Find the anonymous field by type
 	structType := b.instance.Type()
 	for i := 0; i < structType.NumField(); i++ {
@@ -268,12 +565,12 @@ func (b *Builder) GetAnonymousFieldValue(fieldType any, value any) error {
 
 			// Check if the types are compatible
 			if fieldValue.Type() != valueReflect.Elem().Type() {
-				return fmt.Errorf(
+				return fieldError("get", fmt.Sprint(fieldTypeReflect), fmt.Errorf(
 					"%w: field type: %s, value type: %s",
 					ErrIncompatibleTypes,
 					fieldValue.Type().String(),
 					valueReflect.Elem().Type().String(),
-				)
+				))
 			}
 
 			// Set the value
@@ -283,23 +580,24 @@ func (b *Builder) GetAnonymousFieldValue(fieldType any, value any) error {
 		}
 	}
 
-	return ErrAnonymousFieldNotFound
+	return fieldError("get", fmt.Sprint(fieldTypeReflect), ErrAnonymousFieldNotFound)
 }
 
 func (b *Builder) GetField(name string) (any, error) {
-	b.m.Lock()
-	defer b.m.Unlock()
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.RLock()
+	defer b.values.RUnlock()
 
 	// Check if instance is built
 	if b.instance == nil {
-		return nil, ErrInstanceNotBuilt
+		return nil, fieldError("get", name, ErrInstanceNotBuilt)
 	}
 
 	// Get the field by name
-	field := b.instance.FieldByName(name)
-
-	if !field.IsValid() {
-		return nil, ErrFieldNotFound
+	field, ok := b.instanceField(name)
+	if !ok {
+		return nil, fieldError("get", name, missingField(b.instance.Type(), name))
 	}
 
 	// Return the field value as interface{}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -2023,6 +2024,18 @@ func TestAddAnonymousField(t *testing.T) {
 			tags:      []string{},
 			wantErr:   nil,
 		},
+		{
+			name:      "add_person_pointer_anonymous_field",
+			fieldType: &PersonTest{},
+			tags:      []string{},
+			wantErr:   nil,
+		},
+		{
+			name:      "add_pointer_to_pointer_anonymous_field",
+			fieldType: new(*PersonTest),
+			tags:      []string{},
+			wantErr:   dynamicstruct.ErrIncompatibleTypes,
+		},
 		{
 			name:      "add_duplicate_anonymous_field",
 			fieldType: PersonTest{},
@@ -2095,6 +2108,71 @@ func TestAddAnonymousField(t *testing.T) {
 	}
 }
 
+func TestAnonymousFieldNameConflict(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(b *dynamicstruct.Builder) error
+	}{
+		{
+			name: "generic_instantiations",
+			setup: func(b *dynamicstruct.Builder) error {
+				_ = b.AddAnonymousField(ListTest[int]{})
+
+				return b.AddAnonymousField(ListTest[string]{})
+			},
+		},
+		{
+			name: "value_and_pointer",
+			setup: func(b *dynamicstruct.Builder) error {
+				_ = b.AddAnonymousField(PersonTest{})
+
+				return b.AddAnonymousField(&PersonTest{})
+			},
+		},
+		{
+			name: "embed_after_field",
+			setup: func(b *dynamicstruct.Builder) error {
+				_ = b.AddField("PersonTest", "")
+
+				return b.AddAnonymousField(PersonTest{})
+			},
+		},
+		{
+			name: "field_after_embed",
+			setup: func(b *dynamicstruct.Builder) error {
+				_ = b.AddAnonymousField(PersonTest{})
+
+				return b.AddField("PersonTest", "")
+			},
+		},
+		{
+			name: "rename_to_embed",
+			setup: func(b *dynamicstruct.Builder) error {
+				_ = b.AddAnonymousField(PersonTest{})
+				_ = b.AddField("Person", "")
+
+				return b.RenameField("Person", "PersonTest")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+
+			err := tt.setup(builder)
+			if !errors.Is(err, dynamicstruct.ErrFieldNameConflict) {
+				t.Errorf("error = %v, want %v", err, dynamicstruct.ErrFieldNameConflict)
+			}
+
+			// The conflicting field isn't added, so the builder still builds
+			if _, err := builder.Build(); err != nil {
+				t.Errorf("Build() error = %v", err)
+			}
+		})
+	}
+}
+
 func TestAnonymousFieldAfterBuild(t *testing.T) {
 	t.Run("add_anonymous_field_after_build", func(t *testing.T) {
 		builder := dynamicstruct.New()
@@ -2111,6 +2189,86 @@ func TestAnonymousFieldAfterBuild(t *testing.T) {
 	})
 }
 
+type GreeterTest struct {
+	Name string
+}
+
+func (g *GreeterTest) Greet() string {
+	return "Hello, " + g.Name
+}
+
+func TestAnonymousPointerField(t *testing.T) {
+	t.Run("named_after_element_type", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddAnonymousField(&PersonTest{})
+		_ = builder.AddField("ID", 0)
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		field := reflect.TypeOf(instance).Field(0)
+		if field.Name != "PersonTest" || !field.Anonymous {
+			t.Errorf("embedded field = %s (anonymous %v), want PersonTest", field.Name, field.Anonymous)
+		}
+
+		person, err := builder.GetAnonymousField(&PersonTest{})
+		if err != nil {
+			t.Fatalf("GetAnonymousField() error = %v", err)
+		}
+
+		if p, ok := person.(*PersonTest); !ok || p != nil {
+			t.Errorf("GetAnonymousField() = %#v, want nil *PersonTest", person)
+		}
+	})
+
+	t.Run("nil_pointer_access", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddAnonymousField(&PersonTest{})
+		_ = builder.AddField("ID", 0)
+		_, _ = builder.Build()
+
+		if _, err := builder.GetField("Name"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("GetField() through nil pointer error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+
+		if err := builder.SetFieldValue("Name", "Alice"); err != nil {
+			t.Fatalf("SetFieldValue() error = %v", err)
+		}
+
+		name, err := builder.GetField("Name")
+		if err != nil || name != "Alice" {
+			t.Errorf("GetField() = %v, %v, want Alice", name, err)
+		}
+	})
+
+	t.Run("promotes_pointer_methods", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddAnonymousField(&GreeterTest{})
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		greeter, ok := instance.(interface{ Greet() string })
+		if !ok {
+			t.Fatalf("instance %T doesn't promote Greet", instance)
+		}
+
+		_ = builder.SetFieldValue("Name", "Bob")
+
+		// reflect.StructOf promotes methods to the struct, not to pointers to it
+		value, _ := builder.InstanceValue()
+		greeter = value.Interface().(interface{ Greet() string })
+
+		if got := greeter.Greet(); got != "Hello, Bob" {
+			t.Errorf("Greet() = %q, want %q", got, "Hello, Bob")
+		}
+	})
+}
+
 func TestGetAnonymousField(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -2423,3 +2581,320 @@ func TestAnonymousFieldsIntegration(t *testing.T) {
 		}
 	})
 }
+
+type CompanyTest struct {
+	Name string
+	City string
+}
+
+func TestPromotedFields(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddAnonymousField(CompanyTest{})
+	_ = builder.AddAnonymousField(AddressTest{})
+	_ = builder.AddField("Age", "")
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		wantErr error
+	}{
+		{name: "promoted", field: "Street", wantErr: nil},
+		{name: "shadowed_by_direct_field", field: "Age", wantErr: nil},
+		{name: "ambiguous", field: "Name", wantErr: dynamicstruct.ErrAmbiguousField},
+		{name: "ambiguous_city", field: "City", wantErr: dynamicstruct.ErrAmbiguousField},
+		{name: "missing", field: "Email", wantErr: dynamicstruct.ErrFieldNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value string
+
+			err := builder.GetFieldValue(tt.field, &value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if _, err := builder.GetField(tt.field); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetField() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err := builder.SetFieldValue(tt.field, "x"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SetFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("promoted_value", func(t *testing.T) {
+		_ = builder.SetFieldValue("Street", "Main St")
+
+		var street string
+		if err := builder.GetFieldValue("Street", &street); err != nil || street != "Main St" {
+			t.Errorf("GetFieldValue() = %q, %v, want %q", street, err, "Main St")
+		}
+	})
+}
+
+type ListTest[T any] struct {
+	Items []T
+}
+
+type OptionalTest[T any] struct {
+	Value T
+	Valid bool
+}
+
+type PairTest[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func TestGenericFieldTypes(t *testing.T) {
+	t.Run("add_generic_fields", func(t *testing.T) {
+		builder := dynamicstruct.New()
+
+		err := builder.AddField("Numbers", ListTest[int]{}, `json:"numbers"`)
+		if err != nil {
+			t.Fatalf("AddField() error = %v", err)
+		}
+
+		err = builder.AddField("Nickname", OptionalTest[string]{}, `json:"nickname,omitempty"`)
+		if err != nil {
+			t.Fatalf("AddField() error = %v", err)
+		}
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		field, ok := reflect.TypeOf(instance).FieldByName("Numbers")
+		if !ok {
+			t.Fatal("Numbers field not found")
+		}
+		if field.Type != reflect.TypeOf(ListTest[int]{}) {
+			t.Errorf("Numbers field type = %v, want %v", field.Type, reflect.TypeOf(ListTest[int]{}))
+		}
+		if field.Tag.Get("json") != "numbers" {
+			t.Errorf("Numbers json tag = %q, want %q", field.Tag.Get("json"), "numbers")
+		}
+
+		var nickname OptionalTest[string]
+		err = builder.GetFieldValue("Nickname", &nickname)
+		if err != nil {
+			t.Errorf("GetFieldValue() error = %v", err)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		fieldType interface{}
+		tags      []string
+		wantName  string
+	}{
+		{
+			name:      "embed_list_of_int",
+			fieldType: ListTest[int]{},
+			wantName:  "ListTest",
+		},
+		{
+			name:      "embed_optional_of_string_with_tags",
+			fieldType: OptionalTest[string]{},
+			tags:      []string{`json:"optional"`},
+			wantName:  "OptionalTest",
+		},
+		{
+			name:      "embed_pair_with_named_type_argument",
+			fieldType: PairTest[string, PersonTest]{},
+			wantName:  "PairTest",
+		},
+		{
+			name:      "embed_nested_instantiation",
+			fieldType: ListTest[OptionalTest[int]]{},
+			wantName:  "ListTest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+
+			err := builder.AddAnonymousField(tt.fieldType, tt.tags...)
+			if err != nil {
+				t.Fatalf("AddAnonymousField() error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			field := reflect.TypeOf(instance).Field(0)
+			if field.Name != tt.wantName {
+				t.Errorf("Anonymous field name = %q, want %q", field.Name, tt.wantName)
+			}
+			if !field.Anonymous {
+				t.Error("Field should be anonymous")
+			}
+			if string(field.Tag) != strings.Join(tt.tags, " ") {
+				t.Errorf("Anonymous field tag = %q, want %q", field.Tag, strings.Join(tt.tags, " "))
+			}
+
+			value, err := builder.GetAnonymousField(tt.fieldType)
+			if err != nil {
+				t.Errorf("GetAnonymousField() error = %v", err)
+			}
+			if reflect.TypeOf(value) != reflect.TypeOf(tt.fieldType) {
+				t.Errorf("GetAnonymousField() type = %T, want %T", value, tt.fieldType)
+			}
+		})
+	}
+
+	t.Run("generic_embed_json_promotion", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddAnonymousField(OptionalTest[string]{})
+		_ = builder.AddField("ID", int(0), `json:"id"`)
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		data, err := json.Marshal(instance)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+
+		want := `{"Value":"","Valid":false,"id":0}`
+		if string(data) != want {
+			t.Errorf("json.Marshal() = %s, want %s", data, want)
+		}
+	})
+}
+
+func TestInstanceValue(t *testing.T) {
+	t.Run("not_built", func(t *testing.T) {
+		builder := dynamicstruct.New()
+
+		_, err := builder.InstanceValue()
+		if !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("InstanceValue() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+
+		_, err = builder.InstanceAddr()
+		if !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("InstanceAddr() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+
+	t.Run("value_is_addressable_and_shared", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Name", "")
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		value, err := builder.InstanceValue()
+		if err != nil {
+			t.Fatalf("InstanceValue() error = %v", err)
+		}
+
+		if !value.CanAddr() || !value.FieldByName("Name").CanSet() {
+			t.Fatal("InstanceValue() should be addressable and settable")
+		}
+
+		value.FieldByName("Name").SetString("Alice")
+
+		name, err := builder.GetField("Name")
+		if err != nil {
+			t.Fatalf("GetField() error = %v", err)
+		}
+		if name != "Alice" {
+			t.Errorf("GetField() = %v, want %v", name, "Alice")
+		}
+	})
+
+	t.Run("addr_can_be_unmarshaled_into", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Age", int(0), `json:"age"`)
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		instancePtr, err := builder.InstanceAddr()
+		if err != nil {
+			t.Fatalf("InstanceAddr() error = %v", err)
+		}
+
+		if err = json.Unmarshal([]byte(`{"age": 42}`), instancePtr); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+
+		var age int
+		if err = builder.GetFieldValue("Age", &age); err != nil {
+			t.Fatalf("GetFieldValue() error = %v", err)
+		}
+		if age != 42 {
+			t.Errorf("GetFieldValue() = %d, want 42", age)
+		}
+	})
+}
+
+func TestConcurrentInstances(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddFieldWithOptions("Name", "", dynamicstruct.Required())
+	_ = builder.AddField("Age", int(0))
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			instancePtr, err := builder.NewInstance()
+			if err != nil {
+				t.Errorf("NewInstance() error = %v", err)
+
+				return
+			}
+
+			value := reflect.ValueOf(instancePtr).Elem()
+
+			for j := 0; j < 100; j++ {
+				value.FieldByName("Age").SetInt(int64(i*100 + j))
+
+				if err := builder.CheckRequired(instancePtr); err == nil {
+					t.Error("CheckRequired() error = nil, want Name required")
+				}
+			}
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if err := builder.SetFieldValue("Age", i*100+j); err != nil {
+					t.Errorf("SetFieldValue() error = %v", err)
+				}
+
+				if _, err := builder.GetField("Age"); err != nil {
+					t.Errorf("GetField() error = %v", err)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
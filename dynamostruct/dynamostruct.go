@@ -0,0 +1,120 @@
+// Package dynamostruct converts dynamic struct instances to and from DynamoDB
+// items, with the semantics of the aws-sdk-go-v2 attributevalue package.
+package dynamostruct
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+// TagKey is the struct tag attributevalue reads attribute names and options
+// such as omitempty from.
+const TagKey = "dynamodbav"
+
+// WithDynamoDBTags adds a dynamodbav tag to fields that don't declare one,
+// naming their attribute with mapper, such as dynamicstruct.SnakeCase. A nil
+// mapper keeps the field name.
+func WithDynamoDBTags(mapper dynamicstruct.NameMapper) dynamicstruct.Option {
+	if mapper == nil {
+		mapper = func(name string) string { return name }
+	}
+
+	return dynamicstruct.WithAutoTags(mapper, TagKey)
+}
+
+// MarshalMap converts instance, a struct or pointer to struct, into a DynamoDB
+// item. Attributes are named and encoded as attributevalue.MarshalMap does:
+// by their dynamodbav tag, or their Go name without one, with tag options
+// such as omitempty, stringset or unixtime.
+func MarshalMap(instance any) (map[string]types.AttributeValue, error) {
+	value := reflect.ValueOf(instance)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, dynamicstruct.ErrValueCannotBeNil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, dynamicstruct.ErrInvalidInstance
+	}
+
+	return attributevalue.MarshalMap(instance)
+}
+
+// UnmarshalMap decodes the DynamoDB item into the struct instancePtr points
+// to, as attributevalue.UnmarshalMap does. Fields whose attributes the item
+// doesn't have keep their values.
+func UnmarshalMap(item map[string]types.AttributeValue, instancePtr any) error {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return dynamicstruct.ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return dynamicstruct.ErrValueCannotBeNil
+	}
+
+	if valueReflect.Elem().Kind() != reflect.Struct {
+		return dynamicstruct.ErrInvalidInstance
+	}
+
+	return attributevalue.UnmarshalMap(item, instancePtr)
+}
+
+// MarshalList converts the instances into DynamoDB items, for batch writes
+// and transactions. It stops at the first instance that doesn't convert.
+func MarshalList(instances []any) ([]map[string]types.AttributeValue, error) {
+	items := make([]map[string]types.AttributeValue, len(instances))
+
+	for i, instance := range instances {
+		item, err := MarshalMap(instance)
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+// UnmarshalList decodes the DynamoDB items, as returned by Query and Scan,
+// into new instances of the struct type instancePtr points to, and returns
+// pointers to them.
+func UnmarshalList(items []map[string]types.AttributeValue, instancePtr any) ([]any, error) {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return nil, dynamicstruct.ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return nil, dynamicstruct.ErrValueCannotBeNil
+	}
+
+	structType := valueReflect.Type().Elem()
+	if structType.Kind() != reflect.Struct {
+		return nil, dynamicstruct.ErrInvalidInstance
+	}
+
+	instances := make([]any, len(items))
+
+	for i, item := range items {
+		instance := reflect.New(structType).Interface()
+		if err := attributevalue.UnmarshalMap(item, instance); err != nil {
+			return nil, err
+		}
+
+		instances[i] = instance
+	}
+
+	return instances, nil
+}
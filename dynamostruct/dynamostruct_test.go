@@ -0,0 +1,153 @@
+package dynamostruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/gosmos-space/dynamicstruct/dynamostruct"
+)
+
+func newItemBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New(dynamostruct.WithDynamoDBTags(dynamicstruct.SnakeCase))
+	_ = builder.AddField("TenantID", "", `dynamodbav:"pk"`)
+	_ = builder.AddField("DisplayName", "")
+	_ = builder.AddField("Score", 0)
+	_ = builder.AddField("Labels", []string(nil), `dynamodbav:"labels,stringset,omitempty"`)
+	_ = builder.AddField("Note", "", `dynamodbav:",omitempty"`)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestMarshalMap(t *testing.T) {
+	builder := newItemBuilder(t)
+	_ = builder.SetFieldValue("TenantID", "acme")
+	_ = builder.SetFieldValue("DisplayName", "Acme")
+	_ = builder.SetFieldValue("Score", 7)
+	_ = builder.SetFieldValue("Labels", []string{"gold"})
+
+	instance, _ := builder.InstanceAddr()
+
+	item, err := dynamostruct.MarshalMap(instance)
+	if err != nil {
+		t.Fatalf("MarshalMap() error = %v", err)
+	}
+
+	want := map[string]types.AttributeValue{
+		"pk":           &types.AttributeValueMemberS{Value: "acme"},
+		"display_name": &types.AttributeValueMemberS{Value: "Acme"},
+		"score":        &types.AttributeValueMemberN{Value: "7"},
+		"labels":       &types.AttributeValueMemberSS{Value: []string{"gold"}},
+	}
+
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("MarshalMap() = %#v, want %#v", item, want)
+	}
+}
+
+func TestUnmarshalMap(t *testing.T) {
+	builder := newItemBuilder(t)
+	_ = builder.SetFieldValue("Note", "kept")
+
+	instance, _ := builder.InstanceAddr()
+
+	item := map[string]types.AttributeValue{
+		"pk":     &types.AttributeValueMemberS{Value: "acme"},
+		"score":  &types.AttributeValueMemberN{Value: "42"},
+		"labels": &types.AttributeValueMemberSS{Value: []string{"gold", "beta"}},
+	}
+
+	if err := dynamostruct.UnmarshalMap(item, instance); err != nil {
+		t.Fatalf("UnmarshalMap() error = %v", err)
+	}
+
+	value := reflect.ValueOf(instance).Elem()
+
+	if got := value.FieldByName("TenantID").String(); got != "acme" {
+		t.Errorf("TenantID = %q, want acme", got)
+	}
+
+	if got := value.FieldByName("Score").Int(); got != 42 {
+		t.Errorf("Score = %d, want 42", got)
+	}
+
+	if got := value.FieldByName("Labels").Interface(); !reflect.DeepEqual(got, []string{"gold", "beta"}) {
+		t.Errorf("Labels = %v, want [gold beta]", got)
+	}
+
+	if got := value.FieldByName("Note").String(); got != "kept" {
+		t.Errorf("Note = %q, want the value the item doesn't have kept", got)
+	}
+}
+
+func TestLists(t *testing.T) {
+	builder := newItemBuilder(t)
+
+	first, _ := builder.NewInstance()
+	reflect.ValueOf(first).Elem().FieldByName("TenantID").SetString("a")
+
+	second, _ := builder.NewInstance()
+	reflect.ValueOf(second).Elem().FieldByName("TenantID").SetString("b")
+
+	items, err := dynamostruct.MarshalList([]any{first, second})
+	if err != nil {
+		t.Fatalf("MarshalList() error = %v", err)
+	}
+
+	instances, err := dynamostruct.UnmarshalList(items, first)
+	if err != nil {
+		t.Fatalf("UnmarshalList() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(instances, []any{first, second}) {
+		t.Errorf("UnmarshalList() = %v, want %v", instances, []any{first, second})
+	}
+}
+
+func TestConversionErrors(t *testing.T) {
+	var nilPtr *struct{ Name string }
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"marshal_non_struct", func() error {
+			_, err := dynamostruct.MarshalMap(42)
+
+			return err
+		}, dynamicstruct.ErrInvalidInstance},
+		{"marshal_nil", func() error {
+			_, err := dynamostruct.MarshalMap(nilPtr)
+
+			return err
+		}, dynamicstruct.ErrValueCannotBeNil},
+		{"unmarshal_non_pointer", func() error {
+			return dynamostruct.UnmarshalMap(nil, struct{}{})
+		}, dynamicstruct.ErrValueMustBePointer},
+		{"unmarshal_nil", func() error {
+			return dynamostruct.UnmarshalMap(nil, nilPtr)
+		}, dynamicstruct.ErrValueCannotBeNil},
+		{"unmarshal_list_non_struct", func() error {
+			_, err := dynamostruct.UnmarshalList(nil, new(int))
+
+			return err
+		}, dynamicstruct.ErrInvalidInstance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
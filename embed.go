@@ -0,0 +1,140 @@
+package dynamicstruct
+
+import "reflect"
+
+// FlattenEmbedded replaces the embedded structs of the builder, added with
+// AddAnonymousField or FromStruct, with the fields they promote, as fields of
+// their own with the same types and tags. Encodings then see a flat struct
+// without relying on how each of them treats embedded structs: a struct
+// embedded with a json name, for one, is no longer encoded as an object.
+//
+// Fields are promoted as Go promotes them: fields of the builder shadow
+// promoted fields of the same name, shallower fields shadow deeper ones, and
+// names promoted from two embedded structs at the same depth are left out.
+// Unexported fields are left out too. The promoted fields come first, where
+// the embedded structs were. Embedded types that aren't structs stay
+// embedded, and methods promoted from the flattened structs are lost.
+//
+// It returns the names of the fields it added. If one can't be added, the
+// builder is left unchanged.
+func (b *Builder) FlattenEmbedded() ([]string, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.frozen() {
+		return nil, ErrInstanceAlreadyBuilt
+	}
+
+	var (
+		kept     []reflect.StructField
+		promoted []reflect.StructField
+	)
+
+	depths := make(map[string]int)
+	ambiguous := make(map[string]bool)
+
+	for _, field := range b.anonymousFields {
+		embeddedType := field.Type
+		if embeddedType.Kind() == reflect.Ptr {
+			embeddedType = embeddedType.Elem()
+		}
+
+		if embeddedType.Kind() != reflect.Struct {
+			kept = append(kept, field)
+
+			continue
+		}
+
+		for _, candidate := range promotedStructFields(embeddedType) {
+			depth, seen := depths[candidate.Name]
+
+			switch {
+			case !seen || len(candidate.Index) < depth:
+				depths[candidate.Name] = len(candidate.Index)
+				ambiguous[candidate.Name] = false
+				promoted = replaceStructField(promoted, candidate)
+			case len(candidate.Index) == depth:
+				ambiguous[candidate.Name] = true
+			}
+		}
+	}
+
+	if len(kept) == len(b.anonymousFields) {
+		return nil, nil
+	}
+
+	state := b.saveState()
+	instance := b.instance
+	order := b.order
+
+	b.anonymousFields = kept
+	b.order = nil
+
+	var added []string
+
+	for _, field := range promoted {
+		if ambiguous[field.Name] || b.hasField(field.Name) {
+			continue
+		}
+
+		if err := b.addField(field.Name, field.Type, []string{string(field.Tag)}); err != nil {
+			b.restoreState(state)
+			b.restoreInstance(instance)
+
+			return nil, err
+		}
+
+		added = append(added, field.Name)
+	}
+
+	b.order = append(b.order, order...)
+
+	if len(b.positions) > 0 {
+		b.arrangeFields()
+	}
+
+	b.invalidate()
+
+	return added, nil
+}
+
+// promotedStructFields returns the exported fields structType promotes, with
+// their depth as the length of their index, in field order. Embedded structs
+// are replaced by their fields.
+func promotedStructFields(structType reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+
+	for _, field := range reflect.VisibleFields(structType) {
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct {
+			continue
+		}
+
+		field.Anonymous = false
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// replaceStructField replaces the field of fields named like field, or
+// appends field when there is none.
+func replaceStructField(fields []reflect.StructField, field reflect.StructField) []reflect.StructField {
+	for i := range fields {
+		if fields[i].Name == field.Name {
+			fields[i] = field
+
+			return fields
+		}
+	}
+
+	return append(fields, field)
+}
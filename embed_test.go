@@ -0,0 +1,147 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+type embedTimestamps struct {
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type embedBase struct {
+	embedTimestamps
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	note string
+}
+
+// EmbedLabel is exported, as reflect.StructOf can't embed unexported
+// non-struct types.
+type EmbedLabel string
+
+type embedOwner struct {
+	Name  string `json:"owner_name"`
+	Email string `json:"email"`
+}
+
+func TestFlattenEmbedded(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(embedBase{}, `json:"base"`)
+	_ = builder.AddAnonymousField(&embedOwner{})
+	_ = builder.AddAnonymousField(EmbedLabel(""))
+	_ = builder.AddField("Email", "", `json:"contact_email"`)
+
+	added, err := builder.FlattenEmbedded()
+	if err != nil {
+		t.Fatalf("FlattenEmbedded() error = %v", err)
+	}
+
+	// Name is promoted by both structs at the same depth, and Email is
+	// shadowed by the builder's own field
+	if want := []string{"CreatedAt", "UpdatedAt", "ID"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("FlattenEmbedded() = %v, want %v", added, want)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	if want := []string{"EmbedLabel", "CreatedAt", "UpdatedAt", "ID", "Email"}; !reflect.DeepEqual(fieldNames(structType), want) {
+		t.Errorf("Build() fields = %v, want %v", fieldNames(structType), want)
+	}
+
+	if field, _ := structType.FieldByName("EmbedLabel"); !field.Anonymous {
+		t.Error("EmbedLabel isn't embedded, want non-struct embeds kept")
+	}
+
+	if field, _ := structType.FieldByName("CreatedAt"); field.Anonymous || field.Tag != `json:"created_at"` {
+		t.Errorf("CreatedAt = %+v, want a field with its tag", field)
+	}
+
+	_ = builder.SetFieldValue("ID", 7)
+	_ = builder.SetFieldValue("CreatedAt", "2024-01-02")
+
+	data, _ := builder.MarshalInstance()
+
+	if want := `{"EmbedLabel":"","created_at":"2024-01-02","updated_at":"","id":7,"contact_email":""}`; string(data) != want {
+		t.Errorf("MarshalInstance() = %s, want %s", data, want)
+	}
+}
+
+func TestFlattenEmbeddedShallowerWins(t *testing.T) {
+	type deep struct {
+		embedTimestamps
+	}
+
+	type shallow struct {
+		CreatedAt int `json:"created"`
+	}
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(deep{})
+	_ = builder.AddAnonymousField(shallow{})
+
+	if _, err := builder.FlattenEmbedded(); err != nil {
+		t.Fatalf("FlattenEmbedded() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	data, _ := json.Marshal(instance)
+	if want := `{"created":0,"updated_at":""}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestFlattenEmbeddedErrors(t *testing.T) {
+	t.Run("nothing_embedded", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Name", "")
+
+		if added, err := builder.FlattenEmbedded(); err != nil || added != nil {
+			t.Errorf("FlattenEmbedded() = %v, %v, want nil, nil", added, err)
+		}
+	})
+
+	t.Run("built", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddAnonymousField(embedOwner{})
+		_, _ = builder.Build()
+
+		if _, err := builder.FlattenEmbedded(); !errors.Is(err, dynamicstruct.ErrInstanceAlreadyBuilt) {
+			t.Errorf("FlattenEmbedded() error = %v, want %v", err, dynamicstruct.ErrInstanceAlreadyBuilt)
+		}
+	})
+
+	t.Run("alias_conflict_leaves_builder", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddAnonymousField(embedOwner{})
+		_ = builder.AddField("Mail", "")
+		_ = builder.AddFieldAlias("Mail", "Email")
+
+		if _, err := builder.FlattenEmbedded(); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Fatalf("FlattenEmbedded() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if want := []string{"EmbedOwner", "Mail"}; !reflect.DeepEqual(fieldNames(reflect.TypeOf(instance)), want) {
+			t.Errorf("Build() fields = %v, want %v", fieldNames(reflect.TypeOf(instance)), want)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package dynamicstruct
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const csvTagKey = "csv"
+
+// Format is the encoding an Encoder writes a sequence of instances in.
+type Format int
+
+const (
+	// FormatJSON writes the instances as the elements of a JSON array.
+	FormatJSON Format = iota
+	// FormatNDJSON writes one JSON object per line.
+	FormatNDJSON
+	// FormatCSV writes a header row and one row per instance.
+	FormatCSV
+)
+
+// Encoder writes a sequence of instances of a built type to a writer, one at
+// a time, so exports don't hold the whole collection in memory. Every call to
+// Encode writes the instance through to the writer. Close finishes the
+// output, and must be called once all instances are encoded. An Encoder isn't
+// safe for concurrent use.
+type Encoder struct {
+	builder *Builder
+	w       io.Writer
+	format  Format
+	count   int
+	csv     *csv.Writer
+	columns []dbColumn
+}
+
+// NewEncoder returns an Encoder writing instances of the built type to w in
+// format. Instances are encoded to JSON as EncodeJSON encodes them.
+//
+// CSV columns are named by the csv tag of each field, or by its json name,
+// and fields tagged csv:"-" or json:"-" are left out. Fields of embedded
+// structs are promoted. Strings and numbers are written as they are, times in
+// RFC 3339, nil pointers as empty cells and structs, slices and maps as JSON.
+func (b *Builder) NewEncoder(w io.Writer, format Format) (*Encoder, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	e := &Encoder{builder: b, w: w, format: format}
+
+	switch format {
+	case FormatJSON, FormatNDJSON:
+	case FormatCSV:
+		e.csv = csv.NewWriter(w)
+		e.columns = csvColumns(b.instance.Type(), nil)
+	default:
+		return nil, fmt.Errorf("unknown encoder format %d", format)
+	}
+
+	return e, nil
+}
+
+// Encode writes instance, an instance of the built type or a pointer to one.
+func (e *Encoder) Encode(instance any) error {
+	if e.format == FormatCSV {
+		return e.encodeCSV(instance)
+	}
+
+	data, err := e.builder.EncodeJSON(instance)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case e.format == FormatNDJSON:
+		data = append(data, '\n')
+	case e.count == 0:
+		data = append([]byte{'['}, data...)
+	default:
+		data = append([]byte{','}, data...)
+	}
+
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+
+	e.count++
+
+	return nil
+}
+
+func (e *Encoder) encodeCSV(instance any) error {
+	value, err := structValue(instance)
+	if err != nil {
+		return err
+	}
+
+	e.builder.m.RLock()
+	structType := e.builder.instance.Type()
+	e.builder.m.RUnlock()
+
+	if value.Type() != structType {
+		return fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			structType.String(),
+		)
+	}
+
+	if err := e.writeCSVHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(e.columns))
+
+	for i, column := range e.columns {
+		field, err := value.FieldByIndexErr(column.index)
+		if err != nil {
+			continue
+		}
+
+		if record[i], err = csvString(field); err != nil {
+			return fieldError("get", column.name, err)
+		}
+	}
+
+	if err := e.csv.Write(record); err != nil {
+		return err
+	}
+
+	e.count++
+	e.csv.Flush()
+
+	return e.csv.Error()
+}
+
+// writeCSVHeader writes the header row before the first record.
+func (e *Encoder) writeCSVHeader() error {
+	if e.count > 0 {
+		return nil
+	}
+
+	header := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		header[i] = column.name
+	}
+
+	return e.csv.Write(header)
+}
+
+// Close finishes the output: it closes the JSON array, or writes the CSV
+// header when no instance was encoded. It doesn't close the writer.
+func (e *Encoder) Close() error {
+	switch e.format {
+	case FormatJSON:
+		closing := "]"
+		if e.count == 0 {
+			closing = "[]"
+		}
+
+		_, err := io.WriteString(e.w, closing)
+
+		return err
+	case FormatCSV:
+		if err := e.writeCSVHeader(); err != nil {
+			return err
+		}
+
+		e.csv.Flush()
+
+		return e.csv.Error()
+	default:
+		return nil
+	}
+}
+
+// csvColumns returns the CSV columns of structType, with the index of their
+// field below the field at index.
+func csvColumns(structType reflect.Type, index []int) []dbColumn {
+	var columns []dbColumn
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+
+		if field.Anonymous && embedded.Kind() == reflect.Struct && field.Tag.Get(csvTagKey) == "" {
+			if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "" {
+				columns = append(columns, csvColumns(embedded, fieldIndex)...)
+
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if _, hasCSVTag := field.Tag.Lookup(csvTagKey); hasCSVTag {
+			name, ok = tagName(field, csvTagKey)
+		}
+
+		if !ok {
+			continue
+		}
+
+		columns = append(columns, dbColumn{name: name, index: fieldIndex})
+	}
+
+	return columns
+}
+
+func csvString(value reflect.Value) (string, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", nil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Type() == timeType && value.CanInterface() {
+		t, _ := value.Interface().(time.Time)
+
+		return t.Format(time.RFC3339Nano), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	default:
+		// Fields promoted from unexported embedded structs can't be read
+		if !value.CanInterface() {
+			return "", nil
+		}
+
+		data, err := json.Marshal(value.Interface())
+
+		return string(data), err
+	}
+}
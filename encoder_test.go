@@ -0,0 +1,162 @@
+package dynamicstruct_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestNewEncoder(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+	_ = builder.AddField("Name", "", `json:"name"`, `csv:"full_name"`)
+	_ = builder.AddOptionalField("Score", float64(0), `json:"score"`)
+	_ = builder.AddField("Tags", []string(nil), `json:"tags"`)
+	_ = builder.AddField("Created", time.Time{}, `json:"created"`)
+	_ = builder.AddField("Secret", "", `json:"-"`)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	score := 9.5
+
+	var instances []any
+
+	for i, name := range []string{"Alice", "Bob, Jr."} {
+		instancePtr, _ := builder.NewInstance()
+		value := reflect.ValueOf(instancePtr).Elem()
+		value.FieldByName("ID").SetInt(int64(i + 1))
+		value.FieldByName("Name").SetString(name)
+		value.FieldByName("Created").Set(reflect.ValueOf(created))
+		value.FieldByName("Secret").SetString("s3cr3t")
+
+		if i == 0 {
+			value.FieldByName("Score").Set(reflect.ValueOf(&score))
+			value.FieldByName("Tags").Set(reflect.ValueOf([]string{"a", "b"}))
+		}
+
+		instances = append(instances, instancePtr)
+	}
+
+	tests := []struct {
+		name   string
+		format dynamicstruct.Format
+		want   string
+		// wantEmpty is the output without instances
+		wantEmpty string
+	}{
+		{
+			name:   "json",
+			format: dynamicstruct.FormatJSON,
+			want: `[{"id":1,"name":"Alice","score":9.5,"tags":["a","b"],"created":"2024-05-01T12:00:00Z"},` +
+				`{"id":2,"name":"Bob, Jr.","tags":null,"created":"2024-05-01T12:00:00Z"}]`,
+			wantEmpty: "[]",
+		},
+		{
+			name:   "ndjson",
+			format: dynamicstruct.FormatNDJSON,
+			want: `{"id":1,"name":"Alice","score":9.5,"tags":["a","b"],"created":"2024-05-01T12:00:00Z"}` + "\n" +
+				`{"id":2,"name":"Bob, Jr.","tags":null,"created":"2024-05-01T12:00:00Z"}` + "\n",
+			wantEmpty: "",
+		},
+		{
+			name:   "csv",
+			format: dynamicstruct.FormatCSV,
+			want: "id,full_name,score,tags,created\n" +
+				"1,Alice,9.5,\"[\"\"a\"\",\"\"b\"\"]\",2024-05-01T12:00:00Z\n" +
+				"2,\"Bob, Jr.\",,null,2024-05-01T12:00:00Z\n",
+			wantEmpty: "id,full_name,score,tags,created\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			encoder, err := builder.NewEncoder(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("NewEncoder() error = %v", err)
+			}
+
+			for i, instancePtr := range instances {
+				if err := encoder.Encode(instancePtr); err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+
+				// Each instance is written through before the next one
+				if i == 0 && buf.Len() == 0 {
+					t.Error("Encode() wrote nothing")
+				}
+			}
+
+			if err := encoder.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+
+			buf.Reset()
+
+			encoder, _ = builder.NewEncoder(&buf, tt.format)
+			if err := encoder.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if buf.String() != tt.wantEmpty {
+				t.Errorf("empty output = %q, want %q", buf.String(), tt.wantEmpty)
+			}
+		})
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		encoder, _ := builder.NewEncoder(&buf, dynamicstruct.FormatNDJSON)
+		for _, instancePtr := range instances {
+			_ = encoder.Encode(instancePtr)
+		}
+
+		decoder, _ := builder.NewDecoder(strings.NewReader(buf.String()))
+
+		n := 0
+		for decoder.Next() {
+			n++
+		}
+
+		if n != len(instances) || decoder.Err() != nil {
+			t.Errorf("decoded %d instances, err = %v, want %d", n, decoder.Err(), len(instances))
+		}
+	})
+}
+
+func TestNewEncoderErrors(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0))
+
+	if _, err := builder.NewEncoder(&bytes.Buffer{}, dynamicstruct.FormatJSON); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("NewEncoder() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	_, _ = builder.Build()
+
+	if _, err := builder.NewEncoder(&bytes.Buffer{}, dynamicstruct.Format(42)); err == nil {
+		t.Error("NewEncoder() error = nil, want error for unknown format")
+	}
+
+	for _, format := range []dynamicstruct.Format{dynamicstruct.FormatJSON, dynamicstruct.FormatCSV} {
+		encoder, _ := builder.NewEncoder(&bytes.Buffer{}, format)
+
+		if err := encoder.Encode(struct{ Other int64 }{}); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("Encode() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	}
+}
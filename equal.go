@@ -0,0 +1,123 @@
+package dynamicstruct
+
+import (
+	"reflect"
+)
+
+// Equal reports whether a and b are deeply equal like reflect.DeepEqual,
+// except that values of distinct but structurally identical struct types are
+// compared field by field. Two struct types are structurally identical when
+// they have the same exported fields, matched by name, with identical or
+// structurally identical types; tags and field order are ignored. This makes
+// instances from separate builders that produced the same shape comparable.
+func Equal(a, b any) bool {
+	return equalValues(reflect.ValueOf(a), reflect.ValueOf(b), make(map[[2]uintptr]bool))
+}
+
+func equalValues(a, b reflect.Value, visited map[[2]uintptr]bool) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if a.Type() == b.Type() {
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+
+	// Distinct named types are never equal, only unnamed composite types and
+	// struct types built with reflect.StructOf are compared structurally
+	if a.Kind() != b.Kind() || a.Type().Name() != "" || b.Type().Name() != "" {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		return equalStructs(a, b, visited)
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		// Pointers already being compared are assumed equal, which ends the
+		// recursion for cyclic values
+		pair := [2]uintptr{a.Pointer(), b.Pointer()}
+		if visited[pair] {
+			return true
+		}
+
+		visited[pair] = true
+
+		return equalValues(a.Elem(), b.Elem(), visited)
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		return equalValues(a.Elem(), b.Elem(), visited)
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			return false
+		}
+
+		return equalElements(a, b, visited)
+	case reflect.Array:
+		return equalElements(a, b, visited)
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() || a.Type().Key() != b.Type().Key() {
+			return false
+		}
+
+		iter := a.MapRange()
+		for iter.Next() {
+			bValue := b.MapIndex(iter.Key())
+			if !bValue.IsValid() || !equalValues(iter.Value(), bValue, visited) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+func equalStructs(a, b reflect.Value, visited map[[2]uintptr]bool) bool {
+	aType, bType := a.Type(), b.Type()
+	if aType.NumField() != bType.NumField() {
+		return false
+	}
+
+	for i := 0; i < aType.NumField(); i++ {
+		aField := aType.Field(i)
+
+		// Unexported fields can't be read, so their structs are only equal to
+		// values of the same type
+		if aField.PkgPath != "" {
+			return false
+		}
+
+		bField, ok := bType.FieldByName(aField.Name)
+		if !ok || len(bField.Index) != 1 || bField.Anonymous != aField.Anonymous {
+			return false
+		}
+
+		if !equalValues(a.Field(i), b.Field(bField.Index[0]), visited) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalElements(a, b reflect.Value, visited map[[2]uintptr]bool) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	for i := 0; i < a.Len(); i++ {
+		if !equalValues(a.Index(i), b.Index(i), visited) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,194 @@
+package dynamicstruct_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newEqualInstance(t *testing.T, setup func(b *dynamicstruct.Builder)) reflect.Value {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	setup(builder)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.New(reflect.TypeOf(instance)).Elem()
+}
+
+func newAddressBuilder(b *dynamicstruct.Builder) {
+	_ = b.AddField("City", "")
+}
+
+func newTaggedAddressBuilder(b *dynamicstruct.Builder) {
+	_ = b.AddField("City", "", `json:"city"`)
+}
+
+func TestEqual(t *testing.T) {
+	nested := func(tag string) func(b *dynamicstruct.Builder) {
+		return func(b *dynamicstruct.Builder) {
+			address := dynamicstruct.New()
+			newAddressBuilder(address)
+
+			addressInstance, _ := address.Build()
+
+			_ = b.AddField("Name", "", tag)
+			_ = b.AddField("Age", 0)
+			_ = b.AddField("Address", addressInstance)
+		}
+	}
+
+	fill := func(v reflect.Value, name, city string) reflect.Value {
+		v.FieldByName("Name").SetString(name)
+		v.FieldByName("Age").SetInt(30)
+		v.FieldByName("Address").FieldByName("City").SetString(city)
+
+		return v
+	}
+
+	tests := []struct {
+		name string
+		a, b any
+		want bool
+	}{
+		{
+			name: "same_type_equal",
+			a:    fill(newEqualInstance(t, nested(`json:"name"`)), "Alice", "Oslo").Interface(),
+			b:    fill(newEqualInstance(t, nested(`json:"name"`)), "Alice", "Oslo").Interface(),
+			want: true,
+		},
+		{
+			name: "different_builders_and_tags",
+			a:    fill(newEqualInstance(t, nested(`json:"name"`)), "Alice", "Oslo").Interface(),
+			b:    fill(newEqualInstance(t, nested(`json:"full_name"`)), "Alice", "Oslo").Interface(),
+			want: true,
+		},
+		{
+			name: "nested_value_differs",
+			a:    fill(newEqualInstance(t, nested(`json:"name"`)), "Alice", "Oslo").Interface(),
+			b:    fill(newEqualInstance(t, nested(`json:"full_name"`)), "Alice", "Bergen").Interface(),
+			want: false,
+		},
+		{
+			name: "pointers_to_instances",
+			a:    fill(newEqualInstance(t, nested(`json:"name"`)), "Alice", "Oslo").Addr().Interface(),
+			b:    fill(newEqualInstance(t, nested(`json:"full_name"`)), "Alice", "Oslo").Addr().Interface(),
+			want: true,
+		},
+		{
+			name: "field_order_ignored",
+			a: newEqualInstance(t, func(b *dynamicstruct.Builder) {
+				_ = b.AddField("A", "")
+				_ = b.AddField("B", 0)
+			}).Interface(),
+			b: newEqualInstance(t, func(b *dynamicstruct.Builder) {
+				_ = b.AddField("B", 0)
+				_ = b.AddField("A", "")
+			}).Interface(),
+			want: true,
+		},
+		{
+			name: "field_type_differs",
+			a: newEqualInstance(t, func(b *dynamicstruct.Builder) {
+				_ = b.AddField("A", 0)
+			}).Interface(),
+			b: newEqualInstance(t, func(b *dynamicstruct.Builder) {
+				_ = b.AddField("A", int64(0))
+			}).Interface(),
+			want: false,
+		},
+		{
+			name: "extra_field",
+			a: newEqualInstance(t, func(b *dynamicstruct.Builder) {
+				_ = b.AddField("A", 0)
+			}).Interface(),
+			b: newEqualInstance(t, func(b *dynamicstruct.Builder) {
+				_ = b.AddField("A", 0)
+				_ = b.AddField("B", 0)
+			}).Interface(),
+			want: false,
+		},
+		{
+			name: "named_struct_and_dynamic_struct",
+			a:    PersonTest{},
+			b: newEqualInstance(t, func(b *dynamicstruct.Builder) {
+				_ = b.AddField("Name", "")
+				_ = b.AddField("Age", 0)
+			}).Interface(),
+			want: false,
+		},
+		{
+			name: "nil_values",
+			a:    nil,
+			b:    nil,
+			want: true,
+		},
+		{
+			name: "nil_and_value",
+			a:    nil,
+			b:    PersonTest{},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dynamicstruct.Equal(tt.a, tt.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqualCollections(t *testing.T) {
+	first := newEqualInstance(t, newAddressBuilder)
+	second := newEqualInstance(t, newTaggedAddressBuilder)
+
+	first.FieldByName("City").SetString("Oslo")
+	second.FieldByName("City").SetString("Oslo")
+
+	tests := []struct {
+		name string
+		a, b reflect.Value
+		want bool
+	}{
+		{
+			name: "slices",
+			a:    reflect.Append(reflect.MakeSlice(reflect.SliceOf(first.Type()), 0, 1), first),
+			b:    reflect.Append(reflect.MakeSlice(reflect.SliceOf(second.Type()), 0, 1), second),
+			want: true,
+		},
+		{
+			name: "maps",
+			a:    mapWith(first),
+			b:    mapWith(second),
+			want: true,
+		},
+		{
+			name: "nil_and_empty_slice",
+			a:    reflect.Zero(reflect.SliceOf(first.Type())),
+			b:    reflect.MakeSlice(reflect.SliceOf(second.Type()), 0, 0),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dynamicstruct.Equal(tt.a.Interface(), tt.b.Interface()); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mapWith(value reflect.Value) reflect.Value {
+	m := reflect.MakeMap(reflect.MapOf(reflect.TypeOf(""), value.Type()))
+	m.SetMapIndex(reflect.ValueOf("home"), value)
+
+	return m
+}
@@ -13,4 +13,52 @@ var (
 	ErrInvalidTag                  = errors.New("invalid struct tag format")
 	ErrAnonymousFieldAlreadyExists = errors.New("anonymous field of this type already exists")
 	ErrAnonymousFieldNotFound      = errors.New("anonymous field not found")
+	ErrInvalidSample               = errors.New("invalid sample")
+	ErrInvalidInstance             = errors.New("instance must be a struct")
+	ErrInvalidCursor               = errors.New("invalid cursor")
+	ErrInvalidSchema               = errors.New("invalid schema")
+	ErrInvalidName                 = errors.New("invalid name")
+	ErrValidation                  = errors.New("validation failed")
+	ErrUnsupported                 = errors.New("not supported on this platform")
+	ErrSchemaAlreadyExists         = errors.New("schema already exists")
+	ErrSchemaNotFound              = errors.New("schema not found")
+	ErrInvalidReference            = errors.New("invalid reference")
+	ErrReadOnly                    = errors.New("instance is read-only")
+	ErrNotImplemented              = errors.New("interface not implemented")
+	ErrAmbiguousField              = errors.New("ambiguous field")
+	ErrFieldNameConflict           = errors.New("field name conflicts with another field")
+	ErrInvalidPatch                = errors.New("invalid patch")
+	ErrPatchTestFailed             = errors.New("patch test failed")
+	ErrInvalidExpression           = errors.New("invalid expression")
+	ErrInvalidMessage              = errors.New("invalid message")
+	ErrConflictingTypes            = errors.New("conflicting types in samples")
+	ErrUnknownField                = errors.New("unknown field")
+	ErrCircularReference           = errors.New("circular reference")
+	ErrInvalidPosition             = errors.New("invalid field position")
 )
+
+// FieldError records the operation and the field an error happened on. It
+// wraps one of the errors above, so errors.Is keeps working on it. Field is
+// the field's name, or the type of an embedded field.
+type FieldError struct {
+	Field string
+	Op    string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Op + " " + e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldError wraps err in a FieldError, or returns nil when err is nil.
+func fieldError(op, field string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &FieldError{Field: field, Op: op, Err: err}
+}
@@ -0,0 +1,118 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestFieldError(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Age", 0)
+
+	tests := []struct {
+		name      string
+		call      func() error
+		wantErr   error
+		wantField string
+		wantOp    string
+		wantMsg   string
+	}{
+		{
+			name:      "add_duplicate",
+			call:      func() error { return builder.AddField("Name", "") },
+			wantErr:   dynamicstruct.ErrFieldAlreadyExists,
+			wantField: "Name",
+			wantOp:    "add",
+			wantMsg:   "add Name: field already exists",
+		},
+		{
+			name:      "add_invalid_tag",
+			call:      func() error { return builder.AddField("Email", "", `json:"email`) },
+			wantErr:   dynamicstruct.ErrInvalidTag,
+			wantField: "Email",
+			wantOp:    "add",
+		},
+		{
+			name: "set_incompatible",
+			call: func() error {
+				_, _ = builder.Build()
+
+				return builder.SetFieldValue("Age", "42")
+			},
+			wantErr:   dynamicstruct.ErrIncompatibleTypes,
+			wantField: "Age",
+			wantOp:    "set",
+			wantMsg:   "set Age: incompatible types of value and field: field type: int, value type: string",
+		},
+		{
+			name: "get_missing",
+			call: func() error {
+				_, err := builder.GetField("Missing")
+
+				return err
+			},
+			wantErr:   dynamicstruct.ErrFieldNotFound,
+			wantField: "Missing",
+			wantOp:    "get",
+			wantMsg:   "get Missing: field not found",
+		},
+		{
+			name: "get_value_incompatible",
+			call: func() error {
+				var name int
+
+				return builder.GetFieldValue("Name", &name)
+			},
+			wantErr:   dynamicstruct.ErrIncompatibleTypes,
+			wantField: "Name",
+			wantOp:    "get",
+		},
+		{
+			name:      "remove_built",
+			call:      func() error { return builder.RemoveField("Age") },
+			wantErr:   dynamicstruct.ErrInstanceAlreadyBuilt,
+			wantField: "Age",
+			wantOp:    "remove",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			var fieldErr *dynamicstruct.FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("error = %T, want *FieldError", err)
+			}
+
+			if fieldErr.Field != tt.wantField || fieldErr.Op != tt.wantOp {
+				t.Errorf("FieldError = {%s %s}, want {%s %s}", fieldErr.Field, fieldErr.Op, tt.wantField, tt.wantOp)
+			}
+
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestFieldErrorAnonymous(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+
+	err := builder.AddAnonymousField(PersonTest{})
+	if !errors.Is(err, dynamicstruct.ErrAnonymousFieldAlreadyExists) {
+		t.Fatalf("AddAnonymousField() error = %v, want %v", err, dynamicstruct.ErrAnonymousFieldAlreadyExists)
+	}
+
+	want := "embed dynamicstruct_test.PersonTest: anonymous field of this type already exists"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,551 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Eval evaluates expression against the fields of instance, a struct or a
+// pointer to one, and returns its value. The expression uses Go syntax, with
+// fields as variables:
+//
+//	Age >= 18 && Country == "US"
+//	Address.City == "Berlin" || len(Tags) > 2
+//
+// Supported are literals, true, false and nil, field and selector access
+// (including promoted fields), indexing of slices, arrays, strings and maps,
+// len, parentheses and the arithmetic, comparison and logical operators.
+// Integers are evaluated as int64 and floats as float64, and mixing them
+// converts to float64. Selecting a field through a nil pointer evaluates to
+// nil. As in Go, nil slices and maps equal nil, and comparisons with NaN are
+// false.
+//
+// Syntax errors and unsupported constructs return ErrInvalidExpression,
+// unknown fields ErrFieldNotFound or ErrAmbiguousField, and operands of the
+// wrong type ErrIncompatibleTypes.
+func Eval(instance any, expression string) (any, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExpression, err.Error())
+	}
+
+	return evalExpr(value, expr)
+}
+
+func evalExpr(root reflect.Value, expr ast.Expr) (any, error) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return evalExpr(root, e.X)
+	case *ast.BasicLit:
+		return evalLiteral(e)
+	case *ast.Ident:
+		return evalIdent(root, e.Name)
+	case *ast.SelectorExpr:
+		x, err := evalExpr(root, e.X)
+		if err != nil {
+			return nil, err
+		}
+
+		return evalSelector(x, e.Sel.Name)
+	case *ast.IndexExpr:
+		return evalIndex(root, e)
+	case *ast.CallExpr:
+		return evalCall(root, e)
+	case *ast.UnaryExpr:
+		return evalUnary(root, e)
+	case *ast.BinaryExpr:
+		return evalBinary(root, e)
+	default:
+		return nil, fmt.Errorf("%w: unsupported expression %T", ErrInvalidExpression, expr)
+	}
+}
+
+func evalLiteral(lit *ast.BasicLit) (any, error) {
+	switch lit.Kind {
+	case token.INT:
+		n, err := strconv.ParseInt(lit.Value, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExpression, err.Error())
+		}
+
+		return n, nil
+	case token.FLOAT:
+		f, err := strconv.ParseFloat(lit.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExpression, err.Error())
+		}
+
+		return f, nil
+	case token.STRING:
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExpression, err.Error())
+		}
+
+		return s, nil
+	case token.CHAR:
+		r, _, _, err := strconv.UnquoteChar(lit.Value[1:len(lit.Value)-1], '\'')
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExpression, err.Error())
+		}
+
+		return int64(r), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported literal %s", ErrInvalidExpression, lit.Value)
+	}
+}
+
+// evalIdent resolves name as a field of root, falling back to the
+// predeclared true, false and nil, so that fields can shadow them as
+// variables do in Go.
+func evalIdent(root reflect.Value, name string) (any, error) {
+	if field, ok := fieldByName(root, name); ok && ast.IsExported(name) {
+		return evalValue(field), nil
+	}
+
+	switch name {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "nil":
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", missingField(root.Type(), name), name)
+}
+
+func evalSelector(x any, name string) (any, error) {
+	if x == nil {
+		return nil, nil
+	}
+
+	value := reflect.ValueOf(x)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s has no field %s", ErrIncompatibleTypes, value.Type(), name)
+	}
+
+	field, ok := fieldByName(value, name)
+	if !ok || !ast.IsExported(name) {
+		return nil, fmt.Errorf("%w: %s", missingField(value.Type(), name), name)
+	}
+
+	return evalValue(field), nil
+}
+
+// evalValue converts a field to the values expressions operate on: numbers to
+// int64 or float64, strings and bools to their basic types, and pointers to
+// what they point to, or nil.
+func evalValue(value reflect.Value) any {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+
+		if value.Kind() == reflect.Ptr && value.Elem().Kind() == reflect.Struct {
+			break
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := value.Uint(); n <= math.MaxInt64 {
+			return int64(n)
+		}
+
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.String:
+		return value.String()
+	}
+
+	if !value.CanInterface() {
+		return nil
+	}
+
+	return value.Interface()
+}
+
+func evalIndex(root reflect.Value, e *ast.IndexExpr) (any, error) {
+	x, err := evalExpr(root, e.X)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := evalExpr(root, e.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := x.(string); ok {
+		i, err := evalInt(index, len(s))
+		if err != nil {
+			return nil, err
+		}
+
+		return int64(s[i]), nil
+	}
+
+	value := reflect.ValueOf(x)
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, err := evalInt(index, value.Len())
+		if err != nil {
+			return nil, err
+		}
+
+		return evalValue(value.Index(i)), nil
+	case reflect.Map:
+		key, err := evalMapKey(value.Type().Key(), index)
+		if err != nil {
+			return nil, err
+		}
+
+		elem := value.MapIndex(key)
+		if !elem.IsValid() {
+			return evalValue(reflect.Zero(value.Type().Elem())), nil
+		}
+
+		return evalValue(elem), nil
+	default:
+		return nil, fmt.Errorf("%w: can't index %T", ErrIncompatibleTypes, x)
+	}
+}
+
+// evalInt returns index as an index of a value of length n.
+func evalInt(index any, n int) (int, error) {
+	i, ok := index.(int64)
+	if !ok {
+		return 0, fmt.Errorf("%w: index %v isn't an integer", ErrIncompatibleTypes, index)
+	}
+
+	if i < 0 || i >= int64(n) {
+		return 0, fmt.Errorf("%w: index %d out of range", ErrIncompatibleTypes, i)
+	}
+
+	return int(i), nil
+}
+
+// evalMapKey converts key to the key type of a map.
+func evalMapKey(keyType reflect.Type, key any) (reflect.Value, error) {
+	if key == nil {
+		return reflect.Value{}, fmt.Errorf("%w: nil map key", ErrIncompatibleTypes)
+	}
+
+	value := reflect.ValueOf(key)
+
+	switch {
+	case value.Type().AssignableTo(keyType):
+		return value, nil
+	case value.Kind() == reflect.String && keyType.Kind() == reflect.String:
+		return value.Convert(keyType), nil
+	case value.Kind() == reflect.Int64 && isIntegerKind(keyType.Kind()):
+		converted := value.Convert(keyType)
+		if evalValue(converted) != key {
+			return reflect.Value{}, fmt.Errorf("%w: key %v overflows %s", ErrIncompatibleTypes, key, keyType)
+		}
+
+		return converted, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: key %v of %T, map key type: %s", ErrIncompatibleTypes, key, key, keyType)
+	}
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func evalCall(root reflect.Value, e *ast.CallExpr) (any, error) {
+	name, ok := e.Fun.(*ast.Ident)
+	if !ok || name.Name != "len" || len(e.Args) != 1 || e.Ellipsis.IsValid() {
+		return nil, fmt.Errorf("%w: only len(x) calls are supported", ErrInvalidExpression)
+	}
+
+	x, err := evalExpr(root, e.Args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if x == nil {
+		return int64(0), nil
+	}
+
+	value := reflect.ValueOf(x)
+
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return int64(value.Len()), nil
+	default:
+		return nil, fmt.Errorf("%w: invalid argument %T for len", ErrIncompatibleTypes, x)
+	}
+}
+
+func evalUnary(root reflect.Value, e *ast.UnaryExpr) (any, error) {
+	x, err := evalExpr(root, e.X)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Op {
+	case token.NOT:
+		b, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: operator ! on %T", ErrIncompatibleTypes, x)
+		}
+
+		return !b, nil
+	case token.SUB, token.ADD:
+		switch n := x.(type) {
+		case int64:
+			if e.Op == token.SUB {
+				return -n, nil
+			}
+
+			return n, nil
+		case float64:
+			if e.Op == token.SUB {
+				return -n, nil
+			}
+
+			return n, nil
+		default:
+			return nil, fmt.Errorf("%w: operator %s on %T", ErrIncompatibleTypes, e.Op, x)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported operator %s", ErrInvalidExpression, e.Op)
+	}
+}
+
+func evalBinary(root reflect.Value, e *ast.BinaryExpr) (any, error) {
+	x, err := evalExpr(root, e.X)
+	if err != nil {
+		return nil, err
+	}
+
+	// && and || only evaluate their right operand when it decides the result
+	if e.Op == token.LAND || e.Op == token.LOR {
+		left, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: operator %s on %T", ErrIncompatibleTypes, e.Op, x)
+		}
+
+		if left == (e.Op == token.LOR) {
+			return left, nil
+		}
+
+		y, err := evalExpr(root, e.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		right, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: operator %s on %T", ErrIncompatibleTypes, e.Op, y)
+		}
+
+		return right, nil
+	}
+
+	y, err := evalExpr(root, e.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Op {
+	case token.EQL, token.NEQ:
+		equal := evalEqual(x, y)
+		if e.Op == token.NEQ {
+			return !equal, nil
+		}
+
+		return equal, nil
+	case token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return evalCompare(e.Op, x, y)
+	case token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+		return evalArithmetic(e.Op, x, y)
+	default:
+		return nil, fmt.Errorf("%w: unsupported operator %s", ErrInvalidExpression, e.Op)
+	}
+}
+
+// evalNumbers returns x and y as int64s, or as float64s when either is a
+// float.
+func evalNumbers(x, y any) (xi, yi int64, xf, yf float64, isFloat, ok bool) {
+	switch a := x.(type) {
+	case int64:
+		switch b := y.(type) {
+		case int64:
+			return a, b, 0, 0, false, true
+		case float64:
+			return 0, 0, float64(a), b, true, true
+		}
+	case float64:
+		switch b := y.(type) {
+		case int64:
+			return 0, 0, a, float64(b), true, true
+		case float64:
+			return 0, 0, a, b, true, true
+		}
+	}
+
+	return 0, 0, 0, 0, false, false
+}
+
+func evalEqual(x, y any) bool {
+	if xi, yi, xf, yf, isFloat, ok := evalNumbers(x, y); ok {
+		if isFloat {
+			return xf == yf
+		}
+
+		return xi == yi
+	}
+
+	// As in Go, nil slices, maps, funcs and channels equal nil
+	if x == nil {
+		return isNilValue(y)
+	}
+
+	if y == nil {
+		return isNilValue(x)
+	}
+
+	return reflect.DeepEqual(x, y)
+}
+
+// isNilValue reports whether x is nil or a nil slice, map, func, channel,
+// pointer or interface.
+func isNilValue(x any) bool {
+	if x == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(x)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Func, reflect.Chan, reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+
+	return false
+}
+
+func evalCompare(op token.Token, x, y any) (any, error) {
+	var cmp int
+
+	if xi, yi, xf, yf, isFloat, ok := evalNumbers(x, y); ok {
+		// Every ordered comparison with NaN is false
+		if isFloat && (math.IsNaN(xf) || math.IsNaN(yf)) {
+			return false, nil
+		}
+
+		switch {
+		case isFloat && xf < yf, !isFloat && xi < yi:
+			cmp = -1
+		case isFloat && xf > yf, !isFloat && xi > yi:
+			cmp = 1
+		}
+	} else {
+		a, aok := x.(string)
+		b, bok := y.(string)
+
+		if !aok || !bok {
+			return nil, fmt.Errorf("%w: operator %s on %T and %T", ErrIncompatibleTypes, op, x, y)
+		}
+
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	}
+
+	switch op {
+	case token.LSS:
+		return cmp < 0, nil
+	case token.LEQ:
+		return cmp <= 0, nil
+	case token.GTR:
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func evalArithmetic(op token.Token, x, y any) (any, error) {
+	if a, ok := x.(string); ok && op == token.ADD {
+		if b, ok := y.(string); ok {
+			return a + b, nil
+		}
+	}
+
+	xi, yi, xf, yf, isFloat, ok := evalNumbers(x, y)
+	if !ok {
+		return nil, fmt.Errorf("%w: operator %s on %T and %T", ErrIncompatibleTypes, op, x, y)
+	}
+
+	if isFloat {
+		switch op {
+		case token.ADD:
+			return xf + yf, nil
+		case token.SUB:
+			return xf - yf, nil
+		case token.MUL:
+			return xf * yf, nil
+		case token.QUO:
+			return xf / yf, nil
+		default:
+			return nil, fmt.Errorf("%w: operator %% on floats", ErrIncompatibleTypes)
+		}
+	}
+
+	switch op {
+	case token.ADD:
+		return xi + yi, nil
+	case token.SUB:
+		return xi - yi, nil
+	case token.MUL:
+		return xi * yi, nil
+	}
+
+	if yi == 0 {
+		return nil, fmt.Errorf("%w: division by zero", ErrIncompatibleTypes)
+	}
+
+	if op == token.QUO {
+		return xi / yi, nil
+	}
+
+	return xi % yi, nil
+}
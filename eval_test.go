@@ -0,0 +1,145 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newEvalInstance(t *testing.T) any {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("Country", "")
+	_ = builder.AddField("Score", float64(0))
+	_ = builder.AddField("Address", &AddressTest{})
+	_ = builder.AddField("Manager", &PersonTest{})
+	_ = builder.AddField("Tags", []string{})
+	_ = builder.AddField("Limits", map[string]int{})
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Name", "Alice")
+	_ = builder.SetFieldValue("Age", 30)
+	_ = builder.SetFieldValue("Country", "US")
+	_ = builder.SetFieldValue("Score", 7.5)
+	_ = builder.SetFieldValue("Address", &AddressTest{City: "Berlin"})
+	_ = builder.SetFieldValue("Tags", []string{"a", "b"})
+	_ = builder.SetFieldValue("Limits", map[string]int{"daily": 3})
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	return instancePtr
+}
+
+func TestEval(t *testing.T) {
+	instancePtr := newEvalInstance(t)
+
+	tests := []struct {
+		name       string
+		expression string
+		want       any
+		wantErr    error
+	}{
+		{name: "rule", expression: `Age >= 18 && Country == "US"`, want: true},
+		{name: "or", expression: `Age < 18 || Country == "DE"`, want: false},
+		{name: "not", expression: `!(Age > 40)`, want: true},
+		{name: "arithmetic", expression: `Age * 2 + 1`, want: int64(61)},
+		{name: "integer_division", expression: `Age / 4`, want: int64(7)},
+		{name: "mixed_numbers", expression: `Score + Age`, want: 37.5},
+		{name: "string_concat", expression: `Name + "!"`, want: "Alice!"},
+		{name: "string_compare", expression: `Name < "Bob"`, want: true},
+		{name: "selector", expression: `Address.City == "Berlin"`, want: true},
+		{name: "nil_pointer", expression: `Manager == nil && Address != nil`, want: true},
+		{name: "len", expression: `len(Tags) == 2`, want: true},
+		{name: "index", expression: `Tags[1]`, want: "b"},
+		{name: "map_index", expression: `Limits["daily"] > Limits["weekly"]`, want: true},
+		{name: "unknown_field", expression: `Email == ""`, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "syntax_error", expression: `Age >=`, wantErr: dynamicstruct.ErrInvalidExpression},
+		{name: "unsupported_call", expression: `cap(Tags)`, wantErr: dynamicstruct.ErrInvalidExpression},
+		{name: "type_mismatch", expression: `Age > "18"`, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "division_by_zero", expression: `Age / 0`, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "index_out_of_range", expression: `Tags[2]`, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "short_circuit", expression: `false && Email == ""`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dynamicstruct.Eval(instancePtr, tt.expression)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Eval() = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalNilPointerSelector(t *testing.T) {
+	instancePtr := newEvalInstance(t)
+
+	got, err := dynamicstruct.Eval(instancePtr, `Manager.Name`)
+	if err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+
+	if got != nil {
+		t.Errorf("Eval() = %v, want nil", got)
+	}
+}
+
+func TestEvalNilAndNaN(t *testing.T) {
+	instance := struct {
+		Tags   []string
+		Limits map[string]int
+		Ratio  float64
+	}{Ratio: math.NaN()}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: `Tags == nil`, want: true},
+		{expression: `nil != Tags`, want: false},
+		{expression: `Limits == nil`, want: true},
+		{expression: `Ratio < 1.0`, want: false},
+		{expression: `Ratio <= 1.0`, want: false},
+		{expression: `Ratio >= 1.0`, want: false},
+		{expression: `1 > Ratio`, want: false},
+		{expression: `Ratio == Ratio`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := dynamicstruct.Eval(instance, tt.expression)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalInvalidInstance(t *testing.T) {
+	if _, err := dynamicstruct.Eval(42, `true`); !errors.Is(err, dynamicstruct.ErrInvalidInstance) {
+		t.Errorf("Eval() error = %v, want %v", err, dynamicstruct.ErrInvalidInstance)
+	}
+}
@@ -0,0 +1,80 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"strings"
+)
+
+// setInstance makes instance the builder's instance and indexes its fields by
+// name.
+func (b *Builder) setInstance(instance reflect.Value) {
+	structType := instance.Type()
+
+	b.index = make(map[string]int, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		b.index[structType.Field(i).Name] = i
+	}
+
+	b.foldIndex = nil
+
+	if b.caseInsensitive {
+		b.foldIndex = make(map[string]int, len(b.index))
+
+		for name, i := range b.index {
+			folded := strings.ToLower(name)
+
+			// Names that differ only in case can't be told apart
+			if _, ok := b.foldIndex[folded]; ok {
+				b.foldIndex[folded] = -1
+			} else {
+				b.foldIndex[folded] = i
+			}
+		}
+	}
+
+	b.instance = &instance
+}
+
+// instanceField returns the named field of the builder's instance. b.m must
+// be held.
+func (b *Builder) instanceField(name string) (reflect.Value, bool) {
+	return b.structField(*b.instance, name)
+}
+
+// structField returns the named field of value, a value of the built type,
+// resolving aliases, looking up direct fields in the index and promoted
+// fields by name, then, with WithCaseInsensitiveFieldLookup, direct fields
+// regardless of case. b.m must be held.
+func (b *Builder) structField(value reflect.Value, name string) (reflect.Value, bool) {
+	if canonical, ok := b.aliases[name]; ok {
+		name = canonical
+	}
+
+	if i, ok := b.index[name]; ok {
+		return value.Field(i), true
+	}
+
+	if field, ok := fieldByName(value, name); ok {
+		return field, true
+	}
+
+	if i, ok := b.foldIndex[strings.ToLower(name)]; ok && i >= 0 {
+		return value.Field(i), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// FieldIndex returns the index of the named field in the built struct, for
+// use with reflect.Value.Field on instances of the built type. Looking fields
+// up by index is faster than by name in loops over many instances. Fields
+// promoted from embedded structs have no index of their own and report false,
+// as does a builder that isn't built.
+func (b *Builder) FieldIndex(name string) (int, bool) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	i, ok := b.index[name]
+
+	return i, ok
+}
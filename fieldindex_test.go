@@ -0,0 +1,68 @@
+package dynamicstruct_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestFieldIndex(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Age", 0)
+
+	if _, ok := builder.FieldIndex("Name"); ok {
+		t.Error("FieldIndex() before Build ok = true, want false")
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		wantIndex int
+		wantOK    bool
+	}{
+		{name: "ContactTest", wantIndex: 0, wantOK: true},
+		{name: "Name", wantIndex: 1, wantOK: true},
+		{name: "Age", wantIndex: 2, wantOK: true},
+		{name: "Email", wantOK: false},
+		{name: "Missing", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, ok := builder.FieldIndex(tt.name)
+			if ok != tt.wantOK || index != tt.wantIndex {
+				t.Errorf("FieldIndex() = %d, %v, want %d, %v", index, ok, tt.wantIndex, tt.wantOK)
+			}
+		})
+	}
+
+	index, _ := builder.FieldIndex("Age")
+	value := reflect.New(reflect.TypeOf(instance)).Elem()
+	value.Field(index).SetInt(42)
+
+	if got := value.FieldByName("Age").Int(); got != 42 {
+		t.Errorf("Field(FieldIndex(Age)) = %d, want 42", got)
+	}
+
+	// Promoted fields are still reachable by name
+	if err := builder.SetFieldValue("Email", "a@example.com"); err != nil {
+		t.Fatalf("SetFieldValue() error = %v", err)
+	}
+
+	if got, _ := builder.GetField("Email"); got != "a@example.com" {
+		t.Errorf("GetField() = %v, want a@example.com", got)
+	}
+
+	builder.Reset()
+
+	if _, ok := builder.FieldIndex("Name"); ok {
+		t.Error("FieldIndex() after Reset ok = true, want false")
+	}
+}
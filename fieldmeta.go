@@ -0,0 +1,43 @@
+package dynamicstruct
+
+// SetFieldMeta attaches val to the named field under key, for information
+// that doesn't belong in struct tags, such as UI labels, column widths or
+// where a field came from. Metadata doesn't change the built type, so it can
+// be set after Build. It is kept by Clone and written by EncodeBinary.
+func (b *Builder) SetFieldMeta(field, key string, val any) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if _, ok := b.fields[field]; !ok {
+		return fieldError("set meta", field, ErrFieldNotFound)
+	}
+
+	if b.meta == nil {
+		b.meta = make(map[string]fieldMeta)
+	}
+
+	meta := b.meta[field]
+
+	// Copy the values, so that states saved by Rebuild keep their own
+	values := make(map[string]any, len(meta.values)+1)
+	for k, v := range meta.values {
+		values[k] = v
+	}
+
+	values[key] = val
+	meta.values = values
+	b.meta[field] = meta
+
+	return nil
+}
+
+// FieldMeta returns the metadata set for the named field under key, and
+// whether there is any.
+func (b *Builder) FieldMeta(field, key string) (any, bool) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	val, ok := b.meta[field].values[key]
+
+	return val, ok
+}
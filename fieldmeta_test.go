@@ -0,0 +1,106 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestFieldMeta(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Email", "", `json:"email"`)
+	_ = builder.AddFieldWithOptions("Age", 0, dynamicstruct.Required())
+
+	if err := builder.SetFieldMeta("Email", "label", "E-mail address"); err != nil {
+		t.Fatalf("SetFieldMeta() error = %v", err)
+	}
+
+	_ = builder.SetFieldMeta("Email", "width", 40)
+	_ = builder.SetFieldMeta("Age", "source", "crm")
+
+	t.Run("get", func(t *testing.T) {
+		if got, ok := builder.FieldMeta("Email", "label"); !ok || got != "E-mail address" {
+			t.Errorf("FieldMeta() = %v, %v, want E-mail address, true", got, ok)
+		}
+
+		if got, ok := builder.FieldMeta("Email", "width"); !ok || got != 40 {
+			t.Errorf("FieldMeta() = %v, %v, want 40, true", got, ok)
+		}
+
+		if _, ok := builder.FieldMeta("Email", "missing"); ok {
+			t.Error("FieldMeta() ok = true for unset key, want false")
+		}
+
+		if _, ok := builder.FieldMeta("Missing", "label"); ok {
+			t.Error("FieldMeta() ok = true for unknown field, want false")
+		}
+	})
+
+	t.Run("unknown_field", func(t *testing.T) {
+		if err := builder.SetFieldMeta("Missing", "label", "x"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("SetFieldMeta() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+	})
+
+	t.Run("keeps_options", func(t *testing.T) {
+		clone := builder.Clone()
+
+		instance, err := clone.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if err := clone.CheckRequired(instance); err == nil {
+			t.Error("CheckRequired() error = nil, want Age required")
+		}
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		clone := builder.Clone()
+		_ = clone.SetFieldMeta("Email", "label", "Email")
+
+		if got, _ := clone.FieldMeta("Email", "label"); got != "Email" {
+			t.Errorf("clone FieldMeta() = %v, want Email", got)
+		}
+
+		if got, _ := builder.FieldMeta("Email", "label"); got != "E-mail address" {
+			t.Errorf("FieldMeta() = %v, want E-mail address", got)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if err := builder.SetFieldMeta("Age", "label", "Age"); err != nil {
+			t.Fatalf("SetFieldMeta() after Build error = %v", err)
+		}
+
+		data, err := builder.EncodeBinary(instance)
+		if err != nil {
+			t.Fatalf("EncodeBinary() error = %v", err)
+		}
+
+		decoded, _, err := dynamicstruct.DecodeBinary(data)
+		if err != nil {
+			t.Fatalf("DecodeBinary() error = %v", err)
+		}
+
+		for _, tt := range []struct {
+			field, key string
+			want       any
+		}{
+			{"Email", "label", "E-mail address"},
+			{"Email", "width", 40},
+			{"Age", "source", "crm"},
+			{"Age", "label", "Age"},
+		} {
+			if got, _ := decoded.FieldMeta(tt.field, tt.key); got != tt.want {
+				t.Errorf("FieldMeta(%q, %q) = %v, want %v", tt.field, tt.key, got, tt.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,289 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type fieldConfig struct {
+	tags         []string
+	required     bool
+	hasDefault   bool
+	defaultValue any
+	group        string
+}
+
+// FieldOption configures a field added with AddFieldWithOptions.
+type FieldOption func(*fieldConfig)
+
+// fieldMeta holds what the builder knows about a field beyond its
+// reflect.StructField.
+type fieldMeta struct {
+	required     bool
+	defaultValue reflect.Value
+	group        string
+	values       map[string]any
+}
+
+// Tags sets the struct tags of the field, as AddField does.
+func Tags(tags ...string) FieldOption {
+	return func(c *fieldConfig) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// Required marks the field as required, to be reported by CheckRequired when
+// it is unset.
+func Required() FieldOption {
+	return func(c *fieldConfig) {
+		c.required = true
+	}
+}
+
+// Default sets the value the field has in the built instance and in instances
+// created with NewInstance.
+func Default(value any) FieldOption {
+	return func(c *fieldConfig) {
+		c.hasDefault = true
+		c.defaultValue = value
+	}
+}
+
+// AddFieldWithOptions adds a field like AddField, configured with options. A
+// *Builder kind adds a nested struct field as AddField does; Default is
+// refused for it with ErrIncompatibleTypes, since the nested builder's own
+// defaults apply.
+func (b *Builder) AddFieldWithOptions(name string, kind any, opts ...FieldOption) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	return b.addFieldWithOptions(name, kind, opts)
+}
+
+func (b *Builder) addFieldWithOptions(name string, kind any, opts []FieldOption) error {
+	cfg := fieldConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meta := fieldMeta{required: cfg.required}
+
+	if cfg.hasDefault && cfg.defaultValue != nil {
+		// The nested struct type isn't known until build, and the nested
+		// builder's own defaults apply to it
+		if _, ok := kind.(*Builder); ok {
+			return fieldError("add", name, fmt.Errorf("%w: default on a nested builder field", ErrIncompatibleTypes))
+		}
+
+		defaultValue, err := defaultFor(reflect.TypeOf(kind), cfg.defaultValue)
+		if err != nil {
+			return fieldError("add", name, err)
+		}
+
+		meta.defaultValue = defaultValue
+	}
+
+	if err := b.addKind(name, kind, cfg.tags); err != nil {
+		return err
+	}
+
+	// The field is in the open group, if any, unless Group says otherwise
+	meta.group = b.meta[name].group
+	if cfg.group != "" {
+		meta.group = cfg.group
+	}
+
+	if meta.required || meta.defaultValue.IsValid() || meta.group != "" {
+		if b.meta == nil {
+			b.meta = make(map[string]fieldMeta)
+		}
+
+		b.meta[name] = meta
+	}
+
+	return nil
+}
+
+// defaultFor converts value to fieldType. Besides assignable values, numbers
+// are accepted for fields of another numeric type, so Default(8080) works for
+// an int64 field, as long as they fit it: Default(300) for an int8 field or
+// Default(1.5) for an int field return ErrIncompatibleTypes.
+func defaultFor(fieldType reflect.Type, value any) (reflect.Value, error) {
+	valueReflect := reflect.ValueOf(value)
+
+	switch {
+	case fieldType == nil:
+		return reflect.Value{}, fmt.Errorf("%w: field has no type", ErrIncompatibleTypes)
+	case valueReflect.Type().AssignableTo(fieldType):
+		converted := reflect.New(fieldType).Elem()
+		converted.Set(valueReflect)
+
+		return converted, nil
+	case isNumberKind(valueReflect.Kind()) && isNumberKind(fieldType.Kind()):
+		converted, _, err := convertNumber(fieldType, valueReflect)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error())
+		}
+
+		return converted, nil
+	default:
+		return reflect.Value{}, fmt.Errorf(
+			"%w: field type: %s, default type: %s",
+			ErrIncompatibleTypes,
+			fieldType.String(),
+			valueReflect.Type().String(),
+		)
+	}
+}
+
+// applyDefaults sets the default values on the struct value. Slices and maps
+// are copied, so instances don't share them.
+func (b *Builder) applyDefaults(value reflect.Value) {
+	for name, meta := range b.meta {
+		if !meta.defaultValue.IsValid() {
+			continue
+		}
+
+		// Fields left out of a profile have no default to apply
+		field := value.FieldByName(name)
+		if !field.IsValid() {
+			continue
+		}
+
+		field.Set(copyDefault(meta.defaultValue))
+	}
+}
+
+func copyDefault(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(copied, value)
+
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), iter.Value())
+		}
+
+		return copied
+	default:
+		return value
+	}
+}
+
+// NewInstance returns a pointer to a new instance of the built type, with
+// default values applied.
+func (b *Builder) NewInstance() (any, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	instance := reflect.New(b.instance.Type())
+	b.applyDefaults(instance.Elem())
+	b.applyNestedDefaults(instance.Elem())
+
+	return instance.Interface(), nil
+}
+
+// ZeroInstance resets the instance instancePtr points to in place, to what
+// NewInstance returns: zero, with default values applied. It lets callers
+// reuse instances in loops instead of allocating new ones.
+func (b *Builder) ZeroInstance(instancePtr any) error {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return ErrInstanceNotBuilt
+	}
+
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	if value.Type() != b.instance.Type() {
+		return fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			b.instance.Type().String(),
+		)
+	}
+
+	value.Set(reflect.Zero(value.Type()))
+	b.applyDefaults(value)
+	b.applyNestedDefaults(value)
+
+	return nil
+}
+
+// CheckRequired reports the required fields of instance, a value of the built
+// type or a pointer to one, that are unset: nil, or the zero value for types
+// that can't be nil. It returns ValidationErrors keyed by field name.
+func (b *Builder) CheckRequired(instance any) error {
+	b.m.RLock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		b.m.RUnlock()
+
+		return ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+
+	var required []string
+
+	for _, name := range b.order {
+		if b.meta[name].required {
+			required = append(required, name)
+		}
+	}
+
+	b.m.RUnlock()
+
+	value, err := structValue(instance)
+	if err != nil {
+		return err
+	}
+
+	if value.Type() != structType {
+		return fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			structType.String(),
+		)
+	}
+
+	errs := ValidationErrors{}
+
+	for _, name := range required {
+		if !hasValue(value.FieldByName(name)) {
+			errs[name] = &RuleError{Rule: "required", msg: "is required"}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newOptionsBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+
+	fields := []struct {
+		name string
+		kind any
+		opts []dynamicstruct.FieldOption
+	}{
+		{name: "Host", kind: "", opts: []dynamicstruct.FieldOption{dynamicstruct.Tags(`json:"host"`), dynamicstruct.Required()}},
+		{name: "Port", kind: int64(0), opts: []dynamicstruct.FieldOption{dynamicstruct.Tags(`json:"port"`), dynamicstruct.Default(8080)}},
+		{name: "Token", kind: (*string)(nil), opts: []dynamicstruct.FieldOption{dynamicstruct.Required()}},
+		{name: "Tags", kind: []string{}, opts: []dynamicstruct.FieldOption{dynamicstruct.Default([]string{"web"})}},
+		{name: "Debug", kind: false, opts: nil},
+	}
+
+	for _, field := range fields {
+		if err := builder.AddFieldWithOptions(field.name, field.kind, field.opts...); err != nil {
+			t.Fatalf("AddFieldWithOptions(%s) error = %v", field.name, err)
+		}
+	}
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestAddFieldWithOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    any
+		opts    []dynamicstruct.FieldOption
+		wantErr error
+	}{
+		{name: "tags", kind: "", opts: []dynamicstruct.FieldOption{dynamicstruct.Tags(`json:"a"`, `xml:"a"`)}, wantErr: nil},
+		{name: "assignable_default", kind: "", opts: []dynamicstruct.FieldOption{dynamicstruct.Default("x")}, wantErr: nil},
+		{name: "numeric_default", kind: float32(0), opts: []dynamicstruct.FieldOption{dynamicstruct.Default(1)}, wantErr: nil},
+		{name: "nil_default", kind: (*int)(nil), opts: []dynamicstruct.FieldOption{dynamicstruct.Default(nil)}, wantErr: nil},
+		{name: "overflowing_default", kind: int8(0), opts: []dynamicstruct.FieldOption{dynamicstruct.Default(300)}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "fractional_default", kind: int(0), opts: []dynamicstruct.FieldOption{dynamicstruct.Default(1.5)}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "negative_unsigned_default", kind: uint(0), opts: []dynamicstruct.FieldOption{dynamicstruct.Default(-1)}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "incompatible_default", kind: "", opts: []dynamicstruct.FieldOption{dynamicstruct.Default(1)}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "invalid_tag", kind: "", opts: []dynamicstruct.FieldOption{dynamicstruct.Tags(`json:"a`)}, wantErr: dynamicstruct.ErrInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.New().AddFieldWithOptions("Field", tt.kind, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddFieldWithOptions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("duplicate", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Field", "")
+
+		err := builder.AddFieldWithOptions("Field", "", dynamicstruct.Default("x"))
+		if !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Errorf("AddFieldWithOptions() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+	})
+
+	t.Run("nested_builder", func(t *testing.T) {
+		address := dynamicstruct.New()
+		_ = address.AddFieldWithOptions("Country", "", dynamicstruct.Default("NL"))
+
+		builder := dynamicstruct.New()
+
+		err := builder.AddFieldWithOptions("Address", address, dynamicstruct.Tags(`json:"address"`), dynamicstruct.Required())
+		if err != nil {
+			t.Fatalf("AddFieldWithOptions() error = %v", err)
+		}
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		data, _ := json.Marshal(instance)
+		if want := `{"address":{"Country":"NL"}}`; string(data) != want {
+			t.Errorf("Marshal() = %s, want %s", data, want)
+		}
+	})
+
+	t.Run("nested_builder_default", func(t *testing.T) {
+		err := dynamicstruct.New().AddFieldWithOptions("Address", dynamicstruct.New(), dynamicstruct.Default(struct{}{}))
+		if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("AddFieldWithOptions() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	})
+}
+
+func TestDefaults(t *testing.T) {
+	builder := newOptionsBuilder(t)
+
+	t.Run("built_instance", func(t *testing.T) {
+		port, err := builder.GetField("Port")
+		if err != nil || port != int64(8080) {
+			t.Errorf("GetField(Port) = %v, %v, want 8080", port, err)
+		}
+	})
+
+	t.Run("new_instance", func(t *testing.T) {
+		instancePtr, err := builder.NewInstance()
+		if err != nil {
+			t.Fatalf("NewInstance() error = %v", err)
+		}
+
+		data, _ := json.Marshal(instancePtr)
+		if want := `{"host":"","port":8080,"Token":null,"Tags":["web"],"Debug":false}`; string(data) != want {
+			t.Errorf("Marshal() = %s, want %s", data, want)
+		}
+	})
+
+	t.Run("defaults_not_shared", func(t *testing.T) {
+		first, _ := builder.NewInstance()
+		second, _ := builder.NewInstance()
+
+		reflect.ValueOf(first).Elem().FieldByName("Tags").Index(0).SetString("changed")
+
+		if got := reflect.ValueOf(second).Elem().FieldByName("Tags").Index(0).String(); got != "web" {
+			t.Errorf("second Tags[0] = %q, want %q", got, "web")
+		}
+	})
+
+	t.Run("not_built", func(t *testing.T) {
+		if _, err := dynamicstruct.New().NewInstance(); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("NewInstance() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
+
+func TestCheckRequired(t *testing.T) {
+	builder := newOptionsBuilder(t)
+	token := "secret"
+
+	tests := []struct {
+		name       string
+		host       string
+		token      *string
+		wantFields []string
+	}{
+		{name: "all_set", host: "localhost", token: &token, wantFields: nil},
+		{name: "zero_value", host: "", token: &token, wantFields: []string{"Host"}},
+		{name: "nil_pointer", host: "localhost", token: nil, wantFields: []string{"Token"}},
+		{name: "both_unset", host: "", token: nil, wantFields: []string{"Host", "Token"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instancePtr, _ := builder.NewInstance()
+			value := reflect.ValueOf(instancePtr).Elem()
+			value.FieldByName("Host").SetString(tt.host)
+			value.FieldByName("Token").Set(reflect.ValueOf(tt.token))
+
+			err := builder.CheckRequired(instancePtr)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Errorf("CheckRequired() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var validationErrors dynamicstruct.ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Fatalf("CheckRequired() error = %v, want ValidationErrors", err)
+			}
+
+			if len(validationErrors) != len(tt.wantFields) {
+				t.Errorf("CheckRequired() error = %v, want fields %v", err, tt.wantFields)
+			}
+
+			for _, field := range tt.wantFields {
+				if _, ok := validationErrors[field]; !ok {
+					t.Errorf("CheckRequired() missing field %s in %v", field, err)
+				}
+			}
+		})
+	}
+
+	t.Run("removed_field", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddFieldWithOptions("Name", "", dynamicstruct.Required())
+		_ = builder.RemoveField("Name")
+		_ = builder.AddField("Name", "")
+		instance, _ := builder.Build()
+
+		if err := builder.CheckRequired(instance); err != nil {
+			t.Errorf("CheckRequired() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("other_type", func(t *testing.T) {
+		if err := builder.CheckRequired(PersonTest{}); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("CheckRequired() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	})
+}
+
+func TestZeroInstance(t *testing.T) {
+	builder := newOptionsBuilder(t)
+
+	instancePtr, _ := builder.NewInstance()
+	_ = json.Unmarshal([]byte(`{"host":"example.com","port":9090,"Tags":["api"],"Debug":true}`), instancePtr)
+
+	tags := reflect.ValueOf(instancePtr).Elem().FieldByName("Tags").Interface()
+
+	if err := builder.ZeroInstance(instancePtr); err != nil {
+		t.Fatalf("ZeroInstance() error = %v", err)
+	}
+
+	data, _ := json.Marshal(instancePtr)
+	if want := `{"host":"","port":8080,"Token":null,"Tags":["web"],"Debug":false}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	// The default slice is copied, not the one the instance had
+	if got := tags.([]string); got[0] != "api" {
+		t.Errorf("previous Tags = %v, want [api]", got)
+	}
+
+	tests := []struct {
+		name     string
+		instance any
+		wantErr  error
+	}{
+		{name: "not_pointer", instance: reflect.ValueOf(instancePtr).Elem().Interface(), wantErr: dynamicstruct.ErrValueMustBePointer},
+		{name: "other_type", instance: &PersonTest{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "nil", instance: (*PersonTest)(nil), wantErr: dynamicstruct.ErrValueCannotBeNil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := builder.ZeroInstance(tt.instance); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ZeroInstance() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("not_built", func(t *testing.T) {
+		if err := dynamicstruct.New().ZeroInstance(instancePtr); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("ZeroInstance() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Flatten returns the fields of instance, a struct or a pointer to one, as a
+// flat map. Fields of nested structs are keyed by their path joined with sep,
+// such as "Address.City" for sep ".", and fields of embedded structs by their
+// promoted name. Slices, maps and structs with unexported fields, such as
+// time.Time, are values of their own. A nil pointer to a struct is stored as
+// nil under the field's key. An empty sep defaults to ".".
+//
+// Flatten returns nil when instance isn't a struct or a non-nil pointer to
+// one.
+func Flatten(instance any, sep string) map[string]any {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil
+	}
+
+	if sep == "" {
+		sep = "."
+	}
+
+	flat := make(map[string]any)
+	flattenStruct(value, "", sep, flat)
+
+	return flat
+}
+
+func flattenStruct(value reflect.Value, prefix, sep string, flat map[string]any) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		nested := reflect.Indirect(fieldValue)
+
+		// Fields of embedded structs are stored under their promoted names
+		if field.Anonymous && nested.IsValid() && isDiffableStruct(nested.Type()) {
+			flattenStruct(nested, prefix, sep, flat)
+
+			continue
+		}
+
+		key := prefix + field.Name
+
+		if nested.IsValid() && isDiffableStruct(nested.Type()) {
+			flattenStruct(nested, key+sep, sep, flat)
+
+			continue
+		}
+
+		flat[key] = fieldValue.Interface()
+	}
+}
+
+// Unflatten sets the fields of the struct instancePtr points to from a map
+// produced by Flatten with the same sep. Nil pointers to nested structs are
+// allocated as needed. Values must be assignable to their fields, except that
+// numbers convert between numeric types and strings are parsed for fields of
+// other types, so maps read back from text based storage can be used.
+func Unflatten(flat map[string]any, sep string, instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	if sep == "" {
+		sep = "."
+	}
+
+	for key, fieldValue := range flat {
+		field, ok := flatField(value, strings.Split(key, sep))
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrFieldNotFound, key)
+		}
+
+		if err := setFlatValue(field, fieldValue); err != nil {
+			return fmt.Errorf("%w: field %s: %s", ErrIncompatibleTypes, key, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// flatField returns the field at path, allocating nil struct pointers on the
+// way, including those of embedded structs.
+func flatField(value reflect.Value, path []string) (reflect.Value, bool) {
+	for i, name := range path {
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		field, ok := value.Type().FieldByName(name)
+		if !ok || field.PkgPath != "" {
+			return reflect.Value{}, false
+		}
+
+		for j, index := range field.Index {
+			if j > 0 && value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					value.Set(reflect.New(value.Type().Elem()))
+				}
+
+				value = value.Elem()
+			}
+
+			value = value.Field(index)
+		}
+
+		// A nil value for a pointer to a nested struct is set as is
+		if i == len(path)-1 {
+			return value, value.CanSet()
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func setFlatValue(field reflect.Value, value any) error {
+	valueReflect := reflect.ValueOf(value)
+
+	switch {
+	case !valueReflect.IsValid():
+		switch field.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+			field.Set(reflect.Zero(field.Type()))
+
+			return nil
+		default:
+			return fmt.Errorf("can't set nil on %s", field.Type().String())
+		}
+	case valueReflect.Type().AssignableTo(field.Type()):
+		field.Set(valueReflect)
+
+		return nil
+	case isNumberKind(valueReflect.Kind()) && isNumberKind(field.Kind()):
+		converted, _, err := convertNumber(field.Type(), valueReflect)
+		if err != nil {
+			return err
+		}
+
+		field.Set(converted)
+
+		return nil
+	case valueReflect.Kind() == reflect.String:
+		return setFromString(field, valueReflect.String())
+	default:
+		return fmt.Errorf("can't set %s on %s", valueReflect.Type().String(), field.Type().String())
+	}
+}
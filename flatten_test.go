@@ -0,0 +1,168 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newFlattenType(t *testing.T) reflect.Type {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(&ContactTest{})
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Age", 0)
+	_ = builder.AddField("Tags", []string{})
+	_ = builder.AddField("Address", AddressTest{})
+	_ = builder.AddField("Billing", (*AddressTest)(nil))
+	_ = builder.AddField("CreatedAt", time.Time{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestFlatten(t *testing.T) {
+	structType := newFlattenType(t)
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	value := reflect.New(structType).Elem()
+	value.Field(0).Set(reflect.ValueOf(&ContactTest{Email: "alice@example.com"}))
+	value.FieldByName("Name").SetString("Alice")
+	value.FieldByName("Age").SetInt(30)
+	value.FieldByName("Tags").Set(reflect.ValueOf([]string{"a"}))
+	value.FieldByName("Address").Set(reflect.ValueOf(AddressTest{Street: "Main St", City: "Oslo"}))
+	value.FieldByName("CreatedAt").Set(reflect.ValueOf(createdAt))
+
+	tests := []struct {
+		name     string
+		instance any
+		sep      string
+		want     map[string]any
+	}{
+		{
+			name:     "dot_separator",
+			instance: value.Interface(),
+			sep:      ".",
+			want: map[string]any{
+				"Email":          "alice@example.com",
+				"Phone":          "",
+				"Name":           "Alice",
+				"Age":            30,
+				"Tags":           []string{"a"},
+				"Address.Street": "Main St",
+				"Address.City":   "Oslo",
+				"Billing":        (*AddressTest)(nil),
+				"CreatedAt":      createdAt,
+			},
+		},
+		{
+			name:     "custom_separator_and_pointer",
+			instance: value.Addr().Interface(),
+			sep:      "__",
+			want: map[string]any{
+				"Email":           "alice@example.com",
+				"Phone":           "",
+				"Name":            "Alice",
+				"Age":             30,
+				"Tags":            []string{"a"},
+				"Address__Street": "Main St",
+				"Address__City":   "Oslo",
+				"Billing":         (*AddressTest)(nil),
+				"CreatedAt":       createdAt,
+			},
+		},
+		{
+			name:     "not_a_struct",
+			instance: 1,
+			sep:      ".",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dynamicstruct.Flatten(tt.instance, tt.sep); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		value.FieldByName("Billing").Set(reflect.ValueOf(&AddressTest{City: "Bergen"}))
+
+		decoded := reflect.New(structType)
+		if err := dynamicstruct.Unflatten(dynamicstruct.Flatten(value.Interface(), "."), ".", decoded.Interface()); err != nil {
+			t.Fatalf("Unflatten() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(decoded.Elem().Interface(), value.Interface()) {
+			t.Errorf("Unflatten() = %+v, want %+v", decoded.Elem().Interface(), value.Interface())
+		}
+	})
+}
+
+func TestUnflatten(t *testing.T) {
+	structType := newFlattenType(t)
+
+	t.Run("strings_and_numbers", func(t *testing.T) {
+		decoded := reflect.New(structType)
+
+		flat := map[string]any{
+			"Email":        "bob@example.com",
+			"Age":          "42",
+			"Billing:City": "Bergen",
+			"CreatedAt":    "2024-01-02T03:04:05Z",
+		}
+
+		if err := dynamicstruct.Unflatten(flat, ":", decoded.Interface()); err != nil {
+			t.Fatalf("Unflatten() error = %v", err)
+		}
+
+		got := decoded.Elem()
+		if email := got.FieldByName("Email").String(); email != "bob@example.com" {
+			t.Errorf("Unflatten() Email = %q, want %q", email, "bob@example.com")
+		}
+
+		if age := got.FieldByName("Age").Int(); age != 42 {
+			t.Errorf("Unflatten() Age = %d, want 42", age)
+		}
+
+		if billing := got.FieldByName("Billing").Interface(); !reflect.DeepEqual(billing, &AddressTest{City: "Bergen"}) {
+			t.Errorf("Unflatten() Billing = %v, want %v", billing, &AddressTest{City: "Bergen"})
+		}
+
+		want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		if createdAt := got.FieldByName("CreatedAt").Interface(); createdAt != want {
+			t.Errorf("Unflatten() CreatedAt = %v, want %v", createdAt, want)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		flat    map[string]any
+		wantErr error
+	}{
+		{name: "unknown_field", flat: map[string]any{"Missing": 1}, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "path_through_leaf", flat: map[string]any{"Name.First": "A"}, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "wrong_type", flat: map[string]any{"Tags": 1}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "unparsable_string", flat: map[string]any{"Age": "old"}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "nil_for_value_field", flat: map[string]any{"Age": nil}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.Unflatten(tt.flat, ".", reflect.New(structType).Interface())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unflatten() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package dynamicstruct
+
+// Field adds a field like AddField and returns the builder, for chained
+// definitions:
+//
+//	instance, err := dynamicstruct.New().
+//		Field("Name", "", `json:"name"`).
+//		Field("Age", 0, `json:"age"`).
+//		Embed(Base{}).
+//		Build()
+//
+// Errors are not returned by Field but kept, and Build returns them all as
+// FieldErrors without building.
+func (b *Builder) Field(name string, kind any, tags ...string) *Builder {
+	b.chain(b.AddField(name, kind, tags...))
+
+	return b
+}
+
+// FieldWithOptions adds a field like AddFieldWithOptions and returns the
+// builder, keeping errors for Build as Field does.
+func (b *Builder) FieldWithOptions(name string, kind any, opts ...FieldOption) *Builder {
+	b.chain(b.AddFieldWithOptions(name, kind, opts...))
+
+	return b
+}
+
+// Embed adds an anonymous field like AddAnonymousField and returns the
+// builder, keeping errors for Build as Field does.
+func (b *Builder) Embed(fieldType any, tags ...string) *Builder {
+	b.chain(b.AddAnonymousField(fieldType, tags...))
+
+	return b
+}
+
+// Err returns the errors kept by Field, FieldWithOptions and Embed so far, as
+// FieldErrors, or nil when there are none.
+func (b *Builder) Err() error {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	return b.chainErr()
+}
+
+func (b *Builder) chain(err error) {
+	if err == nil {
+		return
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	fieldErr, ok := err.(*FieldError)
+	if !ok {
+		fieldErr = &FieldError{Op: "add", Err: err}
+	}
+
+	b.chainErrs = append(b.chainErrs, fieldErr)
+}
+
+// chainErr returns the kept errors. b.m must be held.
+func (b *Builder) chainErr() error {
+	if len(b.chainErrs) == 0 {
+		return nil
+	}
+
+	return append(FieldErrors(nil), b.chainErrs...)
+}
@@ -0,0 +1,60 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestFluent(t *testing.T) {
+	instance, err := dynamicstruct.New().
+		Embed(ContactTest{}).
+		Field("Name", "", `json:"name"`).
+		FieldWithOptions("Age", 0, dynamicstruct.Default(30)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, []string{"ContactTest", "Name", "Age"}) {
+		t.Errorf("fields = %v, want [ContactTest Name Age]", got)
+	}
+
+	if got := reflect.ValueOf(instance).FieldByName("Age").Int(); got != 30 {
+		t.Errorf("Age = %d, want 30", got)
+	}
+}
+
+func TestFluentErrors(t *testing.T) {
+	builder := dynamicstruct.New().
+		Field("Name", "").
+		Field("Name", 0).
+		Field("Email", "", `json:"email`).
+		Embed(ContactTest{}).
+		Embed(ContactTest{})
+
+	err := builder.Err()
+
+	var fieldErrs dynamicstruct.FieldErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) != 3 {
+		t.Fatalf("Err() = %v, want 3 FieldErrors", err)
+	}
+
+	if _, err := builder.Build(); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) ||
+		!errors.Is(err, dynamicstruct.ErrInvalidTag) ||
+		!errors.Is(err, dynamicstruct.ErrAnonymousFieldAlreadyExists) {
+		t.Errorf("Build() error = %v, want the chained errors", err)
+	}
+
+	if _, err := builder.GetField("Name"); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("GetField() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	builder.Reset()
+
+	if err := builder.Err(); err != nil {
+		t.Errorf("Err() after Reset = %v, want nil", err)
+	}
+}
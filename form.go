@@ -0,0 +1,169 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const (
+	formTagKey  = "form"
+	queryTagKey = "query"
+)
+
+// DecodeForm sets the fields of the struct instancePtr points to from form
+// values. Each field reads the value named by its `form` tag, or by its JSON
+// name, and fields without a value are left unchanged. Fields of embedded
+// structs are read as if they were promoted.
+//
+// Values are converted to the field type: numbers, booleans (including the
+// "on" of checkboxes), durations, times in one of TimeLayouts, which include
+// the formats of HTML date and datetime-local inputs, and types implementing
+// encoding.TextUnmarshaler. Slice fields take every value of their key.
+// Decoded values go through Transform.
+func DecodeForm(values url.Values, instancePtr any) error {
+	return decodeValues(values, instancePtr, formTagKey)
+}
+
+// DecodeQuery sets the fields of the struct instancePtr points to from the
+// query string of r, like DecodeForm, reading the parameter named by each
+// field's `query` tag or JSON name.
+func DecodeQuery(r *http.Request, instancePtr any) error {
+	if r == nil || r.URL == nil {
+		return ErrValueCannotBeNil
+	}
+
+	return decodeValues(r.URL.Query(), instancePtr, queryTagKey)
+}
+
+func decodeValues(values url.Values, instancePtr any, key string) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	if _, err := decodeValuesStruct(values, value, key); err != nil {
+		return err
+	}
+
+	return transformStruct(value)
+}
+
+// decodeValuesStruct reports whether any field of value was set.
+func decodeValuesStruct(values url.Values, value reflect.Value, key string) (bool, error) {
+	structType := value.Type()
+	decoded := false
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldValue := value.Field(i)
+
+		if field.Anonymous {
+			set, err := decodeValuesEmbedded(values, fieldValue, key)
+			if err != nil {
+				return false, err
+			}
+
+			decoded = decoded || set
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if _, tagged := field.Tag.Lookup(key); tagged {
+			name, ok = tagName(field, key)
+		}
+
+		if !ok {
+			continue
+		}
+
+		raws, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		if err := setFormStrings(fieldValue, raws); err != nil {
+			return false, fmt.Errorf("%w: field %s: %s", ErrIncompatibleTypes, field.Name, err.Error())
+		}
+
+		decoded = true
+	}
+
+	return decoded, nil
+}
+
+// decodeValuesEmbedded decodes the fields of an embedded struct, allocating a
+// nil embedded pointer only when one of its fields has a value.
+func decodeValuesEmbedded(values url.Values, value reflect.Value, key string) (bool, error) {
+	switch {
+	case value.Kind() == reflect.Struct:
+		return decodeValuesStruct(values, value, key)
+	case value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct:
+		if !value.IsNil() {
+			return decodeValuesStruct(values, value.Elem(), key)
+		}
+
+		embedded := reflect.New(value.Type().Elem())
+
+		set, err := decodeValuesStruct(values, embedded.Elem(), key)
+		if set && value.CanSet() {
+			value.Set(embedded)
+		}
+
+		return set, err
+	default:
+		return false, nil
+	}
+}
+
+func setFormStrings(value reflect.Value, raws []string) error {
+	if len(raws) == 0 {
+		return nil
+	}
+
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() == reflect.Uint8 {
+		return setFormString(value, raws[len(raws)-1])
+	}
+
+	slice := reflect.MakeSlice(value.Type(), len(raws), len(raws))
+
+	for i, raw := range raws {
+		if err := setFormString(slice.Index(i), strings.TrimSpace(raw)); err != nil {
+			return err
+		}
+	}
+
+	value.Set(slice)
+
+	return nil
+}
+
+// setFormString is setFromString with the spelling browsers submit for
+// checkboxes.
+func setFormString(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Ptr {
+		elem := reflect.New(value.Type().Elem())
+		if err := setFormString(elem.Elem(), raw); err != nil {
+			return err
+		}
+
+		value.Set(elem)
+
+		return nil
+	}
+
+	if value.Kind() == reflect.Bool && raw == "on" {
+		value.SetBool(true)
+
+		return nil
+	}
+
+	return setFromString(value, raw)
+}
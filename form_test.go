@@ -0,0 +1,151 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newFormType(t *testing.T) reflect.Type {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddField("Name", "", `form:"name"`, `query:"q"`, `transform:"trim"`)
+	_ = builder.AddField("Age", 0, `json:"age"`)
+	_ = builder.AddField("Subscribe", false, `form:"subscribe"`)
+	_ = builder.AddField("Born", time.Time{}, `form:"born"`)
+	_ = builder.AddField("Starts", (*time.Time)(nil), `form:"starts"`)
+	_ = builder.AddField("Tags", []string{}, `form:"tag"`, `query:"tag"`)
+	_ = builder.AddField("Scores", []int{}, `form:"score"`)
+	_ = builder.AddField("Secret", "", `form:"-"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestDecodeForm(t *testing.T) {
+	structType := newFormType(t)
+
+	values := url.Values{
+		"name":      {" Alice "},
+		"age":       {"30"},
+		"subscribe": {"on"},
+		"born":      {"1990-04-12"},
+		"starts":    {"2024-05-01T09:30"},
+		"tag":       {"a", "b"},
+		"score":     {"1", "2", "3"},
+		"Email":     {"alice@example.com"},
+		"Secret":    {"leaked"},
+	}
+
+	value := reflect.New(structType)
+	if err := dynamicstruct.DecodeForm(values, value.Interface()); err != nil {
+		t.Fatalf("DecodeForm() error = %v", err)
+	}
+
+	elem := value.Elem()
+	starts := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+	want := map[string]any{
+		"Name":      "Alice",
+		"Age":       30,
+		"Subscribe": true,
+		"Born":      time.Date(1990, 4, 12, 0, 0, 0, 0, time.UTC),
+		"Starts":    &starts,
+		"Tags":      []string{"a", "b"},
+		"Scores":    []int{1, 2, 3},
+		"Email":     "alice@example.com",
+		"Secret":    "",
+	}
+
+	for name, wantValue := range want {
+		if got := elem.FieldByName(name).Interface(); !reflect.DeepEqual(got, wantValue) {
+			t.Errorf("DecodeForm() %s = %v, want %v", name, got, wantValue)
+		}
+	}
+}
+
+func TestDecodeQuery(t *testing.T) {
+	structType := newFormType(t)
+
+	request := httptest.NewRequest("GET", "/users?q=bob&age=41&tag=x&name=ignored", nil)
+
+	value := reflect.New(structType)
+	if err := dynamicstruct.DecodeQuery(request, value.Interface()); err != nil {
+		t.Fatalf("DecodeQuery() error = %v", err)
+	}
+
+	elem := value.Elem()
+
+	if got := elem.FieldByName("Name").String(); got != "bob" {
+		t.Errorf("DecodeQuery() Name = %q, want %q", got, "bob")
+	}
+
+	if got := elem.FieldByName("Age").Int(); got != 41 {
+		t.Errorf("DecodeQuery() Age = %d, want 41", got)
+	}
+
+	if got := elem.FieldByName("Tags").Interface(); !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("DecodeQuery() Tags = %v, want [x]", got)
+	}
+}
+
+func TestDecodeFormErrors(t *testing.T) {
+	structType := newFormType(t)
+
+	tests := []struct {
+		name        string
+		values      url.Values
+		instancePtr any
+		wantErr     error
+	}{
+		{
+			name:        "bad_number",
+			values:      url.Values{"age": {"old"}},
+			instancePtr: reflect.New(structType).Interface(),
+			wantErr:     dynamicstruct.ErrIncompatibleTypes,
+		},
+		{
+			name:        "bad_time",
+			values:      url.Values{"born": {"yesterday"}},
+			instancePtr: reflect.New(structType).Interface(),
+			wantErr:     dynamicstruct.ErrIncompatibleTypes,
+		},
+		{
+			name:        "bad_slice_element",
+			values:      url.Values{"score": {"1", "two"}},
+			instancePtr: reflect.New(structType).Interface(),
+			wantErr:     dynamicstruct.ErrIncompatibleTypes,
+		},
+		{
+			name:        "not_pointer",
+			values:      url.Values{},
+			instancePtr: PersonTest{},
+			wantErr:     dynamicstruct.ErrValueMustBePointer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := dynamicstruct.DecodeForm(tt.values, tt.instancePtr); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeForm() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("nil_request", func(t *testing.T) {
+		if err := dynamicstruct.DecodeQuery(nil, &PersonTest{}); !errors.Is(err, dynamicstruct.ErrValueCannotBeNil) {
+			t.Errorf("DecodeQuery() error = %v, want %v", err, dynamicstruct.ErrValueCannotBeNil)
+		}
+	})
+}
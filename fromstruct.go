@@ -0,0 +1,175 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SkippedField describes a field FromStruct could not copy into the builder.
+type SkippedField struct {
+	// Path is the field name, prefixed with the names of the embedded structs
+	// it was promoted from, such as "base.id".
+	Path   string
+	Type   reflect.Type
+	Reason string
+}
+
+type structConfig struct {
+	mirrors    bool
+	report     func(SkippedField)
+	collisions CollisionPolicy
+}
+
+type StructOption func(*structConfig)
+
+// WithExportedMirrors copies unexported fields as exported fields of the same
+// type, named with an upper case first letter, instead of skipping them.
+// Values are not copied, since unexported fields can't be read through
+// reflection.
+func WithExportedMirrors() StructOption {
+	return func(c *structConfig) {
+		c.mirrors = true
+	}
+}
+
+// WithStructCollisionPolicy resolves mirrored names that collide with other
+// fields of the struct, such as an unexported id next to an exported Id, with
+// policy instead of skipping the later field. Fields promoted from embedded
+// structs are still skipped when shadowed, as in Go.
+func WithStructCollisionPolicy(policy CollisionPolicy) StructOption {
+	return func(c *structConfig) {
+		c.collisions = policy
+	}
+}
+
+// WithSkipReport calls report for every field FromStruct skips.
+func WithSkipReport(report func(SkippedField)) StructOption {
+	return func(c *structConfig) {
+		c.report = report
+	}
+}
+
+// FromStruct creates a builder with the fields of the struct sample, or of the
+// struct sample points to, keeping their names, types and tags.
+//
+// reflect.StructOf rejects unexported fields, so fields from other packages
+// are handled explicitly: unexported fields are skipped, or mirrored with
+// WithExportedMirrors, and the exported fields of embedded unexported structs
+// are promoted into the builder the same way encoding/json sees them.
+func FromStruct(sample any, opts ...StructOption) (*Builder, error) {
+	cfg := structConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	structType := reflect.TypeOf(sample)
+	for structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, ErrInvalidInstance
+	}
+
+	builder := New()
+
+	if err := builder.copyStructFields(structType, "", &cfg); err != nil {
+		return nil, err
+	}
+
+	return builder, nil
+}
+
+func (b *Builder) copyStructFields(structType reflect.Type, prefix string, cfg *structConfig) error {
+	// Fields of the struct itself shadow fields promoted from embedded structs
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		path := prefix + field.Name
+
+		switch {
+		case field.Anonymous && field.PkgPath != "":
+			// Embedded unexported type, handled after the direct fields
+			continue
+		case field.Anonymous:
+			b.anonymousFields = append(b.anonymousFields, reflect.StructField{
+				Name:      field.Name,
+				Type:      field.Type,
+				Tag:       field.Tag,
+				Anonymous: true,
+			})
+		case field.Name == "_":
+			cfg.skip(path, field.Type, "blank field")
+		case field.PkgPath != "" && !cfg.mirrors:
+			cfg.skip(path, field.Type, "unexported field")
+		default:
+			name := field.Name
+			if field.PkgPath != "" {
+				name = ExportedName(name)
+			}
+
+			if b.hasField(name) {
+				if cfg.collisions == nil || prefix != "" {
+					cfg.skip(path, field.Type, fmt.Sprintf("name %s already used", name))
+
+					continue
+				}
+
+				var err error
+
+				name, err = cfg.collisions(name, field.Name, b.hasField)
+				if err != nil {
+					return fmt.Errorf("%w: field %s", err, path)
+				}
+			}
+
+			if err := b.addField(name, field.Type, []string{string(field.Tag)}); err != nil {
+				return fmt.Errorf("%w: field %s", err, path)
+			}
+		}
+	}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.Anonymous || field.PkgPath == "" {
+			continue
+		}
+
+		embeddedType := field.Type
+		if embeddedType.Kind() == reflect.Ptr {
+			embeddedType = embeddedType.Elem()
+		}
+
+		if embeddedType.Kind() != reflect.Struct {
+			cfg.skip(prefix+field.Name, field.Type, "embedded unexported non-struct type")
+
+			continue
+		}
+
+		if err := b.copyStructFields(embeddedType, prefix+field.Name+".", cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasField reports whether name is used by a field or an anonymous field.
+func (b *Builder) hasField(name string) bool {
+	if _, ok := b.fields[name]; ok {
+		return true
+	}
+
+	for _, field := range b.anonymousFields {
+		if field.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *structConfig) skip(path string, fieldType reflect.Type, reason string) {
+	if c.report != nil {
+		c.report(SkippedField{Path: path, Type: fieldType, Reason: reason})
+	}
+}
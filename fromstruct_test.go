@@ -0,0 +1,178 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+type auditInfo struct {
+	CreatedBy string `json:"created_by"`
+	revision  int
+}
+
+type OrderRecord struct {
+	PersonTest
+	*auditInfo
+	ID     int64  `json:"id"`
+	Status string `json:"status,omitempty"`
+	secret string
+	_      struct{}
+}
+
+func TestFromStruct(t *testing.T) {
+	var skipped []dynamicstruct.SkippedField
+
+	builder, err := dynamicstruct.FromStruct(&OrderRecord{}, dynamicstruct.WithSkipReport(func(field dynamicstruct.SkippedField) {
+		skipped = append(skipped, field)
+	}))
+	if err != nil {
+		t.Fatalf("FromStruct() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	var names []string
+	for i := 0; i < structType.NumField(); i++ {
+		names = append(names, structType.Field(i).Name)
+	}
+
+	wantNames := []string{"PersonTest", "ID", "Status", "CreatedBy"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("fields = %v, want %v", names, wantNames)
+	}
+
+	if field, _ := structType.FieldByName("Status"); field.Tag.Get("json") != "status,omitempty" {
+		t.Errorf("Status json tag = %q, want %q", field.Tag.Get("json"), "status,omitempty")
+	}
+
+	if !structType.Field(0).Anonymous {
+		t.Error("PersonTest is not embedded")
+	}
+
+	var paths []string
+	for _, field := range skipped {
+		paths = append(paths, field.Path)
+	}
+
+	wantPaths := []string{"secret", "_", "auditInfo.revision"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("skipped = %v, want %v", paths, wantPaths)
+	}
+
+	t.Run("json_matches_original", func(t *testing.T) {
+		original, _ := json.Marshal(OrderRecord{auditInfo: &auditInfo{CreatedBy: "x"}})
+
+		instancePtr := reflect.New(structType).Interface()
+		if err := json.Unmarshal(original, instancePtr); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		data, _ := json.Marshal(instancePtr)
+
+		// Promoted fields come after the direct fields, so compare as maps
+		var got, want map[string]any
+		_ = json.Unmarshal(data, &got)
+		_ = json.Unmarshal(original, &want)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Marshal() = %s, want %s", data, original)
+		}
+	})
+}
+
+func TestFromStructExportedMirrors(t *testing.T) {
+	var skipped []dynamicstruct.SkippedField
+
+	builder, err := dynamicstruct.FromStruct(OrderRecord{},
+		dynamicstruct.WithExportedMirrors(),
+		dynamicstruct.WithSkipReport(func(field dynamicstruct.SkippedField) {
+			skipped = append(skipped, field)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("FromStruct() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	for _, name := range []string{"Secret", "Revision"} {
+		field, ok := structType.FieldByName(name)
+		if !ok {
+			t.Errorf("mirror %s not found", name)
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			t.Errorf("mirror %s is not exported", name)
+		}
+	}
+
+	if len(skipped) != 1 || skipped[0].Path != "_" {
+		t.Errorf("skipped = %v, want only _", skipped)
+	}
+}
+
+func TestFromStructShadowedFields(t *testing.T) {
+	type inner struct {
+		Name  string
+		Extra int
+	}
+
+	type outer struct {
+		inner
+		Name string
+	}
+
+	var skipped []dynamicstruct.SkippedField
+
+	builder, _ := dynamicstruct.FromStruct(outer{}, dynamicstruct.WithSkipReport(func(field dynamicstruct.SkippedField) {
+		skipped = append(skipped, field)
+	}))
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if reflect.TypeOf(instance).NumField() != 2 {
+		t.Errorf("fields = %d, want 2", reflect.TypeOf(instance).NumField())
+	}
+
+	if len(skipped) != 1 || skipped[0].Path != "inner.Name" {
+		t.Errorf("skipped = %v, want inner.Name", skipped)
+	}
+}
+
+func TestFromStructInvalidSample(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample any
+	}{
+		{name: "nil", sample: nil},
+		{name: "scalar", sample: 42},
+		{name: "pointer_to_map", sample: &map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dynamicstruct.FromStruct(tt.sample); !errors.Is(err, dynamicstruct.ErrInvalidInstance) {
+				t.Errorf("FromStruct() error = %v, want %v", err, dynamicstruct.ErrInvalidInstance)
+			}
+		})
+	}
+}
@@ -2,4 +2,14 @@ module github.com/gosmos-space/dynamicstruct
 
 go 1.18
 
-require github.com/fatih/structtag v1.2.0
+require (
+	github.com/fatih/structtag v1.2.0
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+)
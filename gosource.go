@@ -0,0 +1,343 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoString returns the source of a Go file in package pkgName that declares
+// the struct as a named type structName, with the imports its field types
+// need. It lets a shape prototyped with the builder be frozen into real code.
+func (b *Builder) GoString(structName, pkgName string) (string, error) {
+	if !token.IsIdentifier(structName) {
+		return "", fmt.Errorf("%w: struct name %q", ErrInvalidName, structName)
+	}
+
+	if !token.IsIdentifier(pkgName) {
+		return "", fmt.Errorf("%w: package name %q", ErrInvalidName, pkgName)
+	}
+
+	b.m.Lock()
+	fields, err := b.resolveStructFields(nil)
+	b.m.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+
+	imports := newGoImports()
+	imports.reserve(structName)
+
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "type %s ", structName)
+	imports.writeStruct(&body, fields)
+	body.WriteString("\n")
+
+	var src strings.Builder
+
+	fmt.Fprintf(&src, "package %s\n\n", pkgName)
+	imports.writeTo(&src)
+	src.WriteString(body.String())
+
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return "", fmt.Errorf("format generated source: %w", err)
+	}
+
+	return string(formatted), nil
+}
+
+// goImports assigns each imported package path a unique name.
+type goImports struct {
+	byPath map[string]string
+	byName map[string]string
+}
+
+func newGoImports() *goImports {
+	return &goImports{
+		byPath: make(map[string]string),
+		byName: make(map[string]string),
+	}
+}
+
+// qualify returns the name to refer to the package at pkgPath by, importing it
+// under name, or under a numbered alias when name is already taken.
+func (im *goImports) qualify(pkgPath, name string) string {
+	if alias, ok := im.byPath[pkgPath]; ok {
+		return alias
+	}
+
+	alias := name
+	for i := 2; im.taken(alias); i++ {
+		alias = name + strconv.Itoa(i)
+	}
+
+	im.byPath[pkgPath] = alias
+	im.byName[alias] = pkgPath
+
+	return alias
+}
+
+// reserve keeps name from being used as an import name, so imports don't
+// shadow the declared type.
+func (im *goImports) reserve(name string) {
+	im.byName[name] = ""
+}
+
+func (im *goImports) taken(name string) bool {
+	_, ok := im.byName[name]
+
+	return ok
+}
+
+func (im *goImports) writeTo(w *strings.Builder) {
+	if len(im.byPath) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(im.byPath))
+	for pkgPath := range im.byPath {
+		paths = append(paths, pkgPath)
+	}
+
+	sort.Strings(paths)
+
+	w.WriteString("import (\n")
+
+	for _, pkgPath := range paths {
+		if alias := im.byPath[pkgPath]; alias != packageNameFromPath(pkgPath) {
+			fmt.Fprintf(w, "\t%s %q\n", alias, pkgPath)
+		} else {
+			fmt.Fprintf(w, "\t%q\n", pkgPath)
+		}
+	}
+
+	w.WriteString(")\n\n")
+}
+
+func (im *goImports) writeStruct(w *strings.Builder, fields []reflect.StructField) {
+	if len(fields) == 0 {
+		w.WriteString("struct{}")
+
+		return
+	}
+
+	w.WriteString("struct {\n")
+
+	for _, field := range fields {
+		if description := field.Tag.Get(descriptionTagKey); description != "" {
+			writeComment(w, description)
+		}
+
+		if !field.Anonymous {
+			w.WriteString(field.Name)
+			w.WriteString(" ")
+		}
+
+		im.writeType(w, field.Type)
+
+		if field.Tag != "" {
+			w.WriteString(" ")
+			w.WriteString(quoteTag(string(field.Tag)))
+		}
+
+		w.WriteString("\n")
+	}
+
+	w.WriteString("}")
+}
+
+func (im *goImports) writeType(w *strings.Builder, t reflect.Type) {
+	if t.Name() != "" {
+		im.writeNamedType(w, t)
+
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		w.WriteString("*")
+		im.writeType(w, t.Elem())
+	case reflect.Slice:
+		w.WriteString("[]")
+		im.writeType(w, t.Elem())
+	case reflect.Array:
+		fmt.Fprintf(w, "[%d]", t.Len())
+		im.writeType(w, t.Elem())
+	case reflect.Map:
+		w.WriteString("map[")
+		im.writeType(w, t.Key())
+		w.WriteString("]")
+		im.writeType(w, t.Elem())
+	case reflect.Chan:
+		im.writeChanType(w, t)
+	case reflect.Func:
+		w.WriteString("func")
+		im.writeSignature(w, t)
+	case reflect.Interface:
+		im.writeInterface(w, t)
+	case reflect.Struct:
+		fields := make([]reflect.StructField, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			fields = append(fields, t.Field(i))
+		}
+
+		im.writeStruct(w, fields)
+	default:
+		w.WriteString(t.String())
+	}
+}
+
+// typeArgPattern matches package qualified identifiers inside the type
+// arguments of an instantiated generic type, such as "example.com/pkg.Type".
+var typeArgPattern = regexp.MustCompile(`([A-Za-z0-9_.~/-]+)\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+func (im *goImports) writeNamedType(w *strings.Builder, t reflect.Type) {
+	name := t.Name()
+
+	if t.PkgPath() != "" {
+		// String() is qualified with the package name, which may differ from
+		// the last element of the import path
+		pkgName := strings.TrimSuffix(t.String(), "."+name)
+		w.WriteString(im.qualify(t.PkgPath(), pkgName))
+		w.WriteString(".")
+	}
+
+	// Type arguments of generic types are qualified with full import paths
+	if i := strings.IndexByte(name, '['); i > 0 {
+		args := typeArgPattern.ReplaceAllStringFunc(name[i:], func(ident string) string {
+			match := typeArgPattern.FindStringSubmatch(ident)
+
+			return im.qualify(match[1], packageNameFromPath(match[1])) + "." + match[2]
+		})
+		name = name[:i] + args
+	}
+
+	w.WriteString(name)
+}
+
+func (im *goImports) writeChanType(w *strings.Builder, t reflect.Type) {
+	switch t.ChanDir() {
+	case reflect.RecvDir:
+		w.WriteString("<-chan ")
+	case reflect.SendDir:
+		w.WriteString("chan<- ")
+	default:
+		w.WriteString("chan ")
+
+		// chan (<-chan T) needs parentheses to not parse as chan<- chan T
+		if elem := t.Elem(); elem.Name() == "" && elem.Kind() == reflect.Chan && elem.ChanDir() == reflect.RecvDir {
+			w.WriteString("(")
+			im.writeType(w, elem)
+			w.WriteString(")")
+
+			return
+		}
+	}
+
+	im.writeType(w, t.Elem())
+}
+
+func (im *goImports) writeSignature(w *strings.Builder, t reflect.Type) {
+	w.WriteString("(")
+
+	for i := 0; i < t.NumIn(); i++ {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			w.WriteString("...")
+			im.writeType(w, t.In(i).Elem())
+
+			continue
+		}
+
+		im.writeType(w, t.In(i))
+	}
+
+	w.WriteString(")")
+
+	switch t.NumOut() {
+	case 0:
+	case 1:
+		w.WriteString(" ")
+		im.writeType(w, t.Out(0))
+	default:
+		w.WriteString(" (")
+
+		for i := 0; i < t.NumOut(); i++ {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+
+			im.writeType(w, t.Out(i))
+		}
+
+		w.WriteString(")")
+	}
+}
+
+func (im *goImports) writeInterface(w *strings.Builder, t reflect.Type) {
+	if t.NumMethod() == 0 {
+		w.WriteString("any")
+
+		return
+	}
+
+	w.WriteString("interface {\n")
+
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+
+		w.WriteString(method.Name)
+		im.writeSignature(w, method.Type)
+		w.WriteString("\n")
+	}
+
+	w.WriteString("}")
+}
+
+// packageNameFromPath guesses the package name for an import path, skipping
+// major version suffixes such as "/v2" and ".v3".
+func packageNameFromPath(pkgPath string) string {
+	name := path.Base(pkgPath)
+
+	if isMajorVersion(name) && path.Dir(pkgPath) != "." {
+		name = path.Base(path.Dir(pkgPath))
+	}
+
+	if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+
+	return name
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+
+	_, err := strconv.Atoi(s[1:])
+
+	return err == nil
+}
+
+func quoteTag(tag string) string {
+	if strings.ContainsAny(tag, "`\n") {
+		return strconv.Quote(tag)
+	}
+
+	return "`" + tag + "`"
+}
@@ -0,0 +1,125 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestGoString(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+	_ = builder.AddField("CreatedAt", time.Time{}, `json:"created_at"`)
+	_ = builder.AddField("History", ListTest[time.Time]{})
+	_ = builder.AddField("Scores", map[string][]float64{})
+	_ = builder.AddField("Events", make(<-chan struct{}))
+	_ = builder.AddField("Format", func(string, ...any) (string, error) { return "", nil })
+	_ = builder.AddField("Label", (*fmt.Stringer)(nil))
+	_ = builder.AddField("Nested", struct {
+		Value string `yaml:"value"`
+	}{})
+
+	src, err := builder.GoString("Record", "models")
+	if err != nil {
+		t.Fatalf("GoString() error = %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "record.go", src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, src)
+	}
+
+	wantLines := []string{
+		"package models",
+		`"fmt"`,
+		`"time"`,
+		`"github.com/gosmos-space/dynamicstruct_test"`,
+		"type Record struct {",
+		"dynamicstruct_test.PersonTest",
+		"ID int64 `json:\"id\"`",
+		"CreatedAt time.Time `json:\"created_at\"`",
+		"History dynamicstruct_test.ListTest[time.Time]",
+		"Scores map[string][]float64",
+		"Events <-chan struct{}",
+		"Format func(string, ...any) (string, error)",
+		"Label *fmt.Stringer",
+		"Nested struct { Value string `yaml:\"value\"` }",
+	}
+
+	// Compare with whitespace collapsed, since gofmt aligns fields
+	normalized := strings.Join(strings.Fields(src), " ")
+
+	for _, want := range wantLines {
+		if !strings.Contains(normalized, want) {
+			t.Errorf("GoString() missing %q in\n%s", want, src)
+		}
+	}
+}
+
+func TestGoStringNoImports(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+
+	src, err := builder.GoString("Item", "main")
+	if err != nil {
+		t.Fatalf("GoString() error = %v", err)
+	}
+
+	want := "package main\n\ntype Item struct {\n\tName string\n}\n"
+	if src != want {
+		t.Errorf("GoString() = %q, want %q", src, want)
+	}
+}
+
+func TestGoStringImportAliases(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Text", (*texttemplate.Template)(nil))
+	_ = builder.AddField("HTML", (*htmltemplate.Template)(nil))
+
+	src, err := builder.GoString("template", "views")
+	if err != nil {
+		t.Fatalf("GoString() error = %v", err)
+	}
+
+	normalized := strings.Join(strings.Fields(src), " ")
+
+	for _, want := range []string{
+		`template3 "html/template"`,
+		`template2 "text/template"`,
+		"Text *template2.Template",
+		"HTML *template3.Template",
+	} {
+		if !strings.Contains(normalized, want) {
+			t.Errorf("GoString() missing %q in\n%s", want, src)
+		}
+	}
+}
+
+func TestGoStringInvalidNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		structName string
+		pkgName    string
+	}{
+		{name: "empty_struct_name", structName: "", pkgName: "main"},
+		{name: "invalid_struct_name", structName: "My Struct", pkgName: "main"},
+		{name: "invalid_package_name", structName: "Item", pkgName: "my-pkg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamicstruct.New().GoString(tt.structName, tt.pkgName)
+			if !errors.Is(err, dynamicstruct.ErrInvalidName) {
+				t.Errorf("GoString() error = %v, want %v", err, dynamicstruct.ErrInvalidName)
+			}
+		})
+	}
+}
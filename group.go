@@ -0,0 +1,116 @@
+package dynamicstruct
+
+import "reflect"
+
+// Group puts the field in the named group, whatever group is open with
+// BeginGroup.
+func Group(name string) FieldOption {
+	return func(c *fieldConfig) {
+		c.group = name
+	}
+}
+
+// BeginGroup opens the named group: fields added until EndGroup or the next
+// BeginGroup are in it. Groups section large definitions, such as the fields
+// of a form, without changing the built type:
+//
+//	builder.BeginGroup("Audit").
+//		Field("CreatedAt", time.Time{}).
+//		Field("CreatedBy", "").
+//		EndGroup()
+//
+// It returns the builder, for chained definitions.
+func (b *Builder) BeginGroup(name string) *Builder {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.group = name
+
+	return b
+}
+
+// EndGroup closes the group opened with BeginGroup, so fields added next are
+// in no group. It returns the builder, for chained definitions.
+func (b *Builder) EndGroup() *Builder {
+	return b.BeginGroup("")
+}
+
+// setGroup puts the named field in group.
+func (b *Builder) setGroup(name, group string) {
+	if b.meta == nil {
+		b.meta = make(map[string]fieldMeta)
+	}
+
+	meta := b.meta[name]
+	meta.group = group
+	b.meta[name] = meta
+}
+
+// FieldGroup returns the group of the named field, or an empty string when it
+// is in none.
+func (b *Builder) FieldGroup(field string) string {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	return b.meta[field].group
+}
+
+// Groups returns the groups of the fields, in the order of their first
+// field.
+func (b *Builder) Groups() []string {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	return b.groups()
+}
+
+func (b *Builder) groups() []string {
+	var groups []string
+
+	seen := make(map[string]bool)
+
+	for _, name := range b.order {
+		group := b.meta[name].group
+		if group == "" || seen[group] {
+			continue
+		}
+
+		seen[group] = true
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// BuildGroup returns a new instance of a struct with the fields of the named
+// group, in their usual order and with their default values, as BuildProfile
+// does for profiles. An empty group selects the fields in no group, and
+// embedded fields are in no group.
+func (b *Builder) BuildGroup(group string) (any, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	fields, err := b.resolveStructFields(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]reflect.StructField, 0, len(fields))
+
+	for _, field := range fields {
+		if !field.Anonymous && b.meta[field.Name].group == group {
+			kept = append(kept, field)
+		}
+	}
+
+	structType, err := structOf(kept)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := reflect.New(structType).Elem()
+	b.applyDefaults(instance)
+	b.applyNestedDefaults(instance)
+
+	return instance.Interface(), nil
+}
@@ -0,0 +1,172 @@
+package dynamicstruct_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newGroupedBuilder() *dynamicstruct.Builder {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Id", 0, `json:"id"`)
+
+	builder.BeginGroup("Contact").
+		Field("Email", "", `json:"email"`).
+		Field("Phone", "", `json:"phone"`)
+
+	_ = builder.AddFieldWithOptions("Notes", "", dynamicstruct.Group("Internal"))
+
+	builder.BeginGroup("Audit")
+	_ = builder.AddFieldWithOptions("CreatedBy", "", dynamicstruct.Default("system"))
+	_ = builder.AddField("CreatedAt", time.Time{})
+	builder.EndGroup()
+
+	_ = builder.AddField("Name", "", `json:"name"`)
+
+	return builder
+}
+
+func TestFieldGroup(t *testing.T) {
+	builder := newGroupedBuilder()
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"Id", ""},
+		{"Email", "Contact"},
+		{"Phone", "Contact"},
+		{"Notes", "Internal"},
+		{"CreatedBy", "Audit"},
+		{"CreatedAt", "Audit"},
+		{"Name", ""},
+		{"Missing", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := builder.FieldGroup(tt.field); got != tt.want {
+				t.Errorf("FieldGroup(%q) = %q, want %q", tt.field, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("groups_in_field_order", func(t *testing.T) {
+		want := []string{"Contact", "Internal", "Audit"}
+		if got := builder.Groups(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Groups() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("kept_by_clone_and_rename", func(t *testing.T) {
+		clone := builder.Clone()
+		if err := clone.RenameField("Email", "Mail"); err != nil {
+			t.Fatalf("RenameField() error = %v", err)
+		}
+
+		if got := clone.FieldGroup("Mail"); got != "Contact" {
+			t.Errorf("FieldGroup(Mail) = %q, want Contact", got)
+		}
+	})
+
+	t.Run("kept_by_replace", func(t *testing.T) {
+		clone := builder.Clone()
+		clone.BeginGroup("Other")
+
+		if err := clone.ReplaceField("Phone", 0); err != nil {
+			t.Fatalf("ReplaceField() error = %v", err)
+		}
+
+		if got := clone.FieldGroup("Phone"); got != "Contact" {
+			t.Errorf("FieldGroup(Phone) = %q, want Contact", got)
+		}
+
+		if err := clone.ReplaceField("Name", 0); err != nil {
+			t.Fatalf("ReplaceField() error = %v", err)
+		}
+
+		if got := clone.FieldGroup("Name"); got != "" {
+			t.Errorf("FieldGroup(Name) = %q, want none", got)
+		}
+	})
+
+	t.Run("removed_with_field", func(t *testing.T) {
+		clone := builder.Clone()
+		_ = clone.RemoveField("Notes")
+
+		want := []string{"Contact", "Audit"}
+		if got := clone.Groups(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Groups() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestBuildGroup(t *testing.T) {
+	builder := newGroupedBuilder()
+
+	tests := []struct {
+		group string
+		want  []string
+	}{
+		{"Contact", []string{"Email", "Phone"}},
+		{"Audit", []string{"CreatedBy", "CreatedAt"}},
+		{"", []string{"Id", "Name"}},
+		{"Unknown", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			instance, err := builder.BuildGroup(tt.group)
+			if err != nil {
+				t.Fatalf("BuildGroup() error = %v", err)
+			}
+
+			got := fieldNames(reflect.TypeOf(instance))
+			if got == nil {
+				got = []string{}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildGroup() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		instance, _ := builder.BuildGroup("Audit")
+
+		if got := reflect.ValueOf(instance).FieldByName("CreatedBy").Interface(); got != "system" {
+			t.Errorf("CreatedBy = %v, want system", got)
+		}
+	})
+}
+
+func TestMarkdownDocGroups(t *testing.T) {
+	doc := newGroupedBuilder().MarkdownDoc()
+
+	sections := strings.Split(doc, "## ")
+	if len(sections) != 4 {
+		t.Fatalf("MarkdownDoc() has %d sections, want 4:\n%s", len(sections), doc)
+	}
+
+	tests := []struct {
+		section string
+		fields  []string
+	}{
+		{sections[0], []string{"`Id`", "`Name`"}},
+		{sections[1], []string{"Contact\n", "`Email`", "`Phone`"}},
+		{sections[2], []string{"Internal\n", "`Notes`"}},
+		{sections[3], []string{"Audit\n", "`CreatedBy`", "`CreatedAt`"}},
+	}
+
+	for _, tt := range tests {
+		for _, field := range tt.fields {
+			if !strings.Contains(tt.section, field) {
+				t.Errorf("section %q doesn't contain %s", tt.section, field)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// HotConverter converts instancePtr, a pointer to an instance of the
+// definition from, into a pointer to an instance of the definition to.
+type HotConverter func(instancePtr any, from, to *Builder) (any, error)
+
+// HotOption configures a Hot.
+type HotOption func(*Hot)
+
+// WithHotConverter sets how Convert carries instances over to the current
+// definition. By default their fields are copied into a new instance with
+// MigrateInstance.
+func WithHotConverter(converter HotConverter) HotOption {
+	return func(h *Hot) {
+		h.converter = converter
+	}
+}
+
+// Hot holds the current version of a definition that is reloaded at runtime,
+// such as one read from configuration. Swap replaces it atomically, while
+// readers keep using the builder they got from Current until they ask again,
+// and Convert brings instances decoded with an earlier version up to date.
+// Earlier definitions are kept for Convert until Retire forgets them. It is
+// safe for concurrent use.
+type Hot struct {
+	mu          sync.RWMutex
+	current     *Builder
+	currentType reflect.Type
+	version     uint64
+	builders    map[reflect.Type]hotVersion
+	converter   HotConverter
+}
+
+// hotVersion is a definition a Hot held, and the last version it was held as.
+type hotVersion struct {
+	builder *Builder
+	version uint64
+}
+
+// NewHot returns a Hot holding builder, which must be built, as version 1.
+func NewHot(builder *Builder, opts ...HotOption) (*Hot, error) {
+	structType, err := builtType(builder)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hot{
+		current:     builder,
+		currentType: structType,
+		version:     1,
+		builders:    map[reflect.Type]hotVersion{structType: {builder: builder, version: 1}},
+		converter:   migrateHot,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h, nil
+}
+
+// builtType returns the type the builder built.
+func builtType(b *Builder) (reflect.Type, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	return b.instance.Type(), nil
+}
+
+// Current returns the current definition and its version. Callers decode
+// with the builder they got for as long as they need to; a Swap doesn't
+// change it.
+func (h *Hot) Current() (*Builder, uint64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.current, h.version
+}
+
+// Swap makes next, which must be built, the current definition, and returns
+// its version. Later calls to Current return it.
+func (h *Hot) Swap(next *Builder) (uint64, error) {
+	structType, err := builtType(next)
+	if err != nil {
+		return 0, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.current = next
+	h.currentType = structType
+	h.version++
+
+	// Instances of a type held before keep converting from its first builder
+	held, ok := h.builders[structType]
+	if !ok {
+		held.builder = next
+	}
+
+	held.version = h.version
+	h.builders[structType] = held
+
+	return h.version, nil
+}
+
+// Retire forgets the definitions last held at version or earlier, other than
+// the current one, and Convert returns ErrIncompatibleTypes for their
+// instances from then on. Call it once no instances of those versions are
+// left to convert, so that a Hot swapped for the lifetime of a service
+// doesn't keep every definition it ever held.
+func (h *Hot) Retire(version uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for structType, held := range h.builders {
+		if held.version <= version && structType != h.currentType {
+			delete(h.builders, structType)
+		}
+	}
+}
+
+// Convert returns instancePtr, a pointer to an instance of the current or an
+// earlier definition, as an instance of the current one: as it is when it
+// already is, and converted by the converter otherwise. Instances of types
+// the Hot never held, or retired, return ErrIncompatibleTypes.
+func (h *Hot) Convert(instancePtr any) (any, error) {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.RLock()
+	to, toType := h.current, h.currentType
+	from, ok := h.builders[value.Type()]
+	h.mu.RUnlock()
+
+	if value.Type() == toType {
+		return instancePtr, nil
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: instance type %s is not a version of the definition", ErrIncompatibleTypes, value.Type())
+	}
+
+	return h.converter(instancePtr, from.builder, to)
+}
+
+func migrateHot(instancePtr any, _, to *Builder) (any, error) {
+	converted, err := to.NewInstance()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := MigrateInstance(instancePtr, converted); err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,197 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestHot(t *testing.T) {
+	v1 := dynamicstruct.New()
+	_ = v1.AddField("Region", "", `json:"region"`)
+	_, _ = v1.Build()
+
+	hot, err := dynamicstruct.NewHot(v1)
+	if err != nil {
+		t.Fatalf("NewHot() error = %v", err)
+	}
+
+	// A reader grabs version 1 and decodes with it
+	grabbed, version := hot.Current()
+	if grabbed != v1 || version != 1 {
+		t.Fatalf("Current() = %p, %d, want v1, 1", grabbed, version)
+	}
+
+	inFlight, _ := grabbed.NewInstance()
+	_ = json.Unmarshal([]byte(`{"region": "eu"}`), inFlight)
+
+	v2 := v1.Clone()
+	_ = v2.AddFieldWithOptions("Replicas", 0, dynamicstruct.Default(3), dynamicstruct.Tags(`json:"replicas"`))
+	_, _ = v2.Build()
+
+	version, err = hot.Swap(v2)
+	if err != nil || version != 2 {
+		t.Fatalf("Swap() = %d, %v, want 2, nil", version, err)
+	}
+
+	if current, _ := hot.Current(); current != v2 {
+		t.Errorf("Current() = %p, want v2", current)
+	}
+
+	t.Run("convert_in_flight", func(t *testing.T) {
+		converted, err := hot.Convert(inFlight)
+		if err != nil {
+			t.Fatalf("Convert() error = %v", err)
+		}
+
+		out, _ := json.Marshal(converted)
+		if string(out) != `{"region":"eu","replicas":3}` {
+			t.Errorf("Convert() = %s, want %s", out, `{"region":"eu","replicas":3}`)
+		}
+	})
+
+	t.Run("convert_current", func(t *testing.T) {
+		current, _ := v2.NewInstance()
+
+		if converted, _ := hot.Convert(current); converted != current {
+			t.Error("Convert() of a current instance returned a copy")
+		}
+	})
+
+	t.Run("convert_unknown_type", func(t *testing.T) {
+		if _, err := hot.Convert(&struct{ Other int }{}); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("Convert() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				_, _ = hot.Swap(v1)
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				builder, _ := hot.Current()
+				instancePtr, _ := builder.NewInstance()
+				_, _ = hot.Convert(instancePtr)
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestHotRetire(t *testing.T) {
+	versions := make([]*dynamicstruct.Builder, 3)
+	for i := range versions {
+		versions[i] = dynamicstruct.New()
+		_ = versions[i].AddField("Region", "")
+
+		for j := 0; j < i; j++ {
+			_ = versions[i].AddField("Extra"+string(rune('A'+j)), 0)
+		}
+
+		_, _ = versions[i].Build()
+	}
+
+	hot, _ := dynamicstruct.NewHot(versions[0])
+	_, _ = hot.Swap(versions[1])
+	_, _ = hot.Swap(versions[2])
+
+	v1Instance, _ := versions[0].NewInstance()
+	v2Instance, _ := versions[1].NewInstance()
+
+	hot.Retire(1)
+
+	if _, err := hot.Convert(v1Instance); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Convert(v1) error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	if _, err := hot.Convert(v2Instance); err != nil {
+		t.Errorf("Convert(v2) error = %v, want v2 kept", err)
+	}
+
+	// The current definition is never retired, even when it was held before
+	_, _ = hot.Swap(versions[0])
+	hot.Retire(4)
+
+	if _, err := hot.Convert(v2Instance); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Convert(v2) error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	if converted, err := hot.Convert(v1Instance); err != nil || converted != v1Instance {
+		t.Errorf("Convert(current) = %v, %v, want the instance itself", converted, err)
+	}
+}
+
+func TestWithHotConverter(t *testing.T) {
+	v1 := dynamicstruct.New()
+	_ = v1.AddField("Port", "")
+	_, _ = v1.Build()
+
+	v2 := dynamicstruct.New()
+	_ = v2.AddField("Port", 0)
+	_, _ = v2.Build()
+
+	var from, to *dynamicstruct.Builder
+
+	hot, _ := dynamicstruct.NewHot(v1, dynamicstruct.WithHotConverter(
+		func(instancePtr any, fromBuilder, toBuilder *dynamicstruct.Builder) (any, error) {
+			from, to = fromBuilder, toBuilder
+
+			converted, _ := toBuilder.NewInstance()
+			reflect.ValueOf(converted).Elem().FieldByName("Port").SetInt(8080)
+
+			return converted, nil
+		},
+	))
+
+	_, _ = hot.Swap(v2)
+
+	instancePtr, _ := v1.NewInstance()
+
+	converted, err := hot.Convert(instancePtr)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if from != v1 || to != v2 {
+		t.Error("converter called with the wrong definitions")
+	}
+
+	if port := reflect.ValueOf(converted).Elem().FieldByName("Port").Int(); port != 8080 {
+		t.Errorf("Port = %d, want 8080", port)
+	}
+}
+
+func TestHotErrors(t *testing.T) {
+	if _, err := dynamicstruct.NewHot(dynamicstruct.New()); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("NewHot() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+	_, _ = builder.Build()
+
+	hot, _ := dynamicstruct.NewHot(builder)
+
+	if _, err := hot.Swap(dynamicstruct.New()); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("Swap() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	if current, version := hot.Current(); current != builder || version != 1 {
+		t.Errorf("Current() after failed Swap = %p, %d, want builder, 1", current, version)
+	}
+}
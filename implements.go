@@ -0,0 +1,82 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MustImplement declares interfaces the built struct must satisfy, through
+// the methods of its embedded fields. Build and Rebuild check them and return
+// ErrNotImplemented naming the missing methods, rather than letting a broken
+// embed surface at call time. An interface is satisfied when either the struct
+// or a pointer to it implements it.
+//
+// Each argument must be an interface type, as returned by
+// reflect.TypeOf((*json.Marshaler)(nil)).Elem().
+func (b *Builder) MustImplement(ifaces ...reflect.Type) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	for _, iface := range ifaces {
+		if iface == nil || iface.Kind() != reflect.Interface {
+			return fmt.Errorf("%w: %v is not an interface type", ErrIncompatibleTypes, iface)
+		}
+	}
+
+	b.interfaces = append(b.interfaces, ifaces...)
+
+	return nil
+}
+
+// checkInterfaces returns an error for the first declared interface
+// structType doesn't implement.
+func (b *Builder) checkInterfaces(structType reflect.Type) error {
+	ptrType := reflect.PtrTo(structType)
+
+	for _, iface := range b.interfaces {
+		if structType.Implements(iface) || ptrType.Implements(iface) {
+			continue
+		}
+
+		var missing []string
+
+		for i := 0; i < iface.NumMethod(); i++ {
+			method := iface.Method(i)
+			if !hasMethod(structType, method) && !hasMethod(ptrType, method) {
+				missing = append(missing, method.Name)
+			}
+		}
+
+		return fmt.Errorf(
+			"%w: %s: missing method %s",
+			ErrNotImplemented,
+			iface.String(),
+			strings.Join(missing, ", "),
+		)
+	}
+
+	return nil
+}
+
+// hasMethod reports whether t has a method with the name and signature of the
+// interface method.
+func hasMethod(t reflect.Type, method reflect.Method) bool {
+	m, ok := t.MethodByName(method.Name)
+	if !ok {
+		return false
+	}
+
+	// Method types of concrete types include the receiver
+	in := make([]reflect.Type, 0, m.Type.NumIn()-1)
+	for i := 1; i < m.Type.NumIn(); i++ {
+		in = append(in, m.Type.In(i))
+	}
+
+	out := make([]reflect.Type, m.Type.NumOut())
+	for i := range out {
+		out[i] = m.Type.Out(i)
+	}
+
+	return reflect.FuncOf(in, out, m.Type.IsVariadic()) == method.Type
+}
@@ -0,0 +1,89 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+var (
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	stringerType  = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+func TestMustImplement(t *testing.T) {
+	tests := []struct {
+		name    string
+		embed   any
+		ifaces  []reflect.Type
+		wantErr error
+		wantMsg string
+	}{
+		{"satisfied_by_embed", time.Time{}, []reflect.Type{marshalerType, stringerType}, nil, ""},
+		{"no_embed", nil, []reflect.Type{marshalerType}, dynamicstruct.ErrNotImplemented, "missing method MarshalJSON"},
+		{"wrong_embed", PersonTest{}, []reflect.Type{stringerType}, dynamicstruct.ErrNotImplemented, "fmt.Stringer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+			if tt.embed != nil {
+				_ = builder.AddAnonymousField(tt.embed)
+			}
+
+			_ = builder.AddField("Label", "")
+
+			if err := builder.MustImplement(tt.ifaces...); err != nil {
+				t.Fatalf("MustImplement() error = %v", err)
+			}
+
+			_, err := builder.Build()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Build() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Build() error = %v, want it to mention %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestMustImplementNotInterface(t *testing.T) {
+	builder := dynamicstruct.New()
+
+	if err := builder.MustImplement(reflect.TypeOf(0)); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("MustImplement() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+}
+
+func TestMustImplementRebuild(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(time.Time{})
+	_ = builder.AddField("Label", "")
+	_ = builder.MustImplement(marshalerType)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_, err := builder.Rebuild(func(b *dynamicstruct.Builder) error {
+		b.Reset()
+		_ = b.AddField("Extra", 0)
+
+		return nil
+	})
+	if !errors.Is(err, dynamicstruct.ErrNotImplemented) {
+		t.Fatalf("Rebuild() error = %v, want %v", err, dynamicstruct.ErrNotImplemented)
+	}
+
+	if _, err := builder.GetField("Label"); err != nil {
+		t.Errorf("GetField() after failed Rebuild error = %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sampleKind is a set of the JSON types seen for a value.
+type sampleKind uint16
+
+const (
+	kindBool sampleKind = 1 << iota
+	kindInt
+	kindFloat
+	kindString
+	kindObject
+	kindArray
+	kindUint
+	kindTime
+	kindOther
+)
+
+// inferredShape is the union of the values seen at one place in a stream of
+// documents.
+type inferredShape struct {
+	kinds sampleKind
+	// other is the Go type of the values of kindOther, such as []byte, and
+	// mixed is set when they didn't all have the same.
+	other reflect.Type
+	mixed bool
+	// nulls is set when the value was null at least once.
+	nulls bool
+	// present counts the objects the value was found in, against the objects
+	// counted by its parent.
+	present int
+	objects int
+	keys    map[string]*inferredShape
+	elem    *inferredShape
+}
+
+func (s *inferredShape) merge(value any) {
+	switch v := value.(type) {
+	case nil:
+		s.nulls = true
+	case bool:
+		s.kinds |= kindBool
+	case string:
+		s.kinds |= kindString
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			s.kinds |= kindInt
+		} else {
+			s.kinds |= kindFloat
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+		s.kinds |= kindInt
+	case uint64:
+		s.kinds |= kindUint
+	case float32, float64:
+		s.kinds |= kindFloat
+	case time.Time:
+		s.kinds |= kindTime
+	case map[any]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[fmt.Sprint(key)] = item
+		}
+
+		s.merge(object)
+	case map[string]any:
+		s.kinds |= kindObject
+		s.objects++
+
+		if s.keys == nil {
+			s.keys = make(map[string]*inferredShape)
+		}
+
+		for key, item := range v {
+			child, ok := s.keys[key]
+			if !ok {
+				child = &inferredShape{}
+				s.keys[key] = child
+			}
+
+			child.present++
+			child.merge(item)
+		}
+	case []any:
+		s.kinds |= kindArray
+
+		if s.elem == nil {
+			s.elem = &inferredShape{}
+		}
+
+		for _, item := range v {
+			s.elem.merge(item)
+		}
+	default:
+		if s.other != nil && s.other != reflect.TypeOf(value) {
+			s.mixed = true
+		}
+
+		s.kinds |= kindOther
+		s.other = reflect.TypeOf(value)
+	}
+}
+
+// fieldType returns the type holding every value of the shape. Integers
+// widen to float64 when floats were seen too, or when both integers and
+// unsigned integers too large for int64 were, and values of different types
+// take the type of the conflict policy.
+func (s *inferredShape) fieldType(tagKey string, cfg *importConfig) (reflect.Type, error) {
+	kinds := s.kinds
+	if kinds&kindFloat != 0 || kinds&(kindInt|kindUint) == kindInt|kindUint {
+		kinds = kinds&^(kindInt|kindUint) | kindFloat
+	}
+
+	switch kinds {
+	case kindBool:
+		return reflect.TypeOf(false), nil
+	case kindInt:
+		return reflect.TypeOf(int64(0)), nil
+	case kindUint:
+		return reflect.TypeOf(uint64(0)), nil
+	case kindFloat:
+		return reflect.TypeOf(float64(0)), nil
+	case kindString:
+		return reflect.TypeOf(""), nil
+	case kindTime:
+		return timeType, nil
+	case kindOther:
+		if s.mixed {
+			return cfg.conflictType("values of different types")
+		}
+
+		return s.other, nil
+	case kindObject:
+		builder := New()
+		if err := s.addFields(builder, tagKey, cfg); err != nil {
+			return nil, err
+		}
+
+		return structOf(builder.buildStructFields())
+	case kindArray:
+		elemType, err := s.elem.fieldType(tagKey, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.elem.nulls {
+			elemType = optionalType(elemType)
+		}
+
+		return reflect.SliceOf(elemType), nil
+	case 0:
+		return anyType, nil
+	default:
+		return cfg.conflictType(kinds.String())
+	}
+}
+
+func (k sampleKind) String() string {
+	names := []string{"boolean", "integer", "number", "string", "object", "array", "unsigned integer", "timestamp", "other"}
+
+	var seen []string
+
+	for i, name := range names {
+		if k&(1<<i) != 0 {
+			seen = append(seen, name)
+		}
+	}
+
+	return strings.Join(seen, " and ")
+}
+
+// addFields adds a field per key of the objects of the shape, tagged with
+// tagKey. Keys missing from some of the objects, or null in some, become
+// pointers tagged omitempty.
+func (s *inferredShape) addFields(builder *Builder, tagKey string, cfg *importConfig) error {
+	keys := make([]string, 0, len(s.keys))
+	for key := range s.keys {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := s.keys[key]
+
+		fieldType, err := child.fieldType(tagKey, cfg)
+		if err != nil {
+			return fmt.Errorf("%w: key %q", err, key)
+		}
+
+		tag := fmt.Sprintf("%s:%q", tagKey, key)
+
+		if child.present < s.objects || child.nulls {
+			fieldType = optionalType(fieldType)
+			tag = fmt.Sprintf(`%s:"%s,omitempty"`, tagKey, key)
+		}
+
+		if err := builder.addImportedField(key, fieldType, tag, cfg); err != nil {
+			return fmt.Errorf("%w: key %q", err, key)
+		}
+	}
+
+	return nil
+}
+
+// optionalType returns a pointer to t, unless t can already be nil.
+func optionalType(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map:
+		return t
+	default:
+		return reflect.PtrTo(t)
+	}
+}
+
+// InferSchema infers a builder from a stream of JSON objects, such as
+// newline-delimited JSON, taking the union of their keys so that one sample
+// missing a key or holding a null doesn't decide the type. Keys missing from
+// some objects or null in some become pointer fields tagged omitempty,
+// integers widen to float64 when floats are seen for the same key, and keys
+// holding values of different JSON types are typed by the ConflictPolicy of
+// WithInferenceOptions, any by default. Nested objects, including those in
+// arrays, are merged the same way.
+//
+// A stream without objects, or with a document that isn't one, returns
+// ErrInvalidSample.
+func InferSchema(r io.Reader, opts ...ImportOption) (*Builder, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	root := &inferredShape{}
+
+	for n := 1; ; n++ {
+		var doc any
+
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: document %d: %s", ErrInvalidSample, n, err.Error())
+		}
+
+		if _, ok := doc.(map[string]any); !ok {
+			return nil, fmt.Errorf("%w: document %d must be an object", ErrInvalidSample, n)
+		}
+
+		root.merge(doc)
+	}
+
+	if root.objects == 0 {
+		return nil, fmt.Errorf("%w: no documents", ErrInvalidSample)
+	}
+
+	builder := New()
+	if err := root.addFields(builder, "json", newImportConfig(opts)); err != nil {
+		return nil, err
+	}
+
+	return builder, nil
+}
@@ -0,0 +1,99 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestInferSchema(t *testing.T) {
+	stream := strings.NewReader(`{"id": 1, "price": 10, "name": "pen", "tags": ["a"], "meta": {"color": "red"}}
+{"id": 2, "price": 2.5, "name": null, "code": "X1", "meta": {"size": 3}}
+{"id": 3, "price": 4, "name": "cap", "code": 7, "items": [{"sku": "A"}, {"sku": "B", "qty": 2}]}
+`)
+
+	builder, err := dynamicstruct.InferSchema(stream)
+	if err != nil {
+		t.Fatalf("InferSchema() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field    string
+		wantType string
+		wantTag  string
+	}{
+		{field: "Id", wantType: "int64", wantTag: "id"},
+		{field: "Price", wantType: "float64", wantTag: "price"},
+		{field: "Name", wantType: "*string", wantTag: "name,omitempty"},
+		{field: "Code", wantType: "interface {}", wantTag: "code,omitempty"},
+		{field: "Tags", wantType: "[]string", wantTag: "tags,omitempty"},
+		{
+			field:    "Meta",
+			wantType: `*struct { Color *string "json:\"color,omitempty\""; Size *int64 "json:\"size,omitempty\"" }`,
+			wantTag:  "meta,omitempty",
+		},
+		{
+			field:    "Items",
+			wantType: `[]struct { Qty *int64 "json:\"qty,omitempty\""; Sku string "json:\"sku\"" }`,
+			wantTag:  "items,omitempty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+
+			if field.Type.String() != tt.wantType {
+				t.Errorf("field %s type = %v, want %v", tt.field, field.Type, tt.wantType)
+			}
+
+			if field.Tag.Get("json") != tt.wantTag {
+				t.Errorf("field %s json tag = %q, want %q", tt.field, field.Tag.Get("json"), tt.wantTag)
+			}
+		})
+	}
+
+	t.Run("decodes_every_document", func(t *testing.T) {
+		instancePtr := reflect.New(structType).Interface()
+
+		if err := json.Unmarshal([]byte(`{"id": 2, "price": 2.5, "name": null, "code": "X1"}`), instancePtr); err != nil {
+			t.Errorf("Unmarshal() error = %v", err)
+		}
+	})
+}
+
+func TestInferSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  string
+		wantErr error
+	}{
+		{name: "empty_stream", stream: "", wantErr: dynamicstruct.ErrInvalidSample},
+		{name: "invalid_json", stream: "{\"id\": 1}\n{\"id\":", wantErr: dynamicstruct.ErrInvalidSample},
+		{name: "not_an_object", stream: "{\"id\": 1}\n[1]", wantErr: dynamicstruct.ErrInvalidSample},
+		{name: "colliding_keys", stream: `{"user_id": 1}` + "\n" + `{"user-id": 2}`, wantErr: dynamicstruct.ErrFieldAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamicstruct.InferSchema(strings.NewReader(tt.stream))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("InferSchema() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConflictPolicy decides the type of a field whose samples disagree, as when
+// a key holds a string in one document and a number in the next, or an array
+// mixes strings and numbers.
+type ConflictPolicy int
+
+const (
+	// ConflictInterface types conflicting values as any. It is the default.
+	ConflictInterface ConflictPolicy = iota
+	// ConflictError fails the inference with ErrConflictingTypes.
+	ConflictError
+	// ConflictString types conflicting values as string. YAML decodes any
+	// scalar into a string, but encoding/json rejects numbers and booleans
+	// for one, so JSON sources are better served by ConflictRawMessage.
+	ConflictString
+	// ConflictRawMessage types conflicting values as json.RawMessage, keeping
+	// them undecoded for the caller to interpret.
+	ConflictRawMessage
+)
+
+// InferenceOptions configure how FromJSONSample, FromYAMLSample,
+// FromMsgpackSample and InferSchema type the values of samples.
+type InferenceOptions struct {
+	// Conflicts decides the type of values whose samples disagree.
+	Conflicts ConflictPolicy
+}
+
+// WithInferenceOptions sets the options the importers infer types from
+// samples with.
+func WithInferenceOptions(options InferenceOptions) ImportOption {
+	return func(c *importConfig) {
+		c.inference = options
+	}
+}
+
+// conflictType returns the type of values that disagree as described by
+// conflict, following the conflict policy.
+func (c *importConfig) conflictType(conflict string) (reflect.Type, error) {
+	switch c.inference.Conflicts {
+	case ConflictError:
+		return nil, fmt.Errorf("%w: %s", ErrConflictingTypes, conflict)
+	case ConflictString:
+		return reflect.TypeOf(""), nil
+	case ConflictRawMessage:
+		return rawMessageType, nil
+	default:
+		return anyType, nil
+	}
+}
@@ -0,0 +1,91 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestWithInferenceOptions(t *testing.T) {
+	stream := `{"code": "X1", "values": [1, "two"]}` + "\n" + `{"code": 7, "values": []}`
+
+	tests := []struct {
+		name      string
+		conflicts dynamicstruct.ConflictPolicy
+		wantType  reflect.Type
+	}{
+		{name: "interface", conflicts: dynamicstruct.ConflictInterface, wantType: reflect.TypeOf((*any)(nil)).Elem()},
+		{name: "string", conflicts: dynamicstruct.ConflictString, wantType: reflect.TypeOf("")},
+		{name: "raw_message", conflicts: dynamicstruct.ConflictRawMessage, wantType: reflect.TypeOf(json.RawMessage(nil))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := dynamicstruct.WithInferenceOptions(dynamicstruct.InferenceOptions{Conflicts: tt.conflicts})
+
+			builder, err := dynamicstruct.InferSchema(strings.NewReader(stream), options)
+			if err != nil {
+				t.Fatalf("InferSchema() error = %v", err)
+			}
+
+			instance, _ := builder.Build()
+			structType := reflect.TypeOf(instance)
+
+			if field, _ := structType.FieldByName("Code"); field.Type != tt.wantType {
+				t.Errorf("InferSchema() Code type = %v, want %v", field.Type, tt.wantType)
+			}
+
+			if field, _ := structType.FieldByName("Values"); field.Type != reflect.SliceOf(tt.wantType) {
+				t.Errorf("InferSchema() Values type = %v, want %v", field.Type, reflect.SliceOf(tt.wantType))
+			}
+
+			builder, err = dynamicstruct.FromJSONSample([]byte(`{"values": [1, "two"]}`), options)
+			if err != nil {
+				t.Fatalf("FromJSONSample() error = %v", err)
+			}
+
+			instance, _ = builder.Build()
+
+			if field, _ := reflect.TypeOf(instance).FieldByName("Values"); field.Type != reflect.SliceOf(tt.wantType) {
+				t.Errorf("FromJSONSample() Values type = %v, want %v", field.Type, reflect.SliceOf(tt.wantType))
+			}
+		})
+	}
+
+	t.Run("raw_message_decodes", func(t *testing.T) {
+		options := dynamicstruct.WithInferenceOptions(dynamicstruct.InferenceOptions{Conflicts: dynamicstruct.ConflictRawMessage})
+
+		builder, _ := dynamicstruct.InferSchema(strings.NewReader(stream), options)
+		instance, _ := builder.Build()
+		instancePtr := reflect.New(reflect.TypeOf(instance)).Interface()
+
+		if err := json.Unmarshal([]byte(`{"code": 7}`), instancePtr); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		code := reflect.ValueOf(instancePtr).Elem().FieldByName("Code").Interface().(json.RawMessage)
+		if string(code) != "7" {
+			t.Errorf("Code = %s, want 7", code)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		options := dynamicstruct.WithInferenceOptions(dynamicstruct.InferenceOptions{Conflicts: dynamicstruct.ConflictError})
+
+		if _, err := dynamicstruct.InferSchema(strings.NewReader(stream), options); !errors.Is(err, dynamicstruct.ErrConflictingTypes) {
+			t.Errorf("InferSchema() error = %v, want %v", err, dynamicstruct.ErrConflictingTypes)
+		}
+
+		if _, err := dynamicstruct.FromYAMLSample([]byte("values: [1, two]\n"), options); !errors.Is(err, dynamicstruct.ErrConflictingTypes) {
+			t.Errorf("FromYAMLSample() error = %v, want %v", err, dynamicstruct.ErrConflictingTypes)
+		}
+
+		if _, err := dynamicstruct.InferSchema(strings.NewReader(`{"id": 1}`+"\n"+`{"id": 2.5}`), options); err != nil {
+			t.Errorf("InferSchema() widening error = %v, want nil", err)
+		}
+	})
+}
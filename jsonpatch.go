@@ -0,0 +1,410 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchError reports the operation of a JSON Patch that failed, by its index
+// in the patch. It wraps ErrInvalidPatch for malformed operations,
+// ErrFieldNotFound for paths that don't exist, ErrIncompatibleTypes for values
+// that don't decode into their target and ErrPatchTestFailed for failed test
+// operations.
+type PatchError struct {
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s %s): %s", e.Index, e.Op, e.Path, e.Err.Error())
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch applies a JSON Patch (RFC 6902) to the struct instancePtr
+// points to. The add, remove, replace and test operations are supported.
+// Path segments name struct fields by their json name, matched like
+// encoding/json does, or by their Go name; slice elements by index, or "-"
+// to append; and map entries by key. Removing a struct field sets it to the
+// zero value.
+//
+// Operations are applied in order to a copy that replaces the instance once
+// every operation applied, so a failing patch leaves the instance unchanged.
+// The error of a failing operation is a *PatchError.
+func ApplyJSONPatch(instancePtr any, patch []byte) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	var ops []patchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return fmt.Errorf("%w: JSON Patch must be an array of operations", ErrInvalidPatch)
+	}
+
+	patched := reflect.New(value.Type()).Elem()
+	patched.Set(value)
+
+	for i, op := range ops {
+		if err := applyPatchOperation(patched, op); err != nil {
+			return &PatchError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+	}
+
+	value.Set(patched)
+
+	return nil
+}
+
+func applyPatchOperation(value reflect.Value, op patchOperation) error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return fmt.Errorf("%w: %s operation without a value", ErrInvalidPatch, op.Op)
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("%w: unsupported operation %q", ErrInvalidPatch, op.Op)
+	}
+
+	tokens, err := pointerTokens(op.Path)
+	if err != nil {
+		return err
+	}
+
+	return walkPatch(value, tokens, func(container reflect.Value, token string) error {
+		switch container.Kind() {
+		case reflect.Struct:
+			return patchStructField(container, token, op)
+		case reflect.Slice, reflect.Array:
+			return patchElement(container, token, op)
+		case reflect.Map:
+			return patchMapEntry(container, token, op)
+		default:
+			return fmt.Errorf("%w: %s has no member %q", ErrFieldNotFound, container.Type(), token)
+		}
+	})
+}
+
+// pointerTokens splits a JSON Pointer (RFC 6901) into its unescaped tokens.
+// The empty pointer, which stands for the whole instance, isn't supported.
+func pointerTokens(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: operations on the whole instance aren't supported", ErrInvalidPatch)
+	}
+
+	if path[0] != '/' {
+		return nil, fmt.Errorf("%w: path %q doesn't start with /", ErrInvalidPatch, path)
+	}
+
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// walkPatch follows tokens from value and calls fn with the container the
+// last token is a member of. Pointers, slices and maps on the way are
+// replaced by copies, so that the instance the patch is applied to a copy of
+// isn't changed.
+func walkPatch(value reflect.Value, tokens []string, fn func(container reflect.Value, token string) error) error {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return fmt.Errorf("%w: %s is nil", ErrFieldNotFound, tokens[0])
+		}
+
+		return walkPatch(ownPointer(value), tokens, fn)
+	case reflect.Interface:
+		if value.IsNil() {
+			return fmt.Errorf("%w: %s is nil", ErrFieldNotFound, tokens[0])
+		}
+
+		concrete := reflect.New(value.Elem().Type()).Elem()
+		concrete.Set(value.Elem())
+
+		if err := walkPatch(concrete, tokens, fn); err != nil {
+			return err
+		}
+
+		value.Set(concrete)
+
+		return nil
+	case reflect.Slice, reflect.Map:
+		ownContainer(value)
+	}
+
+	if len(tokens) == 1 {
+		return fn(value, tokens[0])
+	}
+
+	token, rest := tokens[0], tokens[1:]
+
+	switch value.Kind() {
+	case reflect.Struct:
+		field, ok := patchField(value, token)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrFieldNotFound, token)
+		}
+
+		return walkPatch(field, rest, fn)
+	case reflect.Slice, reflect.Array:
+		i, err := patchIndex(value, token, false)
+		if err != nil {
+			return err
+		}
+
+		return walkPatch(value.Index(i), rest, fn)
+	case reflect.Map:
+		key, err := patchMapKey(value, token)
+		if err != nil {
+			return err
+		}
+
+		existing := value.MapIndex(key)
+		if !existing.IsValid() {
+			return fmt.Errorf("%w: key %s", ErrFieldNotFound, token)
+		}
+
+		// Map entries aren't addressable, so they are patched as copies
+		entry := reflect.New(existing.Type()).Elem()
+		entry.Set(existing)
+
+		if err := walkPatch(entry, rest, fn); err != nil {
+			return err
+		}
+
+		value.SetMapIndex(key, entry)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %s has no member %q", ErrFieldNotFound, value.Type(), token)
+	}
+}
+
+// ownContainer replaces a slice or map with a copy.
+func ownContainer(value reflect.Value) {
+	if value.IsNil() {
+		return
+	}
+
+	if value.Kind() == reflect.Slice {
+		owned := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(owned, value)
+		value.Set(owned)
+
+		return
+	}
+
+	owned := reflect.MakeMapWithSize(value.Type(), value.Len())
+
+	iter := value.MapRange()
+	for iter.Next() {
+		owned.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	value.Set(owned)
+}
+
+// patchField returns the field of the struct value that token names, by json
+// name or by Go name.
+func patchField(value reflect.Value, token string) (reflect.Value, bool) {
+	_, indexes := jsonFields(value.Type())
+
+	index, ok := indexes[token]
+	if !ok {
+		index, ok = foldJSONField(indexes, token)
+	}
+
+	if !ok {
+		field, found := value.Type().FieldByName(token)
+		if !found || field.PkgPath != "" {
+			return reflect.Value{}, false
+		}
+
+		index = field.Index
+	}
+
+	return ownedField(value, index), true
+}
+
+// patchIndex parses token as an index of the slice or array value. With
+// appending, "-" and the length, the index past the last element, are valid.
+func patchIndex(value reflect.Value, token string, appending bool) (int, error) {
+	if appending && token == "-" {
+		return value.Len(), nil
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid index %q", ErrInvalidPatch, token)
+	}
+
+	last := value.Len() - 1
+	if appending {
+		last++
+	}
+
+	if i > last {
+		return 0, fmt.Errorf("%w: index %d out of range", ErrFieldNotFound, i)
+	}
+
+	return i, nil
+}
+
+func patchMapKey(value reflect.Value, token string) (reflect.Value, error) {
+	if value.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, fmt.Errorf("%w: map keys of %s aren't strings", ErrIncompatibleTypes, value.Type())
+	}
+
+	return reflect.ValueOf(token).Convert(value.Type().Key()), nil
+}
+
+// decodePatchValue decodes raw into a new value of type t.
+func decodePatchValue(t reflect.Type, raw json.RawMessage) (reflect.Value, error) {
+	decoded := reflect.New(t)
+	if err := json.Unmarshal(raw, decoded.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error())
+	}
+
+	return decoded.Elem(), nil
+}
+
+// testPatchValue compares the JSON encodings of current and raw.
+func testPatchValue(current reflect.Value, raw json.RawMessage) error {
+	data, err := json.Marshal(current.Interface())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error())
+	}
+
+	var got, want any
+	if err := json.Unmarshal(data, &got); err != nil {
+		return fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error())
+	}
+
+	if err := json.Unmarshal(raw, &want); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("%w: value is %s", ErrPatchTestFailed, bytes.TrimSpace(data))
+	}
+
+	return nil
+}
+
+func patchStructField(container reflect.Value, token string, op patchOperation) error {
+	field, ok := patchField(container, token)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrFieldNotFound, token)
+	}
+
+	switch op.Op {
+	case "remove":
+		field.Set(reflect.Zero(field.Type()))
+
+		return nil
+	case "test":
+		return testPatchValue(field, op.Value)
+	default:
+		decoded, err := decodePatchValue(field.Type(), op.Value)
+		if err != nil {
+			return err
+		}
+
+		field.Set(decoded)
+
+		return nil
+	}
+}
+
+func patchElement(container reflect.Value, token string, op patchOperation) error {
+	isSlice := container.Kind() == reflect.Slice
+
+	if !isSlice && (op.Op == "add" || op.Op == "remove") {
+		return fmt.Errorf("%w: can't %s elements of array %s", ErrIncompatibleTypes, op.Op, container.Type())
+	}
+
+	i, err := patchIndex(container, token, op.Op == "add")
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add":
+		decoded, err := decodePatchValue(container.Type().Elem(), op.Value)
+		if err != nil {
+			return err
+		}
+
+		grown := reflect.MakeSlice(container.Type(), container.Len()+1, container.Len()+1)
+		reflect.Copy(grown, container.Slice(0, i))
+		grown.Index(i).Set(decoded)
+		reflect.Copy(grown.Slice(i+1, grown.Len()), container.Slice(i, container.Len()))
+		container.Set(grown)
+	case "remove":
+		shrunk := reflect.MakeSlice(container.Type(), container.Len()-1, container.Len()-1)
+		reflect.Copy(shrunk, container.Slice(0, i))
+		reflect.Copy(shrunk.Slice(i, shrunk.Len()), container.Slice(i+1, container.Len()))
+		container.Set(shrunk)
+	case "test":
+		return testPatchValue(container.Index(i), op.Value)
+	default:
+		decoded, err := decodePatchValue(container.Type().Elem(), op.Value)
+		if err != nil {
+			return err
+		}
+
+		container.Index(i).Set(decoded)
+	}
+
+	return nil
+}
+
+func patchMapEntry(container reflect.Value, token string, op patchOperation) error {
+	key, err := patchMapKey(container, token)
+	if err != nil {
+		return err
+	}
+
+	existing := container.MapIndex(key)
+	if !existing.IsValid() && op.Op != "add" {
+		return fmt.Errorf("%w: key %s", ErrFieldNotFound, token)
+	}
+
+	switch op.Op {
+	case "remove":
+		container.SetMapIndex(key, reflect.Value{})
+	case "test":
+		return testPatchValue(existing, op.Value)
+	default:
+		decoded, err := decodePatchValue(container.Type().Elem(), op.Value)
+		if err != nil {
+			return err
+		}
+
+		if container.IsNil() {
+			container.Set(reflect.MakeMap(container.Type()))
+		}
+
+		container.SetMapIndex(key, decoded)
+	}
+
+	return nil
+}
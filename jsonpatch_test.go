@@ -0,0 +1,109 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace_by_json_name",
+			patch: `[{"op": "replace", "path": "/email", "value": "a@example.com"}]`,
+			want:  `{"Name":"Alice","Age":30,"email":"a@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "replace_by_go_name",
+			patch: `[{"op": "replace", "path": "/Address/City", "value": "Shelbyville"}]`,
+			want:  `{"Name":"Alice","Age":30,"email":"alice@example.com","address":{"Street":"Main St","City":"Shelbyville"},"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "remove_field",
+			patch: `[{"op": "remove", "path": "/address"}, {"op": "remove", "path": "/Age"}]`,
+			want:  `{"Name":"Alice","Age":0,"email":"alice@example.com","address":null,"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "slice_elements",
+			patch: `[{"op": "add", "path": "/tags/0", "value": "z"}, {"op": "add", "path": "/tags/-", "value": "c"}, {"op": "remove", "path": "/tags/1"}, {"op": "replace", "path": "/tags/1", "value": "y"}]`,
+			want:  `{"Name":"Alice","Age":30,"email":"alice@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["z","y","c"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "map_entries",
+			patch: `[{"op": "add", "path": "/labels/region", "value": "eu"}, {"op": "remove", "path": "/labels/tier"}, {"op": "replace", "path": "/extra/theme/color", "value": "red"}]`,
+			want:  `{"Name":"Alice","Age":30,"email":"alice@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["a","b"],"labels":{"region":"eu","team":"core"},"extra":{"theme":{"color":"red","size":"m"}}}`,
+		},
+		{
+			name:  "test_then_replace",
+			patch: `[{"op": "test", "path": "/address", "value": {"Street": "Main St", "City": "Springfield"}}, {"op": "replace", "path": "/Name", "value": "Bob"}]`,
+			want:  `{"Name":"Bob","Age":30,"email":"alice@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instancePtr := newPatchInstance(t)
+
+			if err := dynamicstruct.ApplyJSONPatch(instancePtr, []byte(tt.patch)); err != nil {
+				t.Fatalf("ApplyJSONPatch() error = %v", err)
+			}
+
+			data, _ := json.Marshal(instancePtr)
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		patch     string
+		wantErr   error
+		wantIndex int
+	}{
+		{name: "not_array", patch: `{"op": "remove"}`, wantErr: dynamicstruct.ErrInvalidPatch, wantIndex: -1},
+		{name: "unsupported_op", patch: `[{"op": "move", "from": "/email", "path": "/Name"}]`, wantErr: dynamicstruct.ErrInvalidPatch},
+		{name: "missing_value", patch: `[{"op": "replace", "path": "/email"}]`, wantErr: dynamicstruct.ErrInvalidPatch},
+		{name: "whole_instance", patch: `[{"op": "replace", "path": "", "value": {}}]`, wantErr: dynamicstruct.ErrInvalidPatch},
+		{name: "unknown_field", patch: `[{"op": "replace", "path": "/phone", "value": "555"}]`, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "index_out_of_range", patch: `[{"op": "replace", "path": "/tags/2", "value": "c"}]`, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "missing_map_key", patch: `[{"op": "remove", "path": "/labels/owner"}]`, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "wrong_type", patch: `[{"op": "replace", "path": "/Age", "value": "old"}]`, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{
+			name:      "failed_test",
+			patch:     `[{"op": "replace", "path": "/Name", "value": "Bob"}, {"op": "test", "path": "/email", "value": "bob@example.com"}]`,
+			wantErr:   dynamicstruct.ErrPatchTestFailed,
+			wantIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instancePtr := newPatchInstance(t)
+			before, _ := json.Marshal(instancePtr)
+
+			err := dynamicstruct.ApplyJSONPatch(instancePtr, []byte(tt.patch))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyJSONPatch() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var patchErr *dynamicstruct.PatchError
+			if tt.wantIndex >= 0 && (!errors.As(err, &patchErr) || patchErr.Index != tt.wantIndex) {
+				t.Errorf("ApplyJSONPatch() error = %v, want a PatchError for operation %d", err, tt.wantIndex)
+			}
+
+			// A failing patch leaves the instance unchanged
+			if after, _ := json.Marshal(instancePtr); string(after) != string(before) {
+				t.Errorf("instance = %s, want %s", after, before)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+type schemaConfig struct {
+	id          string
+	title       string
+	description string
+	closed      bool
+}
+
+type SchemaOption func(*schemaConfig)
+
+// WithSchemaID sets the $id of the generated schema.
+func WithSchemaID(id string) SchemaOption {
+	return func(c *schemaConfig) {
+		c.id = id
+	}
+}
+
+// WithSchemaTitle sets the title of the generated schema.
+func WithSchemaTitle(title string) SchemaOption {
+	return func(c *schemaConfig) {
+		c.title = title
+	}
+}
+
+// WithSchemaDescription sets the description of the generated schema.
+func WithSchemaDescription(description string) SchemaOption {
+	return func(c *schemaConfig) {
+		c.description = description
+	}
+}
+
+// WithoutAdditionalProperties rejects properties that aren't fields of the
+// struct, or of nested structs, by setting additionalProperties to false.
+func WithoutAdditionalProperties() SchemaOption {
+	return func(c *schemaConfig) {
+		c.closed = true
+	}
+}
+
+// ToJSONSchema describes the struct as a JSON Schema (draft 2020-12) document.
+// Pointer fields are nullable, and constraints are taken from validate tags.
+func (b *Builder) ToJSONSchema(opts ...SchemaOption) ([]byte, error) {
+	cfg := schemaConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	b.m.Lock()
+	structType, err := b.resolveStructType()
+	b.m.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := structSchema(structType, func(s *schema) {
+		if typeName, ok := s.Type.(string); ok {
+			s.Type = []string{typeName, "null"}
+		}
+	})
+
+	toJSONSchemaDialect(s, cfg.closed)
+
+	s.Schema = jsonSchemaDialect
+	s.ID = cfg.id
+	s.Title = cfg.title
+	s.Description = cfg.description
+
+	return json.Marshal(s)
+}
+
+// toJSONSchemaDialect rewrites the OpenAPI specific formats schemaForType
+// produces into their JSON Schema equivalents.
+func toJSONSchemaDialect(s *schema, closed bool) {
+	switch s.Format {
+	case "int32", "int64", "float", "double":
+		s.Format = ""
+	case "byte":
+		s.Format = ""
+		s.ContentEncoding = "base64"
+	}
+
+	if s.Items != nil {
+		toJSONSchemaDialect(s.Items, closed)
+	}
+
+	if additional, ok := s.AdditionalProperties.(*schema); ok {
+		toJSONSchemaDialect(additional, closed)
+	}
+
+	if s.Properties != nil {
+		for _, property := range *s.Properties {
+			toJSONSchemaDialect(property.schema, closed)
+		}
+
+		if closed {
+			s.AdditionalProperties = false
+		}
+	}
+}
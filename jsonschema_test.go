@@ -0,0 +1,138 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestToJSONSchema(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0), `json:"id"`, `validate:"required,gt=0"`)
+	_ = builder.AddField("Name", "", `json:"name,omitempty"`, `validate:"required,min=2,max=64"`)
+	_ = builder.AddField("Email", (*string)(nil), `json:"email"`, `validate:"omitempty,email"`)
+	_ = builder.AddField("Role", "", `json:"role,omitempty"`, `validate:"oneof=admin user"`)
+	_ = builder.AddField("Score", float64(0), `json:"score,omitempty"`, `validate:"gte=0,lte=100"`)
+	_ = builder.AddField("Tags", []string{}, `json:"tags,omitempty"`, `validate:"max=5,dive,min=1"`)
+	_ = builder.AddField("Avatar", []byte{}, `json:"avatar,omitempty"`)
+	_ = builder.AddField("CreatedAt", time.Time{}, `json:"created_at"`)
+
+	data, err := builder.ToJSONSchema(
+		dynamicstruct.WithSchemaID("https://example.com/user.json"),
+		dynamicstruct.WithSchemaTitle("User"),
+	)
+	if err != nil {
+		t.Fatalf("ToJSONSchema() error = %v", err)
+	}
+
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema",` +
+		`"$id":"https://example.com/user.json","title":"User","type":"object","properties":{` +
+		`"id":{"type":"integer","exclusiveMinimum":0},` +
+		`"name":{"type":"string","minLength":2,"maxLength":64},` +
+		`"email":{"type":["string","null"],"format":"email"},` +
+		`"role":{"type":"string","enum":["admin","user"]},` +
+		`"score":{"type":"number","minimum":0,"maximum":100},` +
+		`"tags":{"type":"array","maxItems":5,"items":{"type":"string"}},` +
+		`"avatar":{"type":"string","contentEncoding":"base64"},` +
+		`"created_at":{"type":"string","format":"date-time"}},` +
+		`"required":["id","name","created_at"]}`
+
+	if string(data) != want {
+		t.Errorf("ToJSONSchema() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestToJSONSchemaOptions(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Address", struct {
+		City string `json:"city"`
+	}{}, `json:"address"`)
+	_ = builder.AddField("Labels", map[string]int{}, `json:"labels"`, `validate:"min=1"`)
+
+	data, err := builder.ToJSONSchema(
+		dynamicstruct.WithSchemaDescription("Nested shapes"),
+		dynamicstruct.WithoutAdditionalProperties(),
+	)
+	if err != nil {
+		t.Fatalf("ToJSONSchema() error = %v", err)
+	}
+
+	var got struct {
+		Description          string `json:"description"`
+		AdditionalProperties *bool  `json:"additionalProperties"`
+		Properties           map[string]struct {
+			AdditionalProperties any  `json:"additionalProperties"`
+			MinProperties        *int `json:"minProperties"`
+		} `json:"properties"`
+	}
+
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got.Description != "Nested shapes" {
+		t.Errorf("description = %q, want %q", got.Description, "Nested shapes")
+	}
+
+	if got.AdditionalProperties == nil || *got.AdditionalProperties {
+		t.Errorf("additionalProperties = %v, want false", got.AdditionalProperties)
+	}
+
+	if got.Properties["address"].AdditionalProperties != false {
+		t.Errorf("address additionalProperties = %v, want false", got.Properties["address"].AdditionalProperties)
+	}
+
+	if _, ok := got.Properties["labels"].AdditionalProperties.(map[string]any); !ok {
+		t.Errorf("labels additionalProperties = %v, want a schema", got.Properties["labels"].AdditionalProperties)
+	}
+
+	if minProperties := got.Properties["labels"].MinProperties; minProperties == nil || *minProperties != 1 {
+		t.Errorf("labels minProperties = %v, want 1", minProperties)
+	}
+}
+
+func TestSchemaBytes(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Avatar", []byte{}, `json:"avatar"`)
+	_ = builder.AddField("Checksum", [4]byte{}, `json:"checksum"`)
+
+	tests := []struct {
+		name   string
+		export func() ([]byte, error)
+		want   string
+	}{
+		{
+			name: "json_schema",
+			export: func() ([]byte, error) {
+				return builder.ToJSONSchema()
+			},
+			want: `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+				`"avatar":{"type":"string","contentEncoding":"base64"},` +
+				`"checksum":{"type":"array","items":{"type":"integer"}}},` +
+				`"required":["avatar","checksum"]}`,
+		},
+		{
+			name:   "openapi",
+			export: builder.ToOpenAPISchema,
+			want: `{"type":"object","properties":{` +
+				`"avatar":{"type":"string","format":"byte"},` +
+				`"checksum":{"type":"array","items":{"type":"integer","format":"int32"}}},` +
+				`"required":["avatar","checksum"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.export()
+			if err != nil {
+				t.Fatalf("export error = %v", err)
+			}
+
+			if string(data) != tt.want {
+				t.Errorf("export =\n%s\nwant\n%s", data, tt.want)
+			}
+		})
+	}
+}
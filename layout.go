@@ -0,0 +1,91 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// FieldLayout is where a field sits in memory: its offset from the start of
+// the struct, its size and alignment, and the padding between it and the
+// next field, or the end of the struct.
+type FieldLayout struct {
+	Name    string
+	Type    reflect.Type
+	Offset  uintptr
+	Size    uintptr
+	Align   uintptr
+	Padding uintptr
+}
+
+// Layout describes the memory layout of a built struct type.
+type Layout struct {
+	Fields []FieldLayout
+	Size   uintptr
+	Align  uintptr
+	// Padding is the total padding between and after the fields.
+	Padding uintptr
+}
+
+// LayoutReport returns the memory layout of the built type: the offset, size,
+// alignment and trailing padding of every field, and the size and total
+// padding of the struct. Embedded fields are named by their type. Compare it
+// with the report of a build with WithOptimizedLayout to see what reordering
+// the fields saves per instance.
+func (b *Builder) LayoutReport() (Layout, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	if b.instance == nil {
+		return Layout{}, ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+	layout := Layout{
+		Fields: make([]FieldLayout, structType.NumField()),
+		Size:   structType.Size(),
+		Align:  uintptr(structType.Align()),
+	}
+
+	for i := range layout.Fields {
+		field := structType.Field(i)
+
+		end := structType.Size()
+		if i+1 < structType.NumField() {
+			end = structType.Field(i + 1).Offset
+		}
+
+		layout.Fields[i] = FieldLayout{
+			Name:    field.Name,
+			Type:    field.Type,
+			Offset:  field.Offset,
+			Size:    field.Type.Size(),
+			Align:   uintptr(field.Type.Align()),
+			Padding: end - field.Offset - field.Type.Size(),
+		}
+
+		layout.Padding += layout.Fields[i].Padding
+	}
+
+	return layout, nil
+}
+
+// String formats the layout as a table, one line per field, followed by the
+// totals.
+func (l Layout) String() string {
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tTYPE\tOFFSET\tSIZE\tALIGN\tPADDING")
+
+	for _, field := range l.Fields {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", field.Name, field.Type, field.Offset, field.Size, field.Align, field.Padding)
+	}
+
+	_ = w.Flush()
+
+	fmt.Fprintf(&sb, "size %d, align %d, padding %d\n", l.Size, l.Align, l.Padding)
+
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestLayoutReport(t *testing.T) {
+	newBuilder := func() *dynamicstruct.Builder {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Active", false)
+		_ = builder.AddField("ID", int64(0))
+		_ = builder.AddField("Flag", false)
+
+		return builder
+	}
+
+	builder := newBuilder()
+
+	if _, err := builder.LayoutReport(); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("LayoutReport() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	_, _ = builder.Build()
+
+	layout, err := builder.LayoutReport()
+	if err != nil {
+		t.Fatalf("LayoutReport() error = %v", err)
+	}
+
+	word := unsafe.Sizeof(int64(0))
+	align := uintptr(unsafe.Alignof(int64(0)))
+
+	if layout.Size != 3*align || layout.Padding != 2*(align-1) {
+		t.Errorf("LayoutReport() size = %d, padding = %d, want %d, %d", layout.Size, layout.Padding, 3*align, 2*(align-1))
+	}
+
+	id := layout.Fields[1]
+	if id.Name != "ID" || id.Offset != align || id.Size != word || id.Align != align {
+		t.Errorf("LayoutReport() ID = %+v", id)
+	}
+
+	if !strings.Contains(layout.String(), "ID") || !strings.HasSuffix(layout.String(), "padding "+strconv.Itoa(int(layout.Padding))+"\n") {
+		t.Errorf("String() = %s", layout.String())
+	}
+
+	optimized := newBuilder()
+	_, _ = optimized.Build(dynamicstruct.WithOptimizedLayout())
+
+	optimizedLayout, _ := optimized.LayoutReport()
+	if optimizedLayout.Size >= layout.Size {
+		t.Errorf("optimized size = %d, want less than %d", optimizedLayout.Size, layout.Size)
+	}
+}
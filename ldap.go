@@ -0,0 +1,238 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ldapTagKey = "ldap"
+
+// ldapTimeLayouts are the forms of the LDAP GeneralizedTime syntax, with
+// and without fractional seconds.
+var ldapTimeLayouts = []string{"20060102150405Z0700", "20060102150405.999999999Z0700"}
+
+// FromLDAPEntry sets the fields of the struct instancePtr points to from the
+// attributes of a directory entry. Each field reads the attribute named by
+// its `ldap` tag, or by its own name, matched case-insensitively as LDAP
+// attribute names are; fields tagged ldap:"-" and attributes without a field
+// are skipped. Fields of embedded structs are read as if they were promoted.
+//
+// Slice fields take every value of a multi-valued attribute, and other fields
+// the single value of theirs. Values are parsed like SetFieldFromString does,
+// with times also read in the GeneralizedTime syntax ("20240501120000Z"), and
+// []byte fields take the raw value, for binary attributes such as jpegPhoto.
+// An attribute with several values for a field that isn't a slice, or a value
+// that doesn't parse, returns a *FieldError wrapping ErrIncompatibleTypes and
+// leaves the instance unchanged.
+func FromLDAPEntry(entry map[string][]string, instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	attributes := make(map[string][]string, len(entry))
+	for name, values := range entry {
+		attributes[strings.ToLower(name)] = values
+	}
+
+	decoded := reflect.New(value.Type()).Elem()
+	decoded.Set(value)
+
+	if err := decodeLDAPStruct(attributes, decoded); err != nil {
+		return err
+	}
+
+	value.Set(decoded)
+
+	return nil
+}
+
+func decodeLDAPStruct(attributes map[string][]string, value reflect.Value) error {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldValue := value.Field(i)
+
+		if field.Anonymous {
+			// The embedded struct is shared with the instance, so it is
+			// decoded into a copy
+			if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+				fieldValue = ownPointer(fieldValue)
+			}
+
+			if fieldValue.Kind() == reflect.Struct {
+				if err := decodeLDAPStruct(attributes, fieldValue); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := tagName(field, ldapTagKey)
+		if !ok {
+			continue
+		}
+
+		raws, ok := attributes[strings.ToLower(name)]
+		if !ok || len(raws) == 0 {
+			continue
+		}
+
+		if err := setLDAPValues(fieldValue, raws); err != nil {
+			return fieldError("decode", name, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func setLDAPValues(value reflect.Value, raws []string) error {
+	if value.Kind() == reflect.Slice && value.Type() != bytesType {
+		slice := reflect.MakeSlice(value.Type(), len(raws), len(raws))
+
+		for i, raw := range raws {
+			if err := setLDAPValue(slice.Index(i), raw); err != nil {
+				return err
+			}
+		}
+
+		value.Set(slice)
+
+		return nil
+	}
+
+	if len(raws) > 1 {
+		return fmt.Errorf("%d values for single-valued %s", len(raws), value.Type())
+	}
+
+	return setLDAPValue(value, raws[0])
+}
+
+func setLDAPValue(value reflect.Value, raw string) error {
+	switch value.Type() {
+	case bytesType:
+		value.SetBytes([]byte(raw))
+
+		return nil
+	case timeType:
+		for _, layout := range ldapTimeLayouts {
+			if t, err := time.Parse(layout, raw); err == nil {
+				value.Set(reflect.ValueOf(t))
+
+				return nil
+			}
+		}
+	}
+
+	if value.Kind() == reflect.Ptr {
+		elem := reflect.New(value.Type().Elem())
+		if err := setLDAPValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+
+		value.Set(elem)
+
+		return nil
+	}
+
+	return setFromString(value, raw)
+}
+
+// ToLDAPEntry returns the attributes of a directory entry for instance, a
+// struct or a pointer to one, named like FromLDAPEntry reads them. Slices
+// become multi-valued attributes, booleans TRUE or FALSE and times the
+// GeneralizedTime syntax in UTC. Zero values and nil pointers are left out,
+// as LDAP has no empty values.
+func ToLDAPEntry(instance any) (map[string][]string, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := make(map[string][]string)
+	encodeLDAPStruct(value, entry)
+
+	return entry, nil
+}
+
+func encodeLDAPStruct(value reflect.Value, entry map[string][]string) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldValue := value.Field(i)
+
+		if field.Anonymous {
+			if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+				if fieldValue.IsNil() {
+					continue
+				}
+
+				fieldValue = fieldValue.Elem()
+			}
+
+			if fieldValue.Kind() == reflect.Struct {
+				encodeLDAPStruct(fieldValue, entry)
+
+				continue
+			}
+		}
+
+		if field.PkgPath != "" || fieldValue.IsZero() {
+			continue
+		}
+
+		name, ok := tagName(field, ldapTagKey)
+		if !ok {
+			continue
+		}
+
+		var values []string
+
+		if fieldValue.Kind() == reflect.Slice && fieldValue.Type() != bytesType {
+			for j := 0; j < fieldValue.Len(); j++ {
+				values = append(values, ldapString(fieldValue.Index(j)))
+			}
+		} else {
+			values = []string{ldapString(fieldValue)}
+		}
+
+		if len(values) > 0 {
+			entry[name] = values
+		}
+	}
+}
+
+func ldapString(value reflect.Value) string {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Type() {
+	case bytesType:
+		return string(value.Bytes())
+	case timeType:
+		t, _ := value.Interface().(time.Time)
+
+		return t.UTC().Format(ldapTimeLayouts[0])
+	}
+
+	if value.Kind() == reflect.Bool {
+		return strings.ToUpper(strconv.FormatBool(value.Bool()))
+	}
+
+	return fmt.Sprint(value.Interface())
+}
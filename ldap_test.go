@@ -0,0 +1,158 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newLDAPBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("CN", "", `ldap:"cn"`)
+	_ = builder.AddField("Mail", []string{}, `ldap:"mail"`)
+	_ = builder.AddField("UIDNumber", 0, `ldap:"uidNumber"`)
+	_ = builder.AddField("Locked", false, `ldap:"pwdLocked"`)
+	_ = builder.AddField("Created", time.Time{}, `ldap:"createTimestamp"`)
+	_ = builder.AddField("Photo", []byte(nil), `ldap:"jpegPhoto"`)
+	_ = builder.AddField("Manager", (*string)(nil), `ldap:"manager"`)
+	_ = builder.AddField("Password", "", `ldap:"-"`)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestFromLDAPEntry(t *testing.T) {
+	builder := newLDAPBuilder(t)
+	instancePtr, _ := builder.InstanceAddr()
+
+	entry := map[string][]string{
+		"CN":              {"Alice"},
+		"mail":            {"alice@example.com", "a@example.com"},
+		"uidnumber":       {"1001"},
+		"pwdLocked":       {"TRUE"},
+		"createTimestamp": {"20240501120000Z"},
+		"jpegPhoto":       {"\xff\xd8"},
+		"manager":         {"cn=Bob"},
+		"objectClass":     {"person"},
+		"Password":        {"secret"},
+	}
+
+	if err := dynamicstruct.FromLDAPEntry(entry, instancePtr); err != nil {
+		t.Fatalf("FromLDAPEntry() error = %v", err)
+	}
+
+	manager := "cn=Bob"
+
+	tests := []struct {
+		field string
+		want  any
+	}{
+		{field: "CN", want: "Alice"},
+		{field: "Mail", want: []string{"alice@example.com", "a@example.com"}},
+		{field: "UIDNumber", want: 1001},
+		{field: "Locked", want: true},
+		{field: "Created", want: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{field: "Photo", want: []byte("\xff\xd8")},
+		{field: "Manager", want: &manager},
+		{field: "Password", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, _ := builder.GetField(tt.field)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromLDAPEntryErrors(t *testing.T) {
+	builder := newLDAPBuilder(t)
+	instancePtr, _ := builder.InstanceAddr()
+
+	tests := []struct {
+		name      string
+		entry     map[string][]string
+		wantField string
+	}{
+		{name: "multiple_values", entry: map[string][]string{"cn": {"Alice", "Al"}}, wantField: "cn"},
+		{name: "invalid_number", entry: map[string][]string{"cn": {"Alice"}, "uidNumber": {"x"}}, wantField: "uidNumber"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.FromLDAPEntry(tt.entry, instancePtr)
+			if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+				t.Fatalf("FromLDAPEntry() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+			}
+
+			var fieldErr *dynamicstruct.FieldError
+			if !errors.As(err, &fieldErr) || fieldErr.Field != tt.wantField {
+				t.Errorf("FromLDAPEntry() error = %v, want field %s", err, tt.wantField)
+			}
+
+			if cn, _ := builder.GetField("CN"); cn != "" {
+				t.Errorf("FromLDAPEntry() changed CN to %v", cn)
+			}
+		})
+	}
+
+	t.Run("embedded_pointer", func(t *testing.T) {
+		type Contact struct {
+			Mail string `ldap:"mail"`
+		}
+
+		type Entry struct {
+			*Contact
+			Age int `ldap:"age"`
+		}
+
+		contact := &Contact{Mail: "old@example.com"}
+		entry := &Entry{Contact: contact}
+
+		err := dynamicstruct.FromLDAPEntry(map[string][]string{"mail": {"new@example.com"}, "age": {"x"}}, entry)
+		if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Fatalf("FromLDAPEntry() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+
+		if contact.Mail != "old@example.com" || entry.Mail != "old@example.com" {
+			t.Errorf("Mail = %q, embedded Mail = %q, want both unchanged", entry.Mail, contact.Mail)
+		}
+	})
+}
+
+func TestToLDAPEntry(t *testing.T) {
+	builder := newLDAPBuilder(t)
+	_ = builder.SetFieldValue("CN", "Alice")
+	_ = builder.SetFieldValue("Mail", []string{"alice@example.com", "a@example.com"})
+	_ = builder.SetFieldValue("Locked", true)
+	_ = builder.SetFieldValue("Created", time.Date(2024, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))
+	_ = builder.SetFieldValue("Password", "secret")
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	entry, err := dynamicstruct.ToLDAPEntry(instancePtr)
+	if err != nil {
+		t.Fatalf("ToLDAPEntry() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"cn":              {"Alice"},
+		"mail":            {"alice@example.com", "a@example.com"},
+		"pwdLocked":       {"TRUE"},
+		"createTimestamp": {"20240501120000Z"},
+	}
+
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("ToLDAPEntry() = %v, want %v", entry, want)
+	}
+}
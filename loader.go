@@ -0,0 +1,250 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceEnv     Source = "env"
+	SourceQuery   Source = "query"
+	SourceJSON    Source = "json"
+)
+
+type LoaderOption func(*Loader)
+
+// WithProvenance makes the loader record which source supplied each field.
+func WithProvenance() LoaderOption {
+	return func(l *Loader) {
+		l.provenance = make(map[string]Source)
+	}
+}
+
+// Loader populates an instance from layered sources. Sources are applied in
+// call order, so later sources take precedence over earlier ones. Fields read
+// from the environment, query parameters or JSON go through Transform.
+type Loader struct {
+	value      reflect.Value
+	provenance map[string]Source
+}
+
+func NewLoader(instancePtr any, opts ...LoaderOption) (*Loader, error) {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := &Loader{value: value}
+
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader, nil
+}
+
+// Defaults sets the fields named by the keys of values, all or none: when any
+// value is invalid, the instance is left unchanged and the returned
+// FieldErrors lists every invalid entry, sorted by name.
+func (l *Loader) Defaults(values map[string]any) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	// Defaults are set on a copy, so that the instance is only changed when
+	// every value fits its field
+	updated := reflect.New(l.value.Type()).Elem()
+	updated.Set(l.value)
+
+	var errs FieldErrors
+
+	for _, name := range names {
+		if err := setDefault(updated, name, values[name]); err != nil {
+			errs = append(errs, &FieldError{Field: name, Op: "set", Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	l.value.Set(updated)
+
+	for _, name := range names {
+		l.record(name, SourceDefault)
+	}
+
+	return nil
+}
+
+// setDefault sets the field of value called name to the default value.
+func setDefault(value reflect.Value, name string, defaultValue any) error {
+	field, ok := ownedFieldByName(value, name)
+	if !ok || !field.CanSet() {
+		return missingField(value.Type(), name)
+	}
+
+	valueReflect := reflect.ValueOf(defaultValue)
+	if valueReflect.IsValid() && !valueReflect.Type().AssignableTo(field.Type()) {
+		converted, ok, err := convertTime(field.Type(), valueReflect)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error())
+		}
+
+		if ok {
+			valueReflect = converted
+		}
+	}
+
+	if !valueReflect.IsValid() || !valueReflect.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf(
+			"%w: field type: %s, value type: %T",
+			ErrIncompatibleTypes,
+			field.Type().String(),
+			defaultValue,
+		)
+	}
+
+	field.Set(valueReflect)
+
+	return nil
+}
+
+// Env reads every field from the environment variable named by its `env` tag,
+// or by prefix followed by the upper snake case field name.
+func (l *Loader) Env(prefix string) error {
+	structType := l.value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Anonymous {
+			continue
+		}
+
+		key, ok := field.Tag.Lookup("env")
+		if !ok || key == "" {
+			key = prefix + strings.ToUpper(SnakeCase(field.Name))
+		}
+
+		if key == "-" {
+			continue
+		}
+
+		raw, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+
+		if err := setFromString(l.value.Field(i), raw); err != nil {
+			return fmt.Errorf("%w: field %s: %s", ErrIncompatibleTypes, field.Name, err.Error())
+		}
+
+		l.record(field.Name, SourceEnv)
+	}
+
+	return transformStruct(l.value)
+}
+
+// Query reads every field from the query parameter named by its `query` tag,
+// or by its JSON name.
+func (l *Loader) Query(values url.Values) error {
+	structType := l.value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Anonymous {
+			continue
+		}
+
+		key, ok := jsonName(field)
+		if _, tagged := field.Tag.Lookup("query"); tagged {
+			key, ok = tagName(field, "query")
+		}
+
+		if !ok {
+			continue
+		}
+
+		raws, ok := values[key]
+		if !ok {
+			continue
+		}
+
+		if err := setFromStrings(l.value.Field(i), raws); err != nil {
+			return fmt.Errorf("%w: field %s: %s", ErrIncompatibleTypes, field.Name, err.Error())
+		}
+
+		l.record(field.Name, SourceQuery)
+	}
+
+	return transformStruct(l.value)
+}
+
+func (l *Loader) JSON(data []byte) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, l.value.Addr().Interface()); err != nil {
+		return err
+	}
+
+	if err := transformStruct(l.value); err != nil {
+		return err
+	}
+
+	if l.provenance == nil {
+		return nil
+	}
+
+	structType := l.value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Anonymous {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		// encoding/json matches keys case-insensitively
+		for key := range keys {
+			if strings.EqualFold(key, name) {
+				l.record(field.Name, SourceJSON)
+
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// Provenance returns the source that last supplied the field's value. It
+// returns false when provenance isn't tracked or the field was never set.
+func (l *Loader) Provenance(name string) (Source, bool) {
+	source, ok := l.provenance[name]
+
+	return source, ok
+}
+
+func (l *Loader) record(name string, source Source) {
+	if l.provenance != nil {
+		l.provenance[name] = source
+	}
+}
@@ -0,0 +1,212 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newConfigInstance(t *testing.T) any {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Host", "", `json:"host"`)
+	_ = builder.AddField("Port", int(0), `json:"port"`, `env:"APP_LISTEN_PORT"`)
+	_ = builder.AddField("Debug", false, `json:"debug"`, `query:"verbose"`)
+	_ = builder.AddField("Timeout", time.Duration(0), `json:"timeout"`)
+	_ = builder.AddField("Tags", []string{}, `json:"tags"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.New(reflect.TypeOf(instance)).Interface()
+}
+
+func TestLoaderProvenance(t *testing.T) {
+	instancePtr := newConfigInstance(t)
+
+	loader, err := dynamicstruct.NewLoader(instancePtr, dynamicstruct.WithProvenance())
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+
+	err = loader.Defaults(map[string]interface{}{
+		"Host":    "localhost",
+		"Port":    8080,
+		"Timeout": 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Defaults() error = %v", err)
+	}
+
+	t.Setenv("APP_LISTEN_PORT", "9090")
+	t.Setenv("APP_TIMEOUT", "30s")
+
+	if err = loader.Env("APP_"); err != nil {
+		t.Fatalf("Env() error = %v", err)
+	}
+
+	err = loader.Query(url.Values{"verbose": {"true"}, "tags": {"a", "b"}})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	if err = loader.JSON([]byte(`{"HOST": "example.com"}`)); err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	value := reflect.ValueOf(instancePtr).Elem()
+
+	tests := []struct {
+		field      string
+		wantValue  interface{}
+		wantSource dynamicstruct.Source
+	}{
+		{field: "Host", wantValue: "example.com", wantSource: dynamicstruct.SourceJSON},
+		{field: "Port", wantValue: 9090, wantSource: dynamicstruct.SourceEnv},
+		{field: "Debug", wantValue: true, wantSource: dynamicstruct.SourceQuery},
+		{field: "Timeout", wantValue: 30 * time.Second, wantSource: dynamicstruct.SourceEnv},
+		{field: "Tags", wantValue: []string{"a", "b"}, wantSource: dynamicstruct.SourceQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got := value.FieldByName(tt.field).Interface()
+			if !reflect.DeepEqual(got, tt.wantValue) {
+				t.Errorf("%s = %v, want %v", tt.field, got, tt.wantValue)
+			}
+
+			source, ok := loader.Provenance(tt.field)
+			if !ok || source != tt.wantSource {
+				t.Errorf("Provenance(%s) = %q, %v, want %q", tt.field, source, ok, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestLoaderWithoutProvenance(t *testing.T) {
+	instancePtr := newConfigInstance(t)
+
+	loader, err := dynamicstruct.NewLoader(instancePtr)
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+
+	if err = loader.JSON([]byte(`{"host": "example.com"}`)); err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	if _, ok := loader.Provenance("Host"); ok {
+		t.Error("Provenance() should not be recorded without WithProvenance()")
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	t.Run("non_pointer_instance", func(t *testing.T) {
+		_, err := dynamicstruct.NewLoader(struct{}{})
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("NewLoader() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+
+	t.Run("pointer_to_non_struct", func(t *testing.T) {
+		value := 1
+		_, err := dynamicstruct.NewLoader(&value)
+		if !errors.Is(err, dynamicstruct.ErrInvalidInstance) {
+			t.Errorf("NewLoader() error = %v, want %v", err, dynamicstruct.ErrInvalidInstance)
+		}
+	})
+
+	t.Run("unknown_default", func(t *testing.T) {
+		loader, _ := dynamicstruct.NewLoader(newConfigInstance(t))
+		err := loader.Defaults(map[string]interface{}{"Missing": 1})
+		if !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("Defaults() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+	})
+
+	t.Run("incompatible_default", func(t *testing.T) {
+		loader, _ := dynamicstruct.NewLoader(newConfigInstance(t))
+		err := loader.Defaults(map[string]interface{}{"Port": "8080"})
+		if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("Defaults() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	})
+
+	t.Run("unparsable_query", func(t *testing.T) {
+		loader, _ := dynamicstruct.NewLoader(newConfigInstance(t))
+		err := loader.Query(url.Values{"port": {"eighty"}})
+		if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("Query() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	})
+}
+
+func TestLoaderDefaultsAllOrNone(t *testing.T) {
+	instancePtr := newConfigInstance(t)
+
+	loader, err := dynamicstruct.NewLoader(instancePtr, dynamicstruct.WithProvenance())
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+
+	err = loader.Defaults(map[string]any{"Host": "localhost", "Port": "8080", "Missing": 1, "Debug": true})
+
+	var errs dynamicstruct.FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Defaults() error = %v, want FieldErrors", err)
+	}
+
+	if len(errs) != 2 || errs[0].Field != "Missing" || errs[1].Field != "Port" {
+		t.Errorf("Defaults() error = %v, want Missing and Port in order", err)
+	}
+
+	if !errors.Is(err, dynamicstruct.ErrFieldNotFound) || !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Defaults() error = %v, want %v and %v", err, dynamicstruct.ErrFieldNotFound, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	if host := reflect.ValueOf(instancePtr).Elem().FieldByName("Host").String(); host != "" {
+		t.Errorf("Host = %q, want unchanged", host)
+	}
+
+	if _, ok := loader.Provenance("Host"); ok {
+		t.Error("Provenance(Host) recorded for a failed Defaults")
+	}
+}
+
+func TestLoaderDefaultsEmbeddedPointer(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Host", "")
+	_ = builder.AddAnonymousField(&ContactTest{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instancePtr := reflect.New(reflect.TypeOf(instance))
+
+	loader, err := dynamicstruct.NewLoader(instancePtr.Interface(), dynamicstruct.WithProvenance())
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+
+	// The nil embedded pointer is allocated to hold the promoted field
+	if err = loader.Defaults(map[string]any{"Email": "ops@example.com"}); err != nil {
+		t.Fatalf("Defaults() error = %v", err)
+	}
+
+	if got := instancePtr.Elem().FieldByName("Email").String(); got != "ops@example.com" {
+		t.Errorf("Email = %q, want ops@example.com", got)
+	}
+
+	if source, _ := loader.Provenance("Email"); source != dynamicstruct.SourceDefault {
+		t.Errorf("Provenance() = %v, want %v", source, dynamicstruct.SourceDefault)
+	}
+}
@@ -0,0 +1,132 @@
+//go:build go1.21
+
+package dynamicstruct
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// LogValue returns instance, a struct or a pointer to one, as a slog group
+// value with an attribute per field in declaration order, keyed by field
+// name. Fields of embedded structs are promoted, nested structs become
+// groups, and sensitive fields are logged as Redacted, as are sensitive fields
+// of structs in slices and maps. A pointer back to a struct that is being
+// logged, as in a child linking to its parent, is logged as "<cycle>". Other
+// values are returned as slog.AnyValue.
+func LogValue(instance any) slog.Value {
+	value, err := structValue(instance)
+	if err != nil {
+		return slog.AnyValue(instance)
+	}
+
+	visiting := make(map[refKey]bool)
+
+	if ptr := reflect.ValueOf(instance); ptr.Kind() == reflect.Ptr {
+		visiting[newRefKey(ptr)] = true
+	}
+
+	return structLogValue(value, visiting)
+}
+
+// Loggable wraps instance in a slog.LogValuer that calls LogValue, so that
+// it is only converted when the record is logged:
+//
+//	logger.Info("created", "user", dynamicstruct.Loggable(instancePtr))
+func Loggable(instance any) slog.LogValuer {
+	return logValuer{instance: instance}
+}
+
+type logValuer struct {
+	instance any
+}
+
+func (l logValuer) LogValue() slog.Value {
+	return LogValue(l.instance)
+}
+
+// structLogValue returns the group value of the struct value. visiting holds
+// the pointers to the structs being logged, to log pointers back to them as
+// cycles instead of following them.
+func structLogValue(value reflect.Value, visiting map[refKey]bool) slog.Value {
+	attrs := make([]slog.Attr, 0, value.NumField())
+
+	return slog.GroupValue(appendLogAttrs(attrs, value, visiting)...)
+}
+
+func appendLogAttrs(attrs []slog.Attr, value reflect.Value, visiting map[refKey]bool) []slog.Attr {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if isSensitive(field) {
+			attrs = append(attrs, slog.String(field.Name, Redacted))
+
+			continue
+		}
+
+		fieldValue := value.Field(i)
+
+		// Self fields hold their pointers in interfaces
+		ptr := fieldValue
+		if ptr.Kind() == reflect.Interface && !ptr.IsNil() {
+			ptr = ptr.Elem()
+		}
+
+		nested := reflect.Indirect(ptr)
+		isStruct := nested.IsValid() && nested.Kind() == reflect.Struct && nested.Type() != timeType
+
+		if !isStruct {
+			attrs = append(attrs, slog.Any(field.Name, redactedInterface(fieldValue, visiting)))
+
+			continue
+		}
+
+		if ptr.Kind() != reflect.Ptr {
+			attrs = appendStructAttr(attrs, field, nested, visiting)
+
+			continue
+		}
+
+		key := newRefKey(ptr)
+		if visiting[key] {
+			attrs = append(attrs, slog.String(field.Name, cycleText))
+
+			continue
+		}
+
+		visiting[key] = true
+		attrs = appendStructAttr(attrs, field, nested, visiting)
+		delete(visiting, key)
+	}
+
+	return attrs
+}
+
+// appendStructAttr appends the struct value of field as a group, or its
+// attributes when field is embedded.
+func appendStructAttr(attrs []slog.Attr, field reflect.StructField, value reflect.Value, visiting map[refKey]bool) []slog.Attr {
+	if field.Anonymous {
+		return appendLogAttrs(attrs, value, visiting)
+	}
+
+	return append(attrs, slog.Attr{Key: field.Name, Value: structLogValue(value, visiting)})
+}
+
+// redactedInterface returns value as an interface, redacted when it can hold
+// sensitive fields. The copy is redacted with visited, which it shares with
+// the structs being logged.
+func redactedInterface(value reflect.Value, visited map[refKey]bool) any {
+	if !hasSensitiveFields(value.Type(), map[reflect.Type]bool{}) {
+		return value.Interface()
+	}
+
+	copied := deepCopy(value)
+	redactValue(copied, visited)
+
+	return copied.Interface()
+}
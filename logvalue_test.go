@@ -0,0 +1,139 @@
+//go:build go1.21
+
+package dynamicstruct_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestLogValue(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("Token", "", dynamicstruct.Sensitive())
+	_ = builder.AddField("Address", &AddressTest{})
+	_ = builder.AddField("Credentials", []CredentialTest{})
+	_ = builder.AddField("Tags", []string{})
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Name", "Alice")
+	_ = builder.SetFieldValue("Token", "abc")
+	_ = builder.SetFieldValue("Address", &AddressTest{City: "Berlin"})
+	_ = builder.SetFieldValue("Credentials", []CredentialTest{{User: "root", Password: "hunter2"}})
+	_ = builder.SetFieldValue("Tags", []string{"a"})
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+	logger.Info("created", "user", dynamicstruct.Loggable(instancePtr))
+
+	want := `msg=created user.Name=Alice user.Age=0 user.Token=[REDACTED] user.Address.Street="" user.Address.City=Berlin ` +
+		`user.Credentials="[{User:root Password:[REDACTED]}]" user.Tags=[a]` + "\n"
+
+	if buf.String() != want {
+		t.Errorf("log = %s, want %s", buf.String(), want)
+	}
+
+	// The instance isn't redacted itself
+	var password struct{ Credentials []CredentialTest }
+
+	data, _ := json.Marshal(instancePtr)
+	_ = json.Unmarshal(data, &password)
+
+	if got := password.Credentials[0].Password; got != "hunter2" {
+		t.Errorf("Password = %s, want %s", got, "hunter2")
+	}
+
+	t.Run("not_struct", func(t *testing.T) {
+		if got := dynamicstruct.LogValue(42); got.Int64() != 42 {
+			t.Errorf("LogValue() = %v, want 42", got)
+		}
+	})
+}
+
+type logNodeTest struct {
+	Name   string
+	Token  string `sensitive:"true"`
+	Parent *logNodeTest
+	Left   *logNodeTest
+	Right  *logNodeTest
+}
+
+func TestLogValueCycle(t *testing.T) {
+	root := &logNodeTest{Name: "root", Token: "abc"}
+	leaf := &logNodeTest{Name: "leaf", Parent: root}
+	root.Left, root.Right = leaf, leaf
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+	logger.Info("", "node", dynamicstruct.Loggable(root))
+
+	// The leaf is logged under both fields, but its parent is a cycle
+	want := `node.Name=root node.Token=[REDACTED] node.Parent=<nil> ` +
+		`node.Left.Name=leaf node.Left.Token=[REDACTED] node.Left.Parent=<cycle> node.Left.Left=<nil> node.Left.Right=<nil> ` +
+		`node.Right.Name=leaf node.Right.Token=[REDACTED] node.Right.Parent=<cycle> node.Right.Left=<nil> node.Right.Right=<nil>` + "\n"
+
+	if buf.String() != want {
+		t.Errorf("log = %s, want %s", buf.String(), want)
+	}
+
+	t.Run("self_fields", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		if err := builder.AddField("Token", "", dynamicstruct.Sensitive()); err != nil {
+			t.Fatalf("AddField() error = %v", err)
+		}
+
+		if err := builder.AddSelfField("Parent", dynamicstruct.PtrToSelf); err != nil {
+			t.Fatalf("AddSelfField() error = %v", err)
+		}
+
+		if err := builder.AddSelfField("Children", dynamicstruct.SliceOfSelf); err != nil {
+			t.Fatalf("AddSelfField() error = %v", err)
+		}
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		parent, _ := builder.NewInstance()
+		child, _ := builder.NewInstance()
+
+		reflect.ValueOf(parent).Elem().FieldByName("Children").Set(reflect.ValueOf([]any{child}))
+		reflect.ValueOf(child).Elem().FieldByName("Parent").Set(reflect.ValueOf(parent))
+
+		buf.Reset()
+		logger.Info("", "node", dynamicstruct.Loggable(child))
+
+		if !strings.Contains(buf.String(), "node.Parent.Token=[REDACTED]") {
+			t.Errorf("log = %s, want the parent logged as a group", buf.String())
+		}
+	})
+}
@@ -0,0 +1,374 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MapView is a read-only, map-like view of a struct instance, keyed by the
+// json names of its fields. It reads from the instance on every access
+// instead of copying it.
+type MapView struct {
+	value  reflect.Value
+	fields []jsonField
+	index  map[string]int
+}
+
+// AsMapView returns a view of instance. Pass a pointer for the view to
+// reflect later changes to the instance; a struct value is viewed as the copy
+// made when it was passed in.
+//
+// Keys follow encoding/json: fields tagged "-" and unexported fields are left
+// out, and the fields of untagged embedded structs are promoted. A name
+// promoted from several fields is taken by the shallowest one, then by the
+// only tagged one among them, and is left out when that is still ambiguous.
+// MarshalJSON also honours the omitempty and string options, so it encodes
+// the same object as json.Marshal of the instance; Get, Range and Map return
+// every key regardless of its options.
+func AsMapView(instance any) (*MapView, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := jsonFieldList(value.Type())
+
+	index := make(map[string]int, len(fields))
+	for i, field := range fields {
+		index[field.name] = i
+	}
+
+	return &MapView{value: value, fields: fields, index: index}, nil
+}
+
+// jsonField is a field encoding/json encodes, with its json name, the index
+// path of the field and the options of its json tag.
+type jsonField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+// jsonFields returns the json names of the fields of the struct type t, in
+// field order, and the index path of the field each name stands for. Names
+// follow encoding/json, as described on AsMapView.
+func jsonFields(t reflect.Type) ([]string, map[string][]int) {
+	fields := jsonFieldList(t)
+
+	keys := make([]string, 0, len(fields))
+	index := make(map[string][]int, len(fields))
+
+	for _, field := range fields {
+		keys = append(keys, field.name)
+		index[field.name] = field.index
+	}
+
+	return keys, index
+}
+
+// jsonFieldList returns the fields of the struct type t that encoding/json
+// encodes, in field order, with name collisions resolved as it does.
+func jsonFieldList(t reflect.Type) []jsonField {
+	var candidates []jsonField
+
+	addJSONFields(t, nil, map[reflect.Type]bool{t: true}, &candidates)
+
+	// Group the candidates by name
+	var names []string
+
+	byName := make(map[string][]jsonField)
+
+	for _, field := range candidates {
+		if _, ok := byName[field.name]; !ok {
+			names = append(names, field.name)
+		}
+
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	fields := make([]jsonField, 0, len(names))
+
+	for _, name := range names {
+		if field, ok := dominantField(byName[name]); ok {
+			fields = append(fields, field)
+		}
+	}
+
+	// Fields are in the order of the field that took the name
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+
+	return fields
+}
+
+func lessIndex(x, y []int) bool {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] != y[i] {
+			return x[i] < y[i]
+		}
+	}
+
+	return len(x) < len(y)
+}
+
+func addJSONFields(t reflect.Type, parent []int, visiting map[reflect.Type]bool, fields *[]jsonField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// Like encoding/json, unexported embedded structs still promote
+		// their exported fields
+		if field.PkgPath != "" && (!field.Anonymous || fieldType.Kind() != reflect.Struct) {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		index := append(append([]int(nil), parent...), i)
+		tag, tagged := field.Tag.Lookup("json")
+		tagName, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && tagName == "" {
+			// A struct embedding itself through a pointer promotes nothing new
+			if !visiting[fieldType] {
+				visiting[fieldType] = true
+				addJSONFields(fieldType, index, visiting, fields)
+				delete(visiting, fieldType)
+			}
+
+			continue
+		}
+
+		*fields = append(*fields, jsonField{
+			name:      name,
+			index:     index,
+			tagged:    tagged && tagName != "",
+			omitEmpty: hasTagOption(tag, "omitempty"),
+			quoted:    hasTagOption(tag, "string"),
+		})
+	}
+}
+
+// dominantField returns the field that takes a name shared by fields, as
+// encoding/json picks it: the shallowest field, or the only tagged one among
+// the shallowest. It reports false when the name is ambiguous.
+func dominantField(fields []jsonField) (jsonField, bool) {
+	depth := len(fields[0].index)
+	for _, field := range fields[1:] {
+		if len(field.index) < depth {
+			depth = len(field.index)
+		}
+	}
+
+	var (
+		shallowest []jsonField
+		tagged     []jsonField
+	)
+
+	for _, field := range fields {
+		if len(field.index) != depth {
+			continue
+		}
+
+		shallowest = append(shallowest, field)
+
+		if field.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	default:
+		return jsonField{}, false
+	}
+}
+
+// Get returns the current value for key. It reports false when there is no
+// such key, or when the key is promoted through a nil embedded pointer.
+func (v *MapView) Get(key string) (any, bool) {
+	i, ok := v.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	field, ok := v.field(i)
+	if !ok {
+		return nil, false
+	}
+
+	return field.Interface(), true
+}
+
+// field returns the value of the i-th field of the view, and false when it is
+// promoted through a nil embedded pointer.
+func (v *MapView) field(i int) (reflect.Value, bool) {
+	field, err := v.value.FieldByIndexErr(v.fields[i].index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	return field, true
+}
+
+// Value returns the current value for key, or nil when Get would report
+// false. Unlike Get it can be called from templates: {{.Value "name"}}.
+func (v *MapView) Value(key string) any {
+	value, _ := v.Get(key)
+
+	return value
+}
+
+// Has reports whether key is a key of the view.
+func (v *MapView) Has(key string) bool {
+	_, ok := v.index[key]
+
+	return ok
+}
+
+// Len returns the number of keys.
+func (v *MapView) Len() int {
+	return len(v.fields)
+}
+
+// Keys returns the keys in field order.
+func (v *MapView) Keys() []string {
+	keys := make([]string, 0, len(v.fields))
+	for _, field := range v.fields {
+		keys = append(keys, field.name)
+	}
+
+	return keys
+}
+
+// Range calls fn for each key and its current value in field order, until fn
+// returns false.
+func (v *MapView) Range(fn func(key string, value any) bool) {
+	for i, field := range v.fields {
+		value, ok := v.field(i)
+		if !ok {
+			continue
+		}
+
+		if !fn(field.name, value.Interface()) {
+			return
+		}
+	}
+}
+
+// Map copies the current values into a new map, for APIs that need one.
+func (v *MapView) Map() map[string]any {
+	m := make(map[string]any, len(v.fields))
+
+	v.Range(func(key string, value any) bool {
+		m[key] = value
+
+		return true
+	})
+
+	return m
+}
+
+// MarshalJSON encodes the view as a JSON object with keys in field order.
+// Fields tagged omitempty are left out when empty and fields tagged string
+// are quoted, as json.Marshal does.
+func (v *MapView) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	first := true
+
+	for i, field := range v.fields {
+		value, ok := v.field(i)
+		if !ok || field.omitEmpty && isEmptyJSONValue(value) {
+			continue
+		}
+
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		if field.quoted && isQuotableKind(value) {
+			if data, err = json.Marshal(string(data)); err != nil {
+				return nil, err
+			}
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+
+		first = false
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// isEmptyJSONValue reports whether omitempty leaves value out.
+func isEmptyJSONValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return value.IsZero()
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// isQuotableKind reports whether the string option applies to value, which
+// encoding/json limits to strings, booleans and numbers, or pointers to them.
+func isQuotableKind(value reflect.Value) bool {
+	kind := value.Kind()
+	if kind == reflect.Ptr {
+		if value.IsNil() {
+			return false
+		}
+
+		kind = value.Elem().Kind()
+	}
+
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
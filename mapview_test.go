@@ -0,0 +1,228 @@
+package dynamicstruct_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"text/template"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newMapViewInstance(t *testing.T) any {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+	_ = builder.AddField("Email", "", `json:"email,omitempty"`)
+	_ = builder.AddField("Secret", "", `json:"-"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	value := reflect.New(reflect.TypeOf(instance))
+	value.Elem().FieldByName("Name").SetString("Alice")
+	value.Elem().FieldByName("ID").SetInt(7)
+
+	return value.Interface()
+}
+
+func TestAsMapView(t *testing.T) {
+	instancePtr := newMapViewInstance(t)
+
+	view, err := dynamicstruct.AsMapView(instancePtr)
+	if err != nil {
+		t.Fatalf("AsMapView() error = %v", err)
+	}
+
+	wantKeys := []string{"Name", "Age", "id", "email"}
+	if !reflect.DeepEqual(view.Keys(), wantKeys) {
+		t.Errorf("Keys() = %v, want %v", view.Keys(), wantKeys)
+	}
+
+	if view.Len() != len(wantKeys) {
+		t.Errorf("Len() = %d, want %d", view.Len(), len(wantKeys))
+	}
+
+	tests := []struct {
+		key    string
+		want   any
+		wantOk bool
+	}{
+		{key: "Name", want: "Alice", wantOk: true},
+		{key: "id", want: int64(7), wantOk: true},
+		{key: "email", want: "", wantOk: true},
+		{key: "Secret", want: nil, wantOk: false},
+		{key: "missing", want: nil, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok := view.Get(tt.key)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Get(%q) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.wantOk)
+			}
+
+			if view.Has(tt.key) != tt.wantOk {
+				t.Errorf("Has(%q) = %v, want %v", tt.key, view.Has(tt.key), tt.wantOk)
+			}
+		})
+	}
+
+	t.Run("live_view", func(t *testing.T) {
+		reflect.ValueOf(instancePtr).Elem().FieldByName("Email").SetString("alice@example.com")
+
+		if got, _ := view.Get("email"); got != "alice@example.com" {
+			t.Errorf("Get(email) = %v, want updated value", got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(view)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+
+		want := `{"Name":"Alice","Age":0,"id":7,"email":"alice@example.com"}`
+		if string(data) != want {
+			t.Errorf("Marshal() = %s, want %s", data, want)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		tmpl := template.Must(template.New("t").Parse(`{{.Value "Name"}} <{{.Value "email"}}>`))
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, view); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		if buf.String() != "Alice <alice@example.com>" {
+			t.Errorf("Execute() = %q", buf.String())
+		}
+	})
+
+	t.Run("range_stops", func(t *testing.T) {
+		var keys []string
+
+		view.Range(func(key string, _ any) bool {
+			keys = append(keys, key)
+
+			return len(keys) < 2
+		})
+
+		if len(keys) != 2 {
+			t.Errorf("Range() visited %v, want 2 keys", keys)
+		}
+	})
+
+	t.Run("map_copy", func(t *testing.T) {
+		m := view.Map()
+		if len(m) != 4 || m["id"] != int64(7) {
+			t.Errorf("Map() = %v", m)
+		}
+	})
+}
+
+func TestAsMapViewShadowing(t *testing.T) {
+	type Inner struct {
+		Name  string
+		Extra int
+	}
+
+	type Outer struct {
+		*Inner
+		Name string
+	}
+
+	view, err := dynamicstruct.AsMapView(&Outer{Name: "outer"})
+	if err != nil {
+		t.Fatalf("AsMapView() error = %v", err)
+	}
+
+	if got, _ := view.Get("Name"); got != "outer" {
+		t.Errorf("Get(Name) = %v, want outer", got)
+	}
+
+	// Extra is promoted through a nil pointer
+	if _, ok := view.Get("Extra"); ok {
+		t.Error("Get(Extra) ok = true through nil embedded pointer")
+	}
+}
+
+func TestAsMapViewMatchesEncodingJSON(t *testing.T) {
+	type Left struct {
+		Shared string
+		Tagged string
+		Left   int
+	}
+
+	type Right struct {
+		Shared string
+		Tagged string `json:"Tagged"`
+	}
+
+	type Document struct {
+		Left
+		Right
+		Count   int     `json:"count,omitempty"`
+		Labels  []int   `json:"labels,omitempty"`
+		Ratio   float64 `json:"ratio,string"`
+		Comment *string `json:"comment,omitempty"`
+	}
+
+	instance := Document{
+		Left:  Left{Shared: "left", Tagged: "left", Left: 1},
+		Right: Right{Shared: "right", Tagged: "right"},
+		Ratio: 0.5,
+	}
+
+	view, err := dynamicstruct.AsMapView(&instance)
+	if err != nil {
+		t.Fatalf("AsMapView() error = %v", err)
+	}
+
+	// Shared is ambiguous, and the tagged Tagged wins over the untagged one
+	wantKeys := []string{"Left", "Tagged", "count", "labels", "ratio", "comment"}
+	if !reflect.DeepEqual(view.Keys(), wantKeys) {
+		t.Errorf("Keys() = %v, want %v", view.Keys(), wantKeys)
+	}
+
+	if got, _ := view.Get("Tagged"); got != "right" {
+		t.Errorf("Get(Tagged) = %v, want right", got)
+	}
+
+	got, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("Marshal(view) error = %v", err)
+	}
+
+	want, _ := json.Marshal(instance)
+	if string(got) != string(want) {
+		t.Errorf("Marshal(view) = %s, want %s", got, want)
+	}
+}
+
+func TestAsMapViewErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance any
+		wantErr  error
+	}{
+		{name: "nil_pointer", instance: (*PersonTest)(nil), wantErr: dynamicstruct.ErrValueCannotBeNil},
+		{name: "not_struct", instance: map[string]any{}, wantErr: dynamicstruct.ErrInvalidInstance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dynamicstruct.AsMapView(tt.instance); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AsMapView() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	descriptionTagKey = "description"
+	exampleTagKey     = "example"
+)
+
+// MarkdownDoc renders the fields of the builder as a Markdown table with
+// their name, type, tags, description and example. Descriptions and examples
+// are read from `description` and `example` tags, which are left out of the
+// tags column. When fields are in groups, the fields in no group come first,
+// followed by a section per group with a heading and a table of its own.
+func (b *Builder) MarkdownDoc() string {
+	b.m.Lock()
+	fields, err := b.resolveStructFields(nil)
+	if err != nil {
+		fields = b.buildStructFields()
+	}
+
+	groups := b.groups()
+	grouped := make(map[string][]reflect.StructField, len(groups)+1)
+
+	for _, field := range fields {
+		group := ""
+		if !field.Anonymous {
+			group = b.meta[field.Name].group
+		}
+
+		grouped[group] = append(grouped[group], field)
+	}
+
+	b.m.Unlock()
+
+	var doc strings.Builder
+
+	if len(groups) == 0 || len(grouped[""]) > 0 {
+		writeMarkdownTable(&doc, grouped[""])
+	}
+
+	for i, group := range groups {
+		if i > 0 || len(grouped[""]) > 0 {
+			doc.WriteString("\n")
+		}
+
+		doc.WriteString("## " + markdownCell(group) + "\n\n")
+		writeMarkdownTable(&doc, grouped[group])
+	}
+
+	return doc.String()
+}
+
+// writeMarkdownTable writes the table of fields to doc.
+func writeMarkdownTable(doc *strings.Builder, fields []reflect.StructField) {
+	doc.WriteString("| Field | Type | Tags | Description | Example |\n")
+	doc.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, field := range fields {
+		name := "`" + field.Name + "`"
+		if field.Anonymous {
+			name += " (embedded)"
+		}
+
+		example := field.Tag.Get(exampleTagKey)
+		if example != "" {
+			example = markdownCode(example)
+		}
+
+		cells := []string{
+			name,
+			markdownCode(field.Type.String()),
+			markdownTags(field.Tag),
+			markdownCell(field.Tag.Get(descriptionTagKey)),
+			example,
+		}
+
+		doc.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+// markdownTags renders tag without its description and example keys, or an
+// empty string when nothing else is left.
+func markdownTags(tag reflect.StructTag) string {
+	tags := splitTag(tag)
+
+	kept := tags[:0]
+	for _, t := range tags {
+		if t.key != descriptionTagKey && t.key != exampleTagKey {
+			kept = append(kept, t)
+		}
+	}
+
+	if len(kept) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(kept))
+	for i, t := range kept {
+		parts[i] = t.raw
+	}
+
+	return markdownCode(strings.Join(parts, " "))
+}
+
+type rawTag struct {
+	key string
+	raw string
+}
+
+// splitTag splits tag into its key:"value" pairs, following the parsing rules
+// of reflect.StructTag.Lookup. Parsing stops at the first malformed pair.
+func splitTag(tag reflect.StructTag) []rawTag {
+	var tags []rawTag
+
+	s := string(tag)
+	for s != "" {
+		s = strings.TrimLeft(s, " ")
+
+		i := 0
+		for i < len(s) && s[i] > ' ' && s[i] != ':' && s[i] != '"' && s[i] != 0x7f {
+			i++
+		}
+
+		if i == 0 || i+1 >= len(s) || s[i] != ':' || s[i+1] != '"' {
+			break
+		}
+
+		key := s[:i]
+		j := i + 2
+
+		for j < len(s) && s[j] != '"' {
+			if s[j] == '\\' {
+				j++
+			}
+
+			j++
+		}
+
+		if j >= len(s) {
+			break
+		}
+
+		tags = append(tags, rawTag{key: key, raw: s[:j+1]})
+		s = s[j+1:]
+	}
+
+	return tags
+}
+
+// markdownCell escapes s for use in a table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
+
+// markdownCode renders s as a code span in a table cell, using a longer run of
+// backticks than s contains.
+func markdownCode(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+
+	s = markdownCell(s)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+
+	return fence + s + fence
+}
@@ -0,0 +1,48 @@
+package dynamicstruct_test
+
+import (
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestMarkdownDoc(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(b *dynamicstruct.Builder)
+		want  string
+	}{
+		{
+			name:  "empty_builder",
+			setup: func(b *dynamicstruct.Builder) {},
+			want: "| Field | Type | Tags | Description | Example |\n" +
+				"| --- | --- | --- | --- | --- |\n",
+		},
+		{
+			name: "fields_with_docs",
+			setup: func(b *dynamicstruct.Builder) {
+				_ = b.AddAnonymousField(AddressTest{})
+				_ = b.AddField("Name", "", `json:"name"`, `description:"Full name"`, `example:"Alice"`)
+				_ = b.AddField("Tags", []string{}, `json:"tags,omitempty"`, `validate:"max=3"`)
+				_ = b.AddField("Mode", "", `description:"One of a | b"`)
+			},
+			want: "| Field | Type | Tags | Description | Example |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| `AddressTest` (embedded) | `dynamicstruct_test.AddressTest` |  |  |  |\n" +
+				"| `Name` | `string` | `json:\"name\"` | Full name | `Alice` |\n" +
+				"| `Tags` | `[]string` | `json:\"tags,omitempty\" validate:\"max=3\"` |  |  |\n" +
+				"| `Mode` | `string` |  | One of a \\| b |  |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+			tt.setup(builder)
+
+			if got := builder.MarkdownDoc(); got != tt.want {
+				t.Errorf("MarkdownDoc() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package dynamicstruct
+
+import "reflect"
+
+// MarshalInstance encodes the builder's instance to JSON as EncodeJSON does,
+// without the caller handling pointers to the built type.
+func (b *Builder) MarshalInstance() ([]byte, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.RLock()
+	defer b.values.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	return encodeJSON(b.instance.Addr().Interface(), *b.instance, b.unknownField)
+}
+
+// UnmarshalInstance decodes the JSON object data into the builder's instance
+// as DecodeJSON does. As with json.Unmarshal, fields whose keys data doesn't
+// have keep their values. When data doesn't decode, no field changes.
+func (b *Builder) UnmarshalInstance(data []byte) error {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.Lock()
+	defer b.values.Unlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return ErrInstanceNotBuilt
+	}
+
+	// Decode into a copy, so that the instance is only changed when data
+	// decodes
+	decoded := reflect.New(b.instance.Type()).Elem()
+	decoded.Set(*b.instance)
+
+	if err := decodeJSON(data, decoded, b.selfFields, b.unknownField); err != nil {
+		return err
+	}
+
+	b.instance.Set(decoded)
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestMarshalInstance(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", 0, `json:"id"`)
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.CaptureUnknown("Extra")
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if err := builder.UnmarshalInstance([]byte(`{"id":42,"name":"Alice","plan":"pro"}`)); err != nil {
+		t.Fatalf("UnmarshalInstance() error = %v", err)
+	}
+
+	if got, _ := builder.GetField("ID"); got != 42 {
+		t.Errorf("ID = %v, want 42", got)
+	}
+
+	data, err := builder.MarshalInstance()
+	if err != nil {
+		t.Fatalf("MarshalInstance() error = %v", err)
+	}
+
+	if want := `{"id":42,"name":"Alice","plan":"pro"}`; string(data) != want {
+		t.Errorf("MarshalInstance() = %s, want %s", data, want)
+	}
+
+	t.Run("missing_keys_keep_values", func(t *testing.T) {
+		if err := builder.UnmarshalInstance([]byte(`{"name":"Bob"}`)); err != nil {
+			t.Fatalf("UnmarshalInstance() error = %v", err)
+		}
+
+		if got, _ := builder.GetField("ID"); got != 42 {
+			t.Errorf("ID = %v, want 42", got)
+		}
+
+		if got, _ := builder.GetField("Name"); got != "Bob" {
+			t.Errorf("Name = %v, want Bob", got)
+		}
+	})
+
+	t.Run("invalid_leaves_instance", func(t *testing.T) {
+		if err := builder.UnmarshalInstance([]byte(`{"id":7,"name":1}`)); err == nil {
+			t.Fatal("UnmarshalInstance() error = nil, want an error")
+		}
+
+		if got, _ := builder.GetField("ID"); got != 42 {
+			t.Errorf("ID = %v, want 42", got)
+		}
+	})
+}
+
+func TestMarshalInstanceNotBuilt(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", 0)
+
+	if _, err := builder.MarshalInstance(); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("MarshalInstance() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	if err := builder.UnmarshalInstance([]byte(`{}`)); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("UnmarshalInstance() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+}
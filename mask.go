@@ -0,0 +1,106 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const scopeTagKey = "scope"
+
+// Scopes returns a scope tag restricting a field to the named scopes, such as
+// roles, for use as one of the tags of AddField:
+//
+//	builder.AddField("Salary", 0, `json:"salary"`, dynamicstruct.Scopes("admin", "owner"))
+//
+// MaskByScope keeps the fields of the given scopes. Fields without a scope tag
+// are in every scope.
+func Scopes(names ...string) string {
+	return scopeTagKey + ":" + strconv.Quote(strings.Join(names, ","))
+}
+
+// MaskInstance returns a copy of instance, a struct or a pointer to one, as a
+// value of a new struct type with only the fields named in allow, by Go name
+// or json name, in their usual order. Embedded fields are named by their type
+// name. Unexported fields are left out.
+//
+// Values are copied as they are, so slices, maps and pointers are shared with
+// instance.
+func MaskInstance(instance any, allow []string) (any, error) {
+	allowed := make(map[string]bool, len(allow))
+	for _, name := range allow {
+		allowed[name] = true
+	}
+
+	return maskInstance(instance, func(field reflect.StructField) bool {
+		if allowed[field.Name] {
+			return true
+		}
+
+		name, ok := jsonName(field)
+
+		return ok && allowed[name]
+	})
+}
+
+// MaskByScope is MaskInstance keeping the fields whose scope tag lists one of
+// scopes, and the fields without a scope tag, to project a record for the
+// roles of a caller.
+func MaskByScope(instance any, scopes ...string) (any, error) {
+	return maskInstance(instance, func(field reflect.StructField) bool {
+		tag, ok := field.Tag.Lookup(scopeTagKey)
+		if !ok {
+			return true
+		}
+
+		for _, name := range strings.Split(tag, ",") {
+			for _, scope := range scopes {
+				if strings.TrimSpace(name) == scope {
+					return true
+				}
+			}
+		}
+
+		return false
+	})
+}
+
+func maskInstance(instance any, keep func(reflect.StructField) bool) (any, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	structType := value.Type()
+
+	var (
+		fields  []reflect.StructField
+		indexes []int
+	)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" || !keep(field) {
+			continue
+		}
+
+		// The field is placed in the new struct as it is declared
+		field.Index = nil
+		field.Offset = 0
+
+		fields = append(fields, field)
+		indexes = append(indexes, i)
+	}
+
+	maskedType, err := structOf(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	masked := reflect.New(maskedType).Elem()
+	for i, index := range indexes {
+		masked.Field(i).Set(value.Field(index))
+	}
+
+	return masked.Interface(), nil
+}
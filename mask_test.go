@@ -0,0 +1,101 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newMaskInstance(t *testing.T) any {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("ID", 0, `json:"id"`)
+	_ = builder.AddField("Email", "", `json:"email"`, dynamicstruct.Scopes("owner", "admin"))
+	_ = builder.AddField("Salary", 0, `json:"salary"`, dynamicstruct.Scopes("admin"))
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Name", "Alice")
+	_ = builder.SetFieldValue("ID", 7)
+	_ = builder.SetFieldValue("Email", "alice@example.com")
+	_ = builder.SetFieldValue("Salary", 5000)
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	return instancePtr
+}
+
+func TestMaskInstance(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []string
+		want  string
+	}{
+		{name: "go_names", allow: []string{"ID", "Email"}, want: `{"id":7,"email":"alice@example.com"}`},
+		{name: "json_names", allow: []string{"id", "salary"}, want: `{"id":7,"salary":5000}`},
+		{name: "embedded", allow: []string{"PersonTest", "ID"}, want: `{"Name":"Alice","Age":0,"id":7}`},
+		{name: "unknown_ignored", allow: []string{"id", "Phone"}, want: `{"id":7}`},
+		{name: "none", allow: nil, want: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masked, err := dynamicstruct.MaskInstance(newMaskInstance(t), tt.allow)
+			if err != nil {
+				t.Fatalf("MaskInstance() error = %v", err)
+			}
+
+			if data, _ := json.Marshal(masked); string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid_instance", func(t *testing.T) {
+		if _, err := dynamicstruct.MaskInstance(42, nil); !errors.Is(err, dynamicstruct.ErrInvalidInstance) {
+			t.Errorf("MaskInstance() error = %v, want %v", err, dynamicstruct.ErrInvalidInstance)
+		}
+	})
+}
+
+func TestMaskByScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   string
+	}{
+		{name: "public", scopes: nil, want: `{"Name":"Alice","Age":0,"id":7}`},
+		{name: "owner", scopes: []string{"owner"}, want: `{"Name":"Alice","Age":0,"id":7,"email":"alice@example.com"}`},
+		{name: "admin", scopes: []string{"admin"}, want: `{"Name":"Alice","Age":0,"id":7,"email":"alice@example.com","salary":5000}`},
+		{name: "several", scopes: []string{"guest", "owner"}, want: `{"Name":"Alice","Age":0,"id":7,"email":"alice@example.com"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masked, err := dynamicstruct.MaskByScope(newMaskInstance(t), tt.scopes...)
+			if err != nil {
+				t.Fatalf("MaskByScope() error = %v", err)
+			}
+
+			if data, _ := json.Marshal(masked); string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+
+	t.Run("same_type_for_same_fields", func(t *testing.T) {
+		first, _ := dynamicstruct.MaskByScope(newMaskInstance(t), "owner")
+		second, _ := dynamicstruct.MaskByScope(newMaskInstance(t), "owner")
+
+		if reflect.TypeOf(first) != reflect.TypeOf(second) {
+			t.Errorf("types differ: %T and %T", first, second)
+		}
+	})
+}
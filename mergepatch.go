@@ -0,0 +1,247 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to the struct
+// instancePtr points to. Members of the patch name fields by their json name,
+// matched like encoding/json does. A null member sets its field to the zero
+// value, an object member is merged into a struct or map field, and any other
+// member replaces the field, so arrays are replaced as a whole. Keys set to
+// null in a map field are deleted.
+//
+// The patch is applied to a copy that replaces the instance once every member
+// applied, so a failing patch leaves the instance unchanged. Members that
+// don't name a field fail with ErrFieldNotFound.
+func ApplyMergePatch(instancePtr any, patch []byte) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	members, err := patchObject(patch)
+	if err != nil {
+		return err
+	}
+
+	patched := reflect.New(value.Type()).Elem()
+	patched.Set(value)
+
+	if err := mergeStruct(patched, members, ""); err != nil {
+		return err
+	}
+
+	value.Set(patched)
+
+	return nil
+}
+
+// patchObject decodes data as a JSON object.
+func patchObject(data []byte) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return nil, fmt.Errorf("%w: merge patch of a struct must be a JSON object", ErrInvalidPatch)
+	}
+
+	return members, nil
+}
+
+func mergeStruct(value reflect.Value, members map[string]json.RawMessage, path string) error {
+	_, indexes := jsonFields(value.Type())
+
+	for _, key := range sortedKeys(members) {
+		raw := members[key]
+
+		index, ok := indexes[key]
+		if !ok {
+			index, ok = foldJSONField(indexes, key)
+		}
+
+		if !ok {
+			return fieldError("patch", path+key, ErrFieldNotFound)
+		}
+
+		if err := mergeValue(ownedField(value, index), raw, path+key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the members of a patch object in key order, so that the
+// same patch always fails on the same member.
+func sortedKeys(members map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(members))
+	for key := range members {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// foldJSONField looks key up case-insensitively, as encoding/json does for
+// keys without an exact match.
+func foldJSONField(indexes map[string][]int, key string) ([]int, bool) {
+	for name, index := range indexes {
+		if strings.EqualFold(name, key) {
+			return index, true
+		}
+	}
+
+	return nil, false
+}
+
+// ownedField returns the field at index, replacing the embedded pointers on
+// the way with pointers to copies, so that changing the field doesn't change
+// structs the original instance shares.
+func ownedField(value reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			value = ownPointer(value)
+		}
+
+		value = value.Field(x)
+	}
+
+	return value
+}
+
+// ownPointer points value to a copy of what it points to, or to a new zero
+// value when it is nil, and returns the copy.
+func ownPointer(value reflect.Value) reflect.Value {
+	owned := reflect.New(value.Type().Elem())
+	if !value.IsNil() {
+		owned.Elem().Set(value.Elem())
+	}
+
+	value.Set(owned)
+
+	return owned.Elem()
+}
+
+func mergeValue(field reflect.Value, raw json.RawMessage, path string) error {
+	trimmed := bytes.TrimSpace(raw)
+
+	if bytes.Equal(trimmed, []byte("null")) {
+		field.Set(reflect.Zero(field.Type()))
+
+		return nil
+	}
+
+	target := field
+	if target.Kind() == reflect.Ptr && isMergeable(target.Type().Elem()) {
+		target = ownPointer(target)
+	}
+
+	isObject := len(trimmed) > 0 && trimmed[0] == '{'
+
+	// An object merged into an interface, as in a map[string]any, merges
+	// into the map it holds, or into an empty one
+	if isObject && target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		object := reflect.ValueOf(map[string]any{})
+		if !target.IsNil() && target.Elem().Type() == object.Type() {
+			object = target.Elem()
+		}
+
+		merged := reflect.New(object.Type()).Elem()
+		merged.Set(object)
+
+		if err := mergeValue(merged, trimmed, path); err != nil {
+			return err
+		}
+
+		target.Set(merged)
+
+		return nil
+	}
+
+	if isObject && isMergeable(target.Type()) {
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &members); err != nil {
+			return fieldError("patch", path, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error()))
+		}
+
+		if target.Kind() == reflect.Map {
+			return mergeMap(target, members, path+".")
+		}
+
+		return mergeStruct(target, members, path+".")
+	}
+
+	// Anything else replaces the field
+	replaced := reflect.New(field.Type())
+	if err := json.Unmarshal(trimmed, replaced.Interface()); err != nil {
+		return fieldError("patch", path, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error()))
+	}
+
+	field.Set(replaced.Elem())
+
+	return nil
+}
+
+// isMergeable reports whether an object in a patch is merged into values of
+// t rather than replacing them: t is a struct or a map with string keys that
+// doesn't decode JSON itself.
+func isMergeable(t reflect.Type) bool {
+	if t.Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+func mergeMap(value reflect.Value, members map[string]json.RawMessage, path string) error {
+	mapType := value.Type()
+
+	// The map is copied, as the original instance may share it
+	merged := reflect.MakeMapWithSize(mapType, value.Len()+len(members))
+
+	iter := value.MapRange()
+	for iter.Next() {
+		merged.SetMapIndex(iter.Key(), iter.Value())
+	}
+
+	for _, key := range sortedKeys(members) {
+		raw := members[key]
+		mapKey := reflect.ValueOf(key).Convert(mapType.Key())
+
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			merged.SetMapIndex(mapKey, reflect.Value{})
+
+			continue
+		}
+
+		elem := reflect.New(mapType.Elem()).Elem()
+		if existing := merged.MapIndex(mapKey); existing.IsValid() {
+			elem.Set(existing)
+		}
+
+		if err := mergeValue(elem, raw, path+key); err != nil {
+			return err
+		}
+
+		merged.SetMapIndex(mapKey, elem)
+	}
+
+	value.Set(merged)
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newPatchInstance(t *testing.T) any {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("Email", "", `json:"email"`)
+	_ = builder.AddField("Address", &AddressTest{}, `json:"address"`)
+	_ = builder.AddField("Tags", []string{}, `json:"tags"`)
+	_ = builder.AddField("Labels", map[string]string{}, `json:"labels"`)
+	_ = builder.AddField("Extra", map[string]any{}, `json:"extra"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instancePtr := reflect.New(reflect.TypeOf(instance)).Interface()
+
+	original := `{
+		"Name": "Alice", "Age": 30, "email": "alice@example.com",
+		"address": {"Street": "Main St", "City": "Springfield"},
+		"tags": ["a", "b"], "labels": {"team": "core", "tier": "1"},
+		"extra": {"theme": {"color": "blue", "size": "m"}}
+	}`
+	if err := json.Unmarshal([]byte(original), instancePtr); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	return instancePtr
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "replace_value",
+			patch: `{"email": "a@example.com", "Age": 31}`,
+			want:  `{"Name":"Alice","Age":31,"email":"a@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "null_zeroes",
+			patch: `{"email": null, "address": null}`,
+			want:  `{"Name":"Alice","Age":30,"email":"","address":null,"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "nested_object",
+			patch: `{"address": {"City": "Shelbyville"}}`,
+			want:  `{"Name":"Alice","Age":30,"email":"alice@example.com","address":{"Street":"Main St","City":"Shelbyville"},"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "array_replaced",
+			patch: `{"tags": ["c"]}`,
+			want:  `{"Name":"Alice","Age":30,"email":"alice@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["c"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+		{
+			name:  "map_keys",
+			patch: `{"labels": {"tier": null, "region": "eu"}, "extra": {"theme": {"size": null, "font": "serif"}}}`,
+			want:  `{"Name":"Alice","Age":30,"email":"alice@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["a","b"],"labels":{"region":"eu","team":"core"},"extra":{"theme":{"color":"blue","font":"serif"}}}`,
+		},
+		{
+			name:  "case_insensitive",
+			patch: `{"EMAIL": "b@example.com", "name": "Bob"}`,
+			want:  `{"Name":"Bob","Age":30,"email":"b@example.com","address":{"Street":"Main St","City":"Springfield"},"tags":["a","b"],"labels":{"team":"core","tier":"1"},"extra":{"theme":{"color":"blue","size":"m"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instancePtr := newPatchInstance(t)
+
+			if err := dynamicstruct.ApplyMergePatch(instancePtr, []byte(tt.patch)); err != nil {
+				t.Fatalf("ApplyMergePatch() error = %v", err)
+			}
+
+			data, _ := json.Marshal(instancePtr)
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatchShared(t *testing.T) {
+	instancePtr := newPatchInstance(t)
+
+	// A shallow copy shares the address and the maps with the instance
+	shared := reflect.ValueOf(instancePtr).Elem().Interface()
+	before, _ := json.Marshal(shared)
+
+	patch := `{"address": {"City": "Shelbyville"}, "labels": {"team": null}}`
+	if err := dynamicstruct.ApplyMergePatch(instancePtr, []byte(patch)); err != nil {
+		t.Fatalf("ApplyMergePatch() error = %v", err)
+	}
+
+	if after, _ := json.Marshal(shared); string(after) != string(before) {
+		t.Errorf("shared copy = %s, want %s", after, before)
+	}
+}
+
+func TestApplyMergePatchErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr error
+	}{
+		{name: "not_object", patch: `["email"]`, wantErr: dynamicstruct.ErrInvalidPatch},
+		{name: "invalid_json", patch: `{"email":`, wantErr: dynamicstruct.ErrInvalidPatch},
+		{name: "unknown_field", patch: `{"phone": "555"}`, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "unknown_nested_field", patch: `{"address": {"Zip": "1"}}`, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "wrong_type", patch: `{"email": "b@example.com", "Age": "old"}`, wantErr: dynamicstruct.ErrIncompatibleTypes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instancePtr := newPatchInstance(t)
+			before, _ := json.Marshal(instancePtr)
+
+			err := dynamicstruct.ApplyMergePatch(instancePtr, []byte(tt.patch))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApplyMergePatch() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// A failing patch leaves the instance unchanged
+			if after, _ := json.Marshal(instancePtr); string(after) != string(before) {
+				t.Errorf("instance = %s, want %s", after, before)
+			}
+		})
+	}
+
+	t.Run("field_path", func(t *testing.T) {
+		err := dynamicstruct.ApplyMergePatch(newPatchInstance(t), []byte(`{"address": {"Zip": "1"}}`))
+
+		var fieldErr *dynamicstruct.FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != "address.Zip" {
+			t.Errorf("ApplyMergePatch() error = %v, want a FieldError for address.Zip", err)
+		}
+	})
+
+	t.Run("not_pointer", func(t *testing.T) {
+		err := dynamicstruct.ApplyMergePatch(PersonTest{}, []byte(`{}`))
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("ApplyMergePatch() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const msgpackTagKey = "msgpack"
+
+// FromMsgpackSample infers a builder from a MessagePack encoded map, like
+// FromJSONSample does for JSON. Fields get a msgpack tag with their key.
+func FromMsgpackSample(data []byte, opts ...ImportOption) (*Builder, error) {
+	var sample map[string]any
+	if err := msgpack.Unmarshal(data, &sample); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSample, err.Error())
+	}
+
+	return fromSample(sample, msgpackTagKey, newImportConfig(opts))
+}
+
+func MarshalMsgpack(instance any) ([]byte, error) {
+	return msgpack.Marshal(instance)
+}
+
+func UnmarshalMsgpack(data []byte, instancePtr any) error {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return ErrValueCannotBeNil
+	}
+
+	if err := msgpack.Unmarshal(data, instancePtr); err != nil {
+		return err
+	}
+
+	return transformDecoded(valueReflect)
+}
@@ -0,0 +1,143 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestFromMsgpackSample(t *testing.T) {
+	sample, err := msgpack.Marshal(map[string]any{
+		"service_name": "billing",
+		"replicas":     3,
+		"ratio":        0.75,
+		"enabled":      true,
+		"hosts":        []string{"a.example.com", "b.example.com"},
+		"payload":      []byte{1, 2},
+		"database":     map[string]any{"host": "localhost", "port": 5432},
+	})
+	if err != nil {
+		t.Fatalf("msgpack.Marshal() error = %v", err)
+	}
+
+	builder, err := dynamicstruct.FromMsgpackSample(sample)
+	if err != nil {
+		t.Fatalf("FromMsgpackSample() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field    string
+		wantType reflect.Type
+		wantTag  string
+	}{
+		{field: "ServiceName", wantType: reflect.TypeOf(""), wantTag: "service_name"},
+		{field: "Replicas", wantType: reflect.TypeOf(int64(0)), wantTag: "replicas"},
+		{field: "Ratio", wantType: reflect.TypeOf(float64(0)), wantTag: "ratio"},
+		{field: "Enabled", wantType: reflect.TypeOf(false), wantTag: "enabled"},
+		{field: "Hosts", wantType: reflect.TypeOf([]string{}), wantTag: "hosts"},
+		{field: "Payload", wantType: reflect.TypeOf([]byte{}), wantTag: "payload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+
+			if field.Type != tt.wantType {
+				t.Errorf("field %s type = %v, want %v", tt.field, field.Type, tt.wantType)
+			}
+
+			if field.Tag.Get("msgpack") != tt.wantTag {
+				t.Errorf("field %s msgpack tag = %q, want %q", tt.field, field.Tag.Get("msgpack"), tt.wantTag)
+			}
+		})
+	}
+
+	t.Run("decode_sample", func(t *testing.T) {
+		value := reflect.New(structType)
+		if err := dynamicstruct.UnmarshalMsgpack(sample, value.Interface()); err != nil {
+			t.Fatalf("UnmarshalMsgpack() error = %v", err)
+		}
+
+		if got := value.Elem().FieldByName("Database").FieldByName("Port").Int(); got != 5432 {
+			t.Errorf("UnmarshalMsgpack() Database.Port = %d, want 5432", got)
+		}
+	})
+
+	t.Run("invalid_sample", func(t *testing.T) {
+		_, err := dynamicstruct.FromMsgpackSample([]byte{0xc1})
+		if !errors.Is(err, dynamicstruct.ErrInvalidSample) {
+			t.Errorf("FromMsgpackSample() error = %v, want %v", err, dynamicstruct.ErrInvalidSample)
+		}
+	})
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithMsgpackTags())
+	_ = builder.AddField("Name", "", `json:"name"`, `transform:"trim"`)
+	_ = builder.AddField("Age", 0, `json:"age,omitempty"`)
+	_ = builder.AddField("Note", "", `json:"note"`, `msgpack:"n"`)
+	_ = builder.AddField("Raw", "")
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tags := map[string]string{"Name": "name", "Age": "age,omitempty", "Note": "n", "Raw": ""}
+	for name, want := range tags {
+		field, _ := structType.FieldByName(name)
+		if got := field.Tag.Get("msgpack"); got != want {
+			t.Errorf("field %s msgpack tag = %q, want %q", name, got, want)
+		}
+	}
+
+	value := reflect.New(structType)
+	value.Elem().FieldByName("Name").SetString(" Alice ")
+	value.Elem().FieldByName("Note").SetString("hi")
+
+	data, err := dynamicstruct.MarshalMsgpack(value.Interface())
+	if err != nil {
+		t.Fatalf("MarshalMsgpack() error = %v", err)
+	}
+
+	var keys map[string]any
+	if err := msgpack.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("msgpack.Unmarshal() error = %v", err)
+	}
+
+	want := map[string]any{"name": " Alice ", "n": "hi", "Raw": ""}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("MarshalMsgpack() = %v, want %v", keys, want)
+	}
+
+	decoded := reflect.New(structType)
+	if err := dynamicstruct.UnmarshalMsgpack(data, decoded.Interface()); err != nil {
+		t.Fatalf("UnmarshalMsgpack() error = %v", err)
+	}
+
+	if got := decoded.Elem().FieldByName("Name").String(); got != "Alice" {
+		t.Errorf("UnmarshalMsgpack() Name = %q, want %q", got, "Alice")
+	}
+
+	t.Run("not_a_pointer", func(t *testing.T) {
+		err := dynamicstruct.UnmarshalMsgpack(data, decoded.Elem().Interface())
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("UnmarshalMsgpack() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
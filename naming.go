@@ -0,0 +1,111 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NameMapper maps a Go field name to a name used in struct tags.
+type NameMapper func(name string) string
+
+// ExportedName converts an arbitrary key such as "user_id" or "first-name"
+// into an exported Go identifier ("UserId", "FirstName"), as the importers
+// name fields.
+func ExportedName(key string) string {
+	var sb strings.Builder
+
+	upperNext := true
+
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+
+			continue
+		}
+
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+
+		sb.WriteRune(r)
+	}
+
+	name := sb.String()
+
+	// Identifiers must start with an upper case letter to be exported
+	if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+		name = "X" + name
+	}
+
+	return name
+}
+
+// SnakeCase converts a Go identifier such as "UserID" or "HTTPPort" into
+// "user_id" and "http_port".
+func SnakeCase(name string) string {
+	runes := []rune(name)
+
+	var sb strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word on a lower-to-upper transition, or at the last
+			// upper case letter of an acronym followed by a lower case letter
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// CamelCase converts a Go identifier such as "UserID" or "HTTPPort" into
+// "userID" and "httpPort".
+func CamelCase(name string) string {
+	runes := []rune(name)
+
+	// Lower the leading upper case run, keeping the last letter of an acronym
+	// upper case when it starts the next word
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
+
+// jsonName returns the key encoding/json uses for the field, and false when
+// the field is skipped with `json:"-"`.
+func jsonName(field reflect.StructField) (string, bool) {
+	return tagName(field, "json")
+}
+
+// tagName returns the name part of the field's tag for key, falling back to
+// the field name when the tag is absent or has an empty name.
+func tagName(field reflect.StructField, key string) (string, bool) {
+	tag, ok := field.Tag.Lookup(key)
+	if !ok {
+		return field.Name, true
+	}
+
+	if tag == "-" {
+		return "", false
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
@@ -0,0 +1,306 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const nestTagKey = "nest"
+
+// parseNestTag parses a `nest:"billing.address"` tag into the names of the
+// JSON objects the field is nested under.
+func parseNestTag(tag string) ([]string, error) {
+	path := strings.Split(tag, ".")
+
+	for _, name := range path {
+		if name == "" {
+			return nil, fmt.Errorf("%w: nest %q must be dot separated names", ErrInvalidTag, tag)
+		}
+	}
+
+	return path, nil
+}
+
+// nestPaths maps the json names of the fields of t to the nest path of the
+// field, for the fields with a nest tag.
+func nestPaths(t reflect.Type) (map[string][]string, error) {
+	paths := make(map[string][]string)
+
+	var walk func(t reflect.Type) error
+
+	walk = func(t reflect.Type) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+
+			name, ok := jsonName(field)
+			if !ok {
+				continue
+			}
+
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+
+			if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+				if err := walk(fieldType); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			tag, ok := field.Tag.Lookup(nestTagKey)
+			if !ok {
+				continue
+			}
+
+			path, err := parseNestTag(tag)
+			if err != nil {
+				return err
+			}
+
+			paths[name] = path
+		}
+
+		return nil
+	}
+
+	if err := walk(t); err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// MarshalNested encodes instance as JSON like json.Marshal, except that fields
+// with a `nest:"billing"` tag are emitted inside a "billing" object instead of
+// at the top level. Dots nest deeper: `nest:"billing.address"`.
+func MarshalNested(instance any) ([]byte, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := nestPaths(value.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	flat, err := decodeJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &jsonObject{values: make(map[string]any)}
+
+	for _, key := range flat.keys {
+		object := root
+
+		for _, name := range paths[key] {
+			object, err = object.child(name)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if _, ok := object.values[key]; ok {
+			return nil, fmt.Errorf("%w: key %q is both a field and a nest group", ErrInvalidTag, key)
+		}
+
+		object.set(key, flat.values[key])
+	}
+
+	var buf bytes.Buffer
+	if err := root.encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalNested decodes JSON produced by MarshalNested into the struct
+// instancePtr points to, reading fields with a nest tag from their nested
+// objects.
+func UnmarshalNested(data []byte, instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	paths, err := nestPaths(value.Type())
+	if err != nil {
+		return err
+	}
+
+	nested, err := decodeJSONObject(data)
+	if err != nil {
+		return err
+	}
+
+	groups := make(map[string]bool)
+	for _, path := range paths {
+		groups[path[0]] = true
+	}
+
+	flat := make(map[string]json.RawMessage)
+
+	// Top level keys are fields as they are, unless they are groups or belong
+	// to fields that are read from a group
+	for _, key := range nested.keys {
+		if _, nestedField := paths[key]; nestedField || groups[key] {
+			continue
+		}
+
+		flat[key] = nested.values[key].(json.RawMessage)
+	}
+
+	for key, path := range paths {
+		raw, ok, err := nested.lookup(append(append([]string(nil), path...), key))
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			flat[key] = raw
+		}
+	}
+
+	flatData, err := json.Marshal(flat)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(flatData, instancePtr); err != nil {
+		return err
+	}
+
+	return transformStruct(value)
+}
+
+// jsonObject is a JSON object that keeps the order of its keys. Values are
+// json.RawMessage or *jsonObject.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func decodeJSONObject(data []byte) (*jsonObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("JSON value is not an object")
+	}
+
+	object := &jsonObject{values: make(map[string]any)}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		object.set(token.(string), raw)
+	}
+
+	return object, nil
+}
+
+func (o *jsonObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+
+	o.values[key] = value
+}
+
+// child returns the object stored under name, adding it when missing.
+func (o *jsonObject) child(name string) (*jsonObject, error) {
+	switch existing := o.values[name].(type) {
+	case nil:
+		child := &jsonObject{values: make(map[string]any)}
+		o.set(name, child)
+
+		return child, nil
+	case *jsonObject:
+		return existing, nil
+	default:
+		return nil, fmt.Errorf("%w: nest group %q is also a field", ErrInvalidTag, name)
+	}
+}
+
+// lookup returns the raw value at path, decoding nested objects on the way.
+func (o *jsonObject) lookup(path []string) (json.RawMessage, bool, error) {
+	raw, ok := o.values[path[0]].(json.RawMessage)
+	if !ok {
+		return nil, false, nil
+	}
+
+	if len(path) == 1 {
+		return raw, true, nil
+	}
+
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, false, nil
+	}
+
+	child, err := decodeJSONObject(raw)
+	if err != nil {
+		return nil, false, fmt.Errorf("nest group %q: %w", path[0], err)
+	}
+
+	return child.lookup(path[1:])
+}
+
+func (o *jsonObject) encode(buf *bytes.Buffer) error {
+	buf.WriteByte('{')
+
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+
+		switch value := o.values[key].(type) {
+		case *jsonObject:
+			if err := value.encode(buf); err != nil {
+				return err
+			}
+		case json.RawMessage:
+			buf.Write(value)
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
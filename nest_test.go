@@ -0,0 +1,134 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newNestedType(t *testing.T) reflect.Type {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+	_ = builder.AddField("BillingName", "", `json:"name"`, `nest:"billing"`)
+	_ = builder.AddField("BillingCity", "", `json:"city"`, `nest:"billing.address"`)
+	_ = builder.AddField("BillingZip", "", `json:"zip,omitempty"`, `nest:"billing.address"`)
+	_ = builder.AddField("Plan", "", `json:"plan"`, `nest:"subscription"`)
+	_ = builder.AddField("Note", "", `json:"note"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestMarshalNested(t *testing.T) {
+	structType := newNestedType(t)
+
+	value := reflect.New(structType)
+	value.Elem().FieldByName("ID").SetInt(1)
+	value.Elem().FieldByName("BillingName").SetString("Alice")
+	value.Elem().FieldByName("BillingCity").SetString("Oslo")
+	value.Elem().FieldByName("Plan").SetString("pro")
+
+	data, err := dynamicstruct.MarshalNested(value.Interface())
+	if err != nil {
+		t.Fatalf("MarshalNested() error = %v", err)
+	}
+
+	want := `{"id":1,"billing":{"name":"Alice","address":{"city":"Oslo"}},"subscription":{"plan":"pro"},"note":""}`
+	if string(data) != want {
+		t.Errorf("MarshalNested() = %s, want %s", data, want)
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		decoded := reflect.New(structType)
+		if err := dynamicstruct.UnmarshalNested(data, decoded.Interface()); err != nil {
+			t.Fatalf("UnmarshalNested() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(decoded.Elem().Interface(), value.Elem().Interface()) {
+			t.Errorf("UnmarshalNested() = %+v, want %+v", decoded.Elem().Interface(), value.Elem().Interface())
+		}
+	})
+}
+
+func TestUnmarshalNested(t *testing.T) {
+	structType := newNestedType(t)
+
+	tests := []struct {
+		name string
+		data string
+		want map[string]any
+	}{
+		{
+			name: "nested_values",
+			data: `{"id":2,"billing":{"name":"Bob","address":{"city":"Bergen","zip":"5003"}}}`,
+			want: map[string]any{"ID": int64(2), "BillingName": "Bob", "BillingCity": "Bergen", "BillingZip": "5003"},
+		},
+		{
+			name: "missing_and_null_groups",
+			data: `{"id":3,"billing":null}`,
+			want: map[string]any{"ID": int64(3)},
+		},
+		{
+			name: "flat_key_ignored_for_nested_field",
+			data: `{"plan":"flat","subscription":{"plan":"nested"}}`,
+			want: map[string]any{"Plan": "nested"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded := reflect.New(structType)
+			if err := dynamicstruct.UnmarshalNested([]byte(tt.data), decoded.Interface()); err != nil {
+				t.Fatalf("UnmarshalNested() error = %v", err)
+			}
+
+			for name, want := range tt.want {
+				if got := decoded.Elem().FieldByName(name).Interface(); got != want {
+					t.Errorf("%s = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("group_not_object", func(t *testing.T) {
+		err := dynamicstruct.UnmarshalNested([]byte(`{"billing":"x"}`), reflect.New(structType).Interface())
+		if err == nil {
+			t.Error("UnmarshalNested() error = nil, want error")
+		}
+	})
+
+	t.Run("not_pointer", func(t *testing.T) {
+		err := dynamicstruct.UnmarshalNested([]byte(`{}`), reflect.New(structType).Elem().Interface())
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("UnmarshalNested() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
+
+func TestNestTag(t *testing.T) {
+	t.Run("invalid_tag", func(t *testing.T) {
+		err := dynamicstruct.New().AddField("City", "", `nest:"billing..address"`)
+		if !errors.Is(err, dynamicstruct.ErrInvalidTag) {
+			t.Errorf("AddField() error = %v, want %v", err, dynamicstruct.ErrInvalidTag)
+		}
+	})
+
+	t.Run("group_collides_with_field", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Billing", "", `json:"billing"`)
+		_ = builder.AddField("Name", "", `json:"name"`, `nest:"billing"`)
+		instance, _ := builder.Build()
+
+		if _, err := dynamicstruct.MarshalNested(instance); !errors.Is(err, dynamicstruct.ErrInvalidTag) {
+			t.Errorf("MarshalNested() error = %v, want %v", err, dynamicstruct.ErrInvalidTag)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package dynamicstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// builderType is the placeholder type of a field whose type is given by a
+// nested builder until it is resolved.
+var builderType = reflect.TypeOf((*Builder)(nil))
+
+// addBuilderField adds a field whose type is the struct defined by child. The
+// child is resolved when the builder is built, so it may still be edited
+// until then.
+func (b *Builder) addBuilderField(name string, child *Builder, tags []string) error {
+	if child == nil {
+		return fieldError("add", name, fmt.Errorf("%w: nested builder", ErrValueCannotBeNil))
+	}
+
+	if err := b.addField(name, builderType, tags); err != nil {
+		return err
+	}
+
+	if b.nested == nil {
+		b.nested = make(map[string]*Builder)
+	}
+
+	b.nested[name] = child
+
+	return nil
+}
+
+// nestingStep is a builder being resolved and the field of it whose nested
+// builder is resolved next.
+type nestingStep struct {
+	builder *Builder
+	field   string
+}
+
+// resolveStructFields returns the struct fields of the builder with nested
+// builders replaced by the struct types they define. path holds the builders
+// being resolved, to detect builders nested in themselves.
+func (b *Builder) resolveStructFields(path []nestingStep) ([]reflect.StructField, error) {
+	fields := b.buildStructFields()
+
+	for i, field := range fields {
+		child, ok := b.nested[field.Name]
+		if !ok || field.Anonymous {
+			continue
+		}
+
+		childType, err := child.nestedType(append(path, nestingStep{builder: b, field: field.Name}))
+		if err != nil {
+			// Circular references already name the fields of the cycle
+			if errors.Is(err, ErrCircularReference) {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		fields[i].Type = childType
+	}
+
+	return fields, nil
+}
+
+// nestedType returns the struct type the builder defines: the type of its
+// instance when it is built, and the type it would build otherwise. A builder
+// found on path is nested in itself, which no struct type can be, and returns
+// ErrCircularReference with the fields from the builder being built to where
+// it comes back, such as "Customer.Orders".
+func (b *Builder) nestedType(path []nestingStep) (reflect.Type, error) {
+	for _, step := range path {
+		if step.builder != b {
+			continue
+		}
+
+		fields := make([]string, len(path))
+		for i, pathStep := range path {
+			fields[i] = pathStep.field
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrCircularReference, strings.Join(fields, "."))
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.instance != nil {
+		return b.instance.Type(), nil
+	}
+
+	fields, err := b.resolveStructFields(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return structOf(fields)
+}
+
+// resolveStructType returns the struct type the builder's definition would
+// build, with nested builders resolved. b.m must be held.
+func (b *Builder) resolveStructType() (reflect.Type, error) {
+	fields, err := b.resolveStructFields(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return structOf(fields)
+}
+
+// applyNestedDefaults sets the defaults of nested builders' fields in value,
+// an instance of the builder's type.
+func (b *Builder) applyNestedDefaults(value reflect.Value) {
+	for name, child := range b.nested {
+		field := value.FieldByName(name)
+		if !field.IsValid() {
+			continue
+		}
+
+		child.m.RLock()
+		child.applyDefaults(field)
+		child.applyNestedDefaults(field)
+		child.m.RUnlock()
+	}
+}
+
+// typeFields returns the fields of a struct type.
+func typeFields(structType reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, structType.NumField())
+	for i := range fields {
+		fields[i] = structType.Field(i)
+	}
+
+	return fields
+}
@@ -0,0 +1,172 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestAddFieldBuilder(t *testing.T) {
+	geo := dynamicstruct.New()
+	_ = geo.AddField("Lat", 0.0, `json:"lat"`)
+
+	address := dynamicstruct.New()
+	_ = address.AddField("Street", "", `json:"street"`)
+	_ = address.AddField("Geo", geo, `json:"geo"`)
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+
+	if err := builder.AddField("Address", address, `json:"address"`); err != nil {
+		t.Fatalf("AddField() error = %v", err)
+	}
+
+	// The child definition can still change before the parent is built
+	_ = address.AddFieldWithOptions("Country", "", dynamicstruct.Tags(`json:"country"`), dynamicstruct.Default("NL"))
+	_ = geo.AddField("Lng", 0.0, `json:"lng"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	data, _ := json.Marshal(instance)
+
+	want := `{"name":"","address":{"street":"","geo":{"lat":0,"lng":0},"country":"NL"}}`
+	if string(data) != want {
+		t.Errorf("Build() = %s, want %s", data, want)
+	}
+
+	fresh, _ := builder.NewInstance()
+	if got := reflect.ValueOf(fresh).Elem().FieldByName("Address").FieldByName("Country").String(); got != "NL" {
+		t.Errorf("NewInstance() Address.Country = %q, want %q", got, "NL")
+	}
+
+	if _, err := address.InstanceValue(); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("child InstanceValue() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	t.Run("built_child", func(t *testing.T) {
+		child := dynamicstruct.New()
+		_ = child.AddField("City", "")
+
+		childInstance, _ := child.Build()
+
+		parent := dynamicstruct.New()
+		_ = parent.AddField("Home", child)
+
+		instance, err := parent.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if got := reflect.TypeOf(instance).Field(0).Type; got != reflect.TypeOf(childInstance) {
+			t.Errorf("Build() Home type = %s, want %s", got, reflect.TypeOf(childInstance))
+		}
+	})
+}
+
+func TestAddFieldBuilderErrors(t *testing.T) {
+	t.Run("nil_builder", func(t *testing.T) {
+		var child *dynamicstruct.Builder
+
+		err := dynamicstruct.New().AddField("Child", child)
+		if !errors.Is(err, dynamicstruct.ErrValueCannotBeNil) {
+			t.Errorf("AddField() error = %v, want %v", err, dynamicstruct.ErrValueCannotBeNil)
+		}
+	})
+
+	t.Run("nested_in_itself", func(t *testing.T) {
+		a := dynamicstruct.New()
+		b := dynamicstruct.New()
+		_ = a.AddField("B", b)
+		_ = b.AddField("A", a)
+
+		_, err := a.Build()
+		if !errors.Is(err, dynamicstruct.ErrCircularReference) {
+			t.Fatalf("Build() error = %v, want %v", err, dynamicstruct.ErrCircularReference)
+		}
+
+		if want := "circular reference: B.A"; err.Error() != want {
+			t.Errorf("Build() error = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("cycle_below_root", func(t *testing.T) {
+		root := dynamicstruct.New()
+		a := dynamicstruct.New()
+		b := dynamicstruct.New()
+		_ = root.AddField("A", a)
+		_ = a.AddField("Name", "")
+		_ = a.AddField("B", b)
+		_ = b.AddField("Back", a)
+
+		_, err := root.Build()
+		if !errors.Is(err, dynamicstruct.ErrCircularReference) {
+			t.Fatalf("Build() error = %v, want %v", err, dynamicstruct.ErrCircularReference)
+		}
+
+		if want := "circular reference: A.B.Back"; err.Error() != want {
+			t.Errorf("Build() error = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("nested_in_itself_directly", func(t *testing.T) {
+		a := dynamicstruct.New()
+		_ = a.AddField("Self", a)
+
+		if _, err := a.Build(); !errors.Is(err, dynamicstruct.ErrCircularReference) {
+			t.Errorf("Build() error = %v, want %v", err, dynamicstruct.ErrCircularReference)
+		}
+	})
+
+	t.Run("removed_field", func(t *testing.T) {
+		child := dynamicstruct.New()
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Child", child)
+		_ = builder.RemoveField("Child")
+		_ = builder.AddField("Child", "")
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if got := reflect.TypeOf(instance).Field(0).Type.Kind(); got != reflect.String {
+			t.Errorf("Build() Child kind = %s, want string", got)
+		}
+	})
+}
+
+func TestRebuildNestedBuilder(t *testing.T) {
+	address := dynamicstruct.New()
+	_ = address.AddField("Street", "")
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Address", address)
+
+	instance, _ := builder.Build()
+
+	value := reflect.New(reflect.TypeOf(instance)).Elem()
+	value.Field(0).FieldByName("Street").SetString("Main St")
+	_ = builder.SetFieldValue("Address", value.Field(0).Interface())
+
+	_ = address.AddField("City", "")
+
+	rebuilt, err := builder.Rebuild(func(*dynamicstruct.Builder) error { return nil })
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+
+	nested := reflect.ValueOf(rebuilt).FieldByName("Address")
+	if _, ok := nested.Type().FieldByName("City"); !ok {
+		t.Fatalf("Rebuild() Address has no City field")
+	}
+
+	if got := nested.FieldByName("Street").String(); got != "Main St" {
+		t.Errorf("Rebuild() Address.Street = %q, want %q", got, "Main St")
+	}
+}
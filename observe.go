@@ -0,0 +1,219 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+func (b *Builder) SetFieldValue(name string, value any) error {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.Lock()
+	defer b.values.Unlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return fieldError("set", name, ErrInstanceNotBuilt)
+	}
+
+	return fieldError("set", name, b.setStructField(*b.instance, name, value))
+}
+
+// setStructField sets the named field of instance, a value of the built type,
+// as SetFieldValue does. b.m must be held.
+func (b *Builder) setStructField(instance reflect.Value, name string, value any) error {
+	field, ok := b.structField(instance, name)
+	if !ok {
+		// A field promoted through a nil embedded pointer is missing until
+		// the pointer is allocated
+		field, ok = fieldByNameAlloc(instance, name)
+		if !ok {
+			return missingField(instance.Type(), name)
+		}
+	}
+
+	if b.coerce {
+		converted, ok, err := convertNumber(field.Type(), reflect.ValueOf(value))
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error())
+		}
+
+		if ok {
+			value = converted.Interface()
+		}
+	}
+
+	_, err := setField(field, value)
+
+	return err
+}
+
+// setFieldValue sets the named field of the addressable struct value and
+// returns its previous value. A nil value sets the zero value of fields that
+// can be nil. Nil embedded pointers on the way to the field are allocated.
+func setFieldValue(value reflect.Value, name string, fieldValue any) (reflect.Value, error) {
+	field, ok := fieldByNameAlloc(value, name)
+	if !ok {
+		return reflect.Value{}, missingField(value.Type(), name)
+	}
+
+	return setField(field, fieldValue)
+}
+
+// setField sets field to fieldValue and returns its previous value. Strings
+// and numbers are converted for time.Time and time.Duration fields.
+func setField(field reflect.Value, fieldValue any) (reflect.Value, error) {
+	if !field.CanSet() {
+		return reflect.Value{}, ErrFieldNotFound
+	}
+
+	newValue := reflect.ValueOf(fieldValue)
+
+	if !newValue.IsValid() {
+		switch field.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+			newValue = reflect.Zero(field.Type())
+		default:
+			return reflect.Value{}, fmt.Errorf(
+				"%w: field type: %s, value type: nil",
+				ErrIncompatibleTypes,
+				field.Type().String(),
+			)
+		}
+	}
+
+	// Times and durations are also accepted as strings and numbers
+	if !newValue.Type().AssignableTo(field.Type()) {
+		converted, ok, err := convertTime(field.Type(), newValue)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error())
+		}
+
+		if ok {
+			newValue = converted
+		}
+	}
+
+	// Check if the types are compatible
+	if !newValue.Type().AssignableTo(field.Type()) {
+		return reflect.Value{}, fmt.Errorf(
+			"%w: field type: %s, value type: %s",
+			ErrIncompatibleTypes,
+			field.Type().String(),
+			newValue.Type().String(),
+		)
+	}
+
+	old := reflect.New(field.Type()).Elem()
+	old.Set(field)
+
+	field.Set(newValue)
+
+	return old, nil
+}
+
+type observer struct {
+	fn func(old, new any)
+}
+
+// Observable wraps an instance so that changes made through SetFieldValue are
+// reported to the callbacks registered with OnSet.
+type Observable struct {
+	value     reflect.Value
+	observers map[string][]*observer
+	m         sync.Mutex
+}
+
+// Observe returns an Observable for the struct instancePtr points to. Changes
+// made to the instance directly, rather than through the Observable, are not
+// reported.
+func Observe(instancePtr any) (*Observable, error) {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Observable{
+		value:     value,
+		observers: make(map[string][]*observer),
+	}, nil
+}
+
+// OnSet registers fn to be called with the old and new value whenever
+// SetFieldValue changes the named field. Setting a field to a value equal to
+// its current one doesn't call fn. The returned function unregisters fn.
+func (o *Observable) OnSet(name string, fn func(old, new any)) (func(), error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	if _, ok := o.value.Type().FieldByName(name); !ok {
+		return nil, fieldError("observe", name, missingField(o.value.Type(), name))
+	}
+
+	obs := &observer{fn: fn}
+	o.observers[name] = append(o.observers[name], obs)
+
+	return func() {
+		o.m.Lock()
+		defer o.m.Unlock()
+
+		observers := o.observers[name]
+		for i, registered := range observers {
+			if registered == obs {
+				o.observers[name] = append(observers[:i:i], observers[i+1:]...)
+
+				break
+			}
+		}
+	}, nil
+}
+
+// SetFieldValue sets the named field and calls the callbacks registered for
+// it when the value changed. Callbacks run after the field is set, outside the
+// Observable's lock, so they may set other fields.
+func (o *Observable) SetFieldValue(name string, value any) error {
+	o.m.Lock()
+
+	old, err := setFieldValue(o.value, name, value)
+	if err != nil {
+		o.m.Unlock()
+
+		return fieldError("set", name, err)
+	}
+
+	current := o.value.FieldByName(name).Interface()
+	observers := o.observers[name]
+	o.m.Unlock()
+
+	if reflect.DeepEqual(old.Interface(), current) {
+		return nil
+	}
+
+	for _, obs := range observers {
+		obs.fn(old.Interface(), current)
+	}
+
+	return nil
+}
+
+func (o *Observable) GetField(name string) (any, error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	field, ok := fieldByName(o.value, name)
+	if !ok {
+		return nil, fieldError("get", name, missingField(o.value.Type(), name))
+	}
+
+	if !field.CanInterface() {
+		return nil, fieldError("get", name, ErrFieldNotFound)
+	}
+
+	return field.Interface(), nil
+}
+
+// Instance returns the pointer the Observable was created with.
+func (o *Observable) Instance() any {
+	return o.value.Addr().Interface()
+}
@@ -0,0 +1,137 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestSetFieldValue(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("Price", float64(0))
+	_ = builder.AddField("Note", (*string)(nil))
+
+	if err := builder.SetFieldValue("Price", 1.0); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("SetFieldValue() before Build error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	note := "hello"
+
+	tests := []struct {
+		name    string
+		field   string
+		value   any
+		wantErr error
+	}{
+		{name: "scalar", field: "Price", value: 9.99, wantErr: nil},
+		{name: "promoted", field: "Name", value: "Alice", wantErr: nil},
+		{name: "pointer", field: "Note", value: &note, wantErr: nil},
+		{name: "nil_pointer", field: "Note", value: nil, wantErr: nil},
+		{name: "nil_scalar", field: "Price", value: nil, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "incompatible", field: "Price", value: "9.99", wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "missing", field: "Missing", value: 1, wantErr: dynamicstruct.ErrFieldNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := builder.SetFieldValue(tt.field, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			got, _ := builder.GetField(tt.field)
+
+			want := tt.value
+			if want == nil {
+				want = (*string)(nil)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GetField() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestObservable(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Price", float64(0))
+	_ = builder.AddField("Quantity", int(0))
+	_ = builder.AddField("Total", float64(0))
+	instance, _ := builder.Build()
+
+	instancePtr := reflect.New(reflect.TypeOf(instance)).Interface()
+
+	observable, err := dynamicstruct.Observe(instancePtr)
+	if err != nil {
+		t.Fatalf("Observe() error = %v", err)
+	}
+
+	type change struct {
+		old, new any
+	}
+
+	var priceChanges []change
+
+	cancel, err := observable.OnSet("Price", func(old, new any) {
+		priceChanges = append(priceChanges, change{old, new})
+
+		// Callbacks may set other fields
+		quantity, _ := observable.GetField("Quantity")
+		_ = observable.SetFieldValue("Total", new.(float64)*float64(quantity.(int)))
+	})
+	if err != nil {
+		t.Fatalf("OnSet() error = %v", err)
+	}
+
+	_ = observable.SetFieldValue("Quantity", 3)
+	_ = observable.SetFieldValue("Price", 2.5)
+	_ = observable.SetFieldValue("Price", 2.5)
+
+	wantChanges := []change{{old: 0.0, new: 2.5}}
+	if !reflect.DeepEqual(priceChanges, wantChanges) {
+		t.Errorf("changes = %v, want %v", priceChanges, wantChanges)
+	}
+
+	if total := reflect.ValueOf(instancePtr).Elem().FieldByName("Total").Float(); total != 7.5 {
+		t.Errorf("Total = %v, want 7.5", total)
+	}
+
+	if observable.Instance() != instancePtr {
+		t.Error("Instance() does not return the observed pointer")
+	}
+
+	t.Run("cancel", func(t *testing.T) {
+		cancel()
+		_ = observable.SetFieldValue("Price", 4.0)
+
+		if len(priceChanges) != 1 {
+			t.Errorf("changes after cancel = %v, want none added", priceChanges)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := observable.OnSet("Missing", func(_, _ any) {}); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("OnSet() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+
+		if err := observable.SetFieldValue("Price", "x"); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("SetFieldValue() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+
+		if _, err := dynamicstruct.Observe(instance); !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("Observe() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
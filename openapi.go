@@ -0,0 +1,332 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const openAPISchemaRefPrefix = "#/components/schemas/"
+
+// FromOpenAPISchema creates a builder from the components.schemas entry named
+// componentName of an OpenAPI document in JSON or YAML. Properties become
+// fields tagged with their JSON name; optional properties get omitempty and
+// nullable ones become pointers. References to other components are resolved.
+func FromOpenAPISchema(spec []byte, componentName string, opts ...ImportOption) (*Builder, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+	}
+
+	root := &doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	schemas := yamlLookup(yamlLookup(root, "components"), "schemas")
+	if schemas == nil {
+		return nil, fmt.Errorf("%w: components.schemas not found", ErrInvalidSchema)
+	}
+
+	component := yamlLookup(schemas, componentName)
+	if component == nil {
+		return nil, fmt.Errorf("%w: component %q not found", ErrInvalidSchema, componentName)
+	}
+
+	importer := &openAPIImporter{
+		schemas:   schemas,
+		refPrefix: openAPISchemaRefPrefix,
+		resolving: map[string]bool{componentName: true},
+		cfg:       newImportConfig(opts),
+	}
+
+	return importer.builder(component)
+}
+
+// fromJSONSchema creates a builder from a JSON Schema document describing an
+// object, the way FromOpenAPISchema does for a component. References resolve
+// to the document's $defs, or definitions in older drafts.
+func fromJSONSchema(spec []byte, opts ...ImportOption) (*Builder, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSchema, err.Error())
+	}
+
+	root := &doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	if typeName, _ := openAPIType(root); typeName != "object" {
+		return nil, fmt.Errorf("%w: JSON Schema doesn't describe an object", ErrInvalidSchema)
+	}
+
+	importer := &openAPIImporter{
+		schemas:   yamlLookup(root, "$defs"),
+		refPrefix: "#/$defs/",
+		resolving: map[string]bool{},
+		cfg:       newImportConfig(opts),
+	}
+
+	if importer.schemas == nil {
+		importer.schemas = yamlLookup(root, "definitions")
+		importer.refPrefix = "#/definitions/"
+	}
+
+	return importer.builder(root)
+}
+
+func (b *Builder) ToOpenAPISchema() ([]byte, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	structType, err := b.resolveStructType()
+	if err != nil {
+		return nil, err
+	}
+
+	s := structSchema(structType, func(s *schema) {
+		s.Nullable = true
+	})
+
+	return json.Marshal(s)
+}
+
+type openAPIImporter struct {
+	schemas   *yaml.Node
+	refPrefix string
+	resolving map[string]bool
+	cfg       *importConfig
+}
+
+func (im *openAPIImporter) builder(node *yaml.Node) (*Builder, error) {
+	builder := New()
+
+	err := im.addProperties(builder, node)
+	if err != nil {
+		return nil, err
+	}
+
+	return builder, nil
+}
+
+func (im *openAPIImporter) addProperties(builder *Builder, node *yaml.Node) error {
+	return im.withRef(node, func(node *yaml.Node) error {
+		// allOf members contribute their properties to the same object
+		if allOf := yamlLookup(node, "allOf"); allOf != nil {
+			for _, member := range allOf.Content {
+				if err := im.addProperties(builder, member); err != nil {
+					return err
+				}
+			}
+		}
+
+		required := make(map[string]bool)
+		if list := yamlLookup(node, "required"); list != nil {
+			for _, item := range list.Content {
+				required[item.Value] = true
+			}
+		}
+
+		properties := yamlLookup(node, "properties")
+		if properties == nil {
+			return nil
+		}
+
+		for i := 0; i+1 < len(properties.Content); i += 2 {
+			name := properties.Content[i].Value
+
+			fieldType, err := im.fieldType(properties.Content[i+1])
+			if err != nil {
+				return fmt.Errorf("%w: property %q", err, name)
+			}
+
+			tag := fmt.Sprintf("json:%q", name)
+			if !required[name] {
+				tag = fmt.Sprintf(`json:"%s,omitempty"`, name)
+			}
+
+			if err := builder.addImportedField(name, fieldType, tag, im.cfg); err != nil {
+				return fmt.Errorf("%w: property %q", err, name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// withRef calls fn with the schema node refers to, guarding against cycles.
+func (im *openAPIImporter) withRef(node *yaml.Node, fn func(*yaml.Node) error) error {
+	ref := yamlLookup(node, "$ref")
+	if ref == nil {
+		return fn(node)
+	}
+
+	name := strings.TrimPrefix(ref.Value, im.refPrefix)
+	if name == ref.Value {
+		return fmt.Errorf("%w: unsupported reference %q", ErrInvalidSchema, ref.Value)
+	}
+
+	if im.resolving[name] {
+		return fmt.Errorf("%w: circular reference to %q", ErrInvalidSchema, name)
+	}
+
+	target := yamlLookup(im.schemas, name)
+	if target == nil {
+		return fmt.Errorf("%w: component %q not found", ErrInvalidSchema, name)
+	}
+
+	im.resolving[name] = true
+	defer delete(im.resolving, name)
+
+	return fn(target)
+}
+
+func (im *openAPIImporter) fieldType(node *yaml.Node) (reflect.Type, error) {
+	var fieldType reflect.Type
+
+	err := im.withRef(node, func(node *yaml.Node) error {
+		var err error
+
+		fieldType, err = im.schemaType(node)
+
+		return err
+	})
+
+	return fieldType, err
+}
+
+func (im *openAPIImporter) schemaType(node *yaml.Node) (reflect.Type, error) {
+	typeName, nullable := openAPIType(node)
+
+	var (
+		fieldType reflect.Type
+		err       error
+	)
+
+	format := ""
+	if formatNode := yamlLookup(node, "format"); formatNode != nil {
+		format = formatNode.Value
+	}
+
+	switch typeName {
+	case "string":
+		switch format {
+		case "date-time":
+			fieldType = timeType
+		case "byte", "binary":
+			fieldType = reflect.TypeOf([]byte(nil))
+		default:
+			fieldType = reflect.TypeOf("")
+		}
+	case "integer":
+		if format == "int32" {
+			fieldType = reflect.TypeOf(int32(0))
+		} else {
+			fieldType = reflect.TypeOf(int64(0))
+		}
+	case "number":
+		if format == "float" {
+			fieldType = reflect.TypeOf(float32(0))
+		} else {
+			fieldType = reflect.TypeOf(float64(0))
+		}
+	case "boolean":
+		fieldType = reflect.TypeOf(false)
+	case "array":
+		elemType := anyType
+
+		if items := yamlLookup(node, "items"); items != nil {
+			if elemType, err = im.fieldType(items); err != nil {
+				return nil, err
+			}
+		}
+
+		fieldType = reflect.SliceOf(elemType)
+	case "object":
+		if fieldType, err = im.objectType(node); err != nil {
+			return nil, err
+		}
+	default:
+		fieldType = anyType
+	}
+
+	if nullable && fieldType.Kind() != reflect.Interface {
+		fieldType = reflect.PtrTo(fieldType)
+	}
+
+	return fieldType, nil
+}
+
+func (im *openAPIImporter) objectType(node *yaml.Node) (reflect.Type, error) {
+	if yamlLookup(node, "properties") == nil && yamlLookup(node, "allOf") == nil {
+		elemType := anyType
+
+		if additional := yamlLookup(node, "additionalProperties"); additional != nil && additional.Kind == yaml.MappingNode {
+			var err error
+			if elemType, err = im.fieldType(additional); err != nil {
+				return nil, err
+			}
+		}
+
+		return reflect.MapOf(reflect.TypeOf(""), elemType), nil
+	}
+
+	builder, err := im.builder(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return structOf(builder.buildStructFields())
+}
+
+// openAPIType returns the schema type and whether null is allowed, covering
+// both the OpenAPI 3.0 nullable keyword and 3.1 type arrays.
+func openAPIType(node *yaml.Node) (string, bool) {
+	nullable := false
+	if nullableNode := yamlLookup(node, "nullable"); nullableNode != nil {
+		nullable = nullableNode.Value == "true"
+	}
+
+	typeNode := yamlLookup(node, "type")
+
+	switch {
+	case typeNode == nil:
+		if yamlLookup(node, "properties") != nil || yamlLookup(node, "allOf") != nil {
+			return "object", nullable
+		}
+
+		return "", nullable
+	case typeNode.Kind == yaml.SequenceNode:
+		typeName := ""
+
+		for _, item := range typeNode.Content {
+			if item.Value == "null" {
+				nullable = true
+			} else {
+				typeName = item.Value
+			}
+		}
+
+		return typeName, nullable
+	default:
+		return typeNode.Value, nullable
+	}
+}
+
+func yamlLookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+          format: int64
+    Address:
+      type: object
+      properties:
+        city:
+          type: string
+    User:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - type: object
+          required: [email]
+          properties:
+            email:
+              type: string
+            nickname:
+              type: string
+              nullable: true
+            age:
+              type: integer
+              format: int32
+            score:
+              type: number
+            active:
+              type: boolean
+            created_at:
+              type: string
+              format: date-time
+            tags:
+              type: array
+              items:
+                type: string
+            labels:
+              type: object
+              additionalProperties:
+                type: string
+            address:
+              $ref: '#/components/schemas/Address'
+            extra: {}
+    Node:
+      type: object
+      properties:
+        next:
+          $ref: '#/components/schemas/Node'
+`
+
+func TestFromOpenAPISchema(t *testing.T) {
+	builder, err := dynamicstruct.FromOpenAPISchema([]byte(testOpenAPISpec), "User")
+	if err != nil {
+		t.Fatalf("FromOpenAPISchema() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field    string
+		wantType reflect.Type
+		wantTag  string
+	}{
+		{field: "Id", wantType: reflect.TypeOf(int64(0)), wantTag: "id"},
+		{field: "Email", wantType: reflect.TypeOf(""), wantTag: "email"},
+		{field: "Nickname", wantType: reflect.TypeOf((*string)(nil)), wantTag: "nickname,omitempty"},
+		{field: "Age", wantType: reflect.TypeOf(int32(0)), wantTag: "age,omitempty"},
+		{field: "Score", wantType: reflect.TypeOf(float64(0)), wantTag: "score,omitempty"},
+		{field: "Active", wantType: reflect.TypeOf(false), wantTag: "active,omitempty"},
+		{field: "CreatedAt", wantType: reflect.TypeOf(time.Time{}), wantTag: "created_at,omitempty"},
+		{field: "Tags", wantType: reflect.TypeOf([]string{}), wantTag: "tags,omitempty"},
+		{field: "Labels", wantType: reflect.TypeOf(map[string]string{}), wantTag: "labels,omitempty"},
+		{field: "Extra", wantType: reflect.TypeOf((*interface{})(nil)).Elem(), wantTag: "extra,omitempty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if field.Type != tt.wantType {
+				t.Errorf("field %s type = %v, want %v", tt.field, field.Type, tt.wantType)
+			}
+			if field.Tag.Get("json") != tt.wantTag {
+				t.Errorf("field %s json tag = %q, want %q", tt.field, field.Tag.Get("json"), tt.wantTag)
+			}
+		})
+	}
+
+	t.Run("referenced_component", func(t *testing.T) {
+		field, ok := structType.FieldByName("Address")
+		if !ok {
+			t.Fatal("field Address not found")
+		}
+		if _, ok := field.Type.FieldByName("City"); !ok {
+			t.Error("Address.City not found")
+		}
+	})
+
+	t.Run("field_order_follows_spec", func(t *testing.T) {
+		if structType.Field(0).Name != "Id" || structType.Field(1).Name != "Email" {
+			t.Errorf("first fields = %s, %s, want Id, Email", structType.Field(0).Name, structType.Field(1).Name)
+		}
+	})
+}
+
+func TestFromOpenAPISchemaErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		component string
+	}{
+		{name: "invalid_document", spec: `{`, component: "User"},
+		{name: "missing_components", spec: `openapi: 3.0.3`, component: "User"},
+		{name: "missing_component", spec: testOpenAPISpec, component: "Missing"},
+		{name: "circular_reference", spec: testOpenAPISpec, component: "Node"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamicstruct.FromOpenAPISchema([]byte(tt.spec), tt.component)
+			if !errors.Is(err, dynamicstruct.ErrInvalidSchema) {
+				t.Errorf("FromOpenAPISchema() error = %v, want %v", err, dynamicstruct.ErrInvalidSchema)
+			}
+		})
+	}
+}
+
+func TestToOpenAPISchema(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+	_ = builder.AddField("Nickname", (*string)(nil), `json:"nickname"`)
+	_ = builder.AddField("Tags", []string{}, `json:"tags,omitempty"`)
+	_ = builder.AddField("CreatedAt", time.Time{}, `json:"created_at"`)
+	_ = builder.AddField("Secret", "", `json:"-"`)
+
+	data, err := builder.ToOpenAPISchema()
+	if err != nil {
+		t.Fatalf("ToOpenAPISchema() error = %v", err)
+	}
+
+	want := `{"type":"object","properties":{` +
+		`"Name":{"type":"string"},` +
+		`"Age":{"type":"integer","format":"int64"},` +
+		`"id":{"type":"integer","format":"int64"},` +
+		`"nickname":{"type":"string","nullable":true},` +
+		`"tags":{"type":"array","items":{"type":"string"}},` +
+		`"created_at":{"type":"string","format":"date-time"}},` +
+		`"required":["Name","Age","id","created_at"]}`
+
+	if string(data) != want {
+		t.Errorf("ToOpenAPISchema() =\n%s\nwant\n%s", data, want)
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		spec, _ := json.Marshal(map[string]interface{}{
+			"components": map[string]interface{}{
+				"schemas": map[string]json.RawMessage{"Exported": data},
+			},
+		})
+
+		imported, err := dynamicstruct.FromOpenAPISchema(spec, "Exported")
+		if err != nil {
+			t.Fatalf("FromOpenAPISchema() error = %v", err)
+		}
+
+		instance, err := imported.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if reflect.TypeOf(instance).NumField() != 6 {
+			t.Errorf("imported fields = %d, want 6", reflect.TypeOf(instance).NumField())
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// omitemptyTagKeys are the tag keys whose encoders understand omitempty.
+var omitemptyTagKeys = map[string]bool{
+	"json":        true,
+	"yaml":        true,
+	"xml":         true,
+	msgpackTagKey: true,
+	cborTagKey:    true,
+}
+
+// AddOptionalField adds a field that may be absent: its type is a pointer to
+// the type of kind, nil until a value is set, and its json tag, along with any
+// yaml, xml, msgpack and cbor tags, gets the omitempty option. A field without
+// a json tag gets `json:",omitempty"`. A kind that already is a pointer is
+// used as it is.
+func (b *Builder) AddOptionalField(name string, kind any, tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	fieldType := reflect.TypeOf(kind)
+	if fieldType == nil {
+		return fieldError("add", name, fmt.Errorf("%w: kind of optional field", ErrValueCannotBeNil))
+	}
+
+	if fieldType.Kind() != reflect.Ptr {
+		fieldType = reflect.PtrTo(fieldType)
+	}
+
+	if err := b.addField(name, fieldType, tags); err != nil {
+		return err
+	}
+
+	field := b.fields[name]
+	field.Tag = withOmitempty(field.Tag)
+	b.fields[name] = field
+
+	return nil
+}
+
+// withOmitempty adds the omitempty option to the encoding keys of tag, and a
+// json key when tag has none.
+func withOmitempty(tag reflect.StructTag) reflect.StructTag {
+	parts := make([]string, 0, len(omitemptyTagKeys))
+	hasJSON := false
+
+	for _, t := range splitTag(tag) {
+		hasJSON = hasJSON || t.key == "json"
+
+		value, err := strconv.Unquote(t.raw[len(t.key)+1:])
+		if err != nil || !omitemptyTagKeys[t.key] || value == "-" || hasOption(value, "omitempty") {
+			parts = append(parts, t.raw)
+
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%s:%q", t.key, value+",omitempty"))
+	}
+
+	if !hasJSON {
+		parts = append(parts, `json:",omitempty"`)
+	}
+
+	return reflect.StructTag(strings.Join(parts, " "))
+}
+
+// hasOption reports whether a tag value such as "name,omitempty" has option.
+func hasOption(value, option string) bool {
+	_, options, _ := strings.Cut(value, ",")
+
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestAddOptionalField(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithMsgpackTags())
+	_ = builder.AddOptionalField("Nickname", "", `json:"nickname"`)
+	_ = builder.AddOptionalField("Age", 0, `json:"age,string"`, `yaml:"age"`)
+	_ = builder.AddOptionalField("Score", (*float64)(nil), `json:"score,omitempty"`)
+	_ = builder.AddOptionalField("Tags", []string{})
+	_ = builder.AddOptionalField("Secret", "", `json:"-"`, `db:"secret"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		wantType reflect.Type
+		wantTag  reflect.StructTag
+	}{
+		{
+			field:    "Nickname",
+			wantType: reflect.TypeOf((*string)(nil)),
+			wantTag:  `json:"nickname,omitempty" msgpack:"nickname,omitempty"`,
+		},
+		{
+			field:    "Age",
+			wantType: reflect.TypeOf((*int)(nil)),
+			wantTag:  `json:"age,string,omitempty" yaml:"age,omitempty" msgpack:"age,string,omitempty"`,
+		},
+		{
+			field:    "Score",
+			wantType: reflect.TypeOf((*float64)(nil)),
+			wantTag:  `json:"score,omitempty" msgpack:"score,omitempty"`,
+		},
+		{
+			field:    "Tags",
+			wantType: reflect.TypeOf((*[]string)(nil)),
+			wantTag:  `json:",omitempty"`,
+		},
+		{
+			field:    "Secret",
+			wantType: reflect.TypeOf((*string)(nil)),
+			wantTag:  `json:"-" db:"secret" msgpack:"-"`,
+		},
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+
+			if field.Type != tt.wantType {
+				t.Errorf("field %s type = %s, want %s", tt.field, field.Type, tt.wantType)
+			}
+
+			if field.Tag != tt.wantTag {
+				t.Errorf("field %s tag = %q, want %q", tt.field, field.Tag, tt.wantTag)
+			}
+		})
+	}
+
+	data, _ := json.Marshal(instance)
+	if string(data) != `{}` {
+		t.Errorf("json.Marshal() = %s, want {}", data)
+	}
+
+	nickname := "Al"
+	_ = builder.SetFieldValue("Nickname", &nickname)
+
+	instanceValue, _ := builder.InstanceValue()
+	data, _ = json.Marshal(instanceValue.Interface())
+
+	if string(data) != `{"nickname":"Al"}` {
+		t.Errorf("json.Marshal() = %s, want %s", data, `{"nickname":"Al"}`)
+	}
+}
+
+func TestAddOptionalFieldErrors(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+
+	tests := []struct {
+		name    string
+		field   string
+		kind    any
+		wantErr error
+	}{
+		{name: "nil_kind", field: "Value", kind: nil, wantErr: dynamicstruct.ErrValueCannotBeNil},
+		{name: "duplicate", field: "Name", kind: "", wantErr: dynamicstruct.ErrFieldAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := builder.AddOptionalField(tt.field, tt.kind); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddOptionalField() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"sort"
+)
+
+type Option func(*Builder)
+
+type autoTag struct {
+	key    string
+	mapper NameMapper
+	// mirror names a tag key whose value is copied instead of mapping the
+	// field name
+	mirror string
+	// merge, if set, returns the value replacing one the field declares
+	merge func(existing, name string) string
+}
+
+// WithAutoTags adds a tag for every key to fields that don't declare one,
+// named by mapping the field name with mapper.
+func WithAutoTags(mapper NameMapper, keys ...string) Option {
+	return func(b *Builder) {
+		for _, key := range keys {
+			b.autoTags = append(b.autoTags, autoTag{key: key, mapper: mapper})
+		}
+	}
+}
+
+// WithMsgpackTags adds a msgpack tag to fields that declare a json tag but no
+// msgpack tag, with the same value, so that instances encode to the same keys
+// in MessagePack as in JSON.
+func WithMsgpackTags() Option {
+	return func(b *Builder) {
+		b.autoTags = append(b.autoTags, autoTag{key: msgpackTagKey, mirror: "json"})
+	}
+}
+
+// WithCaseInsensitiveFieldLookup makes GetField, GetFieldValue and
+// SetFieldValue fall back to a case-insensitive match when no field has the
+// exact name, so "email" finds Email. Names that differ only in case match
+// exactly only.
+func WithCaseInsensitiveFieldLookup() Option {
+	return func(b *Builder) {
+		b.caseInsensitive = true
+	}
+}
+
+// WithAllowRebuild lets the definition be changed after Build. Adding,
+// removing, renaming or replacing a field then drops the built instance
+// instead of returning ErrInstanceAlreadyBuilt, and the next Build builds the
+// new definition.
+func WithAllowRebuild() Option {
+	return func(b *Builder) {
+		b.allowRebuild = true
+	}
+}
+
+// WithKeepValuesOnRebuild allows rebuilding like WithAllowRebuild, and carries
+// the values of the dropped instance over to the next Build, as Rebuild does:
+// fields whose name and type are unchanged keep their values, and new or
+// changed fields start with their default or zero value. Reset drops the
+// values instead.
+func WithKeepValuesOnRebuild() Option {
+	return func(b *Builder) {
+		b.allowRebuild = true
+		b.keepValues = true
+	}
+}
+
+// WithCoercion makes SetFieldValue convert numbers to the numeric type of
+// the field, so the float64 values encoding/json decodes into an any set int
+// fields. Conversions that would overflow the field or drop a fraction fail
+// with ErrIncompatibleTypes.
+func WithCoercion() Option {
+	return func(b *Builder) {
+		b.coerce = true
+	}
+}
+
+// frozen reports whether the definition can't be changed: the builder is
+// built and rebuilding isn't allowed. b.m must be held.
+func (b *Builder) frozen() bool {
+	return b.instance != nil && !b.allowRebuild
+}
+
+// invalidate drops the built instance after a change to the definition,
+// keeping it for WithKeepValuesOnRebuild and RebuildWithValues. b.m must be
+// held.
+func (b *Builder) invalidate() {
+	if b.instance != nil {
+		b.dropped = b.instance
+	}
+
+	b.instance = nil
+	b.index = nil
+	b.foldIndex = nil
+}
+
+// restoreInstance makes instance, when not nil, the builder's instance again
+// after a failed change invalidated it. b.m must be held.
+func (b *Builder) restoreInstance(instance *reflect.Value) {
+	if instance != nil {
+		b.setInstance(*instance)
+		b.dropped = nil
+	}
+}
+
+type buildConfig struct {
+	optimizeLayout bool
+}
+
+// BuildOption configures Build.
+type BuildOption func(*buildConfig)
+
+// WithOptimizedLayout orders the fields of the built struct by decreasing
+// alignment, which removes most of the padding between them. Embedded fields
+// stay first, and fields of equal alignment keep their order. The builder
+// remembers the option, so Rebuild keeps the layout optimized.
+//
+// Field order shows through reflection and encodings that follow it, such as
+// encoding/json, so only use it when the order doesn't matter.
+func WithOptimizedLayout() BuildOption {
+	return func(c *buildConfig) {
+		c.optimizeLayout = true
+	}
+}
+
+// optimizeLayout sorts the regular fields of fields by decreasing alignment.
+func optimizeLayout(fields []reflect.StructField) {
+	first := 0
+	for first < len(fields) && fields[first].Anonymous {
+		first++
+	}
+
+	regular := fields[first:]
+	sort.SliceStable(regular, func(i, j int) bool {
+		return regular[i].Type.Align() > regular[j].Type.Align()
+	})
+}
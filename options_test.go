@@ -0,0 +1,261 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestWithAutoTags(t *testing.T) {
+	builder := dynamicstruct.New(
+		dynamicstruct.WithAutoTags(dynamicstruct.SnakeCase, "json", "xml"),
+	)
+
+	_ = builder.AddField("UserID", int(0))
+	_ = builder.AddField("DisplayName", "", `json:"name"`)
+	_ = builder.AddField("Secret", "", `json:"-"`, `xml:"-"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		field   string
+		wantTag reflect.StructTag
+	}{
+		{field: "UserID", wantTag: `json:"user_id" xml:"user_id"`},
+		{field: "DisplayName", wantTag: `json:"name" xml:"display_name"`},
+		{field: "Secret", wantTag: `json:"-" xml:"-"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := reflect.TypeOf(instance).FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if field.Tag != tt.wantTag {
+				t.Errorf("field %s tag = %q, want %q", tt.field, field.Tag, tt.wantTag)
+			}
+		})
+	}
+}
+
+func TestNameMappers(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantSnake string
+		wantCamel string
+	}{
+		{name: "Name", wantSnake: "name", wantCamel: "name"},
+		{name: "UserID", wantSnake: "user_id", wantCamel: "userID"},
+		{name: "HTTPPort", wantSnake: "http_port", wantCamel: "httpPort"},
+		{name: "ID", wantSnake: "id", wantCamel: "id"},
+		{name: "Address2Line", wantSnake: "address2_line", wantCamel: "address2Line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dynamicstruct.SnakeCase(tt.name); got != tt.wantSnake {
+				t.Errorf("SnakeCase(%q) = %q, want %q", tt.name, got, tt.wantSnake)
+			}
+			if got := dynamicstruct.CamelCase(tt.name); got != tt.wantCamel {
+				t.Errorf("CamelCase(%q) = %q, want %q", tt.name, got, tt.wantCamel)
+			}
+		})
+	}
+}
+
+func TestWithCaseInsensitiveFieldLookup(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithCaseInsensitiveFieldLookup())
+	_ = builder.AddField("Email", "")
+	_ = builder.AddField("ID", 0)
+	_ = builder.AddField("Id", 0)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if err := builder.SetFieldValue("email", "a@example.com"); err != nil {
+		t.Fatalf("SetFieldValue() error = %v", err)
+	}
+
+	var email string
+	if err := builder.GetFieldValue("EMAIL", &email); err != nil || email != "a@example.com" {
+		t.Errorf("GetFieldValue() = %q, %v, want a@example.com, nil", email, err)
+	}
+
+	_ = builder.SetFieldValue("Id", 7)
+
+	if got, _ := builder.GetField("Id"); got != 7 {
+		t.Errorf("GetField() Id = %v, want 7", got)
+	}
+
+	if _, err := builder.GetField("id"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+		t.Errorf("GetField() ambiguous error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+	}
+
+	t.Run("off_by_default", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Email", "")
+		_, _ = builder.Build()
+
+		if _, err := builder.GetField("email"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("GetField() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+	})
+}
+
+func TestWithAllowRebuild(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithAllowRebuild())
+	_ = builder.AddField("Name", "")
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if err := builder.AddField("Name", 0); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+		t.Fatalf("AddField() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+	}
+
+	if _, err := builder.GetField("Name"); err != nil {
+		t.Errorf("GetField() after failed AddField error = %v, want the instance kept", err)
+	}
+
+	if err := builder.AddField("Age", 0); err != nil {
+		t.Fatalf("AddField() after Build error = %v", err)
+	}
+
+	if _, err := builder.GetField("Name"); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("GetField() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	_ = builder.RemoveField("Name")
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, []string{"Age"}) {
+		t.Errorf("fields = %v, want [Age]", got)
+	}
+}
+
+func TestWithKeepValuesOnRebuild(t *testing.T) {
+	newBuilder := func() *dynamicstruct.Builder {
+		builder := dynamicstruct.New(dynamicstruct.WithKeepValuesOnRebuild())
+		_ = builder.AddField("Name", "")
+		_ = builder.AddField("Port", 0)
+		_ = builder.AddFieldWithOptions("Mode", "", dynamicstruct.Default("auto"))
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		_ = builder.SetFieldValue("Name", "api")
+		_ = builder.SetFieldValue("Port", 8080)
+		_ = builder.SetFieldValue("Mode", "manual")
+
+		return builder
+	}
+
+	t.Run("unchanged_fields_keep_values", func(t *testing.T) {
+		builder := newBuilder()
+		_ = builder.AddFieldWithOptions("Region", "", dynamicstruct.Default("eu"))
+		_ = builder.ReplaceField("Port", "")
+		_ = builder.RemoveField("Mode")
+		_ = builder.AddFieldWithOptions("Mode", "", dynamicstruct.Default("auto"))
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		want := map[string]any{"Name": "api", "Port": "", "Region": "eu", "Mode": "manual"}
+		for name, wantValue := range want {
+			if got, _ := builder.GetField(name); got != wantValue {
+				t.Errorf("%s = %v, want %v", name, got, wantValue)
+			}
+		}
+	})
+
+	t.Run("failed_change_keeps_instance", func(t *testing.T) {
+		builder := newBuilder()
+
+		if err := builder.AddField("Name", 0); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Fatalf("AddField() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+
+		if got, _ := builder.GetField("Name"); got != "api" {
+			t.Errorf("Name = %v, want api", got)
+		}
+	})
+
+	t.Run("reset_drops_values", func(t *testing.T) {
+		builder := newBuilder()
+		_ = builder.AddField("Region", "")
+		builder.Reset()
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if got, _ := builder.GetField("Name"); got != "" {
+			t.Errorf("Name = %v, want it dropped", got)
+		}
+	})
+
+	t.Run("without_option", func(t *testing.T) {
+		builder := dynamicstruct.New(dynamicstruct.WithAllowRebuild())
+		_ = builder.AddField("Name", "")
+		_, _ = builder.Build()
+		_ = builder.SetFieldValue("Name", "api")
+		_ = builder.AddField("Region", "")
+		_, _ = builder.Build()
+
+		if got, _ := builder.GetField("Name"); got != "" {
+			t.Errorf("Name = %v, want the zero value", got)
+		}
+	})
+}
+
+func TestWithOptimizedLayout(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddField("A", byte(0))
+	_ = builder.AddField("B", int64(0))
+	_ = builder.AddField("C", byte(0))
+	_ = builder.AddField("D", int32(0))
+
+	instance, err := builder.Build(dynamicstruct.WithOptimizedLayout())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	if got, want := fieldNames(structType), []string{"ContactTest", "B", "D", "A", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+
+	plain, _ := dynamicstruct.New().Field("A", byte(0)).Field("B", int64(0)).Field("C", byte(0)).Field("D", int32(0)).Build()
+	if reflect.TypeOf(plain).Size() <= structType.Size()-reflect.TypeOf(ContactTest{}).Size() {
+		t.Errorf("optimized size %d is not smaller than %d", structType.Size(), reflect.TypeOf(plain).Size())
+	}
+
+	t.Run("kept_by_rebuild", func(t *testing.T) {
+		instance, err := builder.Rebuild(func(b *dynamicstruct.Builder) error {
+			return b.AddField("E", int16(0))
+		})
+		if err != nil {
+			t.Fatalf("Rebuild() error = %v", err)
+		}
+
+		if got, want := fieldNames(reflect.TypeOf(instance)), []string{"ContactTest", "B", "D", "E", "A", "C"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("fields = %v, want %v", got, want)
+		}
+	})
+}
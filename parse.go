@@ -0,0 +1,171 @@
+package dynamicstruct
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// SetFieldFromString parses raw according to the type of the named field and
+// sets it, for values read as text from command lines, CSV files, the
+// environment or forms. It parses numbers, booleans, durations, times in one
+// of TimeLayouts, types implementing encoding.TextUnmarshaler, pointers to any
+// of these, and slices as comma separated elements. Structs, maps and arrays
+// are expected to be JSON encoded. The field is left unchanged when raw
+// doesn't parse.
+func (b *Builder) SetFieldFromString(name, raw string) error {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	b.values.Lock()
+	defer b.values.Unlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return fieldError("set", name, ErrInstanceNotBuilt)
+	}
+
+	field, ok := b.instanceField(name)
+	if !ok {
+		field, ok = fieldByNameAlloc(*b.instance, name)
+		if !ok {
+			return fieldError("set", name, missingField(b.instance.Type(), name))
+		}
+	}
+
+	if !field.CanSet() {
+		return fieldError("set", name, ErrFieldNotFound)
+	}
+
+	parsed := reflect.New(field.Type()).Elem()
+	if err := setFromString(parsed, raw); err != nil {
+		return fieldError("set", name, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error()))
+	}
+
+	field.Set(parsed)
+
+	return nil
+}
+
+// setFromString parses raw according to the type of value and stores the
+// result. value must be settable.
+func setFromString(value reflect.Value, raw string) error {
+	// time.Time is a TextUnmarshaler too, but one that only reads RFC 3339
+	switch value.Type() {
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(int64(d))
+
+		return nil
+	case timeType:
+		t, err := parseTime(raw)
+		if err != nil {
+			return err
+		}
+
+		value.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		unmarshaler, _ := value.Addr().Interface().(encoding.TextUnmarshaler)
+
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(f)
+	case reflect.Ptr:
+		elem := reflect.New(value.Type().Elem())
+		if err := setFromString(elem.Elem(), raw); err != nil {
+			return err
+		}
+
+		value.Set(elem)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			value.SetBytes([]byte(raw))
+
+			return nil
+		}
+
+		return setFromStrings(value, strings.Split(raw, ","))
+	case reflect.Interface:
+		if value.NumMethod() > 0 {
+			return fmt.Errorf("%w: can't parse into %s", ErrIncompatibleTypes, value.Type())
+		}
+
+		value.Set(reflect.ValueOf(raw))
+	default:
+		// Structs, maps and arrays are expected to be JSON encoded
+		return json.Unmarshal([]byte(raw), value.Addr().Interface())
+	}
+
+	return nil
+}
+
+// setFromStrings parses every raw value into an element of a slice, or the
+// last raw value into any other type.
+func setFromStrings(value reflect.Value, raws []string) error {
+	if len(raws) == 0 {
+		return nil
+	}
+
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() == reflect.Uint8 {
+		return setFromString(value, raws[len(raws)-1])
+	}
+
+	slice := reflect.MakeSlice(value.Type(), len(raws), len(raws))
+
+	for i, raw := range raws {
+		if err := setFromString(slice.Index(i), strings.TrimSpace(raw)); err != nil {
+			return err
+		}
+	}
+
+	value.Set(slice)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestSetFieldFromString(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Active", false)
+	_ = builder.AddField("Port", 0)
+	_ = builder.AddField("Size", uint16(0))
+	_ = builder.AddField("Ratio", 0.0)
+	_ = builder.AddField("Timeout", time.Duration(0))
+	_ = builder.AddField("At", time.Time{})
+	_ = builder.AddField("Tags", []string{})
+	_ = builder.AddField("Ports", []int{})
+	_ = builder.AddField("Address", AddressTest{})
+	_ = builder.AddOptionalField("Limit", 0)
+	_, _ = builder.Build()
+
+	limit := 5
+
+	tests := []struct {
+		name    string
+		field   string
+		raw     string
+		want    any
+		wantErr error
+	}{
+		{name: "string", field: "Name", raw: "Alice", want: "Alice"},
+		{name: "bool", field: "Active", raw: "true", want: true},
+		{name: "int", field: "Port", raw: "8080", want: 8080},
+		{name: "uint", field: "Size", raw: "512", want: uint16(512)},
+		{name: "float", field: "Ratio", raw: "0.25", want: 0.25},
+		{name: "duration", field: "Timeout", raw: "5m", want: 5 * time.Minute},
+		{name: "time", field: "At", raw: "2024-03-01", want: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "string_slice", field: "Tags", raw: "a, b,c", want: []string{"a", "b", "c"}},
+		{name: "int_slice", field: "Ports", raw: "80,443", want: []int{80, 443}},
+		{name: "json_struct", field: "Address", raw: `{"Street":"Main St","City":"Springfield"}`, want: AddressTest{Street: "Main St", City: "Springfield"}},
+		{name: "pointer", field: "Limit", raw: "5", want: &limit},
+		{name: "invalid_int", field: "Port", raw: "eighty", wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "overflow", field: "Size", raw: "70000", wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "invalid_slice_element", field: "Ports", raw: "80,http", wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "missing", field: "Email", raw: "a@example.com", wantErr: dynamicstruct.ErrFieldNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := builder.SetFieldFromString(tt.field, tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetFieldFromString() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			got, _ := builder.GetField(tt.field)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unchanged_on_error", func(t *testing.T) {
+		_ = builder.SetFieldFromString("Ports", "80,443")
+		_ = builder.SetFieldFromString("Ports", "8080,http")
+
+		got, _ := builder.GetField("Ports")
+		if want := []int{80, 443}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetField() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("not_built", func(t *testing.T) {
+		err := dynamicstruct.New().SetFieldFromString("Name", "Alice")
+		if !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("SetFieldFromString() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Pool recycles instances of a built type, so code that decodes many
+// documents into the same type doesn't allocate an instance for each one.
+// It is safe for concurrent use.
+type Pool struct {
+	structType reflect.Type
+	defaults   defaultValues
+	pool       sync.Pool
+}
+
+// defaultValues holds the default values of a built type, to apply them to
+// many instances without going through the builder.
+type defaultValues struct {
+	// template is an instance holding the default values of the fields
+	template reflect.Value
+	// fields are the indices of the fields with a default
+	fields []int
+}
+
+// defaultValues returns the default values of the built type. b.m must be
+// held.
+func (b *Builder) defaultValues() defaultValues {
+	structType := b.instance.Type()
+
+	template := reflect.New(structType).Elem()
+	b.applyDefaults(template)
+	b.applyNestedDefaults(template)
+
+	defaults := defaultValues{template: template}
+
+	for i := 0; i < structType.NumField(); i++ {
+		if !template.Field(i).IsZero() {
+			defaults.fields = append(defaults.fields, i)
+		}
+	}
+
+	return defaults
+}
+
+// apply sets the fields of value that have a default to a copy of it.
+func (d defaultValues) apply(value reflect.Value) {
+	for _, i := range d.fields {
+		value.Field(i).Set(copyDefault(d.template.Field(i)))
+	}
+}
+
+// Pool returns a pool of pointers to instances of the built type. Instances
+// from the pool start out like those of NewInstance: zero, with the default
+// values applied.
+func (b *Builder) Pool() (*Pool, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+	p := &Pool{structType: structType, defaults: b.defaultValues()}
+
+	p.pool.New = func() any {
+		instance := reflect.New(structType)
+		p.reset(instance.Elem())
+
+		return instance.Interface()
+	}
+
+	return p, nil
+}
+
+// Get returns a pointer to an instance from the pool, allocating one when the
+// pool is empty.
+func (p *Pool) Get() any {
+	return p.pool.Get()
+}
+
+// Put resets instancePtr and returns it to the pool. The caller must not use
+// it afterwards.
+func (p *Pool) Put(instancePtr any) error {
+	if err := p.Reset(instancePtr); err != nil {
+		return err
+	}
+
+	p.pool.Put(instancePtr)
+
+	return nil
+}
+
+// Reset zeroes the fields of the instance instancePtr points to and applies
+// the default values again.
+func (p *Pool) Reset(instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	if value.Type() != p.structType {
+		return fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			p.structType.String(),
+		)
+	}
+
+	p.reset(value)
+
+	return nil
+}
+
+func (p *Pool) reset(value reflect.Value) {
+	value.Set(reflect.Zero(p.structType))
+	p.defaults.apply(value)
+}
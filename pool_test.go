@@ -0,0 +1,116 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestPool(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddFieldWithOptions("Roles", []string{}, dynamicstruct.Tags(`json:"roles"`), dynamicstruct.Default([]string{"user"}))
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	pool, err := builder.Pool()
+	if err != nil {
+		t.Fatalf("Pool() error = %v", err)
+	}
+
+	instancePtr := pool.Get()
+	if reflect.TypeOf(instancePtr) != reflect.PtrTo(reflect.TypeOf(instance)) {
+		t.Fatalf("Get() type = %T, want pointer to %T", instancePtr, instance)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name":"Alice","roles":["admin"]}`), instancePtr); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	roles := reflect.ValueOf(instancePtr).Elem().FieldByName("Roles").Interface().([]string)
+
+	if err := pool.Put(instancePtr); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	value := reflect.ValueOf(instancePtr).Elem()
+	if got := value.FieldByName("Name").String(); got != "" {
+		t.Errorf("Put() Name = %q, want empty", got)
+	}
+
+	if got := value.FieldByName("Roles").Interface(); !reflect.DeepEqual(got, []string{"user"}) {
+		t.Errorf("Put() Roles = %v, want [user]", got)
+	}
+
+	// Resetting must not share the default slice with instances
+	value.FieldByName("Roles").Index(0).SetString("changed")
+
+	other := reflect.New(reflect.TypeOf(instance))
+	if err := pool.Reset(other.Interface()); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	if got := other.Elem().FieldByName("Roles").Interface(); !reflect.DeepEqual(got, []string{"user"}) {
+		t.Errorf("Reset() Roles = %v, want [user]", got)
+	}
+
+	if !reflect.DeepEqual(roles, []string{"admin"}) {
+		t.Errorf("decoded Roles = %v, want [admin]", roles)
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < 100; j++ {
+					instancePtr := pool.Get()
+					_ = json.Unmarshal([]byte(`{"name":"Bob"}`), instancePtr)
+					_ = pool.Put(instancePtr)
+				}
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestPoolErrors(t *testing.T) {
+	if _, err := dynamicstruct.New().Pool(); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("Pool() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+	_, _ = builder.Build()
+
+	pool, _ := builder.Pool()
+
+	tests := []struct {
+		name        string
+		instancePtr any
+		wantErr     error
+	}{
+		{name: "wrong_type", instancePtr: &PersonTest{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "not_pointer", instancePtr: PersonTest{}, wantErr: dynamicstruct.ErrValueMustBePointer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := pool.Put(tt.instancePtr); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Put() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
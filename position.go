@@ -0,0 +1,142 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AddFieldAt adds a field like AddField, at index in the field order. Fields
+// added with AddFieldAt take their index whatever order they are added in, and
+// the other fields fill the remaining indexes in the order they were added,
+// so goroutines generating fields in parallel assemble the same struct every
+// time:
+//
+//	for i, spec := range specs {
+//		go func(i int, spec dynamicstruct.FieldSpec) {
+//			_ = builder.AddFieldAt(i, spec.Name, spec.Kind, spec.Tags...)
+//		}(i, spec)
+//	}
+//
+// Fields whose index is past the end of the order come last, by index. A
+// negative index, or one another field was added at, returns
+// ErrInvalidPosition.
+func (b *Builder) AddFieldAt(index int, name string, kind any, tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if index < 0 {
+		return fieldError("add", name, fmt.Errorf("%w: %d", ErrInvalidPosition, index))
+	}
+
+	for other, position := range b.positions {
+		if position == index {
+			return fieldError("add", name, fmt.Errorf("%w: %d is taken by %s", ErrInvalidPosition, index, other))
+		}
+	}
+
+	if err := b.addKind(name, kind, tags); err != nil {
+		return err
+	}
+
+	if b.positions == nil {
+		b.positions = make(map[string]int)
+	}
+
+	b.positions[name] = index
+	b.arrangeFields()
+
+	return nil
+}
+
+// InsertFieldBefore adds a field like AddField, right before the anchor field
+// instead of at the end, to keep a meaningful order when a definition is
+// assembled from several sources. An anchor that isn't a field returns
+// ErrFieldNotFound, and one added with AddFieldAt ErrInvalidPosition. Fields
+// added with AddFieldAt keep their index, so they may come between the field
+// and its anchor.
+func (b *Builder) InsertFieldBefore(anchor, name string, kind any, tags ...string) error {
+	return b.insertField(anchor, false, name, kind, tags)
+}
+
+// InsertFieldAfter adds a field like AddField, right after the anchor field
+// instead of at the end. Anchors are checked and fields added with
+// AddFieldAt kept at their index as for InsertFieldBefore.
+func (b *Builder) InsertFieldAfter(anchor, name string, kind any, tags ...string) error {
+	return b.insertField(anchor, true, name, kind, tags)
+}
+
+// insertField adds a field next to the anchor field: after it, or before.
+func (b *Builder) insertField(anchor string, after bool, name string, kind any, tags []string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if _, ok := b.fields[anchor]; !ok {
+		return fieldError("add", name, fmt.Errorf("%w: %s", ErrFieldNotFound, anchor))
+	}
+
+	// A field added at an index has no neighbours to keep
+	if _, ok := b.positions[anchor]; ok {
+		return fieldError("add", name, fmt.Errorf("%w: %s was added at an index", ErrInvalidPosition, anchor))
+	}
+
+	if err := b.addKind(name, kind, tags); err != nil {
+		return err
+	}
+
+	order := make([]string, 0, len(b.order))
+
+	for _, fieldName := range b.order {
+		switch fieldName {
+		case name:
+			continue
+		case anchor:
+			if after {
+				order = append(order, anchor, name)
+			} else {
+				order = append(order, name, anchor)
+			}
+		default:
+			order = append(order, fieldName)
+		}
+	}
+
+	b.order = order
+
+	if len(b.positions) > 0 {
+		b.arrangeFields()
+	}
+
+	return nil
+}
+
+// arrangeFields puts the fields added with AddFieldAt at their index, and the
+// other fields at the remaining ones in the order they were added.
+func (b *Builder) arrangeFields() {
+	var positioned, others []string
+
+	for _, name := range b.order {
+		if _, ok := b.positions[name]; ok {
+			positioned = append(positioned, name)
+		} else {
+			others = append(others, name)
+		}
+	}
+
+	sort.Slice(positioned, func(i, j int) bool {
+		return b.positions[positioned[i]] < b.positions[positioned[j]]
+	})
+
+	order := make([]string, 0, len(b.order))
+
+	for len(positioned) > 0 || len(others) > 0 {
+		if len(positioned) > 0 && (len(others) == 0 || b.positions[positioned[0]] <= len(order)) {
+			order = append(order, positioned[0])
+			positioned = positioned[1:]
+		} else {
+			order = append(order, others[0])
+			others = others[1:]
+		}
+	}
+
+	b.order = order
+}
@@ -0,0 +1,353 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestAddFieldAt(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(b *dynamicstruct.Builder)
+		want []string
+	}{
+		{
+			name: "out_of_order",
+			add: func(b *dynamicstruct.Builder) {
+				_ = b.AddFieldAt(2, "C", "")
+				_ = b.AddFieldAt(0, "A", "")
+				_ = b.AddFieldAt(1, "B", "")
+			},
+			want: []string{"A", "B", "C"},
+		},
+		{
+			name: "between_fields",
+			add: func(b *dynamicstruct.Builder) {
+				_ = b.AddField("A", "")
+				_ = b.AddField("C", "")
+				_ = b.AddFieldAt(1, "B", "")
+			},
+			want: []string{"A", "B", "C"},
+		},
+		{
+			name: "gaps_filled_by_later_fields",
+			add: func(b *dynamicstruct.Builder) {
+				_ = b.AddFieldAt(2, "C", "")
+				_ = b.AddField("A", "")
+				_ = b.AddField("B", "")
+				_ = b.AddField("D", "")
+			},
+			want: []string{"A", "B", "C", "D"},
+		},
+		{
+			name: "past_the_end",
+			add: func(b *dynamicstruct.Builder) {
+				_ = b.AddFieldAt(9, "Z", "")
+				_ = b.AddFieldAt(5, "Y", "")
+				_ = b.AddField("A", "")
+			},
+			want: []string{"A", "Y", "Z"},
+		},
+		{
+			name: "nested_builder",
+			add: func(b *dynamicstruct.Builder) {
+				child := dynamicstruct.New()
+				_ = child.AddField("City", "")
+
+				_ = b.AddField("B", "")
+				_ = b.AddFieldAt(0, "A", child)
+			},
+			want: []string{"A", "B"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+			tt.add(builder)
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddFieldAtConcurrent(t *testing.T) {
+	const count = 32
+
+	want := make([]string, count)
+	for i := range want {
+		want[i] = fmt.Sprintf("Field%d", i)
+	}
+
+	for run := 0; run < 5; run++ {
+		builder := dynamicstruct.New()
+
+		var wg sync.WaitGroup
+
+		for i := count - 1; i >= 0; i-- {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				if err := builder.AddFieldAt(i, want[i], 0); err != nil {
+					t.Errorf("AddFieldAt() error = %v", err)
+				}
+			}(i)
+		}
+
+		wg.Wait()
+
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Build() fields = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestAddFieldAtErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   int
+		field   string
+		wantErr error
+	}{
+		{"negative", -1, "B", dynamicstruct.ErrInvalidPosition},
+		{"taken", 0, "B", dynamicstruct.ErrInvalidPosition},
+		{"existing_field", 1, "A", dynamicstruct.ErrFieldAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+			_ = builder.AddFieldAt(0, "A", "")
+
+			if err := builder.AddFieldAt(tt.index, tt.field, ""); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddFieldAt() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddFieldAtEdits(t *testing.T) {
+	newBuilder := func() *dynamicstruct.Builder {
+		builder := dynamicstruct.New()
+		_ = builder.AddFieldAt(1, "B", "")
+		_ = builder.AddField("A", "")
+		_ = builder.AddField("C", "")
+
+		return builder
+	}
+
+	tests := []struct {
+		name string
+		edit func(b *dynamicstruct.Builder) error
+		want []string
+	}{
+		{
+			name: "rename_keeps_index",
+			edit: func(b *dynamicstruct.Builder) error {
+				if err := b.RenameField("B", "X"); err != nil {
+					return err
+				}
+
+				return b.AddFieldAt(0, "First", "")
+			},
+			want: []string{"First", "X", "A", "C"},
+		},
+		{
+			name: "replace_keeps_index",
+			edit: func(b *dynamicstruct.Builder) error {
+				if err := b.ReplaceField("B", 0); err != nil {
+					return err
+				}
+
+				return b.AddFieldAt(0, "First", "")
+			},
+			want: []string{"First", "B", "A", "C"},
+		},
+		{
+			name: "remove_frees_index",
+			edit: func(b *dynamicstruct.Builder) error {
+				if err := b.RemoveField("B"); err != nil {
+					return err
+				}
+
+				return b.AddFieldAt(1, "X", "")
+			},
+			want: []string{"A", "X", "C"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newBuilder()
+
+			if err := tt.edit(builder); err != nil {
+				t.Fatalf("edit error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("clone_keeps_index", func(t *testing.T) {
+		clone := newBuilder().Clone()
+		_ = clone.AddFieldAt(0, "First", "")
+
+		instance, err := clone.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		want := []string{"First", "B", "A", "C"}
+		if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, want) {
+			t.Errorf("Build() fields = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestInsertField(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(b *dynamicstruct.Builder) error
+		want []string
+	}{
+		{
+			name: "before_first",
+			add: func(b *dynamicstruct.Builder) error {
+				return b.InsertFieldBefore("Name", "ID", 0)
+			},
+			want: []string{"ID", "Name", "CreatedAt"},
+		},
+		{
+			name: "after_last",
+			add: func(b *dynamicstruct.Builder) error {
+				return b.InsertFieldAfter("CreatedAt", "UpdatedAt", "")
+			},
+			want: []string{"Name", "CreatedAt", "UpdatedAt"},
+		},
+		{
+			name: "before_middle",
+			add: func(b *dynamicstruct.Builder) error {
+				return b.InsertFieldBefore("CreatedAt", "Email", "")
+			},
+			want: []string{"Name", "Email", "CreatedAt"},
+		},
+		{
+			name: "after_middle",
+			add: func(b *dynamicstruct.Builder) error {
+				return b.InsertFieldAfter("Name", "Email", "")
+			},
+			want: []string{"Name", "Email", "CreatedAt"},
+		},
+		{
+			name: "fields_added_later_go_last",
+			add: func(b *dynamicstruct.Builder) error {
+				if err := b.InsertFieldBefore("Name", "ID", 0); err != nil {
+					return err
+				}
+
+				return b.AddField("Notes", "")
+			},
+			want: []string{"ID", "Name", "CreatedAt", "Notes"},
+		},
+		{
+			name: "positioned_fields_keep_index",
+			add: func(b *dynamicstruct.Builder) error {
+				if err := b.AddFieldAt(1, "ID", 0); err != nil {
+					return err
+				}
+
+				return b.InsertFieldBefore("Name", "Email", "")
+			},
+			want: []string{"Email", "ID", "Name", "CreatedAt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+			_ = builder.AddField("Name", "")
+			_ = builder.AddField("CreatedAt", "")
+
+			if err := tt.add(builder); err != nil {
+				t.Fatalf("insert error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		anchor  string
+		field   string
+		wantErr error
+	}{
+		{"missing_anchor", "Missing", "Email", dynamicstruct.ErrFieldNotFound},
+		{"existing_field", "Name", "Name", dynamicstruct.ErrFieldAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+			_ = builder.AddField("Name", "")
+
+			if err := builder.InsertFieldAfter(tt.anchor, tt.field, ""); !errors.Is(err, tt.wantErr) {
+				t.Errorf("InsertFieldAfter() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("positioned_anchor", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddFieldAt(0, "ID", 0)
+
+		if err := builder.InsertFieldBefore("ID", "Name", ""); !errors.Is(err, dynamicstruct.ErrInvalidPosition) {
+			t.Errorf("InsertFieldBefore() error = %v, want %v", err, dynamicstruct.ErrInvalidPosition)
+		}
+	})
+
+	t.Run("built", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Name", "")
+		_, _ = builder.Build()
+
+		if err := builder.InsertFieldBefore("Name", "ID", 0); !errors.Is(err, dynamicstruct.ErrInstanceAlreadyBuilt) {
+			t.Errorf("InsertFieldBefore() error = %v, want %v", err, dynamicstruct.ErrInstanceAlreadyBuilt)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const profileTagKey = "profile"
+
+// Profiles returns a profile tag assigning a field to the named profiles, for
+// use as one of the tags of AddField:
+//
+//	builder.AddField("Salary", 0, `json:"salary"`, dynamicstruct.Profiles("admin", "internal"))
+//
+// BuildProfile builds a struct with only the fields of one profile. Fields
+// without a profile tag belong to every profile.
+func Profiles(names ...string) string {
+	return profileTagKey + ":" + strconv.Quote(strings.Join(names, ","))
+}
+
+// BuildProfile returns a new instance of a struct with the fields of the named
+// profile, in their usual order and with their default values. It doesn't
+// build the builder, so it can be called before or after Build, and the
+// builder can be edited afterwards as usual.
+func (b *Builder) BuildProfile(profile string) (any, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	fields, err := b.resolveStructFields(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]reflect.StructField, 0, len(fields))
+
+	for _, field := range fields {
+		if inProfile(field.Tag, profile) {
+			kept = append(kept, field)
+		}
+	}
+
+	structType, err := structOf(kept)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := reflect.New(structType).Elem()
+	b.applyDefaults(instance)
+	b.applyNestedDefaults(instance)
+
+	return instance.Interface(), nil
+}
+
+// inProfile reports whether a field with tag belongs to profile.
+func inProfile(tag reflect.StructTag, profile string) bool {
+	profiles, ok := tag.Lookup(profileTagKey)
+	if !ok {
+		return true
+	}
+
+	for _, name := range strings.Split(profiles, ",") {
+		if strings.TrimSpace(name) == profile {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package dynamicstruct_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestBuildProfile(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Id", 0, `json:"id"`)
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddFieldWithOptions("Salary", 0,
+		dynamicstruct.Tags(`json:"salary"`, dynamicstruct.Profiles("admin", "internal")),
+		dynamicstruct.Default(1000),
+	)
+	_ = builder.AddField("AuditLog", []string{}, dynamicstruct.Profiles("internal"))
+
+	tests := []struct {
+		profile string
+		want    []string
+	}{
+		{"admin", []string{"Id", "Name", "Salary"}},
+		{"internal", []string{"Id", "Name", "Salary", "AuditLog"}},
+		{"public", []string{"Id", "Name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			instance, err := builder.BuildProfile(tt.profile)
+			if err != nil {
+				t.Fatalf("BuildProfile() error = %v", err)
+			}
+
+			if got := fieldNames(reflect.TypeOf(instance)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildProfile() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		instance, _ := builder.BuildProfile("admin")
+
+		if got := reflect.ValueOf(instance).FieldByName("Salary").Interface(); got != 1000 {
+			t.Errorf("Salary = %v, want 1000", got)
+		}
+	})
+
+	t.Run("leaves_builder_unbuilt", func(t *testing.T) {
+		instance, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if got := fieldNames(reflect.TypeOf(instance)); len(got) != 4 {
+			t.Errorf("Build() fields = %v, want all 4", got)
+		}
+	})
+}
+
+func TestProfiles(t *testing.T) {
+	if got := dynamicstruct.Profiles("admin", "internal"); got != `profile:"admin,internal"` {
+		t.Errorf("Profiles() = %s, want %s", got, `profile:"admin,internal"`)
+	}
+}
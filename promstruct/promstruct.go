@@ -0,0 +1,227 @@
+// Package promstruct exposes the numeric fields of dynamic struct instances as
+// Prometheus gauges.
+package promstruct
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// TagKey is the struct tag holding the metric name.
+	TagKey = "metric"
+	// HelpTagKey is the struct tag holding the metric help text.
+	HelpTagKey = "help"
+)
+
+var ErrNoMetrics = errors.New("instance has no numeric fields to expose")
+
+type config struct {
+	getter      func() any
+	namespace   string
+	subsystem   string
+	constLabels prometheus.Labels
+}
+
+type Option func(*config)
+
+// WithGetter reads the gauges from the instance getter returns at every
+// scrape instead of from the instance given to ExposeMetrics. The getter
+// must return a struct or pointer to struct of the same type, such as a
+// snapshot taken under the lock that guards the instance; a value of another
+// type is reported as an invalid metric.
+func WithGetter(getter func() any) Option {
+	return func(c *config) {
+		c.getter = getter
+	}
+}
+
+// WithNamespace prefixes the name of every gauge with namespace.
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithSubsystem prefixes the name of every gauge with subsystem, after the
+// namespace.
+func WithSubsystem(subsystem string) Option {
+	return func(c *config) {
+		c.subsystem = subsystem
+	}
+}
+
+// WithConstLabels adds labels to every gauge, to tell apart the instances of
+// plugins exposing the same struct.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// ExposeMetrics registers the numeric fields of instance, a struct or pointer
+// to struct, with reg as gauges. Gauges are named by the field's metric tag,
+// or by its name in snake case without one, and described by its help tag;
+// `metric:"-"` and unexported fields are left out. Integer, float and
+// pointer-to-number fields are numeric, and nil pointers report no sample.
+//
+// Values are read when reg is gathered, so a pointer to an instance that
+// keeps changing exposes its current values; WithGetter reads them from a
+// function instead. The returned collector unregisters the gauges:
+//
+//	collector, err := promstruct.ExposeMetrics(statsPtr, prometheus.DefaultRegisterer)
+//	...
+//	prometheus.Unregister(collector)
+//
+// Instances without numeric fields return ErrNoMetrics, and gauges reg
+// refuses, such as names already registered, return its error.
+func ExposeMetrics(instance any, reg prometheus.Registerer, opts ...Option) (prometheus.Collector, error) {
+	cfg := &config{
+		getter: func() any { return instance },
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	structType := reflect.TypeOf(instance)
+	for structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, dynamicstruct.ErrInvalidInstance
+	}
+
+	collector := &structCollector{structType: structType, getter: cfg.getter}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" || !isNumber(field.Type) {
+			continue
+		}
+
+		name := field.Tag.Get(TagKey)
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = dynamicstruct.SnakeCase(field.Name)
+		}
+
+		help := field.Tag.Get(HelpTagKey)
+		if help == "" {
+			help = fmt.Sprintf("%s field of %s.", field.Name, structName(structType))
+		}
+
+		collector.gauges = append(collector.gauges, gauge{
+			index: i,
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(cfg.namespace, cfg.subsystem, name),
+				help, nil, cfg.constLabels,
+			),
+		})
+	}
+
+	if len(collector.gauges) == 0 {
+		return nil, ErrNoMetrics
+	}
+
+	if err := reg.Register(collector); err != nil {
+		return nil, err
+	}
+
+	return collector, nil
+}
+
+func isNumber(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// structName names the struct in help texts. Dynamic structs have no name.
+func structName(structType reflect.Type) string {
+	if structType.Name() == "" {
+		return "the instance"
+	}
+
+	return strings.ToLower(structType.Name()[:1]) + structType.Name()[1:]
+}
+
+type gauge struct {
+	index int
+	desc  *prometheus.Desc
+}
+
+// structCollector reports one gauge per numeric field of the struct getter
+// returns.
+type structCollector struct {
+	structType reflect.Type
+	getter     func() any
+	gauges     []gauge
+}
+
+func (c *structCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, g := range c.gauges {
+		ch <- g.desc
+	}
+}
+
+func (c *structCollector) Collect(ch chan<- prometheus.Metric) {
+	instance := c.getter()
+
+	value := reflect.ValueOf(instance)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || value.Type() != c.structType {
+		err := fmt.Errorf("%w: got %T, want %s", dynamicstruct.ErrIncompatibleTypes, instance, c.structType)
+
+		for _, g := range c.gauges {
+			ch <- prometheus.NewInvalidMetric(g.desc, err)
+		}
+
+		return
+	}
+
+	for _, g := range c.gauges {
+		field := value.Field(g.index)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+
+			field = field.Elem()
+		}
+
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, floatValue(field))
+	}
+}
+
+func floatValue(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	default:
+		return value.Float()
+	}
+}
@@ -0,0 +1,158 @@
+package promstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/gosmos-space/dynamicstruct/promstruct"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newStats(t *testing.T) any {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("QueueDepth", 0, `help:"Jobs waiting in the queue."`)
+	_ = builder.AddField("HitRatio", float64(0), `metric:"cache_hit_ratio"`)
+	_ = builder.AddField("Workers", (*uint32)(nil))
+	_ = builder.AddField("Internal", 0, `metric:"-"`)
+	_ = builder.AddField("Name", "")
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instance, _ := builder.NewInstance()
+
+	return instance
+}
+
+func TestExposeMetrics(t *testing.T) {
+	stats := newStats(t)
+	value := reflect.ValueOf(stats).Elem()
+
+	reg := prometheus.NewPedanticRegistry()
+
+	_, err := promstruct.ExposeMetrics(stats, reg, promstruct.WithNamespace("plugin"))
+	if err != nil {
+		t.Fatalf("ExposeMetrics() error = %v", err)
+	}
+
+	value.FieldByName("QueueDepth").SetInt(3)
+	value.FieldByName("HitRatio").SetFloat(0.75)
+
+	want := `
+# HELP plugin_cache_hit_ratio HitRatio field of the instance.
+# TYPE plugin_cache_hit_ratio gauge
+plugin_cache_hit_ratio 0.75
+# HELP plugin_queue_depth Jobs waiting in the queue.
+# TYPE plugin_queue_depth gauge
+plugin_queue_depth 3
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+
+	// Values are read at every gather, and set pointers report a sample
+	workers := uint32(4)
+	value.FieldByName("QueueDepth").SetInt(5)
+	value.FieldByName("Workers").Set(reflect.ValueOf(&workers))
+
+	want = `
+# HELP plugin_queue_depth Jobs waiting in the queue.
+# TYPE plugin_queue_depth gauge
+plugin_queue_depth 5
+# HELP plugin_workers Workers field of the instance.
+# TYPE plugin_workers gauge
+plugin_workers 4
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "plugin_queue_depth", "plugin_workers"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExposeMetricsGetter(t *testing.T) {
+	type stats struct {
+		Requests int64 `metric:"requests"`
+	}
+
+	current := stats{Requests: 1}
+	reg := prometheus.NewRegistry()
+
+	collector, err := promstruct.ExposeMetrics(stats{}, reg,
+		promstruct.WithGetter(func() any { return current }),
+		promstruct.WithConstLabels(prometheus.Labels{"plugin": "billing"}),
+	)
+	if err != nil {
+		t.Fatalf("ExposeMetrics() error = %v", err)
+	}
+
+	current.Requests = 9
+
+	want := `
+# HELP requests Requests field of stats.
+# TYPE requests gauge
+requests{plugin="billing"} 9
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+
+	if !reg.Unregister(collector) {
+		t.Error("Unregister() = false, want the returned collector to unregister the gauges")
+	}
+}
+
+func TestExposeMetricsGetterWrongType(t *testing.T) {
+	type stats struct {
+		Requests int64
+	}
+
+	reg := prometheus.NewRegistry()
+
+	_, err := promstruct.ExposeMetrics(stats{}, reg, promstruct.WithGetter(func() any { return 42 }))
+	if err != nil {
+		t.Fatalf("ExposeMetrics() error = %v", err)
+	}
+
+	if _, err := reg.Gather(); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Gather() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+}
+
+func TestExposeMetricsErrors(t *testing.T) {
+	type named struct {
+		Count int `metric:"count"`
+	}
+
+	tests := []struct {
+		name     string
+		instance any
+		wantErr  error
+	}{
+		{"not_a_struct", 42, dynamicstruct.ErrInvalidInstance},
+		{"no_numeric_fields", struct{ Name string }{}, promstruct.ErrNoMetrics},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := promstruct.ExposeMetrics(tt.instance, prometheus.NewRegistry()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExposeMetrics() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("already_registered", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		_, _ = promstruct.ExposeMetrics(&named{}, reg)
+
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if _, err := promstruct.ExposeMetrics(&named{}, reg); !errors.As(err, &alreadyRegistered) {
+			t.Errorf("ExposeMetrics() error = %v, want prometheus.AlreadyRegisteredError", err)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package protostruct
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// globalTypes caches the struct types of descriptors registered in
+// protoregistry.GlobalFiles, which live as long as the process.
+var globalTypes = NewTypeCache()
+
+// FromDynamicPB converts msg into a pointer to a new instance of the dynamic
+// struct FromProtoDescriptor builds for its descriptor, so that messages can
+// be turned into plain Go values without a struct to decode them into.
+// ToMessage converts the instance back.
+//
+// Instances converted from messages of the same type share their struct
+// type. The struct of a descriptor registered in protoregistry.GlobalFiles,
+// such as one generated by protoc-gen-go, is built once and cached for the
+// life of the process. The struct of a descriptor built at runtime, from
+// server reflection or a schema registry, is built again on every call;
+// convert their messages with a TypeCache to build it once.
+func FromDynamicPB(msg protoreflect.ProtoMessage) (any, error) {
+	md := msg.ProtoReflect().Descriptor()
+
+	if registered, err := protoregistry.GlobalFiles.FindDescriptorByName(md.FullName()); err == nil && registered == md {
+		return globalTypes.FromDynamicPB(msg)
+	}
+
+	return NewTypeCache().FromDynamicPB(msg)
+}
+
+// TypeCache converts dynamic messages like FromDynamicPB, building the struct
+// type of each message descriptor once. It only bounds how often types are
+// built: the runtime never frees the types reflect.StructOf makes, so a
+// dropped cache or a removed descriptor releases the descriptor but not its
+// struct type. It is safe for concurrent use.
+type TypeCache struct {
+	opts []dynamicstruct.ImportOption
+
+	m     sync.Mutex
+	types map[protoreflect.MessageDescriptor]reflect.Type
+}
+
+// NewTypeCache returns an empty cache building struct types with
+// FromProtoDescriptor and opts.
+func NewTypeCache(opts ...dynamicstruct.ImportOption) *TypeCache {
+	return &TypeCache{
+		opts:  opts,
+		types: map[protoreflect.MessageDescriptor]reflect.Type{},
+	}
+}
+
+// FromDynamicPB converts msg into a pointer to a new instance of the struct
+// type cached for its descriptor, building the type on first use.
+func (c *TypeCache) FromDynamicPB(msg protoreflect.ProtoMessage) (any, error) {
+	reflectMsg := msg.ProtoReflect()
+
+	structType, err := c.messageType(reflectMsg.Descriptor())
+	if err != nil {
+		return nil, err
+	}
+
+	instancePtr := reflect.New(structType).Interface()
+	if err := FromMessage(reflectMsg, instancePtr); err != nil {
+		return nil, err
+	}
+
+	return instancePtr, nil
+}
+
+// Remove forgets the struct type built for md, so that the cache no longer
+// holds on to a descriptor that isn't used anymore.
+func (c *TypeCache) Remove(md protoreflect.MessageDescriptor) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	delete(c.types, md)
+}
+
+// messageType returns the struct type built for md, building it on first use.
+func (c *TypeCache) messageType(md protoreflect.MessageDescriptor) (reflect.Type, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if structType, ok := c.types[md]; ok {
+		return structType, nil
+	}
+
+	builder, err := FromProtoDescriptor(md, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	structType := reflect.TypeOf(instance)
+	c.types[md] = structType
+
+	return structType, nil
+}
@@ -0,0 +1,100 @@
+package protostruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct/protostruct"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDynamicPBRoundTrip(t *testing.T) {
+	md := testFile(t).Messages().ByName("User")
+
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(md.Fields().ByName("user_name"), protoreflect.ValueOfString("alice"))
+
+	instancePtr, err := protostruct.FromDynamicPB(msg)
+	if err != nil {
+		t.Fatalf("FromDynamicPB() error = %v", err)
+	}
+
+	name := reflect.ValueOf(instancePtr).Elem().FieldByName("UserName")
+	if got := name.String(); got != "alice" {
+		t.Errorf("UserName = %q, want %q", got, "alice")
+	}
+
+	back, err := protostruct.ToMessage(instancePtr, md)
+	if err != nil {
+		t.Fatalf("ToMessage() error = %v", err)
+	}
+
+	if !proto.Equal(msg, back) {
+		t.Errorf("ToMessage() = %v, want %v", back, msg)
+	}
+
+	// Messages of the same type convert to instances of the same type
+	other, err := protostruct.FromDynamicPB(dynamicpb.NewMessage(md))
+	if err != nil {
+		t.Fatalf("FromDynamicPB() error = %v", err)
+	}
+
+	if reflect.TypeOf(other) != reflect.TypeOf(instancePtr) {
+		t.Errorf("FromDynamicPB() type = %v, want %v", reflect.TypeOf(other), reflect.TypeOf(instancePtr))
+	}
+}
+
+func TestFromDynamicPBRecursive(t *testing.T) {
+	md := testFile(t).Messages().ByName("Node")
+
+	_, err := protostruct.FromDynamicPB(dynamicpb.NewMessage(md))
+	if !errors.Is(err, protostruct.ErrRecursiveMessage) {
+		t.Errorf("FromDynamicPB() error = %v, want %v", err, protostruct.ErrRecursiveMessage)
+	}
+}
+
+func TestTypeCache(t *testing.T) {
+	md := testFile(t).Messages().ByName("User")
+	cache := protostruct.NewTypeCache()
+
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(md.Fields().ByName("id"), protoreflect.ValueOfInt64(7))
+
+	instancePtr, err := cache.FromDynamicPB(msg)
+	if err != nil {
+		t.Fatalf("FromDynamicPB() error = %v", err)
+	}
+
+	if got := reflect.ValueOf(instancePtr).Elem().FieldByName("Id").Int(); got != 7 {
+		t.Errorf("Id = %d, want 7", got)
+	}
+
+	cache.Remove(md)
+
+	other, err := cache.FromDynamicPB(dynamicpb.NewMessage(md))
+	if err != nil {
+		t.Fatalf("FromDynamicPB() error = %v", err)
+	}
+
+	if reflect.TypeOf(other) != reflect.TypeOf(instancePtr) {
+		t.Errorf("FromDynamicPB() type = %v, want %v", reflect.TypeOf(other), reflect.TypeOf(instancePtr))
+	}
+}
+
+func TestFromDynamicPBRegistered(t *testing.T) {
+	ts := timestamppb.New(time.Unix(1700000000, 5))
+
+	instancePtr, err := protostruct.FromDynamicPB(ts)
+	if err != nil {
+		t.Fatalf("FromDynamicPB() error = %v", err)
+	}
+
+	if got := reflect.ValueOf(instancePtr).Elem().FieldByName("Seconds").Int(); got != 1700000000 {
+		t.Errorf("Seconds = %d, want 1700000000", got)
+	}
+}
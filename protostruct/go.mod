@@ -0,0 +1,19 @@
+module github.com/gosmos-space/dynamicstruct/protostruct
+
+go 1.18
+
+require (
+	github.com/gosmos-space/dynamicstruct v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/fatih/structtag v1.2.0 // indirect
+	github.com/fxamacker/cbor/v2 v2.7.0 // indirect
+	github.com/vmihailenco/msgpack/v5 v5.3.5 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/gosmos-space/dynamicstruct => ../
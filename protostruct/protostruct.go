@@ -0,0 +1,584 @@
+// Package protostruct builds dynamic structs from protobuf message
+// descriptors and converts between their instances and dynamicpb messages.
+package protostruct
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TagKey is the struct tag holding the protobuf field number.
+const TagKey = "proto"
+
+var (
+	ErrRecursiveMessage = errors.New("recursive message types are not supported")
+	ErrFieldMismatch    = errors.New("struct field doesn't match the protobuf field")
+)
+
+// FromProtoDescriptor returns a builder with one field per field of the
+// message. Every field carries its number in a `proto:"N"` tag and its
+// protojson name in a `json` tag. Message fields and fields with explicit
+// presence become pointers, repeated fields slices and map fields maps.
+//
+// Fields are named after their protobuf names ("user_id" -> "UserId"). Names
+// that map to the same Go name, as "user_id" and "userId" can in proto2
+// files, are resolved by the policy of dynamicstruct.WithCollisionPolicy, and
+// return dynamicstruct.ErrFieldAlreadyExists by default.
+func FromProtoDescriptor(md protoreflect.MessageDescriptor, opts ...dynamicstruct.ImportOption) (*dynamicstruct.Builder, error) {
+	return fromDescriptor(md, make(map[protoreflect.FullName]bool), opts)
+}
+
+func fromDescriptor(
+	md protoreflect.MessageDescriptor,
+	visiting map[protoreflect.FullName]bool,
+	opts []dynamicstruct.ImportOption,
+) (*dynamicstruct.Builder, error) {
+	if visiting[md.FullName()] {
+		return nil, fmt.Errorf("%w: %s", ErrRecursiveMessage, md.FullName())
+	}
+
+	visiting[md.FullName()] = true
+	defer delete(visiting, md.FullName())
+
+	builder := dynamicstruct.New()
+	fields := md.Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+
+		fieldType, err := goType(fd, visiting, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := builder.ImportedFieldName(fieldName(fd), string(fd.Name()), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, fd.FullName())
+		}
+
+		err = builder.AddField(
+			name,
+			reflect.Zero(fieldType).Interface(),
+			fmt.Sprintf(`%s:"%d"`, TagKey, fd.Number()),
+			fmt.Sprintf(`json:"%s,omitempty"`, fd.JSONName()),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s", err, fd.FullName())
+		}
+	}
+
+	return builder, nil
+}
+
+// fieldName converts the protobuf field name into an exported Go name, the
+// way protoc-gen-go does for plain snake case names ("user_id" -> "UserId").
+// The name is used rather than the json_name, which may not be an identifier.
+func fieldName(fd protoreflect.FieldDescriptor) string {
+	return dynamicstruct.ExportedName(string(fd.Name()))
+}
+
+func goType(
+	fd protoreflect.FieldDescriptor,
+	visiting map[protoreflect.FullName]bool,
+	opts []dynamicstruct.ImportOption,
+) (reflect.Type, error) {
+	if fd.IsMap() {
+		keyType, err := singularType(fd.MapKey(), visiting, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		valueType, err := singularType(fd.MapValue(), visiting, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.MapOf(keyType, valueType), nil
+	}
+
+	elemType, err := singularType(fd, visiting, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if fd.IsList() {
+		return reflect.SliceOf(elemType), nil
+	}
+
+	// Scalars with explicit presence distinguish unset from zero
+	if fd.HasPresence() && elemType.Kind() != reflect.Ptr && elemType.Kind() != reflect.Slice {
+		return reflect.PtrTo(elemType), nil
+	}
+
+	return elemType, nil
+}
+
+func singularType(
+	fd protoreflect.FieldDescriptor,
+	visiting map[protoreflect.FullName]bool,
+	opts []dynamicstruct.ImportOption,
+) (reflect.Type, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return reflect.TypeOf(false), nil
+	case protoreflect.EnumKind, protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return reflect.TypeOf(int32(0)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return reflect.TypeOf(int64(0)), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return reflect.TypeOf(uint32(0)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return reflect.TypeOf(uint64(0)), nil
+	case protoreflect.FloatKind:
+		return reflect.TypeOf(float32(0)), nil
+	case protoreflect.DoubleKind:
+		return reflect.TypeOf(float64(0)), nil
+	case protoreflect.StringKind:
+		return reflect.TypeOf(""), nil
+	case protoreflect.BytesKind:
+		return reflect.TypeOf([]byte(nil)), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		builder, err := fromDescriptor(fd.Message(), visiting, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		instance, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.PtrTo(reflect.TypeOf(instance)), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported kind %s", ErrFieldMismatch, fd.Kind())
+	}
+}
+
+// ToMessage converts a struct (or pointer to struct) into a dynamic message
+// of type md. Struct fields are matched by the number in their proto tag;
+// fields without the tag are ignored. Integers are accepted for integer and
+// enum fields of any width that holds them; values of another kind, and
+// numbers that overflow the proto field, return ErrFieldMismatch.
+func ToMessage(instance any, md protoreflect.MessageDescriptor) (*dynamicpb.Message, error) {
+	value := reflect.ValueOf(instance)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, dynamicstruct.ErrValueCannotBeNil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, dynamicstruct.ErrInvalidInstance
+	}
+
+	msg := dynamicpb.NewMessage(md)
+
+	if err := toMessage(value, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func toMessage(value reflect.Value, msg protoreflect.Message) error {
+	fields := msg.Descriptor().Fields()
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		fd, err := fieldDescriptor(structType.Field(i), fields)
+		if err != nil {
+			return err
+		}
+
+		if fd == nil {
+			continue
+		}
+
+		if err := setMessageField(msg, fd, value.Field(i)); err != nil {
+			return fmt.Errorf("%w: field %s", err, structType.Field(i).Name)
+		}
+	}
+
+	return nil
+}
+
+func fieldDescriptor(field reflect.StructField, fields protoreflect.FieldDescriptors) (protoreflect.FieldDescriptor, error) {
+	tag, ok := field.Tag.Lookup(TagKey)
+	if !ok {
+		return nil, nil
+	}
+
+	number, err := strconv.Atoi(tag)
+	if err != nil {
+		return nil, fmt.Errorf("%w: field %s has invalid tag %q", ErrFieldMismatch, field.Name, tag)
+	}
+
+	fd := fields.ByNumber(protoreflect.FieldNumber(number))
+	if fd == nil {
+		return nil, fmt.Errorf("%w: no field number %d for %s", ErrFieldMismatch, number, field.Name)
+	}
+
+	return fd, nil
+}
+
+func setMessageField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, value reflect.Value) error {
+	switch {
+	case fd.IsMap():
+		if value.Kind() != reflect.Map {
+			return fmt.Errorf("%w: can't set map field %s from %s", ErrFieldMismatch, fd.Name(), value.Type())
+		}
+
+		if value.Len() == 0 {
+			return nil
+		}
+
+		protoMap := msg.Mutable(fd).Map()
+		iter := value.MapRange()
+
+		for iter.Next() {
+			key, err := protoValue(fd.MapKey(), iter.Key(), nil)
+			if err != nil {
+				return err
+			}
+
+			item, err := protoValue(fd.MapValue(), iter.Value(), protoMap.NewValue)
+			if err != nil {
+				return err
+			}
+
+			protoMap.Set(key.MapKey(), item)
+		}
+	case fd.IsList():
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return fmt.Errorf("%w: can't set repeated field %s from %s", ErrFieldMismatch, fd.Name(), value.Type())
+		}
+
+		if value.Len() == 0 {
+			return nil
+		}
+
+		list := msg.Mutable(fd).List()
+
+		for i := 0; i < value.Len(); i++ {
+			item, err := protoValue(fd, value.Index(i), list.NewElement)
+			if err != nil {
+				return err
+			}
+
+			list.Append(item)
+		}
+	default:
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil
+		}
+
+		item, err := protoValue(fd, value, func() protoreflect.Value {
+			return msg.NewField(fd)
+		})
+		if err != nil {
+			return err
+		}
+
+		msg.Set(fd, item)
+	}
+
+	return nil
+}
+
+func protoValue(
+	fd protoreflect.FieldDescriptor,
+	value reflect.Value,
+	newMessage func() protoreflect.Value,
+) (protoreflect.Value, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return protoreflect.Value{}, fmt.Errorf("%w: nil element", ErrFieldMismatch)
+		}
+
+		value = value.Elem()
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if value.Kind() != reflect.Bool {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfBool(value.Bool()), nil
+	case protoreflect.EnumKind:
+		n, ok := intValue(value, 32)
+		if !ok {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, ok := intValue(value, 32)
+		if !ok {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, ok := intValue(value, 64)
+		if !ok {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, ok := uintValue(value, 32)
+		if !ok {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, ok := uintValue(value, 64)
+		if !ok {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfUint64(n), nil
+	case protoreflect.FloatKind:
+		if !isFloat(value) || reflect.Zero(reflect.TypeOf(float32(0))).OverflowFloat(value.Float()) {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfFloat32(float32(value.Float())), nil
+	case protoreflect.DoubleKind:
+		if !isFloat(value) {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfFloat64(value.Float()), nil
+	case protoreflect.StringKind:
+		if value.Kind() != reflect.String {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfString(value.String()), nil
+	case protoreflect.BytesKind:
+		if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.Uint8 {
+			return protoreflect.Value{}, mismatchError(fd, value)
+		}
+
+		return protoreflect.ValueOfBytes(value.Bytes()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if value.Kind() != reflect.Struct {
+			return protoreflect.Value{}, fmt.Errorf("%w: %s is not a struct", ErrFieldMismatch, value.Type())
+		}
+
+		item := newMessage()
+		if err := toMessage(value, item.Message()); err != nil {
+			return protoreflect.Value{}, err
+		}
+
+		return item, nil
+	default:
+		return protoreflect.Value{}, fmt.Errorf("%w: unsupported kind %s", ErrFieldMismatch, fd.Kind())
+	}
+}
+
+func mismatchError(fd protoreflect.FieldDescriptor, value reflect.Value) error {
+	return fmt.Errorf("%w: can't set %s field %s from %s", ErrFieldMismatch, fd.Kind(), fd.Name(), value.Type())
+}
+
+func isFloat(value reflect.Value) bool {
+	return value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64
+}
+
+// intValue returns the integer value holds, and false when it isn't one or
+// overflows bits.
+func intValue(value reflect.Value, bits int) (int64, bool) {
+	var n int64
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+
+		n = int64(value.Uint())
+	default:
+		return 0, false
+	}
+
+	if bits < 64 && (n < -(1<<(bits-1)) || n >= 1<<(bits-1)) {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// uintValue returns the unsigned integer value holds, and false when it isn't
+// one, is negative or overflows bits.
+func uintValue(value reflect.Value, bits int) (uint64, bool) {
+	var n uint64
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 {
+			return 0, false
+		}
+
+		n = uint64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = value.Uint()
+	default:
+		return 0, false
+	}
+
+	if bits < 64 && n >= 1<<bits {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// FromMessage copies the populated fields of msg into the struct instancePtr
+// points to. Struct fields are matched by the number in their proto tag.
+func FromMessage(msg protoreflect.Message, instancePtr any) error {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return dynamicstruct.ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return dynamicstruct.ErrValueCannotBeNil
+	}
+
+	if valueReflect.Elem().Kind() != reflect.Struct {
+		return dynamicstruct.ErrInvalidInstance
+	}
+
+	return fromMessage(msg, valueReflect.Elem())
+}
+
+func fromMessage(msg protoreflect.Message, value reflect.Value) error {
+	fields := msg.Descriptor().Fields()
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		fd, err := fieldDescriptor(structType.Field(i), fields)
+		if err != nil {
+			return err
+		}
+
+		if fd == nil || !msg.Has(fd) {
+			continue
+		}
+
+		if err := setStructField(value.Field(i), fd, msg.Get(fd)); err != nil {
+			return fmt.Errorf("%w: field %s", err, structType.Field(i).Name)
+		}
+	}
+
+	return nil
+}
+
+func setStructField(target reflect.Value, fd protoreflect.FieldDescriptor, item protoreflect.Value) error {
+	switch {
+	case fd.IsMap():
+		if target.Kind() != reflect.Map {
+			return fmt.Errorf("%w: %s is not a map", ErrFieldMismatch, target.Type())
+		}
+
+		protoMap := item.Map()
+		result := reflect.MakeMapWithSize(target.Type(), protoMap.Len())
+
+		var err error
+
+		protoMap.Range(func(key protoreflect.MapKey, mapValue protoreflect.Value) bool {
+			k := reflect.New(target.Type().Key()).Elem()
+			if err = setSingular(k, fd.MapKey(), key.Value()); err != nil {
+				return false
+			}
+
+			v := reflect.New(target.Type().Elem()).Elem()
+			if err = setSingular(v, fd.MapValue(), mapValue); err != nil {
+				return false
+			}
+
+			result.SetMapIndex(k, v)
+
+			return true
+		})
+
+		if err != nil {
+			return err
+		}
+
+		target.Set(result)
+	case fd.IsList():
+		if target.Kind() != reflect.Slice {
+			return fmt.Errorf("%w: %s is not a slice", ErrFieldMismatch, target.Type())
+		}
+
+		list := item.List()
+		result := reflect.MakeSlice(target.Type(), list.Len(), list.Len())
+
+		for i := 0; i < list.Len(); i++ {
+			if err := setSingular(result.Index(i), fd, list.Get(i)); err != nil {
+				return err
+			}
+		}
+
+		target.Set(result)
+	default:
+		return setSingular(target, fd, item)
+	}
+
+	return nil
+}
+
+func setSingular(target reflect.Value, fd protoreflect.FieldDescriptor, item protoreflect.Value) error {
+	if target.Kind() == reflect.Ptr {
+		elem := reflect.New(target.Type().Elem())
+		if err := setSingular(elem.Elem(), fd, item); err != nil {
+			return err
+		}
+
+		target.Set(elem)
+
+		return nil
+	}
+
+	var value reflect.Value
+
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		value = reflect.ValueOf(int32(item.Enum()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if target.Kind() != reflect.Struct {
+			return fmt.Errorf("%w: %s is not a struct", ErrFieldMismatch, target.Type())
+		}
+
+		return fromMessage(item.Message(), target)
+	case protoreflect.BytesKind:
+		value = reflect.ValueOf(append([]byte(nil), item.Bytes()...))
+	default:
+		value = reflect.ValueOf(item.Interface())
+	}
+
+	if !value.Type().ConvertibleTo(target.Type()) {
+		return fmt.Errorf("%w: can't convert %s to %s", ErrFieldMismatch, value.Type(), target.Type())
+	}
+
+	target.Set(value.Convert(target.Type()))
+
+	return nil
+}
@@ -0,0 +1,357 @@
+package protostruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/gosmos-space/dynamicstruct/protostruct"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func field(
+	name string,
+	number int32,
+	kind descriptorpb.FieldDescriptorProto_Type,
+	label descriptorpb.FieldDescriptorProto_Label,
+	typeName string,
+) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Type:   kind.Enum(),
+		Label:  label.Enum(),
+	}
+
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+
+	return f
+}
+
+const (
+	optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+)
+
+// testFile describes:
+//
+//	message Address { string city = 1; }
+//	message User {
+//	  int64 id = 1;
+//	  string user_name = 2;
+//	  repeated string tags = 3;
+//	  Address address = 4;
+//	  map<string, int32> scores = 5;
+//	  Status status = 6;
+//	  bytes avatar = 7;
+//	  repeated Address previous = 8;
+//	}
+//	message Node { Node next = 1; }
+func testFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					field("user_name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, ""),
+					field("address", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".test.Address"),
+					field("scores", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.User.ScoresEntry"),
+					field("status", 6, descriptorpb.FieldDescriptorProto_TYPE_ENUM, optional, ".test.Status"),
+					field("avatar", 7, descriptorpb.FieldDescriptorProto_TYPE_BYTES, optional, ""),
+					field("previous", 8, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.Address"),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("ScoresEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+			{
+				Name: proto.String("Node"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("next", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".test.Node"),
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error = %v", err)
+	}
+
+	return fd
+}
+
+func TestFromProtoDescriptor(t *testing.T) {
+	md := testFile(t).Messages().ByName("User")
+
+	builder, err := protostruct.FromProtoDescriptor(md)
+	if err != nil {
+		t.Fatalf("FromProtoDescriptor() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field    string
+		wantKind reflect.Kind
+		wantTag  string
+		wantJSON string
+	}{
+		{field: "Id", wantKind: reflect.Int64, wantTag: "1", wantJSON: "id,omitempty"},
+		{field: "UserName", wantKind: reflect.String, wantTag: "2", wantJSON: "userName,omitempty"},
+		{field: "Tags", wantKind: reflect.Slice, wantTag: "3", wantJSON: "tags,omitempty"},
+		{field: "Address", wantKind: reflect.Ptr, wantTag: "4", wantJSON: "address,omitempty"},
+		{field: "Scores", wantKind: reflect.Map, wantTag: "5", wantJSON: "scores,omitempty"},
+		{field: "Status", wantKind: reflect.Int32, wantTag: "6", wantJSON: "status,omitempty"},
+		{field: "Avatar", wantKind: reflect.Slice, wantTag: "7", wantJSON: "avatar,omitempty"},
+		{field: "Previous", wantKind: reflect.Slice, wantTag: "8", wantJSON: "previous,omitempty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			f, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if f.Type.Kind() != tt.wantKind {
+				t.Errorf("field %s kind = %v, want %v", tt.field, f.Type.Kind(), tt.wantKind)
+			}
+			if f.Tag.Get(protostruct.TagKey) != tt.wantTag {
+				t.Errorf("field %s proto tag = %q, want %q", tt.field, f.Tag.Get(protostruct.TagKey), tt.wantTag)
+			}
+			if f.Tag.Get("json") != tt.wantJSON {
+				t.Errorf("field %s json tag = %q, want %q", tt.field, f.Tag.Get("json"), tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestFromProtoDescriptorRecursive(t *testing.T) {
+	md := testFile(t).Messages().ByName("Node")
+
+	_, err := protostruct.FromProtoDescriptor(md)
+	if !errors.Is(err, protostruct.ErrRecursiveMessage) {
+		t.Errorf("FromProtoDescriptor() error = %v, want %v", err, protostruct.ErrRecursiveMessage)
+	}
+}
+
+func TestFromProtoDescriptorCollisions(t *testing.T) {
+	userID := field("userId", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, "")
+	userID.JsonName = proto.String("user-id")
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("legacy.proto"),
+		Package: proto.String("legacy"),
+		Syntax:  proto.String("proto2"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("user_id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				userID,
+			},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile() error = %v", err)
+	}
+
+	md := fd.Messages().ByName("User")
+
+	if _, err := protostruct.FromProtoDescriptor(md); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+		t.Errorf("FromProtoDescriptor() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+	}
+
+	builder, err := protostruct.FromProtoDescriptor(md, dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionSuffix))
+	if err != nil {
+		t.Fatalf("FromProtoDescriptor() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	f, ok := reflect.TypeOf(instance).FieldByName("UserId2")
+	if !ok || f.Tag.Get(protostruct.TagKey) != "2" || f.Tag.Get("json") != "user-id,omitempty" {
+		t.Errorf("FromProtoDescriptor() = %T, want UserId2 for field 2", instance)
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	md := testFile(t).Messages().ByName("User")
+
+	builder, err := protostruct.FromProtoDescriptor(md)
+	if err != nil {
+		t.Fatalf("FromProtoDescriptor() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	source := reflect.New(reflect.TypeOf(instance)).Interface()
+
+	err = json.Unmarshal([]byte(`{
+		"id": 7,
+		"userName": "alice",
+		"tags": ["a", "b"],
+		"address": {"city": "Berlin"},
+		"scores": {"math": 90},
+		"status": 1,
+		"avatar": "AQI=",
+		"previous": [{"city": "Paris"}]
+	}`), source)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	msg, err := protostruct.ToMessage(source, md)
+	if err != nil {
+		t.Fatalf("ToMessage() error = %v", err)
+	}
+
+	if got := msg.Get(md.Fields().ByName("user_name")).String(); got != "alice" {
+		t.Errorf("message user_name = %q, want %q", got, "alice")
+	}
+
+	// Serialize and parse to make sure the message is valid on the wire
+	wire, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+
+	parsed := msg.New().Interface()
+	if err = proto.Unmarshal(wire, parsed); err != nil {
+		t.Fatalf("proto.Unmarshal() error = %v", err)
+	}
+
+	target := reflect.New(reflect.TypeOf(instance)).Interface()
+	if err = protostruct.FromMessage(parsed.ProtoReflect(), target); err != nil {
+		t.Fatalf("FromMessage() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(source, target) {
+		t.Errorf("round trip mismatch:\n got  %+v\n want %+v", target, source)
+	}
+}
+
+func TestConversionErrors(t *testing.T) {
+	md := testFile(t).Messages().ByName("User")
+
+	t.Run("to_message_non_struct", func(t *testing.T) {
+		_, err := protostruct.ToMessage(42, md)
+		if !errors.Is(err, dynamicstruct.ErrInvalidInstance) {
+			t.Errorf("ToMessage() error = %v, want %v", err, dynamicstruct.ErrInvalidInstance)
+		}
+	})
+
+	t.Run("unknown_field_number", func(t *testing.T) {
+		instance := struct {
+			Name string `proto:"99"`
+		}{}
+
+		_, err := protostruct.ToMessage(instance, md)
+		if !errors.Is(err, protostruct.ErrFieldMismatch) {
+			t.Errorf("ToMessage() error = %v, want %v", err, protostruct.ErrFieldMismatch)
+		}
+	})
+
+	mismatches := []struct {
+		name     string
+		instance any
+	}{
+		{"string_for_int64", struct {
+			ID string `proto:"1"`
+		}{ID: "1"}},
+		{"int_for_string", struct {
+			UserName int `proto:"2"`
+		}{UserName: 1}},
+		{"string_for_repeated", struct {
+			Tags string `proto:"3"`
+		}{Tags: "a"}},
+		{"slice_for_map", struct {
+			Scores []int32 `proto:"5"`
+		}{Scores: []int32{1}}},
+		{"overflowing_map_value", struct {
+			Scores map[string]int64 `proto:"5"`
+		}{Scores: map[string]int64{"a": 1 << 40}}},
+		{"overflowing_enum", struct {
+			Status int64 `proto:"6"`
+		}{Status: 1 << 40}},
+		{"string_for_bytes", struct {
+			Avatar string `proto:"7"`
+		}{Avatar: "a"}},
+	}
+
+	for _, tt := range mismatches {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := protostruct.ToMessage(tt.instance, md)
+			if !errors.Is(err, protostruct.ErrFieldMismatch) {
+				t.Errorf("ToMessage() error = %v, want %v", err, protostruct.ErrFieldMismatch)
+			}
+		})
+	}
+
+	t.Run("converted_numbers", func(t *testing.T) {
+		instance := struct {
+			ID     int8             `proto:"1"`
+			Scores map[string]uint8 `proto:"5"`
+		}{ID: 7, Scores: map[string]uint8{"a": 200}}
+
+		msg, err := protostruct.ToMessage(instance, md)
+		if err != nil {
+			t.Fatalf("ToMessage() error = %v", err)
+		}
+
+		if got := msg.Get(md.Fields().ByName("id")).Int(); got != 7 {
+			t.Errorf("id = %d, want 7", got)
+		}
+	})
+
+	t.Run("from_message_non_pointer", func(t *testing.T) {
+		msg, _ := protostruct.ToMessage(struct{}{}, md)
+
+		err := protostruct.FromMessage(msg, struct{}{})
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("FromMessage() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
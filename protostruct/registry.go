@@ -0,0 +1,99 @@
+package protostruct
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// RegistryFormat returns the dynamicstruct.SchemaFormat of PROTOBUF schemas,
+// for dynamicstruct.WithSchemaFormat. Registries return Protobuf schemas as
+// .proto source by default, which this package doesn't parse; the client has
+// to fetch them with format=serialized, as the base64 encoded
+// FileDescriptorProto. Imports are resolved in protoregistry.GlobalFiles, so
+// a schema can import the well-known types but not other schema references.
+//
+// The struct is built with opts for the first message of the file, which is
+// the one the message indexes following the schema id select when a file
+// defines a single message. Messages of another message of the file return
+// dynamicstruct.ErrInvalidMessage.
+func RegistryFormat(opts ...dynamicstruct.ImportOption) dynamicstruct.SchemaFormat {
+	return func(schema string) (*dynamicstruct.Builder, dynamicstruct.SchemaDecodeFunc, error) {
+		data, err := base64.StdEncoding.DecodeString(schema)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: schema isn't a serialized file descriptor: %s", dynamicstruct.ErrInvalidSchema, err.Error())
+		}
+
+		fdp := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(data, fdp); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", dynamicstruct.ErrInvalidSchema, err.Error())
+		}
+
+		fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", dynamicstruct.ErrInvalidSchema, err.Error())
+		}
+
+		if fd.Messages().Len() == 0 {
+			return nil, nil, fmt.Errorf("%w: %s defines no message", dynamicstruct.ErrInvalidSchema, fd.Path())
+		}
+
+		md := fd.Messages().Get(0)
+
+		builder, err := FromProtoDescriptor(md, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		decode := func(payload []byte, instancePtr any) error {
+			payload, err := skipMessageIndexes(payload)
+			if err != nil {
+				return err
+			}
+
+			msg := dynamicpb.NewMessage(md)
+			if err := proto.Unmarshal(payload, msg); err != nil {
+				return fmt.Errorf("%w: %s", dynamicstruct.ErrInvalidMessage, err.Error())
+			}
+
+			return FromMessage(msg, instancePtr)
+		}
+
+		return builder, decode, nil
+	}
+}
+
+// skipMessageIndexes returns the payload after the message indexes, the
+// zigzag varint count and indexes of the path to the message type in the
+// file, checking that they select its first message. A single zero stands for
+// that path.
+func skipMessageIndexes(payload []byte) ([]byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 || count < 0 || count > int64(len(payload)) {
+		return nil, fmt.Errorf("%w: invalid message indexes", dynamicstruct.ErrInvalidMessage)
+	}
+
+	payload = payload[n:]
+
+	for i := int64(0); i < count; i++ {
+		index, n := binary.Varint(payload)
+		if n <= 0 {
+			return nil, fmt.Errorf("%w: invalid message indexes", dynamicstruct.ErrInvalidMessage)
+		}
+
+		if index != 0 || count > 1 {
+			return nil, fmt.Errorf("%w: message isn't the first message of its schema", dynamicstruct.ErrInvalidMessage)
+		}
+
+		payload = payload[n:]
+	}
+
+	return payload, nil
+}
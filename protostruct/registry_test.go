@@ -0,0 +1,97 @@
+package protostruct_test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/gosmos-space/dynamicstruct/protostruct"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type registryClientTest map[int]dynamicstruct.RegistrySchema
+
+func (c registryClientTest) SchemaByID(id int) (dynamicstruct.RegistrySchema, error) {
+	schema, ok := c[id]
+	if !ok {
+		return dynamicstruct.RegistrySchema{}, errors.New("schema not found")
+	}
+
+	return schema, nil
+}
+
+// wireMessage frames payload in the schema registry wire format, with the
+// message indexes of Protobuf messages.
+func wireMessage(id int, indexes []byte, payload []byte) []byte {
+	data := make([]byte, 5, 5+len(indexes)+len(payload))
+	binary.BigEndian.PutUint32(data[1:], uint32(id))
+
+	return append(append(data, indexes...), payload...)
+}
+
+func TestRegistryFormat(t *testing.T) {
+	fd := testFile(t)
+
+	schema, err := proto.Marshal(protodesc.ToFileDescriptorProto(fd))
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+
+	codec := dynamicstruct.NewRegistryCodec(
+		registryClientTest{
+			1: {Type: "PROTOBUF", Schema: base64.StdEncoding.EncodeToString(schema)},
+			2: {Type: "PROTOBUF", Schema: "not base64"},
+		},
+		dynamicstruct.WithSchemaFormat("PROTOBUF", protostruct.RegistryFormat()),
+	)
+
+	// The first message of the file is Address
+	address := dynamicpb.NewMessage(fd.Messages().ByName("Address"))
+	address.Set(address.Descriptor().Fields().ByName("city"), protoreflect.ValueOfString("Berlin"))
+
+	payload, err := proto.Marshal(address)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "first_message", data: wireMessage(1, []byte{0}, payload)},
+		{name: "explicit_first_message", data: wireMessage(1, []byte{2, 0}, payload)},
+		{name: "second_message", data: wireMessage(1, []byte{2, 2}, payload), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "nested_message", data: wireMessage(1, []byte{4, 0, 0}, payload), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "truncated_indexes", data: wireMessage(1, []byte{0x80}, nil), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "invalid_payload", data: wireMessage(1, []byte{0}, []byte{0xff}), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "invalid_schema", data: wireMessage(2, []byte{0}, payload), wantErr: dynamicstruct.ErrInvalidSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instancePtr, err := codec.Deserialize("addresses", tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Deserialize() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			roundTrip, err := protostruct.ToMessage(instancePtr, address.Descriptor())
+			if err != nil {
+				t.Fatalf("ToMessage() error = %v", err)
+			}
+
+			if !proto.Equal(roundTrip, address) {
+				t.Errorf("ToMessage() = %v, want %v", roundTrip, address)
+			}
+		})
+	}
+}
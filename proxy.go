@@ -0,0 +1,92 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithStringer sets the function Proxy.String delegates to. reflect.StructOf
+// can't give the built type methods of its own, so instances that need one
+// are wrapped with Wrap.
+func (b *Builder) WithStringer(fn func(inst any) string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.stringer = fn
+}
+
+// WithJSONMarshaler sets the function Proxy.MarshalJSON delegates to.
+func (b *Builder) WithJSONMarshaler(fn func(inst any) ([]byte, error)) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.jsonMarshaler = fn
+}
+
+// Proxy wraps an instance of the built type, implementing fmt.Stringer and
+// json.Marshaler by delegating to the functions set with WithStringer and
+// WithJSONMarshaler. Without them, it formats and marshals the instance as
+// usual. Either way, sensitive fields are masked as by Redact first, so
+// proxies can be logged and returned in error payloads.
+type Proxy struct {
+	instance      any
+	stringer      func(inst any) string
+	jsonMarshaler func(inst any) ([]byte, error)
+}
+
+// Wrap returns a Proxy for instance, a value of the built type or a pointer to
+// one. The delegates are those set when Wrap is called.
+func (b *Builder) Wrap(instance any) (*Proxy, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Type() != b.instance.Type() {
+		return nil, fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			b.instance.Type().String(),
+		)
+	}
+
+	return &Proxy{
+		instance:      instance,
+		stringer:      b.stringer,
+		jsonMarshaler: b.jsonMarshaler,
+	}, nil
+}
+
+// Instance returns the instance the Proxy wraps.
+func (p *Proxy) Instance() any {
+	return p.instance
+}
+
+func (p *Proxy) String() string {
+	redacted := Redact(p.instance)
+
+	if p.stringer == nil {
+		return fmt.Sprintf("%+v", redacted)
+	}
+
+	return p.stringer(redacted)
+}
+
+func (p *Proxy) MarshalJSON() ([]byte, error) {
+	redacted := Redact(p.instance)
+
+	if p.jsonMarshaler == nil {
+		return json.Marshal(redacted)
+	}
+
+	return p.jsonMarshaler(redacted)
+}
@@ -0,0 +1,95 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newProxyBuilder(t *testing.T) (*dynamicstruct.Builder, any) {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddField("Secret", "", `json:"secret"`)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instancePtr, _ := builder.NewInstance()
+	value := reflect.ValueOf(instancePtr).Elem()
+	value.FieldByName("Name").SetString("Alice")
+	value.FieldByName("Secret").SetString("hunter2")
+
+	return builder, instancePtr
+}
+
+func TestWrap(t *testing.T) {
+	builder, instancePtr := newProxyBuilder(t)
+
+	builder.WithStringer(func(inst any) string {
+		return "User(" + reflect.ValueOf(inst).Elem().FieldByName("Name").String() + ")"
+	})
+	builder.WithJSONMarshaler(func(inst any) ([]byte, error) {
+		name := reflect.ValueOf(inst).Elem().FieldByName("Name").String()
+
+		return json.Marshal(map[string]string{"name": name})
+	})
+
+	proxy, err := builder.Wrap(instancePtr)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	if got := fmt.Sprint(proxy); got != "User(Alice)" {
+		t.Errorf("fmt.Sprint() = %s, want User(Alice)", got)
+	}
+
+	data, err := json.Marshal(map[string]any{"user": proxy})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	if want := `{"user":{"name":"Alice"}}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	if proxy.Instance() != instancePtr {
+		t.Error("Instance() should return the wrapped instance")
+	}
+}
+
+func TestWrapDefaults(t *testing.T) {
+	builder, instancePtr := newProxyBuilder(t)
+
+	proxy, err := builder.Wrap(instancePtr)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	if got, want := proxy.String(), "&{Name:Alice Secret:hunter2}"; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+
+	data, _ := json.Marshal(proxy)
+	if want := `{"name":"Alice","secret":"hunter2"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestWrapErrors(t *testing.T) {
+	builder, _ := newProxyBuilder(t)
+
+	if _, err := builder.Wrap(PersonTest{}); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Wrap() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	if _, err := dynamicstruct.New().Wrap(PersonTest{}); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("Wrap() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+}
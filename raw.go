@@ -0,0 +1,93 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// AddRawField adds a json.RawMessage field, which keeps the bytes of its JSON
+// value undecoded. Sub-objects whose shape isn't known yet pass through it
+// unchanged, and can be decoded later with DecodeRawField.
+func (b *Builder) AddRawField(name string, tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	return b.addField(name, rawMessageType, tags)
+}
+
+// DecodeRawField decodes the raw JSON of the named json.RawMessage field of
+// instance, a struct or a pointer to one, into a new instance of the type
+// target built, and returns a pointer to it. The instance gets the defaults
+// of target first, so an empty or null field returns them alone.
+func DecodeRawField(instance any, name string, target *Builder) (any, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := rawField(value, name, "decode")
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := target.NewInstance()
+	if err != nil {
+		return nil, err
+	}
+
+	data := raw.Bytes()
+	if len(data) == 0 {
+		return decoded, nil
+	}
+
+	if err := target.DecodeJSON(data, decoded); err != nil {
+		return nil, fieldError("decode", name, err)
+	}
+
+	return decoded, nil
+}
+
+// EncodeRawField sets the named json.RawMessage field of the struct
+// instancePtr points to to the JSON encoding of value, such as an instance
+// of another dynamic struct.
+func EncodeRawField(instancePtr any, name string, value any) error {
+	instance, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	raw, err := rawField(instance, name, "set")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fieldError("set", name, err)
+	}
+
+	raw.SetBytes(data)
+
+	return nil
+}
+
+func rawField(value reflect.Value, name, op string) (reflect.Value, error) {
+	field, ok := fieldByName(value, name)
+	if !ok {
+		return reflect.Value{}, fieldError(op, name, missingField(value.Type(), name))
+	}
+
+	if field.Type() != rawMessageType {
+		return reflect.Value{}, fieldError(op, name, fmt.Errorf(
+			"%w: field type: %s, want %s",
+			ErrIncompatibleTypes,
+			field.Type(),
+			rawMessageType,
+		))
+	}
+
+	return field, nil
+}
@@ -0,0 +1,128 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestAddRawField(t *testing.T) {
+	envelope := dynamicstruct.New()
+	_ = envelope.AddField("Kind", "", `json:"kind"`)
+	_ = envelope.AddRawField("Payload", `json:"payload"`)
+
+	if _, err := envelope.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	person := dynamicstruct.New()
+	_ = person.AddField("Name", "", `json:"name"`)
+	_ = person.AddFieldWithOptions("Age", 0, dynamicstruct.Default(18), dynamicstruct.Tags(`json:"age"`))
+
+	if _, err := person.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	data := []byte(`{"kind":"person","payload":{"name":"Alice","extra":[1, 2]}}`)
+
+	instancePtr, _ := envelope.NewInstance()
+	if err := json.Unmarshal(data, instancePtr); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	t.Run("keeps_bytes", func(t *testing.T) {
+		payload := reflect.ValueOf(instancePtr).Elem().FieldByName("Payload").Interface().(json.RawMessage)
+		if string(payload) != `{"name":"Alice","extra":[1, 2]}` {
+			t.Errorf("Payload = %s, want %s", payload, `{"name":"Alice","extra":[1, 2]}`)
+		}
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		decoded, err := dynamicstruct.DecodeRawField(instancePtr, "Payload", person)
+		if err != nil {
+			t.Fatalf("DecodeRawField() error = %v", err)
+		}
+
+		out, _ := json.Marshal(decoded)
+		if string(out) != `{"name":"Alice","age":18}` {
+			t.Errorf("DecodeRawField() = %s, want %s", out, `{"name":"Alice","age":18}`)
+		}
+	})
+
+	t.Run("encode", func(t *testing.T) {
+		decoded, _ := person.NewInstance()
+		_ = json.Unmarshal([]byte(`{"name":"Bob","age":40}`), decoded)
+
+		if err := dynamicstruct.EncodeRawField(instancePtr, "Payload", decoded); err != nil {
+			t.Fatalf("EncodeRawField() error = %v", err)
+		}
+
+		out, _ := json.Marshal(instancePtr)
+		if string(out) != `{"kind":"person","payload":{"name":"Bob","age":40}}` {
+			t.Errorf("Marshal() = %s", out)
+		}
+	})
+
+	t.Run("empty_field", func(t *testing.T) {
+		empty, _ := envelope.NewInstance()
+
+		decoded, err := dynamicstruct.DecodeRawField(empty, "Payload", person)
+		if err != nil {
+			t.Fatalf("DecodeRawField() error = %v", err)
+		}
+
+		out, _ := json.Marshal(decoded)
+		if string(out) != `{"name":"","age":18}` {
+			t.Errorf("DecodeRawField() = %s", out)
+		}
+	})
+}
+
+func TestDecodeRawFieldErrors(t *testing.T) {
+	envelope := dynamicstruct.New()
+	_ = envelope.AddField("Kind", "")
+	_ = envelope.AddRawField("Payload")
+	_, _ = envelope.Build()
+
+	person := dynamicstruct.New()
+	_ = person.AddField("Name", "")
+	_, _ = person.Build()
+
+	instancePtr, _ := envelope.NewInstance()
+	_ = json.Unmarshal([]byte(`{"Payload":{"Name":1}}`), instancePtr)
+
+	tests := []struct {
+		name    string
+		field   string
+		target  *dynamicstruct.Builder
+		wantErr error
+	}{
+		{name: "missing_field", field: "Body", target: person, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "not_raw", field: "Kind", target: person, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "target_not_built", field: "Payload", target: dynamicstruct.New(), wantErr: dynamicstruct.ErrInstanceNotBuilt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dynamicstruct.DecodeRawField(instancePtr, tt.field, tt.target); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeRawField() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("invalid_payload", func(t *testing.T) {
+		var fieldErr *dynamicstruct.FieldError
+		if _, err := dynamicstruct.DecodeRawField(instancePtr, "Payload", person); !errors.As(err, &fieldErr) {
+			t.Errorf("DecodeRawField() error = %v, want *FieldError", err)
+		}
+	})
+
+	t.Run("encode_not_pointer", func(t *testing.T) {
+		if err := dynamicstruct.EncodeRawField(struct{}{}, "Payload", 1); !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("EncodeRawField() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
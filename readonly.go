@@ -0,0 +1,207 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ReadOnly is an immutable snapshot of an instance. Its getters don't lock,
+// so it can be shared between goroutines freely, and its setters return
+// ErrReadOnly.
+type ReadOnly struct {
+	value reflect.Value
+	index map[string]int
+}
+
+// BuildReadOnly builds the builder, if it isn't built yet, and returns a
+// read-only snapshot of its instance. Later changes made through the builder
+// don't affect the snapshot.
+func (b *Builder) BuildReadOnly() (*ReadOnly, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.instance == nil {
+		if err := b.build(); err != nil {
+			return nil, err
+		}
+	}
+
+	b.values.RLock()
+	defer b.values.RUnlock()
+
+	index := make(map[string]int, len(b.index))
+	for name, i := range b.index {
+		index[name] = i
+	}
+
+	return &ReadOnly{value: deepCopy(*b.instance), index: index}, nil
+}
+
+// GetField returns a copy of the named field's value, so that changing it
+// doesn't change the snapshot.
+func (r *ReadOnly) GetField(name string) (any, error) {
+	field, ok := r.field(name)
+	if !ok {
+		return nil, fieldError("get", name, missingField(r.value.Type(), name))
+	}
+
+	return deepCopy(field).Interface(), nil
+}
+
+// GetFieldValue copies the named field's value into the variable value points
+// to, as Builder.GetFieldValue does.
+func (r *ReadOnly) GetFieldValue(name string, value any) error {
+	valueReflect := reflect.ValueOf(value)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return fieldError("get", name, ErrValueMustBePointer)
+	}
+
+	if valueReflect.IsNil() {
+		return fieldError("get", name, ErrValueCannotBeNil)
+	}
+
+	field, ok := r.field(name)
+	if !ok {
+		return fieldError("get", name, missingField(r.value.Type(), name))
+	}
+
+	// Check if the types are compatible
+	if field.Type() != valueReflect.Elem().Type() {
+		return fieldError("get", name, fmt.Errorf(
+			"%w: field type: %s, value type: %s",
+			ErrIncompatibleTypes,
+			field.Type().String(),
+			valueReflect.Elem().Type().String(),
+		))
+	}
+
+	valueReflect.Elem().Set(deepCopy(field))
+
+	return nil
+}
+
+// SetFieldValue always returns ErrReadOnly.
+func (r *ReadOnly) SetFieldValue(name string, _ any) error {
+	return fieldError("set", name, ErrReadOnly)
+}
+
+// Instance returns a copy of the snapshot as a value of the built type.
+func (r *ReadOnly) Instance() any {
+	return deepCopy(r.value).Interface()
+}
+
+func (r *ReadOnly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.value.Interface())
+}
+
+func (r *ReadOnly) field(name string) (reflect.Value, bool) {
+	if i, ok := r.index[name]; ok {
+		return r.value.Field(i), true
+	}
+
+	return fieldByName(r.value, name)
+}
+
+// deepCopy returns a copy of value that shares no pointers, slices or maps
+// with it. Unexported struct fields, channels and functions are shared.
+// Pointers, slices and maps reached twice are copied once, so that cycles,
+// such as a child pointing back at its parent, are copied as cycles.
+func deepCopy(value reflect.Value) reflect.Value {
+	return copyValue(value, make(map[refKey]reflect.Value))
+}
+
+// refKey identifies a pointer, slice or map by its address and type, as a
+// struct and its first field share their address.
+type refKey struct {
+	addr      uintptr
+	length    int
+	valueType reflect.Type
+}
+
+func newRefKey(value reflect.Value) refKey {
+	key := refKey{addr: value.Pointer(), valueType: value.Type()}
+	if value.Kind() == reflect.Slice {
+		key.length = value.Len()
+	}
+
+	return key
+}
+
+func copyValue(value reflect.Value, copies map[refKey]reflect.Value) reflect.Value {
+	copied := reflect.New(value.Type()).Elem()
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return copied
+		}
+
+		key := newRefKey(value)
+		if elem, ok := copies[key]; ok {
+			return elem
+		}
+
+		elem := reflect.New(value.Type().Elem())
+		copies[key] = elem
+		elem.Elem().Set(copyValue(value.Elem(), copies))
+		copied.Set(elem)
+	case reflect.Interface:
+		if value.IsNil() {
+			return copied
+		}
+
+		copied.Set(copyValue(value.Elem(), copies))
+	case reflect.Slice:
+		if value.IsNil() {
+			return copied
+		}
+
+		key := newRefKey(value)
+		if elems, ok := copies[key]; ok {
+			return elems
+		}
+
+		copied.Set(reflect.MakeSlice(value.Type(), value.Len(), value.Len()))
+		copies[key] = copied
+
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copyValue(value.Index(i), copies))
+		}
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copyValue(value.Index(i), copies))
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return copied
+		}
+
+		key := newRefKey(value)
+		if entries, ok := copies[key]; ok {
+			return entries
+		}
+
+		copied.Set(reflect.MakeMapWithSize(value.Type(), value.Len()))
+		copies[key] = copied
+
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), copyValue(iter.Value(), copies))
+		}
+	case reflect.Struct:
+		copied.Set(value)
+
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).PkgPath == "" {
+				copied.Field(i).Set(copyValue(value.Field(i), copies))
+			}
+		}
+	default:
+		copied.Set(value)
+	}
+
+	return copied
+}
@@ -0,0 +1,169 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newReadOnlyBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddFieldWithOptions("Region", "", dynamicstruct.Tags(`json:"region"`), dynamicstruct.Default("eu"))
+	_ = builder.AddField("Hosts", []string{}, `json:"hosts"`)
+	_ = builder.AddField("Limits", map[string]int{}, `json:"limits"`)
+
+	return builder
+}
+
+func TestBuildReadOnly(t *testing.T) {
+	builder := newReadOnlyBuilder(t)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Hosts", []string{"a", "b"})
+	_ = builder.SetFieldValue("Limits", map[string]int{"rps": 10})
+	_ = builder.SetFieldValue("Email", "ops@example.com")
+
+	config, err := builder.BuildReadOnly()
+	if err != nil {
+		t.Fatalf("BuildReadOnly() error = %v", err)
+	}
+
+	// Later changes through the builder don't reach the snapshot
+	_ = builder.SetFieldValue("Region", "us")
+
+	if got, _ := config.GetField("Region"); got != "eu" {
+		t.Errorf("GetField() Region = %v, want eu", got)
+	}
+
+	if got, _ := config.GetField("Email"); got != "ops@example.com" {
+		t.Errorf("GetField() Email = %v, want ops@example.com", got)
+	}
+
+	// Values handed out are copies
+	hosts, _ := config.GetField("Hosts")
+	hosts.([]string)[0] = "changed"
+
+	var limits map[string]int
+	if err := config.GetFieldValue("Limits", &limits); err != nil {
+		t.Fatalf("GetFieldValue() error = %v", err)
+	}
+
+	limits["rps"] = 0
+
+	data, _ := json.Marshal(config)
+
+	want := `{"Email":"ops@example.com","Phone":"","region":"eu","hosts":["a","b"],"limits":{"rps":10}}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	instance := config.Instance()
+	reflect.ValueOf(instance).FieldByName("Hosts").Index(1).SetString("changed")
+
+	if got, _ := config.GetField("Hosts"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("GetField() Hosts = %v, want [a b]", got)
+	}
+
+	if err := config.SetFieldValue("Region", "us"); !errors.Is(err, dynamicstruct.ErrReadOnly) {
+		t.Errorf("SetFieldValue() error = %v, want %v", err, dynamicstruct.ErrReadOnly)
+	}
+
+	t.Run("concurrent_reads", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < 100; j++ {
+					_, _ = config.GetField("Hosts")
+				}
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+type readOnlyNode struct {
+	Name     string
+	Parent   *readOnlyNode
+	Children []*readOnlyNode
+}
+
+func TestBuildReadOnlyCycle(t *testing.T) {
+	root := &readOnlyNode{Name: "root"}
+	root.Children = []*readOnlyNode{{Name: "child", Parent: root}}
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Tree", (*readOnlyNode)(nil))
+	_, _ = builder.Build()
+	_ = builder.SetFieldValue("Tree", root)
+
+	config, err := builder.BuildReadOnly()
+	if err != nil {
+		t.Fatalf("BuildReadOnly() error = %v", err)
+	}
+
+	var tree *readOnlyNode
+	if err := config.GetFieldValue("Tree", &tree); err != nil {
+		t.Fatalf("GetFieldValue() error = %v", err)
+	}
+
+	if tree == root {
+		t.Fatal("GetFieldValue() returned the original tree, want a copy")
+	}
+
+	if child := tree.Children[0]; child.Parent != tree || child.Name != "child" {
+		t.Errorf("copied child = %+v, want its parent to be the copied root", child)
+	}
+}
+
+func TestBuildReadOnlyUnbuilt(t *testing.T) {
+	builder := newReadOnlyBuilder(t)
+
+	config, err := builder.BuildReadOnly()
+	if err != nil {
+		t.Fatalf("BuildReadOnly() error = %v", err)
+	}
+
+	if got, _ := config.GetField("Region"); got != "eu" {
+		t.Errorf("GetField() Region = %v, want eu", got)
+	}
+
+	if _, err := builder.InstanceValue(); err != nil {
+		t.Errorf("InstanceValue() error = %v, want builder to be built", err)
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		value   any
+		wantErr error
+	}{
+		{name: "missing_field", field: "Missing", value: new(string), wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "wrong_type", field: "Region", value: new(int), wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "not_pointer", field: "Region", value: "", wantErr: dynamicstruct.ErrValueMustBePointer},
+		{name: "nil_pointer", field: "Region", value: (*string)(nil), wantErr: dynamicstruct.ErrValueCannotBeNil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := config.GetFieldValue(tt.field, tt.value); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// builderState is a copy of a builder's definition, to roll back edits.
+type builderState struct {
+	fields          map[string]reflect.StructField
+	order           []string
+	meta            map[string]fieldMeta
+	anonymousFields []reflect.StructField
+	nested          map[string]*Builder
+	selfFields      map[string]SelfKind
+	unknownField    string
+	aliases         map[string]string
+	positions       map[string]int
+}
+
+func (b *Builder) saveState() builderState {
+	state := builderState{
+		fields:          make(map[string]reflect.StructField, len(b.fields)),
+		order:           append([]string(nil), b.order...),
+		anonymousFields: append([]reflect.StructField(nil), b.anonymousFields...),
+		unknownField:    b.unknownField,
+	}
+
+	for name, field := range b.fields {
+		state.fields[name] = field
+	}
+
+	if b.nested != nil {
+		state.nested = make(map[string]*Builder, len(b.nested))
+		for name, child := range b.nested {
+			state.nested[name] = child
+		}
+	}
+
+	if b.meta != nil {
+		state.meta = make(map[string]fieldMeta, len(b.meta))
+		for name, meta := range b.meta {
+			state.meta[name] = meta
+		}
+	}
+
+	if b.selfFields != nil {
+		state.selfFields = make(map[string]SelfKind, len(b.selfFields))
+		for name, kind := range b.selfFields {
+			state.selfFields[name] = kind
+		}
+	}
+
+	if b.aliases != nil {
+		state.aliases = make(map[string]string, len(b.aliases))
+		for alias, name := range b.aliases {
+			state.aliases[alias] = name
+		}
+	}
+
+	if b.positions != nil {
+		state.positions = make(map[string]int, len(b.positions))
+		for name, position := range b.positions {
+			state.positions[name] = position
+		}
+	}
+
+	return state
+}
+
+func (b *Builder) restoreState(state builderState) {
+	b.fields = state.fields
+	b.order = state.order
+	b.meta = state.meta
+	b.anonymousFields = state.anonymousFields
+	b.nested = state.nested
+	b.selfFields = state.selfFields
+	b.unknownField = state.unknownField
+	b.aliases = state.aliases
+	b.positions = state.positions
+}
+
+// Rebuild changes the definition of a built builder and builds it again. edit
+// is called with the builder unlocked and unbuilt, so it can add, remove and
+// change fields as before the first Build. The values of the current instance
+// are then copied into the new one with MigrateInstance, and fields that are
+// new or changed type start with their default or zero value.
+//
+// Only the fields edit touches have their tags parsed again. When the edit
+// leaves the fields as they were, the struct type is reused. If edit returns
+// an error, or the new definition can't be built, the builder is left as it
+// was before Rebuild.
+//
+// Rebuild doesn't make the change atomic for other goroutines: while edit
+// runs, the builder reports ErrInstanceNotBuilt.
+func (b *Builder) Rebuild(edit func(b *Builder) error) (any, error) {
+	b.m.Lock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		b.m.Unlock()
+
+		return nil, ErrInstanceNotBuilt
+	}
+
+	old := *b.instance
+	state := b.saveState()
+	b.instance = nil
+	b.index = nil
+	b.m.Unlock()
+
+	err := edit(b)
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	rollback := func() {
+		b.restoreState(state)
+		b.setInstance(old)
+	}
+
+	if err != nil {
+		rollback()
+
+		return nil, err
+	}
+
+	if b.instance != nil {
+		rollback()
+
+		return nil, fmt.Errorf("%w: edit must not build the builder", ErrInstanceAlreadyBuilt)
+	}
+
+	structType := old.Type()
+
+	fields, err := b.resolveStructFields(nil)
+	if err != nil {
+		rollback()
+
+		return nil, err
+	}
+
+	if b.optimizeLayout {
+		optimizeLayout(fields)
+	}
+
+	if !sameStructFields(typeFields(structType), fields) {
+		if structType, err = structOf(fields); err != nil {
+			rollback()
+
+			return nil, err
+		}
+
+		if err := b.checkInterfaces(structType); err != nil {
+			rollback()
+
+			return nil, err
+		}
+	}
+
+	instance := reflect.New(structType).Elem()
+
+	b.applyDefaults(instance)
+	b.applyNestedDefaults(instance)
+	migrateStruct(old, instance)
+	b.setInstance(instance)
+
+	return b.instance.Interface(), nil
+}
+
+func sameStructFields(a, b []reflect.StructField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Type != b[i].Type || a[i].Tag != b[i].Tag || a[i].Anonymous != b[i].Anonymous {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MigrateInstance copies the fields of src, a struct or a pointer to one, into
+// the fields of the same name of the struct dstPtr points to. Fields whose
+// type is the same, or assignable, are copied; others, and fields src doesn't
+// have, are left unchanged. Nested structs of different types are migrated
+// the same way, and embedded fields are matched by name as well.
+//
+// It carries values over from an instance of an old definition to one of a
+// new definition, as Rebuild does.
+func MigrateInstance(src any, dstPtr any) error {
+	dst, err := structPtrValue(dstPtr)
+	if err != nil {
+		return err
+	}
+
+	srcValue, err := structValue(src)
+	if err != nil {
+		return err
+	}
+
+	migrateStruct(srcValue, dst)
+
+	return nil
+}
+
+func migrateStruct(src, dst reflect.Value) {
+	if src.Type() == dst.Type() {
+		dst.Set(src)
+
+		return
+	}
+
+	dstType := dst.Type()
+	srcType := src.Type()
+
+	for i := 0; i < dstType.NumField(); i++ {
+		field := dstType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		srcField, ok := srcType.FieldByName(field.Name)
+		if !ok || len(srcField.Index) != 1 || srcField.PkgPath != "" {
+			continue
+		}
+
+		value := src.Field(srcField.Index[0])
+
+		switch {
+		case value.Type().AssignableTo(field.Type):
+			dst.Field(i).Set(value)
+		case value.Kind() == reflect.Struct && field.Type.Kind() == reflect.Struct:
+			// Nested structs whose definition changed are migrated field by field
+			migrateStruct(value, dst.Field(i))
+		}
+	}
+}
+
+// RebuildReport lists what RebuildWithValues did with the fields of the
+// dropped instance, by field name in the order of its fields.
+type RebuildReport struct {
+	// Dropped are the fields the new definition doesn't have.
+	Dropped []string
+	// Converted are the fields whose type changed and whose value was
+	// converted to the new type.
+	Converted []string
+	// Reset are the fields whose type changed and whose value couldn't be
+	// converted, so they start with their default or zero value.
+	Reset []string
+}
+
+// RebuildWithValues builds a definition changed after Build with
+// WithAllowRebuild, and copies the values of the instance the change dropped
+// into the new one, so records can be edited live while their definition is
+// extended. Fields of the same name keep their values when the type is
+// unchanged or assignable, and are converted when both types are numbers, or
+// when reflect can convert one to the other without turning numbers into
+// strings. Numbers that don't fit the new type are reset. Nested structs are
+// copied field by field as MigrateInstance copies them. New fields start with
+// their default or zero value.
+//
+// The report lists the fields whose values were dropped, converted or reset.
+// An unchanged builder returns ErrInstanceAlreadyBuilt. A builder that was
+// never built is built as Build builds it, with an empty report.
+func (b *Builder) RebuildWithValues() (any, RebuildReport, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.instance != nil {
+		return nil, RebuildReport{}, ErrInstanceAlreadyBuilt
+	}
+
+	dropped := b.dropped
+	b.dropped = nil
+
+	if err := b.build(); err != nil {
+		b.dropped = dropped
+
+		return nil, RebuildReport{}, err
+	}
+
+	var report RebuildReport
+	if dropped != nil {
+		report = evolveStruct(*dropped, *b.instance)
+	}
+
+	return b.instance.Interface(), report, nil
+}
+
+// evolveStruct copies the fields of src into the fields of the same name of
+// dst as RebuildWithValues does, and reports what it did.
+func evolveStruct(src, dst reflect.Value) RebuildReport {
+	var report RebuildReport
+
+	srcType := src.Type()
+	dstType := dst.Type()
+
+	for i := 0; i < srcType.NumField(); i++ {
+		srcField := srcType.Field(i)
+		if srcField.PkgPath != "" {
+			continue
+		}
+
+		field, ok := dstType.FieldByName(srcField.Name)
+		if !ok || len(field.Index) != 1 {
+			report.Dropped = append(report.Dropped, srcField.Name)
+
+			continue
+		}
+
+		value := src.Field(i)
+		target := dst.Field(field.Index[0])
+
+		switch {
+		case value.Type() == field.Type:
+			target.Set(value)
+		case value.Type().AssignableTo(field.Type):
+			target.Set(value)
+			report.Converted = append(report.Converted, field.Name)
+		case value.Kind() == reflect.Struct && field.Type.Kind() == reflect.Struct:
+			migrateStruct(value, target)
+			report.Converted = append(report.Converted, field.Name)
+		default:
+			if converted, ok := convertValue(field.Type, value); ok {
+				target.Set(converted)
+				report.Converted = append(report.Converted, field.Name)
+			} else {
+				report.Reset = append(report.Reset, field.Name)
+			}
+		}
+	}
+
+	return report
+}
+
+// convertValue converts value to fieldType: numbers only to numeric types
+// they fit, since reflect turns numbers into strings as runes, and other
+// values as reflect converts them, except slices to arrays, which panic when
+// the slice is too short.
+func convertValue(fieldType reflect.Type, value reflect.Value) (reflect.Value, bool) {
+	if isNumberKind(value.Kind()) {
+		converted, ok, err := convertNumber(fieldType, value)
+
+		return converted, ok && err == nil
+	}
+
+	if value.Kind() == reflect.Slice && (fieldType.Kind() == reflect.Array || fieldType.Kind() == reflect.Ptr) {
+		return reflect.Value{}, false
+	}
+
+	if !value.Type().ConvertibleTo(fieldType) {
+		return reflect.Value{}, false
+	}
+
+	return value.Convert(fieldType), true
+}
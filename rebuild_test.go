@@ -0,0 +1,290 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newRebuildBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{})
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddField("Age", 0)
+	_ = builder.AddField("Note", "")
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Name", "Alice")
+	_ = builder.SetFieldValue("Age", 30)
+	_ = builder.SetFieldValue("Note", "hi")
+	_ = builder.SetFieldValue("Email", "alice@example.com")
+
+	return builder
+}
+
+func TestRebuild(t *testing.T) {
+	builder := newRebuildBuilder(t)
+
+	instance, err := builder.Rebuild(func(b *dynamicstruct.Builder) error {
+		if err := b.RemoveField("Age"); err != nil {
+			return err
+		}
+
+		if err := b.RemoveField("Note"); err != nil {
+			return err
+		}
+
+		if err := b.AddField("Note", 0); err != nil {
+			return err
+		}
+
+		return b.AddFieldWithOptions("Plan", "", dynamicstruct.Default("free"))
+	})
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+
+	value := reflect.ValueOf(instance)
+
+	want := map[string]any{"Name": "Alice", "Email": "alice@example.com", "Note": 0, "Plan": "free"}
+	for name, wantValue := range want {
+		if got := value.FieldByName(name).Interface(); got != wantValue {
+			t.Errorf("Rebuild() %s = %v, want %v", name, got, wantValue)
+		}
+	}
+
+	if _, ok := value.Type().FieldByName("Age"); ok {
+		t.Error("Rebuild() kept removed field Age")
+	}
+
+	if got, _ := builder.GetField("Name"); got != "Alice" {
+		t.Errorf("GetField() = %v, want Alice", got)
+	}
+}
+
+func TestRebuildUnchanged(t *testing.T) {
+	builder := newRebuildBuilder(t)
+
+	before, _ := builder.InstanceValue()
+
+	instance, err := builder.Rebuild(func(b *dynamicstruct.Builder) error { return nil })
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+
+	if reflect.TypeOf(instance) != before.Type() {
+		t.Errorf("Rebuild() type = %s, want %s", reflect.TypeOf(instance), before.Type())
+	}
+
+	if !reflect.DeepEqual(instance, before.Interface()) {
+		t.Errorf("Rebuild() = %+v, want %+v", instance, before.Interface())
+	}
+}
+
+func TestRebuildErrors(t *testing.T) {
+	errEdit := errors.New("edit failed")
+
+	tests := []struct {
+		name    string
+		edit    func(b *dynamicstruct.Builder) error
+		wantErr error
+	}{
+		{
+			name: "edit_error_rolls_back",
+			edit: func(b *dynamicstruct.Builder) error {
+				_ = b.RemoveField("Name")
+				_ = b.AddField("Extra", 0)
+
+				return errEdit
+			},
+			wantErr: errEdit,
+		},
+		{
+			name: "edit_builds",
+			edit: func(b *dynamicstruct.Builder) error {
+				_, err := b.Build()
+
+				return err
+			},
+			wantErr: dynamicstruct.ErrInstanceAlreadyBuilt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newRebuildBuilder(t)
+			before, _ := builder.InstanceValue()
+
+			_, err := builder.Rebuild(tt.edit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rebuild() error = %v, want %v", err, tt.wantErr)
+			}
+
+			after, err := builder.InstanceValue()
+			if err != nil {
+				t.Fatalf("InstanceValue() error = %v", err)
+			}
+
+			if after.Type() != before.Type() {
+				t.Errorf("Rebuild() left type %s, want %s", after.Type(), before.Type())
+			}
+
+			if got, _ := builder.GetField("Name"); got != "Alice" {
+				t.Errorf("GetField() = %v, want Alice", got)
+			}
+		})
+	}
+
+	t.Run("not_built", func(t *testing.T) {
+		_, err := dynamicstruct.New().Rebuild(func(b *dynamicstruct.Builder) error { return nil })
+		if !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("Rebuild() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
+
+func TestMigrateInstance(t *testing.T) {
+	type source struct {
+		Name string
+		Age  int
+		Tags []string
+	}
+
+	type target struct {
+		Name  string
+		Age   int64
+		Tags  []string
+		Extra bool
+	}
+
+	dst := target{Age: 7}
+	if err := dynamicstruct.MigrateInstance(source{Name: "Alice", Age: 30, Tags: []string{"a"}}, &dst); err != nil {
+		t.Fatalf("MigrateInstance() error = %v", err)
+	}
+
+	want := target{Name: "Alice", Age: 7, Tags: []string{"a"}}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("MigrateInstance() = %+v, want %+v", dst, want)
+	}
+
+	if err := dynamicstruct.MigrateInstance(source{}, dst); !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+		t.Errorf("MigrateInstance() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+	}
+}
+
+func TestRebuildWithValues(t *testing.T) {
+	type Label string
+
+	builder := dynamicstruct.New(dynamicstruct.WithAllowRebuild())
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Port", 0)
+	_ = builder.AddField("Ratio", 1.5)
+	_ = builder.AddField("Count", int64(0))
+	_ = builder.AddField("Label", "")
+	_ = builder.AddField("Legacy", true)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Name", "api")
+	_ = builder.SetFieldValue("Port", 8080)
+	_ = builder.SetFieldValue("Ratio", 2.5)
+	_ = builder.SetFieldValue("Count", int64(300))
+	_ = builder.SetFieldValue("Label", "blue")
+
+	_ = builder.ReplaceField("Port", int64(0))
+	_ = builder.ReplaceField("Ratio", 0)
+	_ = builder.ReplaceField("Count", int8(0))
+	_ = builder.ReplaceField("Label", Label(""))
+	_ = builder.RemoveField("Legacy")
+	_ = builder.AddFieldWithOptions("Region", "", dynamicstruct.Default("eu"))
+
+	instance, report, err := builder.RebuildWithValues()
+	if err != nil {
+		t.Fatalf("RebuildWithValues() error = %v", err)
+	}
+
+	want := dynamicstruct.RebuildReport{
+		Dropped:   []string{"Legacy"},
+		Converted: []string{"Port", "Label"},
+		Reset:     []string{"Ratio", "Count"},
+	}
+
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("RebuildWithValues() report = %+v, want %+v", report, want)
+	}
+
+	value := reflect.ValueOf(instance)
+	checks := map[string]any{
+		"Name":   "api",
+		"Port":   int64(8080),
+		"Ratio":  0,
+		"Count":  int8(0),
+		"Label":  Label("blue"),
+		"Region": "eu",
+	}
+
+	for name, wantValue := range checks {
+		if got := value.FieldByName(name).Interface(); got != wantValue {
+			t.Errorf("%s = %#v, want %#v", name, got, wantValue)
+		}
+	}
+}
+
+func TestRebuildWithValuesErrors(t *testing.T) {
+	t.Run("unchanged", func(t *testing.T) {
+		builder := dynamicstruct.New(dynamicstruct.WithAllowRebuild())
+		_ = builder.AddField("Name", "")
+		_, _ = builder.Build()
+
+		if _, _, err := builder.RebuildWithValues(); !errors.Is(err, dynamicstruct.ErrInstanceAlreadyBuilt) {
+			t.Errorf("RebuildWithValues() error = %v, want %v", err, dynamicstruct.ErrInstanceAlreadyBuilt)
+		}
+	})
+
+	t.Run("never_built", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Name", "")
+
+		instance, report, err := builder.RebuildWithValues()
+		if err != nil {
+			t.Fatalf("RebuildWithValues() error = %v", err)
+		}
+
+		if instance == nil || !reflect.DeepEqual(report, dynamicstruct.RebuildReport{}) {
+			t.Errorf("RebuildWithValues() = %v, %+v, want an instance and an empty report", instance, report)
+		}
+	})
+
+	t.Run("failed_build_keeps_values", func(t *testing.T) {
+		builder := dynamicstruct.New(dynamicstruct.WithAllowRebuild())
+		_ = builder.AddField("Name", "")
+		_, _ = builder.Build()
+		_ = builder.SetFieldValue("Name", "api")
+
+		_ = builder.AddField("Self", builder)
+
+		if _, _, err := builder.RebuildWithValues(); !errors.Is(err, dynamicstruct.ErrCircularReference) {
+			t.Fatalf("RebuildWithValues() error = %v, want %v", err, dynamicstruct.ErrCircularReference)
+		}
+
+		_ = builder.RemoveField("Self")
+
+		instance, _, err := builder.RebuildWithValues()
+		if err != nil {
+			t.Fatalf("RebuildWithValues() error = %v", err)
+		}
+
+		if got := reflect.ValueOf(instance).FieldByName("Name").Interface(); got != "api" {
+			t.Errorf("Name = %v, want api", got)
+		}
+	})
+}
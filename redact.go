@@ -0,0 +1,180 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"strconv"
+)
+
+const sensitiveTagKey = "sensitive"
+
+// Redacted replaces the value of sensitive string fields in redacted copies.
+const Redacted = "[REDACTED]"
+
+// Sensitive returns a `sensitive:"true"` tag, marking a field such as a
+// password or a token to be masked by Redact, for use as one of the tags of
+// AddField.
+func Sensitive() string {
+	return sensitiveTagKey + `:"true"`
+}
+
+// Redact returns a copy of instance, a struct or a pointer to one, with the
+// fields tagged `sensitive:"true"` masked: strings, and pointers to them, are
+// replaced by Redacted, and other types are set to their zero value. Structs
+// nested in fields, slices and maps are redacted too. A pointer is returned
+// for a pointer. Values other than structs are returned as they are.
+//
+// The copy shares nothing with instance but unexported fields, channels and
+// functions, so it can be logged or encoded while instance keeps changing.
+// Cyclic instances, such as trees of self fields linking back to their
+// parent, are copied with the same cycles.
+func Redact(instance any) any {
+	value := reflect.ValueOf(instance)
+	if !value.IsValid() || reflect.Indirect(value).Kind() != reflect.Struct {
+		return instance
+	}
+
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return instance
+	}
+
+	// Instances without sensitive fields aren't copied
+	if !hasSensitiveFields(value.Type(), map[reflect.Type]bool{}) {
+		return instance
+	}
+
+	copied := deepCopy(value)
+	redactValue(copied, make(map[refKey]bool))
+
+	return copied.Interface()
+}
+
+// hasSensitiveFields reports whether values of t can hold sensitive fields.
+// Interfaces can hold anything, so they are assumed to.
+func hasSensitiveFields(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasSensitiveFields(t.Elem(), visited)
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if isSensitive(field) || hasSensitiveFields(field.Type, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isSensitive(field reflect.StructField) bool {
+	sensitive, err := strconv.ParseBool(field.Tag.Get(sensitiveTagKey))
+
+	return err == nil && sensitive
+}
+
+// redactValue masks the sensitive fields of the structs in value, which must
+// be settable or reached through a pointer. visited holds the pointers, slices
+// and maps already redacted, so that cyclic values are redacted once.
+func redactValue(value reflect.Value, visited map[refKey]bool) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+
+		if value.Kind() == reflect.Interface {
+			// The value an interface holds isn't settable, so it is
+			// redacted as a copy
+			elem := reflect.New(value.Elem().Type()).Elem()
+			elem.Set(value.Elem())
+			redactValue(elem, visited)
+
+			if value.CanSet() {
+				value.Set(elem)
+			}
+
+			return
+		}
+
+		if revisited(value, visited) {
+			return
+		}
+
+		redactValue(value.Elem(), visited)
+	case reflect.Struct:
+		structType := value.Type()
+
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if isSensitive(field) {
+				maskField(value.Field(i))
+
+				continue
+			}
+
+			redactValue(value.Field(i), visited)
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && revisited(value, visited) {
+			return
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			redactValue(value.Index(i), visited)
+		}
+	case reflect.Map:
+		if revisited(value, visited) {
+			return
+		}
+
+		iter := value.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			redactValue(elem, visited)
+			value.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
+
+// revisited reports whether the pointer, slice or map value is in visited, and
+// adds it when it isn't.
+func revisited(value reflect.Value, visited map[refKey]bool) bool {
+	key := newRefKey(value)
+	if visited[key] {
+		return true
+	}
+
+	visited[key] = true
+
+	return false
+}
+
+func maskField(field reflect.Value) {
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(Redacted)
+	case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.String && !field.IsNil():
+		masked := reflect.New(field.Type().Elem())
+		masked.Elem().SetString(Redacted)
+		field.Set(masked)
+	default:
+		field.Set(reflect.Zero(field.Type()))
+	}
+}
@@ -0,0 +1,157 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+type CredentialTest struct {
+	User     string
+	Password string `sensitive:"true"`
+}
+
+func newRedactBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddField("Token", "", `json:"token"`, dynamicstruct.Sensitive())
+	_ = builder.AddField("PIN", 0, `json:"pin"`, dynamicstruct.Sensitive())
+	_ = builder.AddOptionalField("Secret", "", `json:"secret"`, dynamicstruct.Sensitive())
+	_ = builder.AddField("Credentials", []CredentialTest{}, `json:"credentials"`)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestRedact(t *testing.T) {
+	builder := newRedactBuilder(t)
+
+	instancePtr, _ := builder.NewInstance()
+	data := `{"name":"svc","token":"abc","pin":1234,"secret":"s3cr3t","credentials":[{"User":"root","Password":"hunter2"}]}`
+	_ = json.Unmarshal([]byte(data), instancePtr)
+
+	redacted := dynamicstruct.Redact(instancePtr)
+
+	if reflect.TypeOf(redacted) != reflect.TypeOf(instancePtr) {
+		t.Fatalf("Redact() type = %T, want %T", redacted, instancePtr)
+	}
+
+	got, _ := json.Marshal(redacted)
+	want := `{"name":"svc","token":"[REDACTED]","pin":0,"secret":"[REDACTED]","credentials":[{"User":"root","Password":"[REDACTED]"}]}`
+
+	if string(got) != want {
+		t.Errorf("Marshal(Redact()) = %s, want %s", got, want)
+	}
+
+	// The instance itself is unchanged
+	if original, _ := json.Marshal(instancePtr); string(original) != data {
+		t.Errorf("Marshal(instance) = %s, want %s", original, data)
+	}
+
+	t.Run("struct_value", func(t *testing.T) {
+		redacted := dynamicstruct.Redact(CredentialTest{User: "root", Password: "hunter2"})
+		if want := (CredentialTest{User: "root", Password: dynamicstruct.Redacted}); redacted != want {
+			t.Errorf("Redact() = %+v, want %+v", redacted, want)
+		}
+	})
+
+	t.Run("no_sensitive_fields", func(t *testing.T) {
+		person := &PersonTest{Name: "Alice"}
+		if redacted := dynamicstruct.Redact(person); redacted != person {
+			t.Errorf("Redact() = %p, want the instance %p", redacted, person)
+		}
+	})
+
+	t.Run("not_struct", func(t *testing.T) {
+		if redacted := dynamicstruct.Redact("token"); redacted != "token" {
+			t.Errorf("Redact() = %v, want %v", redacted, "token")
+		}
+	})
+}
+
+func TestRedactCycle(t *testing.T) {
+	builder := dynamicstruct.New()
+	if err := builder.AddField("Token", "", dynamicstruct.Sensitive()); err != nil {
+		t.Fatalf("AddField() error = %v", err)
+	}
+
+	if err := builder.AddSelfField("Parent", dynamicstruct.PtrToSelf); err != nil {
+		t.Fatalf("AddSelfField() error = %v", err)
+	}
+
+	if err := builder.AddSelfField("Children", dynamicstruct.SliceOfSelf); err != nil {
+		t.Fatalf("AddSelfField() error = %v", err)
+	}
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	root, _ := builder.NewInstance()
+	child, _ := builder.NewInstance()
+
+	rootValue, childValue := reflect.ValueOf(root).Elem(), reflect.ValueOf(child).Elem()
+	rootValue.FieldByName("Token").SetString("root-token")
+	rootValue.FieldByName("Children").Set(reflect.ValueOf([]any{child}))
+	childValue.FieldByName("Token").SetString("child-token")
+	childValue.FieldByName("Parent").Set(reflect.ValueOf(root))
+
+	redacted := reflect.ValueOf(dynamicstruct.Redact(root)).Elem()
+	redactedChild := reflect.ValueOf(redacted.FieldByName("Children").Interface().([]any)[0]).Elem()
+
+	if got := redacted.FieldByName("Token").String(); got != dynamicstruct.Redacted {
+		t.Errorf("root Token = %q, want %q", got, dynamicstruct.Redacted)
+	}
+
+	if got := redactedChild.FieldByName("Token").String(); got != dynamicstruct.Redacted {
+		t.Errorf("child Token = %q, want %q", got, dynamicstruct.Redacted)
+	}
+
+	// The cycle is kept in the copy, not followed into the original
+	if parent := redactedChild.FieldByName("Parent").Interface(); parent != redacted.Addr().Interface() {
+		t.Errorf("child Parent = %p, want the redacted root", parent)
+	}
+
+	if got := rootValue.FieldByName("Token").String(); got != "root-token" {
+		t.Errorf("original root Token = %q, want it unchanged", got)
+	}
+}
+
+func TestProxyRedacts(t *testing.T) {
+	builder := newRedactBuilder(t)
+	_ = builder.SetFieldValue("Token", "abc")
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	proxy, err := builder.Wrap(instancePtr)
+	if err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	if s := fmt.Sprint(proxy); strings.Contains(s, "abc") || !strings.Contains(s, dynamicstruct.Redacted) {
+		t.Errorf("String() = %s, want the token redacted", s)
+	}
+
+	if data, _ := json.Marshal(proxy); strings.Contains(string(data), "abc") {
+		t.Errorf("MarshalJSON() = %s, want the token redacted", data)
+	}
+
+	builder.WithStringer(func(inst any) string {
+		return reflect.ValueOf(inst).Elem().FieldByName("Token").String()
+	})
+
+	proxy, _ = builder.Wrap(instancePtr)
+
+	if s := proxy.String(); s != dynamicstruct.Redacted {
+		t.Errorf("String() with a stringer = %s, want %s", s, dynamicstruct.Redacted)
+	}
+}
@@ -0,0 +1,202 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const refTagKey = "ref"
+
+// parseRefTag parses a `ref:"Customer"` tag into the name of the schema the
+// field references.
+func parseRefTag(tag string) (string, error) {
+	target := strings.TrimSpace(tag)
+	if target == "" || strings.ContainsAny(target, ", ") {
+		return "", fmt.Errorf("%w: ref %q must name a single schema", ErrInvalidTag, tag)
+	}
+
+	return target, nil
+}
+
+// Registry holds named builders, the schemas of a tenant or an application,
+// which may reference each other through relation fields. A relation field
+// has a `ref:"Customer"` tag naming the schema it refers to, and typically
+// holds the key of the referenced record.
+type Registry struct {
+	builders map[string]*Builder
+	order    []string
+	m        sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		builders: make(map[string]*Builder),
+	}
+}
+
+func (r *Registry) Register(name string, builder *Builder) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty schema name", ErrInvalidName)
+	}
+
+	if builder == nil {
+		return ErrValueCannotBeNil
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.builders[name]; ok {
+		return fmt.Errorf("%w: %s", ErrSchemaAlreadyExists, name)
+	}
+
+	r.builders[name] = builder
+	r.order = append(r.order, name)
+
+	return nil
+}
+
+func (r *Registry) Get(name string) (*Builder, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	builder, ok := r.builders[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, name)
+	}
+
+	return builder, nil
+}
+
+// Names returns the names of the registered schemas in registration order.
+func (r *Registry) Names() []string {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return append([]string(nil), r.order...)
+}
+
+type schemaRef struct {
+	field    string
+	target   string
+	required bool
+}
+
+// refs returns the relation fields of the builder in field order.
+func (b *Builder) refs() []schemaRef {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	var refs []schemaRef
+
+	for _, name := range b.order {
+		tag, ok := b.fields[name].Tag.Lookup(refTagKey)
+		if !ok {
+			continue
+		}
+
+		// Tags are checked when the field is added
+		target, _ := parseRefTag(tag)
+		refs = append(refs, schemaRef{field: name, target: target, required: b.meta[name].required})
+	}
+
+	return refs
+}
+
+// Validate checks the references between the registered schemas. It reports
+// relation fields whose target schema isn't registered, and cycles of required
+// references, which no set of records can satisfy since each record needs
+// another to exist first. Cycles through optional references are allowed.
+// All problems are reported in one error wrapping ErrInvalidReference.
+func (r *Registry) Validate() error {
+	r.m.RLock()
+
+	names := append([]string(nil), r.order...)
+	refs := make(map[string][]schemaRef, len(names))
+
+	for _, name := range names {
+		refs[name] = r.builders[name].refs()
+	}
+
+	r.m.RUnlock()
+
+	var problems []string
+
+	for _, name := range names {
+		for _, ref := range refs[name] {
+			if _, ok := refs[ref.target]; !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s references unknown schema %s", name, ref.field, ref.target))
+			}
+		}
+	}
+
+	for _, cycle := range requiredCycles(names, refs) {
+		problems = append(problems, "required references form a cycle: "+strings.Join(cycle, " -> "))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidReference, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// requiredCycles returns the cycles formed by required references, each as
+// the path of schema.field steps that leads back to its first schema.
+func requiredCycles(names []string, refs map[string][]schemaRef) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[string]int, len(names))
+
+	var (
+		cycles [][]string
+		path   []string
+		stack  []string
+		visit  func(name string)
+	)
+
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, ref := range refs[name] {
+			if _, ok := refs[ref.target]; !ok || !ref.required {
+				continue
+			}
+
+			path = append(path, name+"."+ref.field)
+
+			switch state[ref.target] {
+			case unvisited:
+				visit(ref.target)
+			case visiting:
+				// The cycle starts where the target is on the stack
+				start := len(stack) - 1
+				for stack[start] != ref.target {
+					start--
+				}
+
+				cycle := append([]string(nil), path[start:]...)
+				cycles = append(cycles, append(cycle, ref.target))
+			}
+
+			path = path[:len(path)-1]
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return cycles
+}
@@ -0,0 +1,165 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	registry := dynamicstruct.NewRegistry()
+	customer := dynamicstruct.New()
+
+	if err := registry.Register("Customer", customer); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		schema  string
+		builder *dynamicstruct.Builder
+		wantErr error
+	}{
+		{name: "duplicate_name", schema: "Customer", builder: dynamicstruct.New(), wantErr: dynamicstruct.ErrSchemaAlreadyExists},
+		{name: "empty_name", schema: "", builder: dynamicstruct.New(), wantErr: dynamicstruct.ErrInvalidName},
+		{name: "nil_builder", schema: "Order", builder: nil, wantErr: dynamicstruct.ErrValueCannotBeNil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Register(tt.schema, tt.builder)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got, err := registry.Get("Customer"); err != nil || got != customer {
+		t.Errorf("Get() = %p, %v, want %p, nil", got, err, customer)
+	}
+
+	if _, err := registry.Get("Order"); !errors.Is(err, dynamicstruct.ErrSchemaNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, dynamicstruct.ErrSchemaNotFound)
+	}
+
+	if got := registry.Names(); len(got) != 1 || got[0] != "Customer" {
+		t.Errorf("Names() = %v, want [Customer]", got)
+	}
+}
+
+func TestRegistryValidate(t *testing.T) {
+	type field struct {
+		name     string
+		ref      string
+		required bool
+	}
+
+	tests := []struct {
+		name    string
+		schemas map[string][]field
+		order   []string
+		wantErr []string
+	}{
+		{
+			name: "valid_references",
+			schemas: map[string][]field{
+				"Customer": {{name: "ID"}},
+				"Order":    {{name: "CustomerID", ref: "Customer", required: true}},
+			},
+			order: []string{"Customer", "Order"},
+		},
+		{
+			name: "missing_target",
+			schemas: map[string][]field{
+				"Order": {{name: "CustomerID", ref: "Customr"}},
+			},
+			order:   []string{"Order"},
+			wantErr: []string{"Order.CustomerID references unknown schema Customr"},
+		},
+		{
+			name: "optional_cycle_allowed",
+			schemas: map[string][]field{
+				"Employee":   {{name: "DepartmentID", ref: "Department", required: true}},
+				"Department": {{name: "ManagerID", ref: "Employee"}},
+			},
+			order: []string{"Employee", "Department"},
+		},
+		{
+			name: "required_cycle",
+			schemas: map[string][]field{
+				"A": {{name: "BID", ref: "B", required: true}},
+				"B": {{name: "CID", ref: "C", required: true}},
+				"C": {{name: "AID", ref: "A", required: true}},
+			},
+			order:   []string{"A", "B", "C"},
+			wantErr: []string{"required references form a cycle: A.BID -> B.CID -> C.AID -> A"},
+		},
+		{
+			name: "required_self_reference",
+			schemas: map[string][]field{
+				"Node": {{name: "ParentID", ref: "Node", required: true}},
+			},
+			order:   []string{"Node"},
+			wantErr: []string{"required references form a cycle: Node.ParentID -> Node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := dynamicstruct.NewRegistry()
+
+			for _, name := range tt.order {
+				builder := dynamicstruct.New()
+
+				for _, f := range tt.schemas[name] {
+					var opts []dynamicstruct.FieldOption
+					if f.ref != "" {
+						opts = append(opts, dynamicstruct.Tags(`ref:"`+f.ref+`"`))
+					}
+
+					if f.required {
+						opts = append(opts, dynamicstruct.Required())
+					}
+
+					if err := builder.AddFieldWithOptions(f.name, int64(0), opts...); err != nil {
+						t.Fatalf("AddFieldWithOptions() error = %v", err)
+					}
+				}
+
+				if err := registry.Register(name, builder); err != nil {
+					t.Fatalf("Register() error = %v", err)
+				}
+			}
+
+			err := registry.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, dynamicstruct.ErrInvalidReference) {
+				t.Fatalf("Validate() error = %v, want %v", err, dynamicstruct.ErrInvalidReference)
+			}
+
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRefTagValidation(t *testing.T) {
+	for _, tag := range []string{`ref:""`, `ref:"A,B"`} {
+		builder := dynamicstruct.New()
+		if err := builder.AddField("ID", 0, tag); !errors.Is(err, dynamicstruct.ErrInvalidTag) {
+			t.Errorf("AddField(%s) error = %v, want %v", tag, err, dynamicstruct.ErrInvalidTag)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package dynamicstruct
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RegistrySchema is a schema as a schema registry returns it.
+type RegistrySchema struct {
+	// Type is AVRO, JSON or PROTOBUF. Registries leave it empty for Avro.
+	Type   string
+	Schema string
+}
+
+// SchemaRegistryClient looks up the schemas messages were written with by
+// the id they carry. Wrap the registry client in use to implement it.
+type SchemaRegistryClient interface {
+	SchemaByID(id int) (RegistrySchema, error)
+}
+
+// SchemaDecodeFunc decodes the payload of a message, the bytes after the
+// schema id, into the instance instancePtr points to.
+type SchemaDecodeFunc func(payload []byte, instancePtr any) error
+
+// SchemaFormat compiles a registry schema into an unbuilt builder for the
+// struct its messages decode into, and the function decoding them.
+type SchemaFormat func(schema string) (*Builder, SchemaDecodeFunc, error)
+
+// RegistryCodec decodes messages in the Confluent wire format (a zero magic
+// byte and the big-endian id of the writer schema, followed by the payload)
+// into instances of structs built from the writer schemas. Schemas are fetched
+// from the registry once per id, and the struct built for them is cached, so
+// it can back a consumer deserializer for topics whose schemas evolve.
+//
+// Avro and JSON Schema are supported out of the box; other formats are added
+// with WithSchemaFormat, as Protobuf is with the format of the protostruct
+// module. It is safe for concurrent use.
+type RegistryCodec struct {
+	client  SchemaRegistryClient
+	formats map[string]SchemaFormat
+
+	m       sync.RWMutex
+	schemas map[int]*registryEntry
+}
+
+// registryEntry is the compiled schema of an id. ready is closed once builder
+// and decode, or err, are set, so that lookups of an id being fetched wait for
+// that fetch instead of starting another.
+type registryEntry struct {
+	ready   chan struct{}
+	builder *Builder
+	decode  SchemaDecodeFunc
+	err     error
+}
+
+// RegistryCodecOption configures a RegistryCodec.
+type RegistryCodecOption func(*RegistryCodec)
+
+// WithSchemaFormat sets the format schemas of schemaType, such as PROTOBUF,
+// are compiled with, replacing the built-in one for AVRO or JSON.
+func WithSchemaFormat(schemaType string, format SchemaFormat) RegistryCodecOption {
+	return func(c *RegistryCodec) {
+		c.formats[strings.ToUpper(schemaType)] = format
+	}
+}
+
+// NewRegistryCodec returns a codec looking up writer schemas with client.
+func NewRegistryCodec(client SchemaRegistryClient, opts ...RegistryCodecOption) *RegistryCodec {
+	c := &RegistryCodec{
+		client: client,
+		formats: map[string]SchemaFormat{
+			"AVRO": avroFormat,
+			"JSON": jsonSchemaFormat,
+		},
+		schemas: map[int]*registryEntry{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Deserialize decodes a message of topic and returns a pointer to a new
+// instance of the struct built for its writer schema. Instances of messages
+// written with the same schema share their type.
+//
+// Messages that aren't in the wire format or don't match their schema return
+// ErrInvalidMessage, and schemas that can't be compiled ErrInvalidSchema.
+// Errors of the registry client are returned wrapped.
+func (c *RegistryCodec) Deserialize(topic string, data []byte) (any, error) {
+	if len(data) < 5 || data[0] != 0 {
+		return nil, fmt.Errorf("%w: topic %s: not in the schema registry wire format", ErrInvalidMessage, topic)
+	}
+
+	id := int(binary.BigEndian.Uint32(data[1:5]))
+
+	entry, err := c.entry(id)
+	if err != nil {
+		return nil, fmt.Errorf("topic %s: schema %d: %w", topic, id, err)
+	}
+
+	instancePtr, err := entry.builder.NewInstance()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entry.decode(data[5:], instancePtr); err != nil {
+		return nil, fmt.Errorf("topic %s: schema %d: %w", topic, id, err)
+	}
+
+	return instancePtr, nil
+}
+
+// entry returns the compiled schema for id, fetching and compiling it on
+// first use. The registry is called without holding the lock, so a slow
+// lookup only delays the messages of its own id.
+func (c *RegistryCodec) entry(id int) (*registryEntry, error) {
+	c.m.RLock()
+	entry, ok := c.schemas[id]
+	c.m.RUnlock()
+
+	if !ok {
+		c.m.Lock()
+
+		entry, ok = c.schemas[id]
+		if !ok {
+			entry = &registryEntry{ready: make(chan struct{})}
+			c.schemas[id] = entry
+		}
+
+		c.m.Unlock()
+
+		if !ok {
+			c.fill(id, entry)
+		}
+	}
+
+	<-entry.ready
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	return entry, nil
+}
+
+// fill compiles the schema for id into entry and marks it ready. It always
+// does, even when compiling panics, so that lookups waiting for the entry
+// never block forever.
+func (c *RegistryCodec) fill(id int, entry *registryEntry) {
+	defer close(entry.ready)
+
+	defer func() {
+		if r := recover(); r != nil {
+			entry.builder, entry.decode = nil, nil
+			entry.err = fmt.Errorf("%w: compiling schema panicked: %v", ErrInvalidSchema, r)
+		}
+
+		// Failures aren't cached, so the next message retries
+		if entry.err != nil {
+			c.m.Lock()
+			delete(c.schemas, id)
+			c.m.Unlock()
+		}
+	}()
+
+	entry.builder, entry.decode, entry.err = c.compile(id)
+}
+
+// compile fetches the schema for id and builds its struct.
+func (c *RegistryCodec) compile(id int) (*Builder, SchemaDecodeFunc, error) {
+	schema, err := c.client.SchemaByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schemaType := strings.ToUpper(schema.Type)
+	if schemaType == "" {
+		schemaType = "AVRO"
+	}
+
+	format, ok := c.formats[schemaType]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unsupported schema type %s", ErrInvalidSchema, schemaType)
+	}
+
+	builder, decode, err := format(schema.Schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := builder.Build(); err != nil && !errors.Is(err, ErrInstanceAlreadyBuilt) {
+		return nil, nil, err
+	}
+
+	return builder, decode, nil
+}
+
+func avroFormat(schema string) (*Builder, SchemaDecodeFunc, error) {
+	s, err := parseAvroSchema([]byte(schema))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.kind != "record" {
+		return nil, nil, fmt.Errorf("%w: Avro schema isn't a record", ErrInvalidSchema)
+	}
+
+	decode := func(payload []byte, instancePtr any) error {
+		return decodeAvro(s, payload, instancePtr)
+	}
+
+	return s.builder, decode, nil
+}
+
+func jsonSchemaFormat(schema string) (*Builder, SchemaDecodeFunc, error) {
+	builder, err := fromJSONSchema([]byte(schema))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decode := func(payload []byte, instancePtr any) error {
+		if err := json.Unmarshal(payload, instancePtr); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMessage, err.Error())
+		}
+
+		return nil
+	}
+
+	return builder, decode, nil
+}
@@ -0,0 +1,218 @@
+package dynamicstruct_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+type registryClientTest struct {
+	schemas map[int]dynamicstruct.RegistrySchema
+	lookups int
+}
+
+func (c *registryClientTest) SchemaByID(id int) (dynamicstruct.RegistrySchema, error) {
+	c.lookups++
+
+	schema, ok := c.schemas[id]
+	if !ok {
+		return dynamicstruct.RegistrySchema{}, errors.New("schema not found")
+	}
+
+	return schema, nil
+}
+
+// wireMessage frames payload in the schema registry wire format.
+func wireMessage(id int, payload []byte) []byte {
+	data := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(data[1:], uint32(id))
+
+	return append(data, payload...)
+}
+
+func TestRegistryCodecJSON(t *testing.T) {
+	client := &registryClientTest{schemas: map[int]dynamicstruct.RegistrySchema{
+		1: {Type: "JSON", Schema: `{
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"address": {"$ref": "#/$defs/Address"}
+			},
+			"$defs": {
+				"Address": {"type": "object", "properties": {"city": {"type": "string"}}}
+			}
+		}`},
+	}}
+
+	codec := dynamicstruct.NewRegistryCodec(client)
+
+	first, err := codec.Deserialize("users", wireMessage(1, []byte(`{"name":"Alice","address":{"city":"Berlin"}}`)))
+	if err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	value := reflect.ValueOf(first).Elem()
+	if got := value.FieldByName("Name").String(); got != "Alice" {
+		t.Errorf("Name = %q, want %q", got, "Alice")
+	}
+
+	if got := value.FieldByName("Address").FieldByName("City").String(); got != "Berlin" {
+		t.Errorf("Address.City = %q, want %q", got, "Berlin")
+	}
+
+	second, err := codec.Deserialize("users", wireMessage(1, []byte(`{"name":"Bob"}`)))
+	if err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	if reflect.TypeOf(first) != reflect.TypeOf(second) {
+		t.Errorf("Deserialize() type = %v, want %v", reflect.TypeOf(second), reflect.TypeOf(first))
+	}
+
+	if client.lookups != 1 {
+		t.Errorf("registry lookups = %d, want 1", client.lookups)
+	}
+}
+
+func TestRegistryCodecSchemaFormat(t *testing.T) {
+	client := &registryClientTest{schemas: map[int]dynamicstruct.RegistrySchema{
+		3: {Type: "PROTOBUF", Schema: "message User { string name = 1; }"},
+	}}
+
+	format := func(schema string) (*dynamicstruct.Builder, dynamicstruct.SchemaDecodeFunc, error) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Name", "")
+
+		decode := func(payload []byte, instancePtr any) error {
+			reflect.ValueOf(instancePtr).Elem().Field(0).SetString(string(payload))
+
+			return nil
+		}
+
+		return builder, decode, nil
+	}
+
+	codec := dynamicstruct.NewRegistryCodec(client, dynamicstruct.WithSchemaFormat("protobuf", format))
+
+	instancePtr, err := codec.Deserialize("users", wireMessage(3, []byte("Alice")))
+	if err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	if got := reflect.ValueOf(instancePtr).Elem().Field(0).String(); got != "Alice" {
+		t.Errorf("Name = %q, want %q", got, "Alice")
+	}
+}
+
+// blockingRegistryClientTest holds lookups of id 2 until release is closed.
+type blockingRegistryClientTest struct {
+	release chan struct{}
+	lookups int32
+}
+
+func (c *blockingRegistryClientTest) SchemaByID(id int) (dynamicstruct.RegistrySchema, error) {
+	atomic.AddInt32(&c.lookups, 1)
+
+	if id == 2 {
+		<-c.release
+	}
+
+	return dynamicstruct.RegistrySchema{Type: "JSON", Schema: `{"type": "object", "properties": {"name": {"type": "string"}}}`}, nil
+}
+
+func TestRegistryCodecConcurrentLookups(t *testing.T) {
+	client := &blockingRegistryClientTest{release: make(chan struct{})}
+	codec := dynamicstruct.NewRegistryCodec(client)
+
+	if _, err := codec.Deserialize("users", wireMessage(1, []byte(`{}`))); err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := codec.Deserialize("users", wireMessage(2, []byte(`{}`))); err != nil {
+				t.Errorf("Deserialize() error = %v", err)
+			}
+		}()
+	}
+
+	// Cached ids decode while the lookup of id 2 is pending
+	if _, err := codec.Deserialize("users", wireMessage(1, []byte(`{"name":"Alice"}`))); err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	close(client.release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&client.lookups); got != 2 {
+		t.Errorf("registry lookups = %d, want 2", got)
+	}
+}
+
+func TestRegistryCodecFormatPanic(t *testing.T) {
+	client := &registryClientTest{schemas: map[int]dynamicstruct.RegistrySchema{
+		1: {Type: "PANIC", Schema: "x"},
+	}}
+
+	codec := dynamicstruct.NewRegistryCodec(client, dynamicstruct.WithSchemaFormat("PANIC",
+		func(string) (*dynamicstruct.Builder, dynamicstruct.SchemaDecodeFunc, error) {
+			panic("broken format")
+		},
+	))
+
+	// The second lookup retries instead of waiting on the failed one
+	for i := 0; i < 2; i++ {
+		if _, err := codec.Deserialize("users", wireMessage(1, nil)); !errors.Is(err, dynamicstruct.ErrInvalidSchema) {
+			t.Fatalf("Deserialize() error = %v, want %v", err, dynamicstruct.ErrInvalidSchema)
+		}
+	}
+
+	if client.lookups != 2 {
+		t.Errorf("registry lookups = %d, want 2", client.lookups)
+	}
+}
+
+func TestRegistryCodecErrors(t *testing.T) {
+	client := &registryClientTest{schemas: map[int]dynamicstruct.RegistrySchema{
+		1: {Type: "JSON", Schema: `{"type": "object", "properties": {"age": {"type": "integer"}}}`},
+		2: {Type: "PROTOBUF", Schema: "message User {}"},
+		3: {Type: "JSON", Schema: `{"type": "array"}`},
+	}}
+
+	codec := dynamicstruct.NewRegistryCodec(client)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "short_message", data: []byte{0, 0, 1}, wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "wrong_magic_byte", data: []byte{1, 0, 0, 0, 1, '{', '}'}, wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "payload_mismatch", data: wireMessage(1, []byte(`{"age":"old"}`)), wantErr: dynamicstruct.ErrInvalidMessage},
+		{name: "unsupported_type", data: wireMessage(2, nil), wantErr: dynamicstruct.ErrInvalidSchema},
+		{name: "not_an_object", data: wireMessage(3, nil), wantErr: dynamicstruct.ErrInvalidSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Deserialize("users", tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Deserialize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := codec.Deserialize("users", wireMessage(9, nil)); err == nil {
+		t.Error("Deserialize() error = nil, want the registry client's error")
+	}
+}
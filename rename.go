@@ -0,0 +1,133 @@
+package dynamicstruct
+
+import "fmt"
+
+// RenameField renames a field, keeping its type, tags, options and position.
+// Tags are kept as they are, including those set by WithAutoTags from the old
+// name.
+func (b *Builder) RenameField(oldName, newName string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.frozen() {
+		return fieldError("rename", oldName, ErrInstanceAlreadyBuilt)
+	}
+
+	field, ok := b.fields[oldName]
+	if !ok {
+		return fieldError("rename", oldName, ErrFieldNotFound)
+	}
+
+	if oldName == newName {
+		return nil
+	}
+
+	if _, ok := b.fields[newName]; ok {
+		return fieldError("rename", oldName, fmt.Errorf("%w: %s", ErrFieldAlreadyExists, newName))
+	}
+
+	if canonical, ok := b.aliases[newName]; ok {
+		return fieldError("rename", oldName, fmt.Errorf("%w: %s is an alias of %s", ErrFieldAlreadyExists, newName, canonical))
+	}
+
+	if err := b.checkAnonymousName(newName); err != nil {
+		return fieldError("rename", oldName, err)
+	}
+
+	delete(b.fields, oldName)
+	field.Name = newName
+	b.fields[newName] = field
+
+	for i, name := range b.order {
+		if name == oldName {
+			b.order[i] = newName
+
+			break
+		}
+	}
+
+	if meta, ok := b.meta[oldName]; ok {
+		delete(b.meta, oldName)
+		b.meta[newName] = meta
+	}
+
+	if child, ok := b.nested[oldName]; ok {
+		delete(b.nested, oldName)
+		b.nested[newName] = child
+	}
+
+	if kind, ok := b.selfFields[oldName]; ok {
+		delete(b.selfFields, oldName)
+		b.selfFields[newName] = kind
+	}
+
+	if b.unknownField == oldName {
+		b.unknownField = newName
+	}
+
+	for alias, name := range b.aliases {
+		if name == oldName {
+			b.aliases[alias] = newName
+		}
+	}
+
+	if position, ok := b.positions[oldName]; ok {
+		delete(b.positions, oldName)
+		b.positions[newName] = position
+	}
+
+	b.invalidate()
+
+	return nil
+}
+
+// ReplaceField changes the type and tags of a field in place, as if it were
+// removed and added again with AddField but without moving it to the end.
+// Required and default options are dropped, since they were given for the
+// old type; metadata set with SetFieldMeta, the group and aliases are kept.
+func (b *Builder) ReplaceField(name string, newKind any, tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.frozen() {
+		return fieldError("replace", name, ErrInstanceAlreadyBuilt)
+	}
+
+	if _, ok := b.fields[name]; !ok {
+		return fieldError("replace", name, ErrFieldNotFound)
+	}
+
+	state := b.saveState()
+	meta := b.meta[name]
+	aliases := b.fieldAliases(name)
+	position, positioned := b.positions[name]
+	b.removeField(name)
+
+	instance := b.instance
+
+	if err := b.addKind(name, newKind, tags); err != nil {
+		b.restoreState(state)
+		b.restoreInstance(instance)
+
+		return err
+	}
+
+	// addField added the field at the end, so put it back where it was
+	b.order = state.order
+
+	for _, alias := range aliases {
+		b.aliases[alias] = name
+	}
+
+	if positioned {
+		b.positions[name] = position
+	}
+
+	if meta.values != nil || meta.group != "" {
+		b.meta[name] = fieldMeta{group: meta.group, values: meta.values}
+	} else {
+		delete(b.meta, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newRenameBuilder() *dynamicstruct.Builder {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Id", 0, `json:"id"`)
+	_ = builder.AddFieldWithOptions("UserName", "", dynamicstruct.Tags(`json:"user_name"`), dynamicstruct.Default("guest"))
+	_ = builder.AddField("Email", "", `json:"email"`)
+	_ = builder.SetFieldMeta("UserName", "label", "User")
+
+	return builder
+}
+
+func fieldNames(t reflect.Type) []string {
+	names := make([]string, t.NumField())
+	for i := range names {
+		names[i] = t.Field(i).Name
+	}
+
+	return names
+}
+
+func TestRenameField(t *testing.T) {
+	builder := newRenameBuilder()
+
+	if err := builder.RenameField("UserName", "Login"); err != nil {
+		t.Fatalf("RenameField() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	if got, want := fieldNames(structType), []string{"Id", "Login", "Email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+
+	if field, _ := structType.FieldByName("Login"); field.Tag != `json:"user_name"` {
+		t.Errorf("Login tag = %q, want %q", field.Tag, `json:"user_name"`)
+	}
+
+	if got, _ := builder.GetField("Login"); got != "guest" {
+		t.Errorf("GetField() Login = %v, want guest", got)
+	}
+
+	if got, _ := builder.FieldMeta("Login", "label"); got != "User" {
+		t.Errorf("FieldMeta() = %v, want User", got)
+	}
+}
+
+func TestRenameFieldErrors(t *testing.T) {
+	built := newRenameBuilder()
+	_, _ = built.Build()
+
+	tests := []struct {
+		name    string
+		builder *dynamicstruct.Builder
+		oldName string
+		newName string
+		wantErr error
+	}{
+		{"not_found", newRenameBuilder(), "Missing", "Other", dynamicstruct.ErrFieldNotFound},
+		{"already_exists", newRenameBuilder(), "UserName", "Email", dynamicstruct.ErrFieldAlreadyExists},
+		{"built", built, "UserName", "Login", dynamicstruct.ErrInstanceAlreadyBuilt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.builder.RenameField(tt.oldName, tt.newName); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RenameField() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplaceField(t *testing.T) {
+	builder := newRenameBuilder()
+
+	if err := builder.ReplaceField("Id", "", `json:"id"`); err != nil {
+		t.Fatalf("ReplaceField() error = %v", err)
+	}
+
+	if err := builder.ReplaceField("UserName", []string{}, `json:"user_names"`); err != nil {
+		t.Fatalf("ReplaceField() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	if got, want := fieldNames(structType), []string{"Id", "UserName", "Email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+
+	if field, _ := structType.FieldByName("Id"); field.Type.Kind() != reflect.String {
+		t.Errorf("Id type = %s, want string", field.Type)
+	}
+
+	if got, _ := builder.GetField("UserName"); !reflect.DeepEqual(got, []string(nil)) {
+		t.Errorf("GetField() UserName = %#v, want no default", got)
+	}
+
+	if got, _ := builder.FieldMeta("UserName", "label"); got != "User" {
+		t.Errorf("FieldMeta() = %v, want User", got)
+	}
+}
+
+func TestReplaceFieldErrors(t *testing.T) {
+	t.Run("not_found", func(t *testing.T) {
+		err := newRenameBuilder().ReplaceField("Missing", "")
+		if !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("ReplaceField() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+	})
+
+	t.Run("invalid_tag_keeps_field", func(t *testing.T) {
+		builder := newRenameBuilder()
+
+		if err := builder.ReplaceField("UserName", 0, `json:"broken`); err == nil {
+			t.Fatal("ReplaceField() error = nil, want tag error")
+		}
+
+		if _, err := builder.Build(); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+
+		if got, _ := builder.GetField("UserName"); got != "guest" {
+			t.Errorf("GetField() UserName = %v, want guest", got)
+		}
+	})
+}
+
+func TestReplaceFieldNested(t *testing.T) {
+	address := dynamicstruct.New()
+	_ = address.AddField("City", "")
+
+	builder := newRenameBuilder()
+	if err := builder.ReplaceField("Email", address); err != nil {
+		t.Fatalf("ReplaceField() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	field, _ := reflect.TypeOf(instance).FieldByName("Email")
+	if _, ok := field.Type.FieldByName("City"); !ok {
+		t.Errorf("Email type = %s, want nested struct with City", field.Type)
+	}
+}
@@ -0,0 +1,47 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	anyType   = reflect.TypeOf((*any)(nil)).Elem()
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+func FromJSONSample(data []byte, opts ...ImportOption) (*Builder, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var sample map[string]any
+	if err := decoder.Decode(&sample); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSample, err.Error())
+	}
+
+	return fromSample(sample, "json", newImportConfig(opts))
+}
+
+// fromSample creates a builder with one field per key of the sample. Every
+// field is tagged with tagKey so the original key survives a round trip.
+// Values are typed like InferSchema types a stream, so that the objects of an
+// array are merged and its integers widen to float64 next to floats.
+func fromSample(sample map[string]any, tagKey string, cfg *importConfig) (*Builder, error) {
+	if sample == nil {
+		return nil, fmt.Errorf("%w: sample must be an object", ErrInvalidSample)
+	}
+
+	root := &inferredShape{}
+	root.merge(sample)
+
+	builder := New()
+	if err := root.addFields(builder, tagKey, cfg); err != nil {
+		return nil, err
+	}
+
+	return builder, nil
+}
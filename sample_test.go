@@ -0,0 +1,151 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestFromJSONSample(t *testing.T) {
+	sample := []byte(`{
+		"id": 42,
+		"user_name": "alice",
+		"score": 9.5,
+		"active": true,
+		"nickname": null,
+		"tags": ["a", "b"],
+		"mixed": [1, "two"],
+		"address": {"city": "Berlin", "zip-code": "10115"}
+	}`)
+
+	builder, err := dynamicstruct.FromJSONSample(sample)
+	if err != nil {
+		t.Fatalf("FromJSONSample() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field    string
+		wantType reflect.Type
+		wantTag  string
+	}{
+		{field: "Id", wantType: reflect.TypeOf(int64(0)), wantTag: "id"},
+		{field: "UserName", wantType: reflect.TypeOf(""), wantTag: "user_name"},
+		{field: "Score", wantType: reflect.TypeOf(float64(0)), wantTag: "score"},
+		{field: "Active", wantType: reflect.TypeOf(false), wantTag: "active"},
+		{field: "Nickname", wantType: reflect.TypeOf((*interface{})(nil)).Elem(), wantTag: "nickname,omitempty"},
+		{field: "Tags", wantType: reflect.TypeOf([]string{}), wantTag: "tags"},
+		{field: "Mixed", wantType: reflect.TypeOf([]interface{}{}), wantTag: "mixed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if field.Type != tt.wantType {
+				t.Errorf("field %s type = %v, want %v", tt.field, field.Type, tt.wantType)
+			}
+			if field.Tag.Get("json") != tt.wantTag {
+				t.Errorf("field %s json tag = %q, want %q", tt.field, field.Tag.Get("json"), tt.wantTag)
+			}
+		})
+	}
+
+	t.Run("nested_object", func(t *testing.T) {
+		field, ok := structType.FieldByName("Address")
+		if !ok {
+			t.Fatal("field Address not found")
+		}
+		if field.Type.Kind() != reflect.Struct {
+			t.Fatalf("Address kind = %v, want struct", field.Type.Kind())
+		}
+		if _, ok := field.Type.FieldByName("ZipCode"); !ok {
+			t.Error("nested field ZipCode not found")
+		}
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		instancePtr := reflect.New(structType).Interface()
+		if err := json.Unmarshal(sample, instancePtr); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+
+		name := reflect.ValueOf(instancePtr).Elem().FieldByName("UserName").String()
+		if name != "alice" {
+			t.Errorf("UserName = %q, want %q", name, "alice")
+		}
+	})
+}
+
+func TestFromJSONSampleArrays(t *testing.T) {
+	tests := []struct {
+		name     string
+		sample   string
+		wantType reflect.Type
+	}{
+		{name: "integers_and_floats", sample: `{"values": [1, 2.5]}`, wantType: reflect.TypeOf([]float64{})},
+		{name: "floats_and_integers", sample: `{"values": [2.5, 1]}`, wantType: reflect.TypeOf([]float64{})},
+		{name: "objects_with_different_keys", sample: `{"values": [{"x": 1}, {"y": 2}]}`, wantType: reflect.TypeOf([]struct {
+			X *int64 `json:"x,omitempty"`
+			Y *int64 `json:"y,omitempty"`
+		}{})},
+		{name: "objects_with_widened_values", sample: `{"values": [{"x": 1}, {"x": 2.5}]}`, wantType: reflect.TypeOf([]struct {
+			X float64 `json:"x"`
+		}{})},
+		{name: "nested_arrays", sample: `{"values": [[1], [2.5]]}`, wantType: reflect.TypeOf([][]float64{})},
+		{name: "nullable_items", sample: `{"values": [1, null]}`, wantType: reflect.TypeOf([]*int64{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := dynamicstruct.WithInferenceOptions(dynamicstruct.InferenceOptions{Conflicts: dynamicstruct.ConflictError})
+
+			builder, err := dynamicstruct.FromJSONSample([]byte(tt.sample), options)
+			if err != nil {
+				t.Fatalf("FromJSONSample() error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			if field, _ := reflect.TypeOf(instance).FieldByName("Values"); field.Type != tt.wantType {
+				t.Errorf("Values type = %v, want %v", field.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestFromJSONSampleErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sample  string
+		wantErr error
+	}{
+		{name: "invalid_json", sample: `{"id":`, wantErr: dynamicstruct.ErrInvalidSample},
+		{name: "top_level_array", sample: `[1, 2]`, wantErr: dynamicstruct.ErrInvalidSample},
+		{name: "null_document", sample: `null`, wantErr: dynamicstruct.ErrInvalidSample},
+		{name: "colliding_keys", sample: `{"user_id": 1, "user-id": 2}`, wantErr: dynamicstruct.ErrFieldAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamicstruct.FromJSONSample([]byte(tt.sample))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FromJSONSample() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// schema is the subset of JSON Schema shared by the OpenAPI and JSON Schema
+// exporters.
+type schema struct {
+	Schema               string            `json:"$schema,omitempty"`
+	ID                   string            `json:"$id,omitempty"`
+	Title                string            `json:"title,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Type                 any               `json:"type,omitempty"`
+	Format               string            `json:"format,omitempty"`
+	ContentEncoding      string            `json:"contentEncoding,omitempty"`
+	Nullable             bool              `json:"nullable,omitempty"`
+	Enum                 []any             `json:"enum,omitempty"`
+	Minimum              *float64          `json:"minimum,omitempty"`
+	Maximum              *float64          `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64          `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64          `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int              `json:"minLength,omitempty"`
+	MaxLength            *int              `json:"maxLength,omitempty"`
+	MinItems             *int              `json:"minItems,omitempty"`
+	MaxItems             *int              `json:"maxItems,omitempty"`
+	MinProperties        *int              `json:"minProperties,omitempty"`
+	MaxProperties        *int              `json:"maxProperties,omitempty"`
+	Items                *schema           `json:"items,omitempty"`
+	Properties           *schemaProperties `json:"properties,omitempty"`
+	AdditionalProperties any               `json:"additionalProperties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+}
+
+type schemaProperty struct {
+	name   string
+	schema *schema
+}
+
+// schemaProperties marshals to a JSON object that keeps the field order.
+type schemaProperties []schemaProperty
+
+func (p schemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, property := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(property.name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(property.schema)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// schemaForType describes t the way encoding/json encodes it. Pointers are
+// reported through the nullable callback so each dialect can express them.
+func schemaForType(t reflect.Type, nullable func(*schema)) *schema {
+	if t.Kind() == reflect.Ptr {
+		s := schemaForType(t.Elem(), nullable)
+		nullable(s)
+
+		return s
+	}
+
+	switch t {
+	case timeType:
+		return &schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &schema{Type: "integer", Format: "int64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// encoding/json writes byte slices as base64 strings, but byte arrays
+		// as arrays of numbers
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+
+		return &schema{Type: "array", Items: schemaForType(t.Elem(), nullable)}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: schemaForType(t.Elem(), nullable)}
+	case reflect.Struct:
+		return structSchema(t, nullable)
+	default:
+		// Interfaces and anything else accept any value
+		return &schema{}
+	}
+}
+
+func structSchema(t reflect.Type, nullable func(*schema)) *schema {
+	s := &schema{Type: "object", Properties: &schemaProperties{}}
+
+	addStructProperties(s, t, nullable)
+
+	return s
+}
+
+func addStructProperties(s *schema, t reflect.Type, nullable func(*schema)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		// Embedded structs without a json name are flattened, like encoding/json does
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			addStructProperties(s, fieldType, nullable)
+
+			continue
+		}
+
+		property := schemaForType(field.Type, nullable)
+		rules, _ := parseValidateTag(field.Tag.Get(validateTagKey))
+		validateRequired := applyValidateRules(property, fieldType, rules)
+		property.Description = field.Tag.Get(descriptionTagKey)
+
+		*s.Properties = append(*s.Properties, schemaProperty{
+			name:   name,
+			schema: property,
+		})
+
+		if validateRequired || field.Type.Kind() != reflect.Ptr && !hasTagOption(field.Tag.Get("json"), "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyValidateRules adds the constraints of validate tag rules to the schema
+// of a field of type t, and reports whether the rules make the field required.
+// Rules without a schema equivalent are ignored.
+func applyValidateRules(s *schema, t reflect.Type, rules []validateRule) bool {
+	required := false
+
+	for _, rule := range rules {
+		switch rule.name {
+		case "required":
+			required = true
+		case "min", "gte":
+			setLowerBound(s, t, rule.param, false)
+		case "max", "lte":
+			setUpperBound(s, t, rule.param, false)
+		case "gt":
+			setLowerBound(s, t, rule.param, true)
+		case "lt":
+			setUpperBound(s, t, rule.param, true)
+		case "len":
+			setLowerBound(s, t, rule.param, false)
+			setUpperBound(s, t, rule.param, false)
+		case "oneof":
+			for _, option := range strings.Fields(rule.param) {
+				s.Enum = append(s.Enum, enumValue(t, option))
+			}
+		case "email":
+			s.Format = "email"
+		case "url", "uri":
+			s.Format = "uri"
+		case "uuid":
+			s.Format = "uuid"
+		case "hostname":
+			s.Format = "hostname"
+		case "ipv4":
+			s.Format = "ipv4"
+		case "ipv6":
+			s.Format = "ipv6"
+		}
+	}
+
+	return required
+}
+
+func setLowerBound(s *schema, t reflect.Type, param string, exclusive bool) {
+	if isNumberKind(t.Kind()) {
+		bound, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+
+		if exclusive {
+			s.ExclusiveMinimum = &bound
+		} else {
+			s.Minimum = &bound
+		}
+
+		return
+	}
+
+	// Lengths are integers, so an exclusive bound is one more
+	length, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+
+	if exclusive {
+		length++
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		s.MinLength = &length
+	case reflect.Slice, reflect.Array:
+		s.MinItems = &length
+	case reflect.Map:
+		s.MinProperties = &length
+	}
+}
+
+func setUpperBound(s *schema, t reflect.Type, param string, exclusive bool) {
+	if isNumberKind(t.Kind()) {
+		bound, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+
+		if exclusive {
+			s.ExclusiveMaximum = &bound
+		} else {
+			s.Maximum = &bound
+		}
+
+		return
+	}
+
+	length, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+
+	if exclusive {
+		length--
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		s.MaxLength = &length
+	case reflect.Slice, reflect.Array:
+		s.MaxItems = &length
+	case reflect.Map:
+		s.MaxProperties = &length
+	}
+}
+
+func enumValue(t reflect.Type, option string) any {
+	if isNumberKind(t.Kind()) {
+		if value, err := strconv.ParseFloat(option, 64); err == nil {
+			return value
+		}
+	}
+
+	return option
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func hasTagOption(tag, option string) bool {
+	_, options, _ := strings.Cut(tag, ",")
+
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
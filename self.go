@@ -0,0 +1,175 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// SelfKind is the shape of a field that refers to the struct being built.
+type SelfKind int
+
+const (
+	// PtrToSelf is a field holding a pointer to another instance, such as
+	// the Parent of a tree node. Its type is any, holding nil or a pointer to
+	// the built type.
+	PtrToSelf SelfKind = iota + 1
+	// SliceOfSelf is a field holding other instances, such as the Children
+	// of a tree node. Its type is []any, holding pointers to the built type.
+	SliceOfSelf
+)
+
+var anySliceType = reflect.TypeOf([]any(nil))
+
+// AddSelfField adds a field that refers to the struct being built. Go types
+// created at runtime can't refer to themselves, so the field is declared as
+// any or []any, by kind, and holds pointers to instances of the built type.
+// DecodeJSON decodes such fields into instances of the built type at any
+// depth.
+func (b *Builder) AddSelfField(name string, kind SelfKind, tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	var fieldType reflect.Type
+
+	switch kind {
+	case PtrToSelf:
+		fieldType = anyType
+	case SliceOfSelf:
+		fieldType = anySliceType
+	default:
+		return fieldError("add", name, fmt.Errorf("%w: unknown self field kind %d", ErrIncompatibleTypes, kind))
+	}
+
+	if err := b.addField(name, fieldType, tags); err != nil {
+		return err
+	}
+
+	if b.selfFields == nil {
+		b.selfFields = make(map[string]SelfKind)
+	}
+
+	b.selfFields[name] = kind
+
+	return nil
+}
+
+// DecodeJSON unmarshals data into instancePtr, a pointer to a value of the
+// built type, and decodes the values of self fields into instances of the
+// built type, recursively. Keys no field takes are kept in the field set with
+// CaptureUnknown, if any.
+func (b *Builder) DecodeJSON(data []byte, instancePtr any) error {
+	b.m.RLock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		b.m.RUnlock()
+
+		return ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+
+	selfFields := make(map[string]SelfKind, len(b.selfFields))
+	for name, kind := range b.selfFields {
+		selfFields[name] = kind
+	}
+
+	unknownField := b.unknownField
+
+	b.m.RUnlock()
+
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	if value.Type() != structType {
+		return fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			structType.String(),
+		)
+	}
+
+	return decodeJSON(data, value, selfFields, unknownField)
+}
+
+// decodeJSON decodes data into value, an addressable value of the built type,
+// as DecodeJSON does.
+func decodeJSON(data []byte, value reflect.Value, selfFields map[string]SelfKind, unknownField string) error {
+	if err := json.Unmarshal(data, value.Addr().Interface()); err != nil {
+		return err
+	}
+
+	if err := resolveSelfFields(value, selfFields); err != nil {
+		return err
+	}
+
+	if unknownField != "" {
+		if err := captureUnknown(value, unknownField, data); err != nil {
+			return err
+		}
+	}
+
+	return transformDecoded(value.Addr())
+}
+
+// resolveSelfFields replaces the generic JSON values json.Unmarshal left in
+// the self fields of value with pointers to instances of value's type.
+func resolveSelfFields(value reflect.Value, selfFields map[string]SelfKind) error {
+	for name, kind := range selfFields {
+		field := value.FieldByName(name)
+
+		switch kind {
+		case PtrToSelf:
+			resolved, err := resolveSelf(field.Interface(), value.Type(), selfFields)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", name, err)
+			}
+
+			field.Set(reflect.ValueOf(&resolved).Elem())
+		case SliceOfSelf:
+			elems, _ := field.Interface().([]any)
+
+			for i, elem := range elems {
+				resolved, err := resolveSelf(elem, value.Type(), selfFields)
+				if err != nil {
+					return fmt.Errorf("field %s[%d]: %w", name, i, err)
+				}
+
+				elems[i] = resolved
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolveSelf(decoded any, structType reflect.Type, selfFields map[string]SelfKind) (any, error) {
+	if decoded == nil {
+		return nil, nil
+	}
+
+	// Values set by the caller are kept as they are
+	if reflect.TypeOf(decoded) == reflect.PtrTo(structType) {
+		return decoded, nil
+	}
+
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := reflect.New(structType)
+	if err := json.Unmarshal(data, instance.Interface()); err != nil {
+		return nil, err
+	}
+
+	if err := resolveSelfFields(instance.Elem(), selfFields); err != nil {
+		return nil, err
+	}
+
+	return instance.Interface(), nil
+}
@@ -0,0 +1,130 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newNodeBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+
+	if err := builder.AddSelfField("Parent", dynamicstruct.PtrToSelf, `json:"parent,omitempty"`); err != nil {
+		t.Fatalf("AddSelfField() error = %v", err)
+	}
+
+	if err := builder.AddSelfField("Children", dynamicstruct.SliceOfSelf, `json:"children,omitempty"`); err != nil {
+		t.Fatalf("AddSelfField() error = %v", err)
+	}
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestAddSelfField(t *testing.T) {
+	builder := newNodeBuilder(t)
+
+	data := []byte(`{"name":"root","parent":{"name":"up"},"children":[{"name":"a","children":[{"name":"a1"}]},{"name":"b"}]}`)
+
+	instancePtr, _ := builder.NewInstance()
+	if err := builder.DecodeJSON(data, instancePtr); err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+
+	nodeType := reflect.TypeOf(instancePtr)
+	root := reflect.ValueOf(instancePtr).Elem()
+
+	parent := root.FieldByName("Parent").Interface()
+	if reflect.TypeOf(parent) != nodeType {
+		t.Fatalf("DecodeJSON() Parent type = %T, want %s", parent, nodeType)
+	}
+
+	if got := reflect.ValueOf(parent).Elem().FieldByName("Name").String(); got != "up" {
+		t.Errorf("DecodeJSON() Parent.Name = %q, want %q", got, "up")
+	}
+
+	children, _ := root.FieldByName("Children").Interface().([]any)
+	if len(children) != 2 {
+		t.Fatalf("DecodeJSON() len(Children) = %d, want 2", len(children))
+	}
+
+	grandchildren, _ := reflect.ValueOf(children[0]).Elem().FieldByName("Children").Interface().([]any)
+	if len(grandchildren) != 1 || reflect.TypeOf(grandchildren[0]) != nodeType {
+		t.Fatalf("DecodeJSON() Children[0].Children = %v, want one node", grandchildren)
+	}
+
+	if got := reflect.ValueOf(grandchildren[0]).Elem().FieldByName("Name").String(); got != "a1" {
+		t.Errorf("DecodeJSON() Children[0].Children[0].Name = %q, want %q", got, "a1")
+	}
+
+	encoded, _ := json.Marshal(instancePtr)
+	if string(encoded) != string(data) {
+		t.Errorf("json.Marshal() = %s, want %s", encoded, data)
+	}
+}
+
+func TestAddSelfFieldErrors(t *testing.T) {
+	t.Run("unknown_kind", func(t *testing.T) {
+		err := dynamicstruct.New().AddSelfField("Parent", dynamicstruct.SelfKind(0))
+		if !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+			t.Errorf("AddSelfField() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Parent", "")
+
+		err := builder.AddSelfField("Parent", dynamicstruct.PtrToSelf)
+		if !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Errorf("AddSelfField() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+	})
+
+	builder := newNodeBuilder(t)
+
+	tests := []struct {
+		name        string
+		data        string
+		instancePtr any
+		wantErr     error
+	}{
+		{name: "wrong_type", data: `{}`, instancePtr: &PersonTest{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "not_pointer", data: `{}`, instancePtr: PersonTest{}, wantErr: dynamicstruct.ErrValueMustBePointer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := builder.DecodeJSON([]byte(tt.data), tt.instancePtr); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeJSON() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("invalid_child", func(t *testing.T) {
+		instancePtr, _ := builder.NewInstance()
+
+		var typeErr *json.UnmarshalTypeError
+
+		err := builder.DecodeJSON([]byte(`{"children":[{"name":1}]}`), instancePtr)
+		if !errors.As(err, &typeErr) {
+			t.Errorf("DecodeJSON() error = %v, want *json.UnmarshalTypeError", err)
+		}
+	})
+
+	t.Run("not_built", func(t *testing.T) {
+		err := dynamicstruct.New().DecodeJSON([]byte(`{}`), &PersonTest{})
+		if !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("DecodeJSON() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
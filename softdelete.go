@@ -0,0 +1,131 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const deletedAtField = "DeletedAt"
+
+// AddSoftDelete adds the soft-delete mixin to the builder: a DeletedAt
+// *time.Time field, tagged `json:"deleted_at,omitempty"`, that is nil while
+// the instance is live. Tags, when given, replace the json tag.
+func (b *Builder) AddSoftDelete(tags ...string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if len(tags) == 0 {
+		tags = []string{`json:"deleted_at,omitempty"`}
+	}
+
+	return b.addField(deletedAtField, reflect.PtrTo(timeType), tags)
+}
+
+// deletedAt returns the DeletedAt field of value, as added by AddSoftDelete
+// or declared as a *time.Time or time.Time, possibly in an embedded struct.
+func deletedAt(value reflect.Value) (reflect.Value, error) {
+	field, ok := fieldByName(value, deletedAtField)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w: %s", ErrFieldNotFound, deletedAtField)
+	}
+
+	if field.Type() != timeType && field.Type() != reflect.PtrTo(timeType) {
+		return reflect.Value{}, fmt.Errorf(
+			"%w: %s must be time.Time or *time.Time, got %s",
+			ErrIncompatibleTypes,
+			deletedAtField,
+			field.Type(),
+		)
+	}
+
+	return field, nil
+}
+
+// IsDeleted reports whether instance, a struct or a pointer to one, has its
+// DeletedAt field set: non-nil for a *time.Time, non-zero for a time.Time.
+func IsDeleted(instance any) (bool, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return false, err
+	}
+
+	field, err := deletedAt(value)
+	if err != nil {
+		return false, err
+	}
+
+	return hasValue(field), nil
+}
+
+// MarkDeleted sets the DeletedAt field of the struct instancePtr points to to
+// at. An instance that is already deleted keeps its original time.
+func MarkDeleted(instancePtr any, at time.Time) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	field, err := deletedAt(value)
+	if err != nil {
+		return err
+	}
+
+	if hasValue(field) {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.ValueOf(&at))
+	} else {
+		field.Set(reflect.ValueOf(at))
+	}
+
+	return nil
+}
+
+// Restore clears the DeletedAt field of the struct instancePtr points to.
+func Restore(instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	field, err := deletedAt(value)
+	if err != nil {
+		return err
+	}
+
+	field.Set(reflect.Zero(field.Type()))
+
+	return nil
+}
+
+// ActiveInstances returns the instances that aren't deleted, in their
+// original order.
+func ActiveInstances(instances []any) ([]any, error) {
+	return filterDeleted(instances, false)
+}
+
+// DeletedInstances returns the instances that are deleted, in their original
+// order.
+func DeletedInstances(instances []any) ([]any, error) {
+	return filterDeleted(instances, true)
+}
+
+func filterDeleted(instances []any, deleted bool) ([]any, error) {
+	filtered := make([]any, 0, len(instances))
+
+	for i, instance := range instances {
+		isDeleted, err := IsDeleted(instance)
+		if err != nil {
+			return nil, fmt.Errorf("instance %d: %w", i, err)
+		}
+
+		if isDeleted == deleted {
+			filtered = append(filtered, instance)
+		}
+	}
+
+	return filtered, nil
+}
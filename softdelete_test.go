@@ -0,0 +1,175 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newSoftDeleteBuilder(t *testing.T) *dynamicstruct.Builder {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+
+	if err := builder.AddSoftDelete(); err != nil {
+		t.Fatalf("AddSoftDelete() error = %v", err)
+	}
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder
+}
+
+func TestSoftDelete(t *testing.T) {
+	builder := newSoftDeleteBuilder(t)
+	instance, _ := builder.InstanceValue()
+
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	value := reflect.New(instance.Type())
+	value.Elem().FieldByName("Name").SetString("Alice")
+
+	deleted, err := dynamicstruct.IsDeleted(value.Interface())
+	if err != nil || deleted {
+		t.Fatalf("IsDeleted() = %v, %v, want false, nil", deleted, err)
+	}
+
+	if err := dynamicstruct.MarkDeleted(value.Interface(), deletedAt); err != nil {
+		t.Fatalf("MarkDeleted() error = %v", err)
+	}
+
+	if err := dynamicstruct.MarkDeleted(value.Interface(), deletedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("MarkDeleted() error = %v", err)
+	}
+
+	deleted, err = dynamicstruct.IsDeleted(value.Elem().Interface())
+	if err != nil || !deleted {
+		t.Fatalf("IsDeleted() = %v, %v, want true, nil", deleted, err)
+	}
+
+	data, _ := json.Marshal(value.Interface())
+	if want := `{"name":"Alice","deleted_at":"2024-05-01T12:00:00Z"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	if err := dynamicstruct.Restore(value.Interface()); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if deleted, _ := dynamicstruct.IsDeleted(value.Interface()); deleted {
+		t.Error("IsDeleted() = true after Restore(), want false")
+	}
+}
+
+func TestSoftDeleteFilters(t *testing.T) {
+	builder := newSoftDeleteBuilder(t)
+	instance, _ := builder.InstanceValue()
+
+	var instances []any
+
+	for i, name := range []string{"Alice", "Bob", "Carol"} {
+		value := reflect.New(instance.Type())
+		value.Elem().FieldByName("Name").SetString(name)
+
+		if i == 1 {
+			_ = dynamicstruct.MarkDeleted(value.Interface(), time.Now())
+		}
+
+		instances = append(instances, value.Interface())
+	}
+
+	names := func(instances []any) []string {
+		var names []string
+		for _, instance := range instances {
+			names = append(names, reflect.ValueOf(instance).Elem().FieldByName("Name").String())
+		}
+
+		return names
+	}
+
+	active, err := dynamicstruct.ActiveInstances(instances)
+	if err != nil {
+		t.Fatalf("ActiveInstances() error = %v", err)
+	}
+
+	if got, want := names(active), []string{"Alice", "Carol"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ActiveInstances() = %v, want %v", got, want)
+	}
+
+	deleted, err := dynamicstruct.DeletedInstances(instances)
+	if err != nil {
+		t.Fatalf("DeletedInstances() error = %v", err)
+	}
+
+	if got, want := names(deleted), []string{"Bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DeletedInstances() = %v, want %v", got, want)
+	}
+
+	_, err = dynamicstruct.ActiveInstances(append(instances, PersonTest{}))
+	if !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+		t.Errorf("ActiveInstances() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+	}
+}
+
+func TestSoftDeleteErrors(t *testing.T) {
+	type wrongType struct {
+		DeletedAt string
+	}
+
+	type plainTime struct {
+		DeletedAt time.Time
+	}
+
+	tests := []struct {
+		name     string
+		instance any
+		wantErr  error
+	}{
+		{name: "no_field", instance: PersonTest{}, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "wrong_type", instance: wrongType{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "not_struct", instance: 1, wantErr: dynamicstruct.ErrInvalidInstance},
+		{name: "time_value", instance: plainTime{}, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dynamicstruct.IsDeleted(tt.instance); !errors.Is(err, tt.wantErr) {
+				t.Errorf("IsDeleted() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("mark_not_pointer", func(t *testing.T) {
+		err := dynamicstruct.MarkDeleted(plainTime{}, time.Now())
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("MarkDeleted() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+
+	t.Run("already_added", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("DeletedAt", "")
+
+		if err := builder.AddSoftDelete(); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Errorf("AddSoftDelete() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+	})
+
+	t.Run("mark_time_value", func(t *testing.T) {
+		instance := plainTime{}
+		if err := dynamicstruct.MarkDeleted(&instance, time.Unix(1, 0)); err != nil {
+			t.Fatalf("MarkDeleted() error = %v", err)
+		}
+
+		if deleted, _ := dynamicstruct.IsDeleted(instance); !deleted {
+			t.Error("IsDeleted() = false, want true")
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type sortKey struct {
+	index      []int
+	fieldType  reflect.Type
+	compare    func(x, y reflect.Value) int
+	descending bool
+}
+
+// LessFunc returns a three-way comparison function for instances of the built
+// type, suitable for slices.SortFunc; sort.Slice takes it as
+// compare(s[i], s[j]) < 0. Instances are ordered by the first field, then by
+// each following field to break ties. A field name prefixed with "-" sorts in
+// descending order. Fields promoted through a nil embedded pointer compare as
+// their zero value, so a pointer field compares as nil.
+//
+// The comparison function accepts instances of the built type or pointers to
+// them and panics on anything else.
+func (b *Builder) LessFunc(fields ...string) (func(a, b any) int, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no sort fields", ErrFieldNotFound)
+	}
+
+	structType := b.instance.Type()
+	keys := make([]sortKey, 0, len(fields))
+
+	for _, name := range fields {
+		descending := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		field, ok := structType.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", missingField(structType, name), name)
+		}
+
+		compare := compareFunc(field.Type)
+		if compare == nil {
+			return nil, fmt.Errorf("%w: field %s of type %s is not ordered", ErrIncompatibleTypes, name, field.Type)
+		}
+
+		keys = append(keys, sortKey{
+			index:      field.Index,
+			fieldType:  field.Type,
+			compare:    compare,
+			descending: descending,
+		})
+	}
+
+	return func(x, y any) int {
+		xValue := reflect.Indirect(reflect.ValueOf(x))
+		yValue := reflect.Indirect(reflect.ValueOf(y))
+
+		for _, key := range keys {
+			c := key.compare(key.field(xValue), key.field(yValue))
+			if c == 0 {
+				continue
+			}
+
+			if key.descending {
+				return -c
+			}
+
+			return c
+		}
+
+		return 0
+	}, nil
+}
+
+// field returns the key's field of value, or its zero value when the field is
+// promoted through a nil embedded pointer.
+func (k sortKey) field(value reflect.Value) reflect.Value {
+	field, err := value.FieldByIndexErr(k.index)
+	if err != nil {
+		return reflect.Zero(k.fieldType)
+	}
+
+	return field
+}
+
+// compareFunc returns a three-way comparison for values of t, or nil when t
+// has no natural order. Nil pointers sort before non-nil ones.
+func compareFunc(t reflect.Type) func(x, y reflect.Value) int {
+	if t == timeType {
+		return func(x, y reflect.Value) int {
+			xTime, yTime := x.Interface().(time.Time), y.Interface().(time.Time)
+
+			switch {
+			case xTime.Before(yTime):
+				return -1
+			case xTime.After(yTime):
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(x, y reflect.Value) int {
+			return compareOrdered(x.Int(), y.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(x, y reflect.Value) int {
+			return compareOrdered(x.Uint(), y.Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(x, y reflect.Value) int {
+			return compareOrdered(x.Float(), y.Float())
+		}
+	case reflect.String:
+		return func(x, y reflect.Value) int {
+			return strings.Compare(x.String(), y.String())
+		}
+	case reflect.Bool:
+		return func(x, y reflect.Value) int {
+			switch {
+			case x.Bool() == y.Bool():
+				return 0
+			case y.Bool():
+				return -1
+			default:
+				return 1
+			}
+		}
+	case reflect.Ptr:
+		elemCompare := compareFunc(t.Elem())
+		if elemCompare == nil {
+			return nil
+		}
+
+		return func(x, y reflect.Value) int {
+			switch {
+			case x.IsNil() && y.IsNil():
+				return 0
+			case x.IsNil():
+				return -1
+			case y.IsNil():
+				return 1
+			default:
+				return elemCompare(x.Elem(), y.Elem())
+			}
+		}
+	default:
+		return nil
+	}
+}
+
+func compareOrdered[T int64 | uint64 | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,182 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newSortBuilder(t *testing.T) (*dynamicstruct.Builder, []any) {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(PersonTest{})
+	_ = builder.AddField("Team", "")
+	_ = builder.AddField("Score", float64(0))
+	_ = builder.AddField("Rank", (*int)(nil))
+	_ = builder.AddField("Tags", []string{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	rows := []struct {
+		name  string
+		team  string
+		score float64
+		rank  *int
+	}{
+		{name: "carol", team: "blue", score: 7},
+		{name: "alice", team: "red", score: 9, rank: intPtr(2)},
+		{name: "bob", team: "blue", score: 9, rank: intPtr(1)},
+		{name: "dave", team: "red", score: 7},
+	}
+
+	instances := make([]any, 0, len(rows))
+
+	for _, row := range rows {
+		value := reflect.New(reflect.TypeOf(instance))
+		value.Elem().FieldByName("Name").SetString(row.name)
+		value.Elem().FieldByName("Team").SetString(row.team)
+		value.Elem().FieldByName("Score").SetFloat(row.score)
+		value.Elem().FieldByName("Rank").Set(reflect.ValueOf(row.rank))
+		instances = append(instances, value.Interface())
+	}
+
+	return builder, instances
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func sortedNames(instances []any, compare func(a, b any) int) []string {
+	sorted := append([]any(nil), instances...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compare(sorted[i], sorted[j]) < 0
+	})
+
+	names := make([]string, 0, len(sorted))
+	for _, instance := range sorted {
+		names = append(names, reflect.ValueOf(instance).Elem().FieldByName("Name").String())
+	}
+
+	return names
+}
+
+func TestLessFunc(t *testing.T) {
+	builder, instances := newSortBuilder(t)
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   []string
+	}{
+		{name: "single_key", fields: []string{"Team"}, want: []string{"carol", "bob", "alice", "dave"}},
+		{name: "multi_key", fields: []string{"Team", "Name"}, want: []string{"bob", "carol", "alice", "dave"}},
+		{name: "descending", fields: []string{"-Score", "Name"}, want: []string{"alice", "bob", "carol", "dave"}},
+		{name: "promoted_field", fields: []string{"Name"}, want: []string{"alice", "bob", "carol", "dave"}},
+		{name: "nil_pointers_first", fields: []string{"Rank", "Name"}, want: []string{"carol", "dave", "bob", "alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compare, err := builder.LessFunc(tt.fields...)
+			if err != nil {
+				t.Fatalf("LessFunc() error = %v", err)
+			}
+
+			if got := sortedNames(instances, compare); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sorted = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("values_and_pointers", func(t *testing.T) {
+		compare, _ := builder.LessFunc("Name")
+
+		a := reflect.ValueOf(instances[1]).Elem().Interface()
+		if got := compare(a, instances[0]); got >= 0 {
+			t.Errorf("compare(alice, carol) = %d, want < 0", got)
+		}
+	})
+}
+
+func TestLessFuncEmbeddedPointer(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", "")
+	_ = builder.AddAnonymousField(&PersonTest{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instances := make([]any, 0, 3)
+
+	for _, row := range []struct {
+		id     string
+		person *PersonTest
+	}{
+		{id: "b", person: &PersonTest{Name: "bob"}},
+		{id: "nil"},
+		{id: "a", person: &PersonTest{Name: "alice"}},
+	} {
+		value := reflect.New(reflect.TypeOf(instance))
+		value.Elem().FieldByName("ID").SetString(row.id)
+		value.Elem().FieldByName("PersonTest").Set(reflect.ValueOf(row.person))
+		instances = append(instances, value.Interface())
+	}
+
+	compare, err := builder.LessFunc("Name")
+	if err != nil {
+		t.Fatalf("LessFunc() error = %v", err)
+	}
+
+	sorted := append([]any(nil), instances...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compare(sorted[i], sorted[j]) < 0
+	})
+
+	ids := make([]string, 0, len(sorted))
+	for _, instance := range sorted {
+		ids = append(ids, reflect.ValueOf(instance).Elem().FieldByName("ID").String())
+	}
+
+	// The unreachable name sorts as the empty string
+	if want := []string{"nil", "a", "b"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("sorted = %v, want %v", ids, want)
+	}
+}
+
+func TestLessFuncErrors(t *testing.T) {
+	builder, _ := newSortBuilder(t)
+
+	tests := []struct {
+		name    string
+		fields  []string
+		wantErr error
+	}{
+		{name: "no_fields", fields: nil, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "unknown_field", fields: []string{"Missing"}, wantErr: dynamicstruct.ErrFieldNotFound},
+		{name: "unordered_field", fields: []string{"Tags"}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := builder.LessFunc(tt.fields...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("LessFunc() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("not_built", func(t *testing.T) {
+		if _, err := dynamicstruct.New().LessFunc("Name"); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("LessFunc() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type printConfig struct {
+	maxItems int
+	maxDepth int
+	indent   string
+}
+
+// PrintOption configures Sprint.
+type PrintOption func(*printConfig)
+
+// MaxItems sets how many elements of a slice, array or map Sprint prints
+// before summarizing the rest as "... N more". The default is 10; 0 prints
+// all of them.
+func MaxItems(n int) PrintOption {
+	return func(c *printConfig) {
+		c.maxItems = n
+	}
+}
+
+// MaxDepth sets how many levels of nested structs and collections Sprint
+// prints before printing them as "...". The default is 0, no limit.
+func MaxDepth(n int) PrintOption {
+	return func(c *printConfig) {
+		c.maxDepth = n
+	}
+}
+
+// Indent sets the string Sprint indents each level with, two spaces by
+// default.
+func Indent(indent string) PrintOption {
+	return func(c *printConfig) {
+		c.indent = indent
+	}
+}
+
+// Sprint returns a readable, multi-line dump of instance: a line per field
+// with its name, type and value, and nested structs, slices and maps indented
+// below their field. Anonymous struct types, such as those of built
+// instances, are printed as "struct". Sensitive fields are printed as
+// Redacted. Values that aren't structs are printed the same way, without
+// fields. Pointers, slices and maps that refer back to a value being printed,
+// such as a child's pointer to its parent, are printed as <cycle>.
+//
+// It is meant for debugging; the format may change.
+func Sprint(instance any, opts ...PrintOption) string {
+	cfg := printConfig{maxItems: 10, indent: "  "}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	p := printer{cfg: cfg, visiting: make(map[refKey]bool)}
+
+	value := reflect.ValueOf(instance)
+	if !value.IsValid() {
+		return "nil"
+	}
+
+	p.buf.WriteString(printTypeName(value.Type()))
+	p.buf.WriteByte(' ')
+	p.value(value, 0)
+
+	return p.buf.String()
+}
+
+type printer struct {
+	cfg printConfig
+	buf strings.Builder
+	// visiting holds the pointers, slices and maps being printed, to print
+	// the values that refer back to them as <cycle>
+	visiting map[refKey]bool
+}
+
+// printTypeName returns the name of t, with anonymous structs shortened to
+// "struct".
+func printTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + printTypeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + printTypeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), printTypeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + printTypeName(t.Key()) + "]" + printTypeName(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return "struct"
+		}
+	}
+
+	return t.String()
+}
+
+func (p *printer) line(depth int, s string) {
+	p.buf.WriteByte('\n')
+	p.buf.WriteString(strings.Repeat(p.cfg.indent, depth))
+	p.buf.WriteString(s)
+}
+
+func (p *printer) value(value reflect.Value, depth int) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			p.buf.WriteString("nil")
+
+			return
+		}
+
+		if value.Kind() == reflect.Ptr {
+			if p.cycle(value) {
+				return
+			}
+
+			defer p.leave(value)
+
+			p.buf.WriteByte('&')
+		}
+
+		p.value(value.Elem(), depth)
+
+		return
+	case reflect.Struct:
+		// Structs with unexported fields, such as time.Time, print themselves
+		if value.Type() == timeType || !isDiffableStruct(value.Type()) {
+			break
+		}
+
+		p.structValue(value, depth)
+
+		return
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if value.Kind() != reflect.Array && value.IsNil() {
+			p.buf.WriteString("nil")
+
+			return
+		}
+
+		if value.Kind() != reflect.Array {
+			if p.cycle(value) {
+				return
+			}
+
+			defer p.leave(value)
+		}
+
+		p.collection(value, depth)
+
+		return
+	case reflect.String:
+		p.buf.WriteString(fmt.Sprintf("%q", value.String()))
+
+		return
+	}
+
+	if value.CanInterface() {
+		p.buf.WriteString(fmt.Sprintf("%v", value.Interface()))
+	}
+}
+
+// cycleText stands for a value that refers back to a value it is nested in.
+const cycleText = "<cycle>"
+
+// cycle prints <cycle> and returns true when the pointer, slice or map value
+// is already being printed, and otherwise marks it as being printed until
+// leave is called.
+func (p *printer) cycle(value reflect.Value) bool {
+	key := newRefKey(value)
+	if p.visiting[key] {
+		p.buf.WriteString(cycleText)
+
+		return true
+	}
+
+	p.visiting[key] = true
+
+	return false
+}
+
+func (p *printer) leave(value reflect.Value) {
+	delete(p.visiting, newRefKey(value))
+}
+
+func (p *printer) tooDeep(depth int) bool {
+	if p.cfg.maxDepth > 0 && depth >= p.cfg.maxDepth {
+		p.buf.WriteString("...")
+
+		return true
+	}
+
+	return false
+}
+
+func (p *printer) structValue(value reflect.Value, depth int) {
+	if p.tooDeep(depth) {
+		return
+	}
+
+	p.buf.WriteByte('{')
+
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		p.line(depth+1, field.Name+" "+printTypeName(field.Type)+" = ")
+
+		if isSensitive(field) {
+			p.buf.WriteString(Redacted)
+
+			continue
+		}
+
+		p.value(value.Field(i), depth+1)
+	}
+
+	p.line(depth, "}")
+}
+
+func (p *printer) collection(value reflect.Value, depth int) {
+	if value.Len() == 0 {
+		if value.Kind() == reflect.Map {
+			p.buf.WriteString("{}")
+		} else {
+			p.buf.WriteString("[]")
+		}
+
+		return
+	}
+
+	if p.tooDeep(depth) {
+		return
+	}
+
+	shown := value.Len()
+	if p.cfg.maxItems > 0 && shown > p.cfg.maxItems {
+		shown = p.cfg.maxItems
+	}
+
+	if value.Kind() == reflect.Map {
+		p.buf.WriteByte('{')
+
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		for _, key := range keys[:shown] {
+			p.line(depth+1, "")
+			p.value(key, depth+1)
+			p.buf.WriteString(": ")
+			p.value(value.MapIndex(key), depth+1)
+		}
+	} else {
+		p.buf.WriteByte('[')
+
+		for i := 0; i < shown; i++ {
+			p.line(depth+1, "")
+			p.value(value.Index(i), depth+1)
+		}
+	}
+
+	if rest := value.Len() - shown; rest > 0 {
+		p.line(depth+1, fmt.Sprintf("... %d more", rest))
+	}
+
+	if value.Kind() == reflect.Map {
+		p.line(depth, "}")
+	} else {
+		p.line(depth, "]")
+	}
+}
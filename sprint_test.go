@@ -0,0 +1,114 @@
+package dynamicstruct_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestSprint(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "")
+	_ = builder.AddField("Token", "", dynamicstruct.Sensitive())
+	_ = builder.AddField("Address", &AddressTest{})
+	_ = builder.AddField("Tags", []string{})
+	_ = builder.AddField("Scores", map[string]int{})
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.SetFieldValue("Name", "Alice")
+	_ = builder.SetFieldValue("Token", "abc")
+	_ = builder.SetFieldValue("Address", &AddressTest{City: "Berlin"})
+	_ = builder.SetFieldValue("Tags", []string{"a", "b", "c"})
+	_ = builder.SetFieldValue("Scores", map[string]int{"math": 1})
+
+	instancePtr, _ := builder.InstanceAddr()
+
+	tests := []struct {
+		name string
+		opts []dynamicstruct.PrintOption
+		want string
+	}{
+		{
+			name: "defaults",
+			want: `*struct &{
+  Name string = "Alice"
+  Token string = [REDACTED]
+  Address *dynamicstruct_test.AddressTest = &{
+    Street string = ""
+    City string = "Berlin"
+  }
+  Tags []string = [
+    "a"
+    "b"
+    "c"
+  ]
+  Scores map[string]int = {
+    "math": 1
+  }
+}`,
+		},
+		{
+			name: "limits_depth",
+			opts: []dynamicstruct.PrintOption{dynamicstruct.MaxItems(1), dynamicstruct.MaxDepth(1)},
+			want: `*struct &{
+  Name string = "Alice"
+  Token string = [REDACTED]
+  Address *dynamicstruct_test.AddressTest = &...
+  Tags []string = ...
+  Scores map[string]int = ...
+}`,
+		},
+		{
+			name: "limits_items",
+			opts: []dynamicstruct.PrintOption{dynamicstruct.MaxItems(2), dynamicstruct.Indent("\t")},
+			want: "\tTags []string = [\n\t\t\"a\"\n\t\t\"b\"\n\t\t... 1 more\n\t]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dynamicstruct.Sprint(instancePtr, tt.opts...)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Sprint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprintNil(t *testing.T) {
+	if got := dynamicstruct.Sprint(nil); got != "nil" {
+		t.Errorf("Sprint() = %s, want nil", got)
+	}
+
+	var person *PersonTest
+	if got := dynamicstruct.Sprint(person); got != "*dynamicstruct_test.PersonTest nil" {
+		t.Errorf("Sprint() = %s, want *dynamicstruct_test.PersonTest nil", got)
+	}
+}
+
+func TestSprintCycle(t *testing.T) {
+	type node struct {
+		Name     string
+		Parent   *node
+		Children []*node
+	}
+
+	root := &node{Name: "root"}
+	shared := &node{Name: "shared"}
+	root.Children = []*node{{Name: "child", Parent: root}, shared, shared}
+
+	got := dynamicstruct.Sprint(root)
+
+	if want := "Parent *dynamicstruct_test.node = <cycle>"; !strings.Contains(got, want) {
+		t.Errorf("Sprint() = %s, want %s", got, want)
+	}
+
+	// A value reached twice without a cycle is printed both times
+	if n := strings.Count(got, `"shared"`); n != 2 {
+		t.Errorf("Sprint() prints shared %d times, want 2:\n%s", n, got)
+	}
+}
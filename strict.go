@@ -0,0 +1,129 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalStrict unmarshals the JSON object data into the struct instancePtr
+// points to, like json.Unmarshal, but rejects what json.Unmarshal lets pass:
+// a key that no field takes returns ErrUnknownField, and a field whose
+// validate tag has the required rule returns ValidationErrors when its key is
+// missing or null. Required fields are checked in nested objects too, and
+// reported under paths such as "Address.City". On error the instance is left
+// unchanged.
+//
+// Unlike Validate, a required field is satisfied by an explicit zero value
+// such as 0 or "": strict decoding checks what the document contains, not
+// what the values are.
+func UnmarshalStrict(data []byte, instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	// encoding/json decodes into the pointers, maps and slices it finds, so
+	// the copy must not share them with the instance
+	decoded := reflect.New(value.Type())
+	decoded.Elem().Set(deepCopy(value))
+
+	if err := decoder.Decode(decoded.Interface()); err != nil {
+		// encoding/json reports unknown keys only through the message
+		const unknownFieldPrefix = "json: unknown field "
+		if message := err.Error(); strings.HasPrefix(message, unknownFieldPrefix) {
+			return fmt.Errorf("%w: %s", ErrUnknownField, strings.TrimPrefix(message, unknownFieldPrefix))
+		}
+
+		return err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+
+	errs := ValidationErrors{}
+	checkRequiredKeys(value.Type(), object, "", errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	value.Set(decoded.Elem())
+
+	return transformDecoded(value.Addr())
+}
+
+// checkRequiredKeys adds to errs the fields of structType that are required
+// by their validate tag and missing or null in object, and checks the objects
+// of nested struct fields the same way.
+func checkRequiredKeys(structType reflect.Type, object map[string]json.RawMessage, prefix string, errs ValidationErrors) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldType := field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous {
+			tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if tag == "" && fieldType.Kind() == reflect.Struct {
+				checkRequiredKeys(fieldType, object, prefix, errs)
+
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		path := prefix + field.Name
+		raw, present := objectKey(object, name)
+		present = present && string(raw) != "null"
+
+		rules, _ := parseValidateTag(field.Tag.Get(validateTagKey))
+		for _, rule := range rules {
+			if rule.name == "required" && !present {
+				errs[path] = &RuleError{Rule: rule.name, msg: "is required"}
+			}
+		}
+
+		if !present || fieldType.Kind() != reflect.Struct || fieldType == timeType {
+			continue
+		}
+
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &nested); err == nil {
+			checkRequiredKeys(fieldType, nested, path+".", errs)
+		}
+	}
+}
+
+// objectKey looks name up in object, falling back to a case-insensitive match
+// as encoding/json does.
+func objectKey(object map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if raw, ok := object[name]; ok {
+		return raw, true
+	}
+
+	for key, raw := range object {
+		if strings.EqualFold(key, name) {
+			return raw, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,118 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestUnmarshalStrict(t *testing.T) {
+	address := dynamicstruct.New()
+	_ = address.AddField("City", "", `json:"city"`, `validate:"required"`)
+	_ = address.AddField("Zip", "", `json:"zip"`)
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`, `validate:"required"`)
+	_ = builder.AddField("Age", 0, `json:"age"`)
+	_ = builder.AddField("Address", address, `json:"address"`)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       string
+		wantErr    error
+		wantFields []string
+	}{
+		{name: "valid", data: `{"name": "Alice", "age": 30, "address": {"city": "Berlin"}}`},
+		{name: "explicit_zero", data: `{"name": ""}`},
+		{name: "case_insensitive_key", data: `{"NAME": "Alice"}`},
+		{name: "unknown_key", data: `{"name": "Alice", "nickname": "Al"}`, wantErr: dynamicstruct.ErrUnknownField},
+		{name: "unknown_nested_key", data: `{"name": "Alice", "address": {"city": "Berlin", "country": "DE"}}`, wantErr: dynamicstruct.ErrUnknownField},
+		{name: "missing_required", data: `{"age": 30}`, wantErr: dynamicstruct.ErrValidation, wantFields: []string{"Name"}},
+		{name: "null_required", data: `{"name": null}`, wantErr: dynamicstruct.ErrValidation, wantFields: []string{"Name"}},
+		{
+			name:       "missing_nested_required",
+			data:       `{"address": {"zip": "10115"}}`,
+			wantErr:    dynamicstruct.ErrValidation,
+			wantFields: []string{"Name", "Address.City"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instancePtr, _ := builder.NewInstance()
+
+			err := dynamicstruct.UnmarshalStrict([]byte(tt.data), instancePtr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnmarshalStrict() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var errs dynamicstruct.ValidationErrors
+			if errors.As(err, &errs) {
+				if len(errs) != len(tt.wantFields) {
+					t.Errorf("UnmarshalStrict() errors = %v, want fields %v", errs, tt.wantFields)
+				}
+
+				for _, field := range tt.wantFields {
+					if _, ok := errs[field]; !ok {
+						t.Errorf("UnmarshalStrict() errors = %v, want %s", errs, field)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("unchanged_on_error", func(t *testing.T) {
+		instancePtr, _ := builder.NewInstance()
+		_ = dynamicstruct.UnmarshalStrict([]byte(`{"name": "Alice"}`), instancePtr)
+
+		if err := dynamicstruct.UnmarshalStrict([]byte(`{"name": "Bob", "extra": 1}`), instancePtr); err == nil {
+			t.Fatal("UnmarshalStrict() error = nil, want error")
+		}
+
+		if name := reflect.ValueOf(instancePtr).Elem().FieldByName("Name").String(); name != "Alice" {
+			t.Errorf("Name = %q, want %q", name, "Alice")
+		}
+	})
+
+	t.Run("embedded_pointer_unchanged_on_error", func(t *testing.T) {
+		type Inner struct {
+			Mail string `json:"mail"`
+		}
+
+		type Entry struct {
+			*Inner
+			Age int `json:"age" validate:"required"`
+		}
+
+		inner := &Inner{Mail: "old"}
+		entry := &Entry{Inner: inner}
+
+		if err := dynamicstruct.UnmarshalStrict([]byte(`{"mail": "new"}`), entry); !errors.Is(err, dynamicstruct.ErrValidation) {
+			t.Fatalf("UnmarshalStrict() error = %v, want %v", err, dynamicstruct.ErrValidation)
+		}
+
+		if inner.Mail != "old" || entry.Mail != "old" {
+			t.Errorf("Mail = %q, embedded Mail = %q, want both %q", entry.Mail, inner.Mail, "old")
+		}
+	})
+
+	t.Run("type_mismatch", func(t *testing.T) {
+		instancePtr, _ := builder.NewInstance()
+
+		if err := dynamicstruct.UnmarshalStrict([]byte(`{"name": 1}`), instancePtr); err == nil {
+			t.Error("UnmarshalStrict() error = nil, want error")
+		}
+	})
+
+	t.Run("not_pointer", func(t *testing.T) {
+		if err := dynamicstruct.UnmarshalStrict([]byte(`{}`), struct{}{}); !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("UnmarshalStrict() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
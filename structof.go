@@ -0,0 +1,31 @@
+//go:build !tinygo
+
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/fatih/structtag"
+)
+
+// structOf reports an error instead of panicking on fields reflect.StructOf
+// rejects, such as an embedded pointer whose type has methods next to other
+// fields.
+func structOf(fields []reflect.StructField) (t reflect.Type, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t, err = nil, fmt.Errorf("%w: reflect.StructOf: %v", ErrIncompatibleTypes, r)
+		}
+	}()
+
+	return reflect.StructOf(fields), nil
+}
+
+func checkTagSyntax(tag string) error {
+	if _, err := structtag.Parse(tag); err != nil {
+		return ErrInvalidTag
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build !tinygo
+
+package dynamicstruct_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+// Under TinyGo, reflect.StructOf fails with ErrUnsupported instead
+func TestStructOfRejectedFields(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(&GreeterTest{})
+	_ = builder.AddField("ID", 0)
+
+	if _, err := builder.Build(); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Build() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+}
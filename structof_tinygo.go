@@ -0,0 +1,72 @@
+//go:build tinygo
+
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// structOf reports an error instead of panicking when the runtime doesn't
+// implement reflect.StructOf, as TinyGo currently doesn't.
+func structOf(fields []reflect.StructField) (t reflect.Type, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			t, err = nil, fmt.Errorf("%w: reflect.StructOf: %v", ErrUnsupported, r)
+		}
+	}()
+
+	return reflect.StructOf(fields), nil
+}
+
+// checkTagSyntax checks that tag is a sequence of key:"value" pairs, the
+// format reflect.StructTag.Get understands, without depending on structtag.
+func checkTagSyntax(tag string) error {
+	for tag != "" {
+		// Skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon. A space, a quote or a control character is a syntax error
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return ErrInvalidTag
+		}
+
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+
+			i++
+		}
+
+		if i >= len(tag) {
+			return ErrInvalidTag
+		}
+
+		if _, err := strconv.Unquote(tag[:i+1]); err != nil {
+			return ErrInvalidTag
+		}
+
+		tag = tag[i+1:]
+	}
+
+	return nil
+}
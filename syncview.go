@@ -0,0 +1,111 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// SyncView guards an instance for shared, mutable use: Load and Store read
+// and write its fields under a read-write mutex, so many goroutines can read
+// it while others update it. Values are copied in and out, so callers never
+// share slices, maps or pointers with the instance.
+type SyncView struct {
+	mu     sync.RWMutex
+	value  reflect.Value
+	coerce bool
+}
+
+// SyncView returns a view guarding the instance instancePtr points to, a
+// pointer to a value of the built type. The instance must only be accessed
+// through the view from then on.
+func (b *Builder) SyncView(instancePtr any) (*SyncView, error) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		return nil, ErrInstanceNotBuilt
+	}
+
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Type() != b.instance.Type() {
+		return nil, fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			b.instance.Type().String(),
+		)
+	}
+
+	return &SyncView{value: value, coerce: b.coerce}, nil
+}
+
+// Load returns a copy of the named field's value.
+func (v *SyncView) Load(name string) (any, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	field, ok := fieldByName(v.value, name)
+	if !ok {
+		return nil, fieldError("get", name, missingField(v.value.Type(), name))
+	}
+
+	return deepCopy(field).Interface(), nil
+}
+
+// Store sets the named field to a copy of value, with the conversions
+// SetFieldValue makes.
+func (v *SyncView) Store(name string, value any) error {
+	if newValue := reflect.ValueOf(value); newValue.IsValid() {
+		value = deepCopy(newValue).Interface()
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	field, ok := fieldByNameAlloc(v.value, name)
+	if !ok {
+		return fieldError("set", name, missingField(v.value.Type(), name))
+	}
+
+	if v.coerce {
+		converted, ok, err := convertNumber(field.Type(), reflect.ValueOf(value))
+		if err != nil {
+			return fieldError("set", name, fmt.Errorf("%w: %s", ErrIncompatibleTypes, err.Error()))
+		}
+
+		if ok {
+			value = converted.Interface()
+		}
+	}
+
+	_, err := setField(field, value)
+
+	return fieldError("set", name, err)
+}
+
+// Update calls fn with a pointer to the instance while holding the write
+// lock, to change several fields at once without readers seeing some of the
+// changes but not others. fn must not keep the pointer or call the view.
+func (v *SyncView) Update(fn func(instancePtr any) error) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return fn(v.value.Addr().Interface())
+}
+
+// Snapshot returns a pointer to a copy of the whole instance, read at once.
+func (v *SyncView) Snapshot() any {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	copied := reflect.New(v.value.Type())
+	copied.Elem().Set(deepCopy(v.value))
+
+	return copied.Interface()
+}
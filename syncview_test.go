@@ -0,0 +1,128 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestSyncView(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithCoercion())
+	_ = builder.AddField("Region", "")
+	_ = builder.AddField("Replicas", 0)
+	_ = builder.AddField("Hosts", []string(nil))
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instancePtr, _ := builder.NewInstance()
+
+	view, err := builder.SyncView(instancePtr)
+	if err != nil {
+		t.Fatalf("SyncView() error = %v", err)
+	}
+
+	t.Run("store_and_load", func(t *testing.T) {
+		if err := view.Store("Region", "eu"); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+
+		// Coercion applies as in SetFieldValue
+		if err := view.Store("Replicas", 3.0); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+
+		region, _ := view.Load("Region")
+		replicas, _ := view.Load("Replicas")
+
+		if region != "eu" || replicas != 3 {
+			t.Errorf("Load() = %v, %v, want eu, 3", region, replicas)
+		}
+	})
+
+	t.Run("copies_values", func(t *testing.T) {
+		hosts := []string{"a", "b"}
+		_ = view.Store("Hosts", hosts)
+		hosts[0] = "changed"
+
+		loaded, _ := view.Load("Hosts")
+		loaded.([]string)[1] = "changed"
+
+		if again, _ := view.Load("Hosts"); !reflect.DeepEqual(again, []string{"a", "b"}) {
+			t.Errorf("Load() = %v, want [a b]", again)
+		}
+	})
+
+	t.Run("update_and_snapshot", func(t *testing.T) {
+		err := view.Update(func(instancePtr any) error {
+			value := reflect.ValueOf(instancePtr).Elem()
+			value.FieldByName("Region").SetString("us")
+			value.FieldByName("Replicas").SetInt(5)
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		snapshot := reflect.ValueOf(view.Snapshot()).Elem()
+		if snapshot.FieldByName("Region").String() != "us" || snapshot.FieldByName("Replicas").Int() != 5 {
+			t.Errorf("Snapshot() = %v", snapshot.Interface())
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(2)
+
+			go func(i int) {
+				defer wg.Done()
+
+				_ = view.Store("Replicas", i)
+				_ = view.Store("Hosts", []string{"h"})
+			}(i)
+
+			go func() {
+				defer wg.Done()
+
+				_, _ = view.Load("Replicas")
+				_, _ = view.Load("Hosts")
+				_ = view.Snapshot()
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestSyncViewErrors(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Region", "")
+
+	if _, err := builder.SyncView(&struct{}{}); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		t.Errorf("SyncView() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+	}
+
+	_, _ = builder.Build()
+
+	if _, err := builder.SyncView(&struct{ Other string }{}); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("SyncView() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+
+	instancePtr, _ := builder.NewInstance()
+	view, _ := builder.SyncView(instancePtr)
+
+	if _, err := view.Load("Missing"); !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+		t.Errorf("Load() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+	}
+
+	if err := view.Store("Region", 1); !errors.Is(err, dynamicstruct.ErrIncompatibleTypes) {
+		t.Errorf("Store() error = %v, want %v", err, dynamicstruct.ErrIncompatibleTypes)
+	}
+}
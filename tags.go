@@ -0,0 +1,70 @@
+package dynamicstruct
+
+import (
+	"reflect"
+	"strings"
+)
+
+// validateTagValues checks the values of the tag keys this package interprets,
+// so that a malformed value is reported when the field is added rather than
+// when the tag is first used.
+func validateTagValues(tag reflect.StructTag) error {
+	if ttl, ok := tag.Lookup(ttlTagKey); ok {
+		if _, _, err := parseTTLTag(ttl); err != nil {
+			return err
+		}
+	}
+
+	if nest, ok := tag.Lookup(nestTagKey); ok {
+		if _, err := parseNestTag(nest); err != nil {
+			return err
+		}
+	}
+
+	if ref, ok := tag.Lookup(refTagKey); ok {
+		if _, err := parseRefTag(ref); err != nil {
+			return err
+		}
+	}
+
+	if cborTag, ok := tag.Lookup(cborTagKey); ok {
+		if err := parseCBORTag(cborTag); err != nil {
+			return err
+		}
+	}
+
+	if immutable, ok := tag.Lookup(immutableTagKey); ok {
+		if _, err := parseImmutableTag(immutable); err != nil {
+			return err
+		}
+	}
+
+	if maxDelta, ok := tag.Lookup(maxDeltaTagKey); ok {
+		if _, err := parseMaxDeltaTag(maxDelta); err != nil {
+			return err
+		}
+	}
+
+	if validate, ok := tag.Lookup(validateTagKey); ok {
+		if err := checkValidateTag(validate); err != nil {
+			return err
+		}
+	}
+
+	if transform, ok := tag.Lookup(transformTagKey); ok {
+		if _, err := parseTransformTag(transform); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cutPrefix is strings.CutPrefix, which is not available in Go 1.18.
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+
+	return s[len(prefix):], true
+}
@@ -0,0 +1,241 @@
+// Package testgold snapshots dynamicstruct instances to golden files.
+//
+// Instances are stored as canonical JSON, with sorted keys and indentation, so
+// golden files are stable and reviewable. On mismatch the failure lists the
+// differing fields rather than the whole document:
+//
+//	func TestPipeline(t *testing.T) {
+//		instance := runPipeline(t)
+//		testgold.Assert(t, "pipeline_output", instance)
+//	}
+//
+// Run the tests with UPDATE_GOLDEN=1 to write the golden files.
+package testgold
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that, when set to a non-empty value,
+// makes Assert write golden files instead of comparing against them.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+type config struct {
+	dir    string
+	update bool
+}
+
+type Option func(*config)
+
+// WithDir sets the directory golden files are stored in. It defaults to
+// "testdata".
+func WithDir(dir string) Option {
+	return func(c *config) {
+		c.dir = dir
+	}
+}
+
+// WithUpdate forces golden files to be written, or compared, regardless of
+// UpdateEnv.
+func WithUpdate(update bool) Option {
+	return func(c *config) {
+		c.update = update
+	}
+}
+
+// Path returns the golden file path for name.
+func Path(dir, name string) string {
+	return filepath.Join(dir, name+".golden.json")
+}
+
+// Assert compares the canonical JSON of instance with the golden file for
+// name, reporting each differing field as a test error.
+func Assert(t testing.TB, name string, instance any, opts ...Option) {
+	t.Helper()
+
+	cfg := config{
+		dir:    "testdata",
+		update: os.Getenv(UpdateEnv) != "",
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	got, err := Canonical(instance)
+	if err != nil {
+		t.Fatalf("testgold: %v", err)
+	}
+
+	path := Path(cfg.dir, name)
+
+	if cfg.update {
+		if err := os.MkdirAll(cfg.dir, 0o755); err != nil {
+			t.Fatalf("testgold: %v", err)
+		}
+
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("testgold: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		t.Fatalf("testgold: golden file %s does not exist, run with %s=1 to create it", path, UpdateEnv)
+	case err != nil:
+		t.Fatalf("testgold: %v", err)
+	}
+
+	diffs, err := Diff(want, got)
+	if err != nil {
+		t.Fatalf("testgold: %s: %v", path, err)
+	}
+
+	if len(diffs) > 0 {
+		t.Errorf("testgold: %s mismatch (run with %s=1 to update):\n\t%s", path, UpdateEnv, strings.Join(diffs, "\n\t"))
+	}
+}
+
+// Canonical returns the JSON encoding of instance with object keys sorted,
+// two-space indentation and a trailing newline.
+func Canonical(instance any) ([]byte, error) {
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Maps are marshaled with sorted keys
+	canonical, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(canonical, '\n'), nil
+}
+
+// Diff compares two JSON documents and returns one line per differing field,
+// in path order, such as `$.user.tags[1]: want "a", got "b"`. Equal documents
+// give no lines.
+func Diff(want, got []byte) ([]string, error) {
+	wantValue, err := decode(want)
+	if err != nil {
+		return nil, fmt.Errorf("decode golden: %w", err)
+	}
+
+	gotValue, err := decode(got)
+	if err != nil {
+		return nil, fmt.Errorf("decode actual: %w", err)
+	}
+
+	var diffs []string
+
+	diffValues("$", wantValue, gotValue, &diffs)
+
+	return diffs, nil
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func diffValues(path string, want, got any, diffs *[]string) {
+	switch wantValue := want.(type) {
+	case map[string]any:
+		if gotValue, ok := got.(map[string]any); ok {
+			diffObjects(path, wantValue, gotValue, diffs)
+
+			return
+		}
+	case []any:
+		if gotValue, ok := got.([]any); ok {
+			diffArrays(path, wantValue, gotValue, diffs)
+
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: want %s, got %s", path, format(want), format(got)))
+	}
+}
+
+func diffObjects(path string, want, got map[string]any, diffs *[]string) {
+	keys := make([]string, 0, len(want)+len(got))
+
+	for key := range want {
+		keys = append(keys, key)
+	}
+
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := path + "." + key
+		wantValue, inWant := want[key]
+		gotValue, inGot := got[key]
+
+		switch {
+		case !inGot:
+			*diffs = append(*diffs, fmt.Sprintf("%s: missing, want %s", keyPath, format(wantValue)))
+		case !inWant:
+			*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", keyPath, format(gotValue)))
+		default:
+			diffValues(keyPath, wantValue, gotValue, diffs)
+		}
+	}
+}
+
+func diffArrays(path string, want, got []any, diffs *[]string) {
+	for i := 0; i < len(want) || i < len(got); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		switch {
+		case i >= len(got):
+			*diffs = append(*diffs, fmt.Sprintf("%s: missing, want %s", itemPath, format(want[i])))
+		case i >= len(want):
+			*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", itemPath, format(got[i])))
+		default:
+			diffValues(itemPath, want[i], got[i], diffs)
+		}
+	}
+}
+
+func format(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
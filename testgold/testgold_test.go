@@ -0,0 +1,159 @@
+package testgold_test
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/gosmos-space/dynamicstruct/testgold"
+)
+
+// recorder captures failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.fatal = true
+	r.Errorf(format, args...)
+}
+
+func newInstance(t *testing.T, name string, tags []string) any {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddField("Tags", []string{}, `json:"tags"`)
+	_ = builder.AddField("Meta", map[string]int{}, `json:"meta"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	value := reflect.New(reflect.TypeOf(instance))
+	value.Elem().FieldByName("Name").SetString(name)
+	value.Elem().FieldByName("Tags").Set(reflect.ValueOf(tags))
+	value.Elem().FieldByName("Meta").Set(reflect.ValueOf(map[string]int{"b": 2, "a": 1}))
+
+	return value.Interface()
+}
+
+func TestCanonical(t *testing.T) {
+	data, err := testgold.Canonical(newInstance(t, "alice", []string{"x"}))
+	if err != nil {
+		t.Fatalf("Canonical() error = %v", err)
+	}
+
+	want := `{
+  "meta": {
+    "a": 1,
+    "b": 2
+  },
+  "name": "alice",
+  "tags": [
+    "x"
+  ]
+}
+`
+	if string(data) != want {
+		t.Errorf("Canonical() = %s, want %s", data, want)
+	}
+}
+
+func TestAssert(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing_golden_file", func(t *testing.T) {
+		r := &recorder{TB: t}
+		testgold.Assert(r, "record", newInstance(t, "alice", []string{"x"}), testgold.WithDir(dir), testgold.WithUpdate(false))
+
+		if !r.fatal || !strings.Contains(r.errors[0], testgold.UpdateEnv) {
+			t.Errorf("Assert() errors = %v, want fatal hint about %s", r.errors, testgold.UpdateEnv)
+		}
+	})
+
+	t.Run("update_writes_file", func(t *testing.T) {
+		r := &recorder{TB: t}
+		testgold.Assert(r, "record", newInstance(t, "alice", []string{"x"}), testgold.WithDir(dir), testgold.WithUpdate(true))
+
+		if len(r.errors) > 0 {
+			t.Fatalf("Assert() errors = %v", r.errors)
+		}
+
+		if _, err := os.Stat(testgold.Path(dir, "record")); err != nil {
+			t.Errorf("golden file not written: %v", err)
+		}
+	})
+
+	t.Run("match", func(t *testing.T) {
+		r := &recorder{TB: t}
+		testgold.Assert(r, "record", newInstance(t, "alice", []string{"x"}), testgold.WithDir(dir), testgold.WithUpdate(false))
+
+		if len(r.errors) > 0 {
+			t.Errorf("Assert() errors = %v, want none", r.errors)
+		}
+	})
+
+	t.Run("mismatch_lists_fields", func(t *testing.T) {
+		r := &recorder{TB: t}
+		testgold.Assert(r, "record", newInstance(t, "bob", []string{"x", "y"}), testgold.WithDir(dir), testgold.WithUpdate(false))
+
+		if len(r.errors) != 1 || r.fatal {
+			t.Fatalf("Assert() errors = %v, want one non-fatal error", r.errors)
+		}
+
+		for _, want := range []string{`$.name: want "alice", got "bob"`, `$.tags[1]: unexpected "y"`} {
+			if !strings.Contains(r.errors[0], want) {
+				t.Errorf("Assert() error = %s, want it to contain %s", r.errors[0], want)
+			}
+		}
+	})
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff []string
+	}{
+		{name: "equal", want: `{"a":1,"b":[1,2]}`, got: `{"b":[1,2],"a":1}`, diff: nil},
+		{name: "changed_value", want: `{"a":1}`, got: `{"a":2}`, diff: []string{"$.a: want 1, got 2"}},
+		{name: "missing_field", want: `{"a":1,"b":2}`, got: `{"a":1}`, diff: []string{"$.b: missing, want 2"}},
+		{name: "unexpected_field", want: `{"a":1}`, got: `{"a":1,"c":true}`, diff: []string{"$.c: unexpected true"}},
+		{name: "nested", want: `{"a":{"b":[{"c":"x"}]}}`, got: `{"a":{"b":[{"c":"y"}]}}`, diff: []string{`$.a.b[0].c: want "x", got "y"`}},
+		{name: "missing_item", want: `[1,2]`, got: `[1]`, diff: []string{"$[1]: missing, want 2"}},
+		{name: "type_change", want: `{"a":{"b":1}}`, got: `{"a":[1]}`, diff: []string{`$.a: want {"b":1}, got [1]`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := testgold.Diff([]byte(tt.want), []byte(tt.got))
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(diff, tt.diff) {
+				t.Errorf("Diff() = %v, want %v", diff, tt.diff)
+			}
+		})
+	}
+
+	t.Run("invalid_json", func(t *testing.T) {
+		if _, err := testgold.Diff([]byte(`{`), []byte(`{}`)); err == nil {
+			t.Error("Diff() error = nil, want error")
+		}
+	})
+}
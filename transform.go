@@ -0,0 +1,233 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const transformTagKey = "transform"
+
+// TransformFunc changes a decoded value in place. value is settable and has
+// the type of the field, or of its elements for slices and arrays. param is
+// the text after "=" in the tag, such as "0:100" in "clamp=0:100".
+type TransformFunc func(value reflect.Value, param string) error
+
+var transforms = struct {
+	funcs map[string]TransformFunc
+	m     sync.RWMutex
+}{
+	funcs: map[string]TransformFunc{
+		"trim":     stringTransform(strings.TrimSpace),
+		"lower":    stringTransform(strings.ToLower),
+		"upper":    stringTransform(strings.ToUpper),
+		"collapse": stringTransform(func(s string) string { return strings.Join(strings.Fields(s), " ") }),
+		"clamp":    clampTransform,
+	},
+}
+
+// RegisterTransform makes fn available as name in transform tags, replacing
+// any transformer registered under that name. Register transformers before
+// adding fields that use them, since AddField rejects unknown names.
+func RegisterTransform(name string, fn TransformFunc) {
+	transforms.m.Lock()
+	defer transforms.m.Unlock()
+
+	transforms.funcs[name] = fn
+}
+
+type transformStep struct {
+	fn    TransformFunc
+	name  string
+	param string
+}
+
+// parseTransformTag parses a `transform:"trim,lower"` tag into the
+// transformers to apply, in order.
+func parseTransformTag(tag string) ([]transformStep, error) {
+	transforms.m.RLock()
+	defer transforms.m.RUnlock()
+
+	var steps []transformStep
+
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(part, "=")
+
+		fn, ok := transforms.funcs[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown transform %q", ErrInvalidTag, name)
+		}
+
+		if name == "clamp" {
+			if _, _, err := parseClamp(param); err != nil {
+				return nil, err
+			}
+		}
+
+		steps = append(steps, transformStep{fn: fn, name: name, param: param})
+	}
+
+	return steps, nil
+}
+
+// Transform applies the transformers named in the transform tags of the
+// fields of the struct instancePtr points to, recursing into nested structs.
+// The package's decoding helpers, such as UnmarshalYAML and Loader, call it
+// after decoding.
+func Transform(instancePtr any) error {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return err
+	}
+
+	return transformStruct(value)
+}
+
+// transformDecoded applies Transform to the value a decoder filled in, when it
+// is a struct.
+func transformDecoded(valuePtr reflect.Value) error {
+	value := valuePtr.Elem()
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return transformStruct(value)
+}
+
+func transformStruct(value reflect.Value) error {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		fieldValue := reflect.Indirect(value.Field(i))
+		if !fieldValue.IsValid() || !fieldValue.CanSet() {
+			continue
+		}
+
+		if tag, ok := field.Tag.Lookup(transformTagKey); ok {
+			steps, err := parseTransformTag(tag)
+			if err != nil {
+				return err
+			}
+
+			for _, step := range steps {
+				if err := applyTransform(fieldValue, step); err != nil {
+					return fmt.Errorf("transform %s on field %s: %w", step.name, field.Name, err)
+				}
+			}
+		}
+
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type() != timeType {
+			if err := transformStruct(fieldValue); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func applyTransform(value reflect.Value, step transformStep) error {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := applyTransform(value.Index(i), step); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+
+		return applyTransform(value.Elem(), step)
+	default:
+		return step.fn(value, step.param)
+	}
+}
+
+// stringTransform applies fn to string values and leaves other kinds alone.
+func stringTransform(fn func(string) string) TransformFunc {
+	return func(value reflect.Value, _ string) error {
+		if value.Kind() == reflect.String {
+			value.SetString(fn(value.String()))
+		}
+
+		return nil
+	}
+}
+
+// clampTransform limits numbers to the range in a "min:max" param. Either
+// bound may be empty.
+func clampTransform(value reflect.Value, param string) error {
+	lower, upper, err := parseClamp(param)
+	if err != nil {
+		return err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := float64(value.Int())
+		if lower != nil && v < *lower {
+			value.SetInt(int64(*lower))
+		} else if upper != nil && v > *upper {
+			value.SetInt(int64(*upper))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v := float64(value.Uint())
+		if lower != nil && v < *lower {
+			value.SetUint(uint64(*lower))
+		} else if upper != nil && v > *upper {
+			value.SetUint(uint64(*upper))
+		}
+	case reflect.Float32, reflect.Float64:
+		v := value.Float()
+		if lower != nil && v < *lower {
+			value.SetFloat(*lower)
+		} else if upper != nil && v > *upper {
+			value.SetFloat(*upper)
+		}
+	}
+
+	return nil
+}
+
+func parseClamp(param string) (lower, upper *float64, err error) {
+	lowerText, upperText, ok := strings.Cut(param, ":")
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: clamp %q must be min:max", ErrInvalidTag, param)
+	}
+
+	parse := func(text string) (*float64, error) {
+		text = strings.TrimFunc(text, unicode.IsSpace)
+		if text == "" {
+			return nil, nil
+		}
+
+		bound, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: clamp bound %q is not a number", ErrInvalidTag, text)
+		}
+
+		return &bound, nil
+	}
+
+	if lower, err = parse(lowerText); err != nil {
+		return nil, nil, err
+	}
+
+	if upper, err = parse(upperText); err != nil {
+		return nil, nil, err
+	}
+
+	return lower, upper, nil
+}
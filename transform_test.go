@@ -0,0 +1,221 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+type transformAddressTest struct {
+	City string `transform:"trim,upper"`
+}
+
+func newTransformType(t *testing.T) reflect.Type {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`, `yaml:"name"`, `transform:"trim"`)
+	_ = builder.AddField("Email", "", `json:"email"`, `yaml:"email"`, `transform:"trim,lower"`)
+	_ = builder.AddField("Bio", "", `json:"bio"`, `yaml:"bio"`, `transform:"collapse"`)
+	_ = builder.AddField("Age", 0, `json:"age"`, `yaml:"age"`, `transform:"clamp=0:130"`)
+	_ = builder.AddField("Score", 0.0, `json:"score"`, `yaml:"score"`, `transform:"clamp=:1"`)
+	_ = builder.AddField("Tags", []string{}, `json:"tags"`, `yaml:"tags"`, `transform:"lower"`)
+	_ = builder.AddField("Nick", (*string)(nil), `json:"nick"`, `yaml:"nick"`, `transform:"trim"`)
+	_ = builder.AddField("Address", transformAddressTest{}, `json:"address"`, `yaml:"address"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestTransform(t *testing.T) {
+	structType := newTransformType(t)
+
+	nick := "  al "
+	value := reflect.New(structType)
+	value.Elem().FieldByName("Name").SetString("  Alice ")
+	value.Elem().FieldByName("Email").SetString(" Alice@Example.COM")
+	value.Elem().FieldByName("Bio").SetString(" likes \n\t  go ")
+	value.Elem().FieldByName("Age").SetInt(200)
+	value.Elem().FieldByName("Score").SetFloat(1.5)
+	value.Elem().FieldByName("Tags").Set(reflect.ValueOf([]string{"A", "b", "C"}))
+	value.Elem().FieldByName("Nick").Set(reflect.ValueOf(&nick))
+	value.Elem().FieldByName("Address").Set(reflect.ValueOf(transformAddressTest{City: " oslo "}))
+
+	if err := dynamicstruct.Transform(value.Interface()); err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+
+	want := map[string]any{
+		"Name":    "Alice",
+		"Email":   "alice@example.com",
+		"Bio":     "likes go",
+		"Age":     130,
+		"Score":   1.0,
+		"Tags":    []string{"a", "b", "c"},
+		"Address": transformAddressTest{City: "OSLO"},
+	}
+
+	for name, wantValue := range want {
+		if got := value.Elem().FieldByName(name).Interface(); !reflect.DeepEqual(got, wantValue) {
+			t.Errorf("Transform() %s = %v, want %v", name, got, wantValue)
+		}
+	}
+
+	if nick != "al" {
+		t.Errorf("Transform() Nick = %q, want %q", nick, "al")
+	}
+
+	t.Run("clamp_lower_bound", func(t *testing.T) {
+		value := reflect.New(structType)
+		value.Elem().FieldByName("Age").SetInt(-5)
+
+		if err := dynamicstruct.Transform(value.Interface()); err != nil {
+			t.Fatalf("Transform() error = %v", err)
+		}
+
+		if got := value.Elem().FieldByName("Age").Int(); got != 0 {
+			t.Errorf("Transform() Age = %d, want 0", got)
+		}
+	})
+
+	t.Run("not_a_pointer", func(t *testing.T) {
+		err := dynamicstruct.Transform(value.Elem().Interface())
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("Transform() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+}
+
+func TestTransformAfterDecode(t *testing.T) {
+	structType := newTransformType(t)
+
+	tests := []struct {
+		name   string
+		decode func(instancePtr any) error
+	}{
+		{
+			name: "yaml",
+			decode: func(instancePtr any) error {
+				return dynamicstruct.UnmarshalYAML([]byte("name: ' Bob '\nemail: BOB@X.IO\nage: -1\n"), instancePtr)
+			},
+		},
+		{
+			name: "nested_json",
+			decode: func(instancePtr any) error {
+				return dynamicstruct.UnmarshalNested([]byte(`{"name":" Bob ","email":"BOB@X.IO","age":-1}`), instancePtr)
+			},
+		},
+		{
+			name: "loader_json",
+			decode: func(instancePtr any) error {
+				loader, err := dynamicstruct.NewLoader(instancePtr)
+				if err != nil {
+					return err
+				}
+
+				return loader.JSON([]byte(`{"name":" Bob ","email":"BOB@X.IO","age":-1}`))
+			},
+		},
+		{
+			name: "loader_query",
+			decode: func(instancePtr any) error {
+				loader, err := dynamicstruct.NewLoader(instancePtr)
+				if err != nil {
+					return err
+				}
+
+				return loader.Query(url.Values{"name": {" Bob "}, "email": {"BOB@X.IO"}, "age": {"-1"}})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := reflect.New(structType)
+			if err := tt.decode(value.Interface()); err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+
+			got := []any{
+				value.Elem().FieldByName("Name").Interface(),
+				value.Elem().FieldByName("Email").Interface(),
+				value.Elem().FieldByName("Age").Interface(),
+			}
+
+			want := []any{"Bob", "bob@x.io", 0}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("decode = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRegisterTransform(t *testing.T) {
+	dynamicstruct.RegisterTransform("title", func(value reflect.Value, _ string) error {
+		if value.Kind() == reflect.String && value.Len() > 0 {
+			value.SetString(strings.ToUpper(value.String()[:1]) + value.String()[1:])
+		}
+
+		return nil
+	})
+
+	dynamicstruct.RegisterTransform("fail", func(reflect.Value, string) error {
+		return errors.New("boom")
+	})
+
+	builder := dynamicstruct.New()
+	if err := builder.AddField("City", "", `transform:"trim,title"`); err != nil {
+		t.Fatalf("AddField() error = %v", err)
+	}
+
+	if err := builder.AddField("Bad", "", `transform:"fail"`); err != nil {
+		t.Fatalf("AddField() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	value := reflect.New(reflect.TypeOf(instance))
+	value.Elem().FieldByName("City").SetString(" oslo")
+
+	err = dynamicstruct.Transform(value.Interface())
+	if err == nil || !strings.Contains(err.Error(), "Bad") {
+		t.Errorf("Transform() error = %v, want error naming field Bad", err)
+	}
+
+	if got := value.Elem().FieldByName("City").String(); got != "Oslo" {
+		t.Errorf("Transform() City = %q, want %q", got, "Oslo")
+	}
+}
+
+func TestTransformTagValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+	}{
+		{name: "unknown_transform", tag: `transform:"trim,shout"`},
+		{name: "clamp_without_range", tag: `transform:"clamp=5"`},
+		{name: "clamp_bad_bound", tag: `transform:"clamp=a:5"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+
+			err := builder.AddField("Value", 0, tt.tag)
+			if !errors.Is(err, dynamicstruct.ErrInvalidTag) {
+				t.Errorf("AddField() error = %v, want %v", err, dynamicstruct.ErrInvalidTag)
+			}
+		})
+	}
+}
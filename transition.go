@@ -0,0 +1,227 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	immutableTagKey = "immutable"
+	maxDeltaTagKey  = "maxdelta"
+)
+
+// parseImmutableTag parses an `immutable:"true"` tag.
+func parseImmutableTag(tag string) (bool, error) {
+	immutable, err := strconv.ParseBool(tag)
+	if err != nil {
+		return false, fmt.Errorf("%w: immutable %q must be a boolean", ErrInvalidTag, tag)
+	}
+
+	return immutable, nil
+}
+
+// parseMaxDeltaTag parses a `maxdelta:"10"` or `maxdelta:"1h"` tag into the
+// largest allowed change, durations in nanoseconds.
+func parseMaxDeltaTag(tag string) (float64, error) {
+	delta, err := strconv.ParseFloat(tag, 64)
+	if err != nil {
+		duration, durationErr := time.ParseDuration(tag)
+		if durationErr != nil {
+			return 0, fmt.Errorf("%w: maxdelta %q must be a number or a duration", ErrInvalidTag, tag)
+		}
+
+		delta = float64(duration)
+	}
+
+	if delta < 0 || math.IsNaN(delta) {
+		return 0, fmt.Errorf("%w: maxdelta %q must not be negative", ErrInvalidTag, tag)
+	}
+
+	return delta, nil
+}
+
+// ValidateTransition checks that changing an instance from old to new, both
+// values of the same struct type or pointers to them, respects the transition
+// rules in the tags of its fields:
+//
+//   - `immutable:"true"` fields must not change.
+//   - `maxdelta:"10"` numeric fields must not change by more than 10 in either
+//     direction. On time.Time and time.Duration fields the limit may be a
+//     duration, such as `maxdelta:"24h"`.
+//
+// Nested and embedded structs are checked recursively; an immutable nested or
+// embedded struct must not change in any of its fields. It returns
+// ValidationErrors, with *RuleError reasons, when any field breaks a rule.
+func ValidateTransition(old, new any) error {
+	oldValue, err := structValue(old)
+	if err != nil {
+		return err
+	}
+
+	newValue, err := structValue(new)
+	if err != nil {
+		return err
+	}
+
+	if oldValue.Type() != newValue.Type() {
+		return fmt.Errorf(
+			"%w: old type: %s, new type: %s",
+			ErrIncompatibleTypes,
+			oldValue.Type().String(),
+			newValue.Type().String(),
+		)
+	}
+
+	errs := ValidationErrors{}
+	if err := validateTransitionStruct(oldValue, newValue, "", errs); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateTransitionStruct(old, new reflect.Value, prefix string, errs ValidationErrors) error {
+	structType := old.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		oldField := old.Field(i)
+		newField := new.Field(i)
+
+		if field.Anonymous {
+			changed, err := immutableChanged(field, oldField, newField)
+			if err != nil {
+				return err
+			}
+
+			// An immutable embedded struct is reported as a whole
+			if changed {
+				errs[prefix+field.Name] = &RuleError{Rule: immutableTagKey, Param: "true", msg: "must not change"}
+
+				continue
+			}
+
+			oldEmbedded := reflect.Indirect(oldField)
+			newEmbedded := reflect.Indirect(newField)
+
+			// Fields of embedded structs are reported under their promoted names
+			if oldEmbedded.Kind() == reflect.Struct && newEmbedded.Kind() == reflect.Struct {
+				if err := validateTransitionStruct(oldEmbedded, newEmbedded, prefix, errs); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		path := prefix + field.Name
+
+		if err := validateTransitionField(field, oldField, newField, path, errs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateTransitionField(field reflect.StructField, old, new reflect.Value, path string, errs ValidationErrors) error {
+	changed, err := immutableChanged(field, old, new)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		errs[path] = &RuleError{Rule: immutableTagKey, Param: "true", msg: "must not change"}
+
+		return nil
+	}
+
+	if tag, ok := field.Tag.Lookup(maxDeltaTagKey); ok {
+		maxDelta, err := parseMaxDeltaTag(tag)
+		if err != nil {
+			return err
+		}
+
+		delta, ok, err := transitionDelta(old, new, path)
+		if err != nil {
+			return err
+		}
+
+		if ok && delta > maxDelta {
+			errs[path] = &RuleError{Rule: maxDeltaTagKey, Param: tag, msg: "must not change by more than " + tag}
+
+			return nil
+		}
+	}
+
+	old = reflect.Indirect(old)
+	new = reflect.Indirect(new)
+
+	if old.Kind() == reflect.Struct && new.Kind() == reflect.Struct && old.Type() != timeType {
+		return validateTransitionStruct(old, new, path+".", errs)
+	}
+
+	return nil
+}
+
+// immutableChanged reports whether field is immutable and its value changed.
+func immutableChanged(field reflect.StructField, old, new reflect.Value) (bool, error) {
+	tag, ok := field.Tag.Lookup(immutableTagKey)
+	if !ok {
+		return false, nil
+	}
+
+	immutable, err := parseImmutableTag(tag)
+	if err != nil {
+		return false, err
+	}
+
+	return immutable && !reflect.DeepEqual(old.Interface(), new.Interface()), nil
+}
+
+// transitionDelta returns the size of the change from old to new, durations
+// and times in nanoseconds. It reports false when either is a nil pointer.
+func transitionDelta(old, new reflect.Value, path string) (float64, bool, error) {
+	if old.Kind() == reflect.Ptr {
+		if old.IsNil() || new.IsNil() {
+			return 0, false, nil
+		}
+
+		old = old.Elem()
+		new = new.Elem()
+	}
+
+	if old.Type() == timeType {
+		oldTime, _ := old.Interface().(time.Time)
+		newTime, _ := new.Interface().(time.Time)
+
+		return math.Abs(float64(newTime.Sub(oldTime))), true, nil
+	}
+
+	switch old.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return math.Abs(float64(new.Int()) - float64(old.Int())), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return math.Abs(float64(new.Uint()) - float64(old.Uint())), true, nil
+	case reflect.Float32, reflect.Float64:
+		return math.Abs(new.Float() - old.Float()), true, nil
+	default:
+		return 0, false, fmt.Errorf(
+			"%w: maxdelta field %s must be a number or a time, got %s",
+			ErrIncompatibleTypes,
+			path,
+			old.Type(),
+		)
+	}
+}
@@ -0,0 +1,179 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newTransitionType(t *testing.T) reflect.Type {
+	t.Helper()
+
+	limits := dynamicstruct.New()
+	_ = limits.AddField("Burst", 0, `maxdelta:"5"`)
+
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(ContactTest{}, `immutable:"true"`)
+	_ = builder.AddField("Region", "", `immutable:"true"`)
+	_ = builder.AddField("Replicas", 0, `maxdelta:"10"`)
+	_ = builder.AddField("Ratio", 0.0, `maxdelta:"0.25"`)
+	_ = builder.AddField("Timeout", time.Duration(0), `maxdelta:"1m"`)
+	_ = builder.AddField("Window", (*time.Time)(nil), `maxdelta:"24h"`)
+	_ = builder.AddField("Label", "", `immutable:"false"`)
+
+	limitsInstance, err := limits.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_ = builder.AddField("Limits", limitsInstance)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestValidateTransition(t *testing.T) {
+	structType := newTransitionType(t)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	newConfig := func(edit func(v reflect.Value)) any {
+		value := reflect.New(structType).Elem()
+		value.FieldByName("Region").SetString("eu")
+		value.FieldByName("Replicas").SetInt(20)
+		value.FieldByName("Ratio").SetFloat(0.5)
+		value.FieldByName("Timeout").SetInt(int64(time.Minute))
+		value.FieldByName("Window").Set(reflect.ValueOf(&day))
+		value.FieldByName("Limits").FieldByName("Burst").SetInt(10)
+
+		if edit != nil {
+			edit(value)
+		}
+
+		return value.Addr().Interface()
+	}
+
+	tests := []struct {
+		name       string
+		edit       func(v reflect.Value)
+		wantFields []string
+	}{
+		{name: "unchanged"},
+		{
+			name: "within_limits",
+			edit: func(v reflect.Value) {
+				v.FieldByName("Replicas").SetInt(10)
+				v.FieldByName("Ratio").SetFloat(0.75)
+				v.FieldByName("Timeout").SetInt(int64(2 * time.Minute))
+				next := day.Add(24 * time.Hour)
+				v.FieldByName("Window").Set(reflect.ValueOf(&next))
+				v.FieldByName("Label").SetString("changed")
+			},
+		},
+		{
+			name: "immutable_changed",
+			edit: func(v reflect.Value) {
+				v.FieldByName("Region").SetString("us")
+				v.FieldByName("Email").SetString("new@example.com")
+			},
+			wantFields: []string{"ContactTest", "Region"},
+		},
+		{
+			name: "delta_exceeded",
+			edit: func(v reflect.Value) {
+				v.FieldByName("Replicas").SetInt(31)
+				v.FieldByName("Ratio").SetFloat(0.1)
+				v.FieldByName("Timeout").SetInt(int64(3 * time.Minute))
+				next := day.Add(-25 * time.Hour)
+				v.FieldByName("Window").Set(reflect.ValueOf(&next))
+				v.FieldByName("Limits").FieldByName("Burst").SetInt(16)
+			},
+			wantFields: []string{"Limits.Burst", "Ratio", "Replicas", "Timeout", "Window"},
+		},
+		{
+			name: "nil_pointer_skipped",
+			edit: func(v reflect.Value) {
+				v.FieldByName("Window").Set(reflect.Zero(v.FieldByName("Window").Type()))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.ValidateTransition(newConfig(nil), newConfig(tt.edit))
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateTransition() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			var errs dynamicstruct.ValidationErrors
+			if !errors.As(err, &errs) || !errors.Is(err, dynamicstruct.ErrValidation) {
+				t.Fatalf("ValidateTransition() error = %v, want ValidationErrors", err)
+			}
+
+			if len(errs) != len(tt.wantFields) {
+				t.Errorf("ValidateTransition() error = %v, want fields %v", err, tt.wantFields)
+			}
+
+			for _, field := range tt.wantFields {
+				if _, ok := errs[field]; !ok {
+					t.Errorf("ValidateTransition() missing field %s in %v", field, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTransitionErrors(t *testing.T) {
+	type wrongKind struct {
+		Name string `maxdelta:"1"`
+	}
+
+	tests := []struct {
+		name    string
+		old     any
+		new     any
+		wantErr error
+	}{
+		{name: "different_types", old: PersonTest{}, new: AddressTest{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "not_struct", old: 1, new: 2, wantErr: dynamicstruct.ErrInvalidInstance},
+		{name: "maxdelta_on_string", old: wrongKind{}, new: wrongKind{Name: "x"}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := dynamicstruct.ValidateTransition(tt.old, tt.new); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateTransition() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransitionTagValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+	}{
+		{name: "immutable_not_bool", tag: `immutable:"yes"`},
+		{name: "maxdelta_not_number", tag: `maxdelta:"lots"`},
+		{name: "maxdelta_negative", tag: `maxdelta:"-1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dynamicstruct.New().AddField("Value", 0, tt.tag)
+			if !errors.Is(err, dynamicstruct.ErrInvalidTag) {
+				t.Errorf("AddField() error = %v, want %v", err, dynamicstruct.ErrInvalidTag)
+			}
+		})
+	}
+}
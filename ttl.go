@@ -0,0 +1,142 @@
+package dynamicstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const ttlTagKey = "ttl"
+
+// parseTTLTag parses a `ttl:"24h,since=FetchedAt"` tag into the TTL and the
+// name of the time.Time field the TTL counts from.
+func parseTTLTag(tag string) (time.Duration, string, error) {
+	durationPart, options, _ := strings.Cut(tag, ",")
+
+	ttl, err := time.ParseDuration(durationPart)
+	if err != nil || ttl <= 0 {
+		return 0, "", fmt.Errorf("%w: ttl %q must be a positive duration", ErrInvalidTag, tag)
+	}
+
+	var since string
+
+	for _, option := range strings.Split(options, ",") {
+		if value, ok := cutPrefix(option, "since="); ok {
+			since = value
+		}
+	}
+
+	if since == "" {
+		return 0, "", fmt.Errorf("%w: ttl %q must name a since= field", ErrInvalidTag, tag)
+	}
+
+	return ttl, since, nil
+}
+
+// Expire zeroes every field of the struct instancePtr points to whose TTL has
+// elapsed at now, and returns the names of the expired fields. A field with a
+// `ttl:"24h,since=FetchedAt"` tag expires 24 hours after the time stored in
+// its FetchedAt field; a zero or nil FetchedAt counts as expired. A FetchedAt
+// promoted through a nil embedded pointer holds no time, and its fields don't
+// expire.
+func Expire(instancePtr any, now time.Time) ([]string, error) {
+	value, err := structPtrValue(instancePtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return expire(value, now)
+}
+
+func expire(value reflect.Value, now time.Time) ([]string, error) {
+	var expired []string
+
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		tag, ok := structType.Field(i).Tag.Lookup(ttlTagKey)
+		if !ok {
+			continue
+		}
+
+		ttl, since, err := parseTTLTag(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		stamp, ok, err := timeField(value, since)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if !stamp.IsZero() && now.Sub(stamp) <= ttl {
+			continue
+		}
+
+		field := value.Field(i)
+		if !field.IsZero() {
+			field.Set(reflect.Zero(field.Type()))
+			expired = append(expired, structType.Field(i).Name)
+		}
+	}
+
+	return expired, nil
+}
+
+// timeField returns the time stored in the named field of value, and false
+// when the field is promoted through a nil embedded pointer and holds no time.
+func timeField(value reflect.Value, name string) (time.Time, bool, error) {
+	if _, ok := value.Type().FieldByName(name); !ok {
+		return time.Time{}, false, fmt.Errorf("%w: ttl since field %s", missingField(value.Type(), name), name)
+	}
+
+	field, ok := fieldByName(value, name)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return time.Time{}, true, nil
+		}
+
+		field = field.Elem()
+	}
+
+	stamp, ok := field.Interface().(time.Time)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf(
+			"%w: ttl since field %s must be time.Time, got %s",
+			ErrIncompatibleTypes,
+			name,
+			field.Type(),
+		)
+	}
+
+	return stamp, true, nil
+}
+
+// MarshalUnexpired marshals a copy of instance to JSON with the fields expired
+// at now zeroed, leaving instance itself untouched. Combine with omitempty to
+// leave expired fields out of the document entirely.
+func MarshalUnexpired(instance any, now time.Time) ([]byte, error) {
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := reflect.New(value.Type()).Elem()
+	clone.Set(value)
+
+	if _, err := expire(clone, now); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(clone.Addr().Interface())
+}
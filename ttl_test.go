@@ -0,0 +1,190 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func newProfileType(t *testing.T) reflect.Type {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `json:"name"`)
+	_ = builder.AddField("EnrichedAt", time.Time{}, `json:"-"`)
+	_ = builder.AddField("Score", int(0), `json:"score,omitempty"`, `ttl:"1h,since=EnrichedAt"`)
+	_ = builder.AddField("Segments", []string{}, `json:"segments,omitempty"`, `ttl:"24h,since=EnrichedAt"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return reflect.TypeOf(instance)
+}
+
+func TestExpire(t *testing.T) {
+	enrichedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		wantExpired []string
+	}{
+		{name: "nothing_expired", now: enrichedAt.Add(30 * time.Minute), wantExpired: nil},
+		{name: "short_ttl_expired", now: enrichedAt.Add(2 * time.Hour), wantExpired: []string{"Score"}},
+		{name: "all_expired", now: enrichedAt.Add(48 * time.Hour), wantExpired: []string{"Score", "Segments"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := reflect.New(newProfileType(t))
+			instance.Elem().FieldByName("Name").SetString("alice")
+			instance.Elem().FieldByName("EnrichedAt").Set(reflect.ValueOf(enrichedAt))
+			instance.Elem().FieldByName("Score").SetInt(10)
+			instance.Elem().FieldByName("Segments").Set(reflect.ValueOf([]string{"vip"}))
+
+			expired, err := dynamicstruct.Expire(instance.Interface(), tt.now)
+			if err != nil {
+				t.Fatalf("Expire() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(expired, tt.wantExpired) {
+				t.Errorf("Expire() = %v, want %v", expired, tt.wantExpired)
+			}
+
+			for _, name := range tt.wantExpired {
+				if !instance.Elem().FieldByName(name).IsZero() {
+					t.Errorf("field %s should be zeroed", name)
+				}
+			}
+
+			if instance.Elem().FieldByName("Name").String() != "alice" {
+				t.Error("fields without ttl should be kept")
+			}
+		})
+	}
+
+	t.Run("zero_since_counts_as_expired", func(t *testing.T) {
+		instance := reflect.New(newProfileType(t))
+		instance.Elem().FieldByName("Score").SetInt(10)
+
+		expired, err := dynamicstruct.Expire(instance.Interface(), enrichedAt)
+		if err != nil {
+			t.Fatalf("Expire() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(expired, []string{"Score"}) {
+			t.Errorf("Expire() = %v, want [Score]", expired)
+		}
+	})
+}
+
+type AuditTest struct {
+	EnrichedAt time.Time
+}
+
+func TestExpireEmbeddedPointer(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddAnonymousField(&AuditTest{})
+	_ = builder.AddField("Score", int(0), `ttl:"1h,since=EnrichedAt"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("nil_embedded_pointer_does_not_expire", func(t *testing.T) {
+		instancePtr := reflect.New(reflect.TypeOf(instance))
+		instancePtr.Elem().FieldByName("Score").SetInt(10)
+
+		expired, err := dynamicstruct.Expire(instancePtr.Interface(), now)
+		if err != nil {
+			t.Fatalf("Expire() error = %v", err)
+		}
+
+		if len(expired) != 0 || instancePtr.Elem().FieldByName("Score").Int() != 10 {
+			t.Errorf("Expire() = %v, want no expired fields", expired)
+		}
+	})
+
+	t.Run("set_embedded_pointer", func(t *testing.T) {
+		instancePtr := reflect.New(reflect.TypeOf(instance))
+		instancePtr.Elem().FieldByName("AuditTest").Set(reflect.ValueOf(&AuditTest{EnrichedAt: now.Add(-2 * time.Hour)}))
+		instancePtr.Elem().FieldByName("Score").SetInt(10)
+
+		expired, err := dynamicstruct.Expire(instancePtr.Interface(), now)
+		if err != nil {
+			t.Fatalf("Expire() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(expired, []string{"Score"}) {
+			t.Errorf("Expire() = %v, want [Score]", expired)
+		}
+	})
+}
+
+func TestMarshalUnexpired(t *testing.T) {
+	enrichedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	instance := reflect.New(newProfileType(t))
+	instance.Elem().FieldByName("Name").SetString("alice")
+	instance.Elem().FieldByName("EnrichedAt").Set(reflect.ValueOf(enrichedAt))
+	instance.Elem().FieldByName("Score").SetInt(10)
+	instance.Elem().FieldByName("Segments").Set(reflect.ValueOf([]string{"vip"}))
+
+	data, err := dynamicstruct.MarshalUnexpired(instance.Interface(), enrichedAt.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("MarshalUnexpired() error = %v", err)
+	}
+
+	want := `{"name":"alice","segments":["vip"]}`
+	if string(data) != want {
+		t.Errorf("MarshalUnexpired() = %s, want %s", data, want)
+	}
+
+	if instance.Elem().FieldByName("Score").Int() != 10 {
+		t.Error("MarshalUnexpired() should not modify the instance")
+	}
+}
+
+func TestTTLTagValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		wantErr error
+	}{
+		{name: "valid", tag: `ttl:"5m,since=UpdatedAt"`, wantErr: nil},
+		{name: "invalid_duration", tag: `ttl:"soon,since=UpdatedAt"`, wantErr: dynamicstruct.ErrInvalidTag},
+		{name: "negative_duration", tag: `ttl:"-5m,since=UpdatedAt"`, wantErr: dynamicstruct.ErrInvalidTag},
+		{name: "missing_since", tag: `ttl:"5m"`, wantErr: dynamicstruct.ErrInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := dynamicstruct.New()
+
+			err := builder.AddField("Value", "", tt.tag)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddField() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing_since_field", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Value", "", `ttl:"5m,since=UpdatedAt"`)
+
+		instance, _ := builder.Build()
+
+		_, err := dynamicstruct.Expire(reflect.New(reflect.TypeOf(instance)).Interface(), time.Now())
+		if !errors.Is(err, dynamicstruct.ErrFieldNotFound) {
+			t.Errorf("Expire() error = %v, want %v", err, dynamicstruct.ErrFieldNotFound)
+		}
+	})
+}
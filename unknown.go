@@ -0,0 +1,202 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var unknownFieldType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// CaptureUnknown adds a map[string]json.RawMessage field, tagged json:"-",
+// that DecodeJSON fills with the keys of the decoded object that no field
+// takes, and EncodeJSON writes back, so open-ended payloads round-trip
+// without losing keys. A builder captures unknown keys in one field only.
+func (b *Builder) CaptureUnknown(name string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.unknownField != "" {
+		return fieldError("add", name, fmt.Errorf("%w: unknown keys are captured by %s", ErrFieldAlreadyExists, b.unknownField))
+	}
+
+	if err := b.addField(name, unknownFieldType, []string{`json:"-"`}); err != nil {
+		return err
+	}
+
+	b.unknownField = name
+
+	return nil
+}
+
+// captureUnknown sets the named field of value to the keys of the JSON object
+// data that none of the fields of value takes, matched case-insensitively as
+// encoding/json matches them. The field is nil when every key is known.
+func captureUnknown(value reflect.Value, name string, data []byte) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+
+	known := jsonKeys(value.Type())
+
+	var unknown map[string]json.RawMessage
+
+	for key, raw := range object {
+		if isKnownKey(known, key) {
+			continue
+		}
+
+		if unknown == nil {
+			unknown = make(map[string]json.RawMessage)
+		}
+
+		unknown[key] = raw
+	}
+
+	field, ok := fieldByNameAlloc(value, name)
+	if !ok {
+		return fieldError("decode", name, missingField(value.Type(), name))
+	}
+
+	field.Set(reflect.ValueOf(unknown))
+
+	return nil
+}
+
+// EncodeJSON marshals instance, an instance of the built type or a pointer to
+// one, like json.Marshal, and adds the keys captured by the CaptureUnknown
+// field after those of the fields, sorted. Captured keys that a field now
+// takes are left out, so the field's value wins.
+func (b *Builder) EncodeJSON(instance any) ([]byte, error) {
+	b.m.RLock()
+
+	// Check if instance is built
+	if b.instance == nil {
+		b.m.RUnlock()
+
+		return nil, ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+	unknownField := b.unknownField
+
+	b.m.RUnlock()
+
+	value, err := structValue(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Type() != structType {
+		return nil, fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			structType.String(),
+		)
+	}
+
+	return encodeJSON(instance, value, unknownField)
+}
+
+// encodeJSON encodes instance, whose struct value is value, as EncodeJSON
+// does.
+func encodeJSON(instance any, value reflect.Value, unknownField string) ([]byte, error) {
+	structType := value.Type()
+
+	data, err := json.Marshal(instance)
+	if err != nil || unknownField == "" {
+		return data, err
+	}
+
+	field, ok := fieldByName(value, unknownField)
+	if !ok || field.Len() == 0 {
+		return data, nil
+	}
+
+	unknown, _ := field.Interface().(map[string]json.RawMessage)
+	known := jsonKeys(structType)
+
+	keys := make([]string, 0, len(unknown))
+	for key := range unknown {
+		if !isKnownKey(known, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+
+	buf.Write(data[:len(data)-1])
+
+	for _, key := range keys {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+
+		if err := json.Compact(&buf, unknown[key]); err != nil {
+			return nil, fieldError("get", unknownField, fmt.Errorf("%w: key %q: %s", ErrIncompatibleTypes, key, err.Error()))
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// jsonKeys returns the JSON keys of the fields of structType, with those of
+// embedded structs promoted as encoding/json promotes them.
+func jsonKeys(structType reflect.Type) []string {
+	var keys []string
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		if field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if tag == "" && embedded.Kind() == reflect.Struct {
+				keys = append(keys, jsonKeys(embedded)...)
+
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name, ok := jsonName(field); ok {
+			keys = append(keys, name)
+		}
+	}
+
+	return keys
+}
+
+func isKnownKey(known []string, key string) bool {
+	for _, name := range known {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+
+	return false
+}
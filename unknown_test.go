@@ -0,0 +1,106 @@
+package dynamicstruct_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestCaptureUnknown(t *testing.T) {
+	builder := dynamicstruct.New()
+	_ = builder.AddField("ID", int64(0), `json:"id"`)
+	_ = builder.AddField("Name", "", `json:"name"`)
+
+	if err := builder.CaptureUnknown("Extra"); err != nil {
+		t.Fatalf("CaptureUnknown() error = %v", err)
+	}
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	data := []byte(`{"id": 1, "NAME": "Alice", "plan": {"tier": "pro"}, "tags": ["a"]}`)
+
+	instancePtr, _ := builder.NewInstance()
+	if err := builder.DecodeJSON(data, instancePtr); err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+
+	extra := reflect.ValueOf(instancePtr).Elem().FieldByName("Extra").Interface().(map[string]json.RawMessage)
+
+	t.Run("captures_unknown_keys", func(t *testing.T) {
+		want := map[string]string{"plan": `{"tier": "pro"}`, "tags": `["a"]`}
+
+		if len(extra) != len(want) {
+			t.Fatalf("Extra = %v, want %v", extra, want)
+		}
+
+		for key, raw := range want {
+			if string(extra[key]) != raw {
+				t.Errorf("Extra[%q] = %s, want %s", key, extra[key], raw)
+			}
+		}
+	})
+
+	t.Run("encode_round_trip", func(t *testing.T) {
+		out, err := builder.EncodeJSON(instancePtr)
+		if err != nil {
+			t.Fatalf("EncodeJSON() error = %v", err)
+		}
+
+		want := `{"id":1,"name":"Alice","plan":{"tier":"pro"},"tags":["a"]}`
+		if string(out) != want {
+			t.Errorf("EncodeJSON() = %s, want %s", out, want)
+		}
+	})
+
+	t.Run("known_field_wins", func(t *testing.T) {
+		extra["name"] = json.RawMessage(`"Mallory"`)
+		defer delete(extra, "name")
+
+		out, _ := builder.EncodeJSON(instancePtr)
+		if want := `{"id":1,"name":"Alice","plan":{"tier":"pro"},"tags":["a"]}`; string(out) != want {
+			t.Errorf("EncodeJSON() = %s, want %s", out, want)
+		}
+	})
+
+	t.Run("no_unknown_keys", func(t *testing.T) {
+		if err := builder.DecodeJSON([]byte(`{"id": 2}`), instancePtr); err != nil {
+			t.Fatalf("DecodeJSON() error = %v", err)
+		}
+
+		if extra := reflect.ValueOf(instancePtr).Elem().FieldByName("Extra"); !extra.IsNil() {
+			t.Errorf("Extra = %v, want nil", extra)
+		}
+	})
+}
+
+func TestCaptureUnknownErrors(t *testing.T) {
+	t.Run("second_field", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.CaptureUnknown("Extra")
+
+		if err := builder.CaptureUnknown("More"); !errors.Is(err, dynamicstruct.ErrFieldAlreadyExists) {
+			t.Errorf("CaptureUnknown() error = %v, want %v", err, dynamicstruct.ErrFieldAlreadyExists)
+		}
+	})
+
+	t.Run("removed_field", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.CaptureUnknown("Extra")
+		_ = builder.RemoveField("Extra")
+
+		if err := builder.CaptureUnknown("More"); err != nil {
+			t.Errorf("CaptureUnknown() error = %v", err)
+		}
+	})
+
+	t.Run("encode_not_built", func(t *testing.T) {
+		if _, err := dynamicstruct.New().EncodeJSON(struct{}{}); !errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+			t.Errorf("EncodeJSON() error = %v, want %v", err, dynamicstruct.ErrInstanceNotBuilt)
+		}
+	})
+}
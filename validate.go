@@ -0,0 +1,377 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationErrors maps the names of the fields that failed validation, such
+// as "Email", "Address.City" or "Tags[2]", to the reason. The reasons are
+// *RuleError values.
+type ValidationErrors map[string]error
+
+func (e ValidationErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field+": "+e[field].Error())
+	}
+
+	return ErrValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// RuleError is the validate tag rule a field value doesn't satisfy.
+type RuleError struct {
+	Rule  string
+	Param string
+	msg   string
+}
+
+func (e *RuleError) Error() string {
+	return e.msg
+}
+
+// Validate checks instance, a value of the built type or a pointer to one,
+// against the go-playground/validator style rules in the validate tags of its
+// fields. It returns ValidationErrors when any field fails.
+//
+// Nested structs are validated recursively, and "dive" applies the rules after
+// it to the elements of slices, arrays and maps. The rules are required,
+// omitempty, len, min, max, eq, ne, gt, gte, lt, lte, oneof, contains,
+// excludes, startswith, endswith, alpha, alphanum, numeric, email, url, uri,
+// uuid, ip, ipv4, ipv6 and hostname; adding a field with any other rule, or
+// with a size rule whose parameter isn't a number, fails with ErrInvalidTag.
+func (b *Builder) Validate(instance any) error {
+	b.m.RLock()
+	if b.instance == nil {
+		b.m.RUnlock()
+
+		return ErrInstanceNotBuilt
+	}
+
+	structType := b.instance.Type()
+	b.m.RUnlock()
+
+	value, err := structValue(instance)
+	if err != nil {
+		return err
+	}
+
+	if value.Type() != structType {
+		return fmt.Errorf(
+			"%w: instance type: %s, built type: %s",
+			ErrIncompatibleTypes,
+			value.Type().String(),
+			structType.String(),
+		)
+	}
+
+	errs := ValidationErrors{}
+	validateStruct(value, "", errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateStruct(value reflect.Value, prefix string, errs ValidationErrors) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		path := prefix + field.Name
+
+		// Fields of embedded structs are reported under their promoted names
+		if field.Anonymous {
+			path = prefix
+		}
+
+		rules, dive := parseValidateTag(field.Tag.Get(validateTagKey))
+		fieldValue := value.Field(i)
+
+		if field.Anonymous {
+			if embedded := reflect.Indirect(fieldValue); embedded.Kind() == reflect.Struct {
+				validateStruct(embedded, path, errs)
+			}
+
+			continue
+		}
+
+		validateValue(fieldValue, path, rules, dive, errs)
+	}
+}
+
+func validateValue(value reflect.Value, path string, rules []validateRule, dive string, errs ValidationErrors) {
+	for _, rule := range rules {
+		switch rule.name {
+		case "omitempty":
+			if !hasValue(value) {
+				return
+			}
+
+			continue
+		case "required":
+			if !hasValue(value) {
+				errs[path] = &RuleError{Rule: rule.name, msg: "is required"}
+
+				return
+			}
+
+			continue
+		}
+
+		// Other rules apply to the value a pointer points to
+		target := value
+		for target.Kind() == reflect.Ptr || target.Kind() == reflect.Interface {
+			if target.IsNil() {
+				break
+			}
+
+			target = target.Elem()
+		}
+
+		if (target.Kind() == reflect.Ptr || target.Kind() == reflect.Interface) && target.IsNil() {
+			return
+		}
+
+		if err := checkRule(target, rule); err != nil {
+			errs[path] = err
+
+			return
+		}
+	}
+
+	value = reflect.Indirect(value)
+
+	if dive != "" {
+		elemRules, elemDive := parseValidateTag(dive)
+
+		switch value.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i), elemRules, elemDive, errs)
+			}
+		case reflect.Map:
+			iter := value.MapRange()
+			for iter.Next() {
+				validateValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), elemRules, elemDive, errs)
+			}
+		}
+	}
+
+	if value.Kind() == reflect.Struct && value.Type() != timeType {
+		validateStruct(value, path+".", errs)
+	}
+}
+
+// hasValue reports whether value satisfies required: non-nil for nillable
+// kinds and non-zero otherwise.
+func hasValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+		return !value.IsNil()
+	default:
+		return !value.IsZero()
+	}
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	alphaPattern    = regexp.MustCompile(`^[a-zA-Z]+$`)
+	alphanumPattern = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	numericPattern  = regexp.MustCompile(`^[-+]?[0-9]+(?:\.[0-9]+)?$`)
+	hostnamePattern = regexp.MustCompile(`^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]{0,61}[a-zA-Z0-9])(\.([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]{0,61}[a-zA-Z0-9]))*$`)
+)
+
+// checkRule returns a *RuleError when value doesn't satisfy rule, and nil when
+// it does or the rule doesn't apply to the kind of value.
+func checkRule(value reflect.Value, rule validateRule) error {
+	if rule.or != nil {
+		var messages []string
+
+		for _, alternative := range rule.or {
+			err := checkRule(value, alternative)
+			if err == nil {
+				return nil
+			}
+
+			messages = append(messages, err.Error())
+		}
+
+		return &RuleError{Rule: rule.name, msg: strings.Join(messages, " or ")}
+	}
+
+	ok, msg := true, ""
+
+	switch rule.name {
+	case "len":
+		ok, msg = sizeRule(value, rule.param, func(c int) bool { return c == 0 }, "exactly")
+	case "min", "gte":
+		ok, msg = sizeRule(value, rule.param, func(c int) bool { return c >= 0 }, "at least")
+	case "max", "lte":
+		ok, msg = sizeRule(value, rule.param, func(c int) bool { return c <= 0 }, "at most")
+	case "gt":
+		ok, msg = sizeRule(value, rule.param, func(c int) bool { return c > 0 }, "greater than")
+	case "lt":
+		ok, msg = sizeRule(value, rule.param, func(c int) bool { return c < 0 }, "less than")
+	case "eq":
+		if equal, applies := equalRule(value, rule.param); applies {
+			ok, msg = equal, fmt.Sprintf("must equal %s", rule.param)
+		}
+	case "ne":
+		if equal, applies := equalRule(value, rule.param); applies {
+			ok, msg = !equal, fmt.Sprintf("must not equal %s", rule.param)
+		}
+	case "oneof":
+		for _, option := range strings.Fields(rule.param) {
+			equal, applies := equalRule(value, option)
+			if !applies {
+				break
+			}
+
+			ok = equal
+			if ok {
+				break
+			}
+		}
+
+		msg = fmt.Sprintf("must be one of [%s]", rule.param)
+	case "contains":
+		ok, msg = stringRule(value, func(s string) bool { return strings.Contains(s, rule.param) }, "must contain "+strconv.Quote(rule.param))
+	case "excludes":
+		ok, msg = stringRule(value, func(s string) bool { return !strings.Contains(s, rule.param) }, "must not contain "+strconv.Quote(rule.param))
+	case "startswith":
+		ok, msg = stringRule(value, func(s string) bool { return strings.HasPrefix(s, rule.param) }, "must start with "+strconv.Quote(rule.param))
+	case "endswith":
+		ok, msg = stringRule(value, func(s string) bool { return strings.HasSuffix(s, rule.param) }, "must end with "+strconv.Quote(rule.param))
+	case "alpha":
+		ok, msg = stringRule(value, alphaPattern.MatchString, "must contain only letters")
+	case "alphanum":
+		ok, msg = stringRule(value, alphanumPattern.MatchString, "must contain only letters and digits")
+	case "numeric":
+		ok, msg = stringRule(value, numericPattern.MatchString, "must be numeric")
+	case "email":
+		ok, msg = stringRule(value, isEmail, "must be a valid email address")
+	case "url", "uri":
+		ok, msg = stringRule(value, isURL, "must be a valid URL")
+	case "uuid":
+		ok, msg = stringRule(value, uuidPattern.MatchString, "must be a valid UUID")
+	case "ip":
+		ok, msg = stringRule(value, func(s string) bool { return net.ParseIP(s) != nil }, "must be a valid IP address")
+	case "ipv4":
+		ok, msg = stringRule(value, func(s string) bool { ip := net.ParseIP(s); return ip != nil && ip.To4() != nil }, "must be a valid IPv4 address")
+	case "ipv6":
+		ok, msg = stringRule(value, func(s string) bool { ip := net.ParseIP(s); return ip != nil && ip.To4() == nil }, "must be a valid IPv6 address")
+	case "hostname":
+		ok, msg = stringRule(value, hostnamePattern.MatchString, "must be a valid hostname")
+	}
+
+	if ok {
+		return nil
+	}
+
+	return &RuleError{Rule: rule.name, Param: rule.param, msg: msg}
+}
+
+// sizeRule compares numbers by value, and strings and collections by length,
+// with param, and reports whether accept holds for the result.
+func sizeRule(value reflect.Value, param string, accept func(int) bool, relation string) (bool, string) {
+	msg := fmt.Sprintf("must be %s %s", relation, param)
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if p, err := strconv.ParseInt(param, 10, 64); err == nil {
+			return accept(compareOrdered(value.Int(), p)), msg
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if p, err := strconv.ParseUint(param, 10, 64); err == nil {
+			return accept(compareOrdered(value.Uint(), p)), msg
+		}
+	case reflect.Float32, reflect.Float64:
+		if p, err := strconv.ParseFloat(param, 64); err == nil {
+			return accept(compareOrdered(value.Float(), p)), msg
+		}
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		if p, err := strconv.ParseInt(param, 10, 64); err == nil {
+			length := value.Len()
+			if value.Kind() == reflect.String {
+				length = utf8.RuneCountInString(value.String())
+			}
+
+			return accept(compareOrdered(int64(length), p)), "length " + msg
+		}
+	}
+
+	return true, ""
+}
+
+// equalRule reports whether value equals param, and whether the comparison
+// applies to the kind of value at all.
+func equalRule(value reflect.Value, param string) (equal bool, applies bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String() == param, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p, err := strconv.ParseInt(param, 10, 64)
+
+		return err == nil && value.Int() == p, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		p, err := strconv.ParseUint(param, 10, 64)
+
+		return err == nil && value.Uint() == p, true
+	case reflect.Float32, reflect.Float64:
+		p, err := strconv.ParseFloat(param, 64)
+
+		return err == nil && value.Float() == p, true
+	case reflect.Bool:
+		p, err := strconv.ParseBool(param)
+
+		return err == nil && value.Bool() == p, true
+	default:
+		return false, false
+	}
+}
+
+func stringRule(value reflect.Value, check func(string) bool, msg string) (bool, string) {
+	if value.Kind() != reflect.String {
+		return true, ""
+	}
+
+	return check(value.String()), msg
+}
+
+func isEmail(s string) bool {
+	address, err := mail.ParseAddress(s)
+
+	return err == nil && address.Address == s
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+}
@@ -0,0 +1,220 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+type validateAddress struct {
+	City string `validate:"required"`
+	Zip  string `validate:"len=5,numeric"`
+}
+
+func newValidateBuilder(t *testing.T) (*dynamicstruct.Builder, reflect.Type) {
+	t.Helper()
+
+	builder := dynamicstruct.New()
+	_ = builder.AddField("Name", "", `validate:"required,min=2,max=10"`)
+	_ = builder.AddField("Age", int(0), `validate:"gte=18,lt=130"`)
+	_ = builder.AddField("Email", (*string)(nil), `validate:"omitempty,email"`)
+	_ = builder.AddField("Role", "", `validate:"oneof=admin user"`)
+	_ = builder.AddField("Website", "", `validate:"omitempty,url|hostname"`)
+	_ = builder.AddField("Tags", []string{}, `validate:"max=3,dive,min=2"`)
+	_ = builder.AddField("Address", validateAddress{})
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return builder, reflect.TypeOf(instance)
+}
+
+func TestValidate(t *testing.T) {
+	builder, structType := newValidateBuilder(t)
+
+	validEmail := "alice@example.com"
+	invalidEmail := "alice"
+
+	valid := map[string]any{
+		"Name":    "Alice",
+		"Age":     30,
+		"Email":   &validEmail,
+		"Role":    "admin",
+		"Website": "example.com",
+		"Tags":    []string{"go", "db"},
+		"Address": validateAddress{City: "Oslo", Zip: "01500"},
+	}
+
+	tests := []struct {
+		name       string
+		overrides  map[string]any
+		wantFields map[string]string
+	}{
+		{name: "valid", overrides: nil, wantFields: nil},
+		{name: "nil_optional_pointer", overrides: map[string]any{"Email": (*string)(nil)}, wantFields: nil},
+		{
+			name:       "required",
+			overrides:  map[string]any{"Name": ""},
+			wantFields: map[string]string{"Name": "required"},
+		},
+		{
+			name:       "string_length",
+			overrides:  map[string]any{"Name": "Maximilianus"},
+			wantFields: map[string]string{"Name": "max"},
+		},
+		{
+			name:       "number_bounds",
+			overrides:  map[string]any{"Age": 17},
+			wantFields: map[string]string{"Age": "gte"},
+		},
+		{
+			name:       "pointer_value",
+			overrides:  map[string]any{"Email": &invalidEmail},
+			wantFields: map[string]string{"Email": "email"},
+		},
+		{
+			name:       "oneof",
+			overrides:  map[string]any{"Role": "root"},
+			wantFields: map[string]string{"Role": "oneof"},
+		},
+		{
+			name:       "alternatives",
+			overrides:  map[string]any{"Website": "not a host!"},
+			wantFields: map[string]string{"Website": "url|hostname"},
+		},
+		{
+			name:       "dive",
+			overrides:  map[string]any{"Tags": []string{"go", "x"}},
+			wantFields: map[string]string{"Tags[1]": "min"},
+		},
+		{
+			name:       "collection_length",
+			overrides:  map[string]any{"Tags": []string{"aa", "bb", "cc", "dd"}},
+			wantFields: map[string]string{"Tags": "max"},
+		},
+		{
+			name:       "nested_struct",
+			overrides:  map[string]any{"Address": validateAddress{Zip: "12a45"}},
+			wantFields: map[string]string{"Address.City": "required", "Address.Zip": "numeric"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := reflect.New(structType).Elem()
+
+			for name, fieldValue := range valid {
+				if override, ok := tt.overrides[name]; ok {
+					fieldValue = override
+				}
+
+				value.FieldByName(name).Set(reflect.ValueOf(fieldValue))
+			}
+
+			err := builder.Validate(value.Addr().Interface())
+
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, dynamicstruct.ErrValidation) {
+				t.Fatalf("Validate() error = %v, want %v", err, dynamicstruct.ErrValidation)
+			}
+
+			var validationErrors dynamicstruct.ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Fatalf("Validate() error = %T, want ValidationErrors", err)
+			}
+
+			got := map[string]string{}
+
+			for field, fieldErr := range validationErrors {
+				var ruleErr *dynamicstruct.RuleError
+				if !errors.As(fieldErr, &ruleErr) {
+					t.Fatalf("field %s error = %T, want *RuleError", field, fieldErr)
+				}
+
+				got[field] = ruleErr.Rule
+			}
+
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("Validate() failed rules = %v, want %v (%v)", got, tt.wantFields, err)
+			}
+		})
+	}
+}
+
+func TestValidateErrorMessage(t *testing.T) {
+	builder, structType := newValidateBuilder(t)
+
+	err := builder.Validate(reflect.New(structType).Elem().Interface())
+
+	want := "validation failed: Address.City: is required; Address.Zip: length must be exactly 5; " +
+		"Age: must be at least 18; Name: is required; Role: must be one of [admin user]"
+	if err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %s", err, want)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	builder, _ := newValidateBuilder(t)
+
+	tests := []struct {
+		name     string
+		builder  *dynamicstruct.Builder
+		instance any
+		wantErr  error
+	}{
+		{name: "not_built", builder: dynamicstruct.New(), instance: PersonTest{}, wantErr: dynamicstruct.ErrInstanceNotBuilt},
+		{name: "other_type", builder: builder, instance: PersonTest{}, wantErr: dynamicstruct.ErrIncompatibleTypes},
+		{name: "not_struct", builder: builder, instance: "text", wantErr: dynamicstruct.ErrInvalidInstance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.builder.Validate(tt.instance)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if strings.Contains(err.Error(), "validation failed") {
+				t.Errorf("Validate() error = %v, want a usage error", err)
+			}
+		})
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		wantErr error
+	}{
+		{tag: `validate:"required,min=1,max=2.5"`},
+		{tag: `validate:"omitempty,url|hostname"`},
+		{tag: `validate:"max=3,dive,min=2"`},
+		{tag: `validate:"-"`},
+		{tag: `validate:"requried"`, wantErr: dynamicstruct.ErrInvalidTag},
+		{tag: `validate:"min=abc"`, wantErr: dynamicstruct.ErrInvalidTag},
+		{tag: `validate:"len=x"`, wantErr: dynamicstruct.ErrInvalidTag},
+		{tag: `validate:"email|hostnme"`, wantErr: dynamicstruct.ErrInvalidTag},
+		{tag: `validate:"dive,gte="`, wantErr: dynamicstruct.ErrInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			err := dynamicstruct.New().AddField("Name", "", tt.tag)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddField() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const validateTagKey = "validate"
+
+// validateRuleNames are the rules Validate checks, mapped to whether their
+// parameter must be a number.
+var validateRuleNames = map[string]bool{
+	"required": false, "omitempty": false,
+	"len": true, "min": true, "max": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	"eq": false, "ne": false, "oneof": false,
+	"contains": false, "excludes": false, "startswith": false, "endswith": false,
+	"alpha": false, "alphanum": false, "numeric": false,
+	"email": false, "url": false, "uri": false, "uuid": false,
+	"ip": false, "ipv4": false, "ipv6": false, "hostname": false,
+}
+
+// validateRule is one rule of a go-playground/validator style validate tag,
+// such as "min=3". Alternatives joined with "|" are kept in or, and the rule
+// passes when any of them does.
+type validateRule struct {
+	name  string
+	param string
+	or    []validateRule
+}
+
+// parseValidateTag splits a validate tag into the rules for the field and, when
+// the tag contains "dive", the tag for the elements of a collection.
+func parseValidateTag(tag string) ([]validateRule, string) {
+	if tag == "" || tag == "-" {
+		return nil, ""
+	}
+
+	var rules []validateRule
+
+	parts := strings.Split(tag, ",")
+
+	for i, part := range parts {
+		if part == "dive" {
+			return rules, strings.Join(parts[i+1:], ",")
+		}
+
+		if part == "" {
+			continue
+		}
+
+		if strings.Contains(part, "|") {
+			var or []validateRule
+
+			for _, alternative := range strings.Split(part, "|") {
+				or = append(or, parseValidateRule(alternative))
+			}
+
+			rules = append(rules, validateRule{name: part, or: or})
+
+			continue
+		}
+
+		rules = append(rules, parseValidateRule(part))
+	}
+
+	return rules, ""
+}
+
+func parseValidateRule(part string) validateRule {
+	name, param, _ := strings.Cut(part, "=")
+
+	// Commas and pipes in parameters are escaped as in validator
+	param = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(param)
+
+	return validateRule{name: name, param: param}
+}
+
+// checkValidateTag returns ErrInvalidTag when a rule of tag, or of the tags
+// after its dives, is unknown or has a parameter that isn't a number where
+// one is expected, so that a misspelled rule doesn't silently turn
+// validation off.
+func checkValidateTag(tag string) error {
+	rules, dive := parseValidateTag(tag)
+
+	for _, rule := range rules {
+		alternatives := rule.or
+		if alternatives == nil {
+			alternatives = []validateRule{rule}
+		}
+
+		for _, alternative := range alternatives {
+			numeric, ok := validateRuleNames[alternative.name]
+			if !ok {
+				return fmt.Errorf("%w: unknown validate rule %q", ErrInvalidTag, alternative.name)
+			}
+
+			if _, err := strconv.ParseFloat(alternative.param, 64); numeric && err != nil {
+				return fmt.Errorf("%w: validate rule %s needs a number, got %q", ErrInvalidTag, alternative.name, alternative.param)
+			}
+		}
+	}
+
+	if dive != "" {
+		return checkValidateTag(dive)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package dynamicstruct
+
+import "reflect"
+
+// structPtrValue returns the addressable struct a non-nil pointer points to.
+func structPtrValue(instancePtr any) (reflect.Value, error) {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return reflect.Value{}, ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return reflect.Value{}, ErrValueCannotBeNil
+	}
+
+	if valueReflect.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, ErrInvalidInstance
+	}
+
+	return valueReflect.Elem(), nil
+}
+
+// structValue returns the struct instance holds, dereferencing pointers.
+func structValue(instance any) (reflect.Value, error) {
+	valueReflect := reflect.ValueOf(instance)
+
+	for valueReflect.Kind() == reflect.Ptr {
+		if valueReflect.IsNil() {
+			return reflect.Value{}, ErrValueCannotBeNil
+		}
+
+		valueReflect = valueReflect.Elem()
+	}
+
+	if valueReflect.Kind() != reflect.Struct {
+		return reflect.Value{}, ErrInvalidInstance
+	}
+
+	return valueReflect, nil
+}
+
+// fieldByName is reflect.Value.FieldByName, but reports a field promoted
+// through a nil embedded pointer as missing instead of panicking.
+func fieldByName(value reflect.Value, name string) (reflect.Value, bool) {
+	field, ok := value.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	fieldValue, err := value.FieldByIndexErr(field.Index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	return fieldValue, true
+}
+
+// fieldByNameAlloc is fieldByName for setting the field: nil embedded
+// pointers on the way to a promoted field are allocated instead of reported
+// as missing. value must be addressable.
+func fieldByNameAlloc(value reflect.Value, name string) (reflect.Value, bool) {
+	field, ok := value.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	current := value
+
+	for i, index := range field.Index {
+		if i > 0 && current.Kind() == reflect.Ptr {
+			if current.IsNil() {
+				if !current.CanSet() {
+					return reflect.Value{}, false
+				}
+
+				current.Set(reflect.New(current.Type().Elem()))
+			}
+
+			current = current.Elem()
+		}
+
+		current = current.Field(index)
+	}
+
+	return current, true
+}
+
+// ownedFieldByName is fieldByNameAlloc for a copy of an instance: the
+// embedded pointers on the way are replaced with pointers to copies, as
+// ownedField does, so that setting the field doesn't change structs the
+// instance shares.
+func ownedFieldByName(value reflect.Value, name string) (reflect.Value, bool) {
+	field, ok := value.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	current := value
+
+	for i, index := range field.Index {
+		if i > 0 && current.Kind() == reflect.Ptr {
+			if !current.CanSet() {
+				return reflect.Value{}, false
+			}
+
+			current = ownPointer(current)
+		}
+
+		current = current.Field(index)
+	}
+
+	return current, true
+}
+
+// missingField returns the error for a name fieldByName doesn't find in
+// structType: ErrAmbiguousField when, as in Go, the name is promoted from
+// more than one embedded struct at the shallowest depth it appears at, and
+// ErrFieldNotFound otherwise.
+func missingField(structType reflect.Type, name string) error {
+	if ambiguousField(structType, name) {
+		return ErrAmbiguousField
+	}
+
+	return ErrFieldNotFound
+}
+
+func ambiguousField(structType reflect.Type, name string) bool {
+	current := []reflect.Type{structType}
+	visited := map[reflect.Type]bool{}
+
+	for len(current) > 0 {
+		var next []reflect.Type
+
+		matches := 0
+
+		// A type embedded twice at the same depth counts twice
+		for _, t := range current {
+			if visited[t] {
+				continue
+			}
+
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if field.Name == name {
+					matches++
+
+					continue
+				}
+
+				if !field.Anonymous {
+					continue
+				}
+
+				embedded := field.Type
+				if embedded.Kind() == reflect.Ptr {
+					embedded = embedded.Elem()
+				}
+
+				if embedded.Kind() == reflect.Struct {
+					next = append(next, embedded)
+				}
+			}
+		}
+
+		if matches > 0 {
+			return matches > 1
+		}
+
+		for _, t := range current {
+			visited[t] = true
+		}
+
+		current = next
+	}
+
+	return false
+}
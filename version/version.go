@@ -0,0 +1,269 @@
+// Package version keeps numbered versions of a dynamicstruct definition and
+// migrates instances between them.
+//
+// Each version is a built builder. Migrations are registered between pairs of
+// versions, and Migrate chains them to bring an instance of any version to a
+// target one:
+//
+//	s := version.New()
+//	_ = s.Register(1, v1)
+//	_ = s.Register(2, v2)
+//	_ = s.RegisterMigration(1, 2, func(oldInst, newInst any) error {
+//		// newInst already holds the fields v1 and v2 share
+//		return nil
+//	})
+//
+//	migrated, err := s.Migrate(stored, 2)
+package version
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+var (
+	ErrUnknownVersion   = errors.New("unknown version")
+	ErrDuplicateVersion = errors.New("duplicate version")
+	ErrNoMigrationPath  = errors.New("no migration path")
+)
+
+// MigrationFunc completes the migration of oldInst, a pointer to an instance
+// of the source version, into newInst, a pointer to an instance of the target
+// version.
+type MigrationFunc func(oldInst, newInst any) error
+
+// Schema holds the versions of a definition and the migrations between them.
+// It is safe for concurrent use.
+type Schema struct {
+	builders   map[int]*dynamicstruct.Builder
+	types      map[reflect.Type]int
+	migrations map[int]map[int]MigrationFunc
+	m          sync.RWMutex
+}
+
+func New() *Schema {
+	return &Schema{
+		builders:   make(map[int]*dynamicstruct.Builder),
+		types:      make(map[reflect.Type]int),
+		migrations: make(map[int]map[int]MigrationFunc),
+	}
+}
+
+// Register adds builder as the given version, building it if it isn't built
+// yet. Versions are told apart by their built type, so two versions must not
+// build identical structs.
+func (s *Schema) Register(version int, builder *dynamicstruct.Builder) error {
+	instance, err := builder.NewInstance()
+	if errors.Is(err, dynamicstruct.ErrInstanceNotBuilt) {
+		if _, err = builder.Build(); err != nil {
+			return err
+		}
+
+		instance, err = builder.NewInstance()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	structType := reflect.TypeOf(instance).Elem()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if _, ok := s.builders[version]; ok {
+		return fmt.Errorf("%w: %d", ErrDuplicateVersion, version)
+	}
+
+	if other, ok := s.types[structType]; ok {
+		return fmt.Errorf("%w: version %d builds the same type as version %d", ErrDuplicateVersion, version, other)
+	}
+
+	s.builders[version] = builder
+	s.types[structType] = version
+
+	return nil
+}
+
+// Builder returns the builder registered as version.
+func (s *Schema) Builder(version int) (*dynamicstruct.Builder, bool) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	builder, ok := s.builders[version]
+
+	return builder, ok
+}
+
+// Versions returns the registered versions in ascending order.
+func (s *Schema) Versions() []int {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	versions := make([]int, 0, len(s.builders))
+	for version := range s.builders {
+		versions = append(versions, version)
+	}
+
+	sort.Ints(versions)
+
+	return versions
+}
+
+// Version returns the version instance, a value of a registered version's
+// type or a pointer to one, belongs to.
+func (s *Schema) Version(instance any) (int, error) {
+	structType := reflect.TypeOf(instance)
+	if structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	version, ok := s.types[structType]
+	if !ok {
+		return 0, fmt.Errorf("%w: type %v", ErrUnknownVersion, structType)
+	}
+
+	return version, nil
+}
+
+// RegisterMigration registers fn to migrate instances from version from to
+// version to. Migrations may skip versions and go backwards; both versions
+// must be registered first.
+func (s *Schema) RegisterMigration(from, to int, fn MigrationFunc) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, version := range []int{from, to} {
+		if _, ok := s.builders[version]; !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+	}
+
+	if s.migrations[from] == nil {
+		s.migrations[from] = make(map[int]MigrationFunc)
+	}
+
+	s.migrations[from][to] = fn
+
+	return nil
+}
+
+// Migrate returns a pointer to a new instance of targetVersion holding the
+// values of instance, a value of a registered version's type or a pointer to
+// one. It follows the shortest chain of registered migrations. For each step
+// the new instance starts with its default values and the fields it shares
+// with the old one, copied by dynamicstruct.MigrateInstance, before the
+// migration function runs.
+func (s *Schema) Migrate(instance any, targetVersion int) (any, error) {
+	version, err := s.Version(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	s.m.RLock()
+	path, err := s.path(version, targetVersion)
+	s.m.RUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	current := instance
+
+	for _, step := range path {
+		next, err := step.builder.NewInstance()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := dynamicstruct.MigrateInstance(current, next); err != nil {
+			return nil, err
+		}
+
+		if step.fn != nil {
+			if err := step.fn(current, next); err != nil {
+				return nil, fmt.Errorf("migrate version %d to %d: %w", step.from, step.to, err)
+			}
+		}
+
+		current = next
+	}
+
+	return current, nil
+}
+
+type migrationStep struct {
+	from, to int
+	builder  *dynamicstruct.Builder
+	fn       MigrationFunc
+}
+
+// path finds the shortest chain of migrations from one version to another.
+// Migrating a version to itself is a single step that copies the instance.
+// s.m must be held.
+func (s *Schema) path(from, to int) ([]migrationStep, error) {
+	target, ok := s.builders[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, to)
+	}
+
+	if from == to {
+		return []migrationStep{{from: from, to: to, builder: target}}, nil
+	}
+
+	previous := map[int]int{from: from}
+	queue := []int{from}
+
+	for len(queue) > 0 {
+		version := queue[0]
+		queue = queue[1:]
+
+		// Visit the targets in order, so the chosen path is deterministic
+		next := make([]int, 0, len(s.migrations[version]))
+		for target := range s.migrations[version] {
+			next = append(next, target)
+		}
+
+		sort.Ints(next)
+
+		for _, target := range next {
+			if _, seen := previous[target]; seen {
+				continue
+			}
+
+			previous[target] = version
+
+			if target == to {
+				return s.steps(previous, from, to), nil
+			}
+
+			queue = append(queue, target)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: from version %d to %d", ErrNoMigrationPath, from, to)
+}
+
+func (s *Schema) steps(previous map[int]int, from, to int) []migrationStep {
+	var steps []migrationStep
+
+	for version := to; version != from; version = previous[version] {
+		step := migrationStep{
+			from:    previous[version],
+			to:      version,
+			builder: s.builders[version],
+			fn:      s.migrations[previous[version]][version],
+		}
+		steps = append([]migrationStep{step}, steps...)
+	}
+
+	return steps
+}
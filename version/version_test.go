@@ -0,0 +1,179 @@
+package version_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+	"github.com/gosmos-space/dynamicstruct/version"
+)
+
+func newSchema(t *testing.T) *version.Schema {
+	t.Helper()
+
+	v1 := dynamicstruct.New()
+	_ = v1.AddField("Name", "", `json:"name"`)
+	_ = v1.AddField("Age", 0, `json:"age"`)
+
+	v2 := dynamicstruct.New()
+	_ = v2.AddField("FirstName", "", `json:"first_name"`)
+	_ = v2.AddField("LastName", "", `json:"last_name"`)
+	_ = v2.AddField("Age", 0, `json:"age"`)
+
+	v3 := dynamicstruct.New()
+	_ = v3.AddField("FirstName", "", `json:"first_name"`)
+	_ = v3.AddField("LastName", "", `json:"last_name"`)
+	_ = v3.AddField("Age", int64(0), `json:"age"`)
+	_ = v3.AddFieldWithOptions("Country", "", dynamicstruct.Default("NO"))
+
+	s := version.New()
+
+	for i, builder := range []*dynamicstruct.Builder{v1, v2, v3} {
+		if err := s.Register(i+1, builder); err != nil {
+			t.Fatalf("Register(%d) error = %v", i+1, err)
+		}
+	}
+
+	err := s.RegisterMigration(1, 2, func(oldInst, newInst any) error {
+		name := reflect.ValueOf(oldInst).Elem().FieldByName("Name").String()
+		first, last, _ := strings.Cut(name, " ")
+
+		value := reflect.ValueOf(newInst).Elem()
+		value.FieldByName("FirstName").SetString(first)
+		value.FieldByName("LastName").SetString(last)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterMigration() error = %v", err)
+	}
+
+	err = s.RegisterMigration(2, 3, func(oldInst, newInst any) error {
+		age := reflect.ValueOf(oldInst).Elem().FieldByName("Age").Int()
+		reflect.ValueOf(newInst).Elem().FieldByName("Age").SetInt(age)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterMigration() error = %v", err)
+	}
+
+	return s
+}
+
+func TestMigrate(t *testing.T) {
+	s := newSchema(t)
+
+	v1, _ := s.Builder(1)
+	old, _ := v1.NewInstance()
+	_ = dynamicstruct.MigrateInstance(struct {
+		Name string
+		Age  int
+	}{"Ada Lovelace", 36}, old)
+
+	migrated, err := s.Migrate(old, 3)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	if got, _ := s.Version(migrated); got != 3 {
+		t.Errorf("Version() = %d, want 3", got)
+	}
+
+	value := reflect.ValueOf(migrated).Elem()
+
+	want := map[string]any{
+		"FirstName": "Ada",
+		"LastName":  "Lovelace",
+		"Age":       int64(36),
+		"Country":   "NO",
+	}
+
+	for name, want := range want {
+		if got := value.FieldByName(name).Interface(); got != want {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	t.Run("same_version", func(t *testing.T) {
+		copied, err := s.Migrate(old, 1)
+		if err != nil {
+			t.Fatalf("Migrate() error = %v", err)
+		}
+
+		if copied == old || !reflect.DeepEqual(copied, old) {
+			t.Errorf("Migrate() = %v, want a copy of %v", copied, old)
+		}
+	})
+}
+
+func TestMigrateErrors(t *testing.T) {
+	s := newSchema(t)
+
+	v3, _ := s.Builder(3)
+	instance, _ := v3.NewInstance()
+
+	tests := []struct {
+		name     string
+		instance any
+		target   int
+		wantErr  error
+	}{
+		{"unknown_instance", struct{ X int }{}, 1, version.ErrUnknownVersion},
+		{"unknown_target", instance, 4, version.ErrUnknownVersion},
+		{"no_path", instance, 1, version.ErrNoMigrationPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Migrate(tt.instance, tt.target); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Migrate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("migration_error", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		_ = s.RegisterMigration(3, 1, func(_, _ any) error { return errFailed })
+
+		if _, err := s.Migrate(instance, 1); !errors.Is(err, errFailed) {
+			t.Errorf("Migrate() error = %v, want %v", err, errFailed)
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	s := newSchema(t)
+
+	t.Run("duplicate_version", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Other", "")
+
+		if err := s.Register(1, builder); !errors.Is(err, version.ErrDuplicateVersion) {
+			t.Errorf("Register() error = %v, want %v", err, version.ErrDuplicateVersion)
+		}
+	})
+
+	t.Run("duplicate_type", func(t *testing.T) {
+		builder := dynamicstruct.New()
+		_ = builder.AddField("Name", "", `json:"name"`)
+		_ = builder.AddField("Age", 0, `json:"age"`)
+
+		if err := s.Register(4, builder); !errors.Is(err, version.ErrDuplicateVersion) {
+			t.Errorf("Register() error = %v, want %v", err, version.ErrDuplicateVersion)
+		}
+	})
+
+	t.Run("unknown_migration_version", func(t *testing.T) {
+		err := s.RegisterMigration(1, 9, func(_, _ any) error { return nil })
+		if !errors.Is(err, version.ErrUnknownVersion) {
+			t.Errorf("RegisterMigration() error = %v, want %v", err, version.ErrUnknownVersion)
+		}
+	})
+
+	if got := s.Versions(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Versions() = %v, want [1 2 3]", got)
+	}
+}
@@ -0,0 +1,228 @@
+package dynamicstruct
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var xmlNameType = reflect.TypeOf(xml.Name{})
+
+// xmlShape accumulates the attributes and child elements seen for an element
+// across all of its occurrences in a sample.
+type xmlShape struct {
+	attrs    []xml.Name
+	children []string
+	child    map[string]*xmlShape
+	repeated map[string]bool
+	hasText  bool
+}
+
+func newXMLShape() *xmlShape {
+	return &xmlShape{
+		child:    make(map[string]*xmlShape),
+		repeated: make(map[string]bool),
+	}
+}
+
+func (s *xmlShape) addAttr(name xml.Name) {
+	for _, attr := range s.attrs {
+		if attr == name {
+			return
+		}
+	}
+
+	s.attrs = append(s.attrs, name)
+}
+
+func (s *xmlShape) childShape(name string) *xmlShape {
+	shape, ok := s.child[name]
+	if !ok {
+		shape = newXMLShape()
+		s.child[name] = shape
+		s.children = append(s.children, name)
+	}
+
+	return shape
+}
+
+// FromXMLSample infers a builder from an XML document. Attributes become
+// fields tagged `xml:"name,attr"`, or `xml:"namespace name,attr"` when they
+// are in a namespace, with an Attr suffix when a child element has the same
+// name. Child elements become string, struct or slice fields, and the root
+// element name is kept in an XMLName field. Text of the root element, or next
+// to attributes or child elements, is kept in a Value field, or in CharData
+// when Value is taken. Leaf values are always inferred as strings, since XML
+// carries no type information.
+func FromXMLSample(data []byte, opts ...ImportOption) (*Builder, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	type frame struct {
+		shape  *xmlShape
+		counts map[string]int
+	}
+
+	var (
+		root     *xmlShape
+		rootName string
+		stack    []*frame
+	)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSample, err.Error())
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var shape *xmlShape
+
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("%w: multiple root elements", ErrInvalidSample)
+				}
+
+				root = newXMLShape()
+				rootName = t.Name.Local
+				shape = root
+			} else {
+				parent := stack[len(stack)-1]
+				shape = parent.shape.childShape(t.Name.Local)
+
+				parent.counts[t.Name.Local]++
+				if parent.counts[t.Name.Local] > 1 {
+					parent.shape.repeated[t.Name.Local] = true
+				}
+			}
+
+			for _, attr := range t.Attr {
+				// Namespace declarations are not data
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+
+				shape.addAttr(attr.Name)
+			}
+
+			stack = append(stack, &frame{shape: shape, counts: make(map[string]int)})
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 && len(bytes.TrimSpace(t)) > 0 {
+				stack[len(stack)-1].shape.hasText = true
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%w: no root element", ErrInvalidSample)
+	}
+
+	builder := New()
+
+	if err := builder.addField("XMLName", xmlNameType, []string{fmt.Sprintf("xml:%q", rootName)}); err != nil {
+		return nil, err
+	}
+
+	if err := root.addFields(builder, newImportConfig(opts)); err != nil {
+		return nil, err
+	}
+
+	return builder, nil
+}
+
+// addFields adds the attributes, child elements and character data of the
+// element to builder.
+func (s *xmlShape) addFields(builder *Builder, cfg *importConfig) error {
+	for _, attr := range s.attrs {
+		name := ExportedName(attr.Local)
+		if _, ok := s.child[attr.Local]; ok {
+			name += "Attr"
+		}
+
+		key := attr.Local
+		if attr.Space != "" {
+			key = attr.Space + " " + attr.Local
+		}
+
+		err := builder.addImportedFieldAs(name, attr.Local, reflect.TypeOf(""), fmt.Sprintf(`xml:"%s,attr"`, key), cfg)
+		if err != nil {
+			return fmt.Errorf("%w: attribute %q", err, key)
+		}
+	}
+
+	for _, name := range s.children {
+		childType, err := s.child[name].fieldType(cfg)
+		if err != nil {
+			return err
+		}
+
+		if s.repeated[name] {
+			childType = reflect.SliceOf(childType)
+		}
+
+		err = builder.addImportedField(name, childType, fmt.Sprintf("xml:%q", name), cfg)
+		if err != nil {
+			return fmt.Errorf("%w: element %q", err, name)
+		}
+	}
+
+	// Elements holding only text are string fields, so text found here is
+	// next to attributes or child elements, or in the root element
+	if s.hasText {
+		name := "Value"
+		if builder.hasField(name) {
+			name = "CharData"
+		}
+
+		if err := builder.addImportedFieldAs(name, "chardata", reflect.TypeOf(""), `xml:",chardata"`, cfg); err != nil {
+			return fmt.Errorf("%w: character data", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *xmlShape) fieldType(cfg *importConfig) (reflect.Type, error) {
+	if len(s.attrs) == 0 && len(s.children) == 0 {
+		return reflect.TypeOf(""), nil
+	}
+
+	builder := New()
+	if err := s.addFields(builder, cfg); err != nil {
+		return nil, err
+	}
+
+	return structOf(builder.buildStructFields())
+}
+
+func MarshalXML(instance any) ([]byte, error) {
+	return xml.Marshal(instance)
+}
+
+func UnmarshalXML(data []byte, instancePtr any) error {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return ErrValueCannotBeNil
+	}
+
+	if err := xml.Unmarshal(data, instancePtr); err != nil {
+		return err
+	}
+
+	return transformDecoded(valueReflect)
+}
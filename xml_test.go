@@ -0,0 +1,291 @@
+package dynamicstruct_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestFromXMLSample(t *testing.T) {
+	sample := []byte(`<?xml version="1.0"?>
+<order id="42" currency="EUR">
+	<customer>Alice</customer>
+	<item sku="A-1">Widget</item>
+	<item sku="B-2">Gadget</item>
+	<shipping>
+		<city>Berlin</city>
+		<express>true</express>
+	</shipping>
+</order>`)
+
+	builder, err := dynamicstruct.FromXMLSample(sample)
+	if err != nil {
+		t.Fatalf("FromXMLSample() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field    string
+		wantKind reflect.Kind
+		wantTag  string
+	}{
+		{field: "XMLName", wantKind: reflect.Struct, wantTag: "order"},
+		{field: "Id", wantKind: reflect.String, wantTag: "id,attr"},
+		{field: "Currency", wantKind: reflect.String, wantTag: "currency,attr"},
+		{field: "Customer", wantKind: reflect.String, wantTag: "customer"},
+		{field: "Item", wantKind: reflect.Slice, wantTag: "item"},
+		{field: "Shipping", wantKind: reflect.Struct, wantTag: "shipping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if field.Type.Kind() != tt.wantKind {
+				t.Errorf("field %s kind = %v, want %v", tt.field, field.Type.Kind(), tt.wantKind)
+			}
+			if field.Tag.Get("xml") != tt.wantTag {
+				t.Errorf("field %s xml tag = %q, want %q", tt.field, field.Tag.Get("xml"), tt.wantTag)
+			}
+		})
+	}
+
+	t.Run("element_with_attributes_and_text", func(t *testing.T) {
+		field, _ := structType.FieldByName("Item")
+		itemType := field.Type.Elem()
+
+		sku, ok := itemType.FieldByName("Sku")
+		if !ok || sku.Tag.Get("xml") != "sku,attr" {
+			t.Errorf("Item.Sku tag = %q, want %q", sku.Tag.Get("xml"), "sku,attr")
+		}
+
+		value, ok := itemType.FieldByName("Value")
+		if !ok || value.Tag.Get("xml") != ",chardata" {
+			t.Errorf("Item.Value tag = %q, want %q", value.Tag.Get("xml"), ",chardata")
+		}
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		instancePtr := reflect.New(structType).Interface()
+
+		if err := dynamicstruct.UnmarshalXML(sample, instancePtr); err != nil {
+			t.Fatalf("UnmarshalXML() error = %v", err)
+		}
+
+		value := reflect.ValueOf(instancePtr).Elem()
+		if got := value.FieldByName("Item").Index(1).FieldByName("Value").String(); got != "Gadget" {
+			t.Errorf("Item[1].Value = %q, want %q", got, "Gadget")
+		}
+
+		data, err := dynamicstruct.MarshalXML(instancePtr)
+		if err != nil {
+			t.Fatalf("MarshalXML() error = %v", err)
+		}
+
+		want := `<order id="42" currency="EUR"><customer>Alice</customer>` +
+			`<item sku="A-1">Widget</item><item sku="B-2">Gadget</item>` +
+			`<shipping><city>Berlin</city><express>true</express></shipping></order>`
+		if string(data) != want {
+			t.Errorf("MarshalXML() = %s, want %s", data, want)
+		}
+	})
+}
+
+func TestFromXMLSampleNameClashes(t *testing.T) {
+	sample := []byte(`<order id="42"><id>A-42</id>` +
+		`<item value="3">Widget<value>EUR</value></item></order>`)
+
+	builder, err := dynamicstruct.FromXMLSample(sample)
+	if err != nil {
+		t.Fatalf("FromXMLSample() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+	field, _ := structType.FieldByName("Item")
+
+	tests := []struct {
+		structType reflect.Type
+		field      string
+		wantTag    string
+	}{
+		{structType: structType, field: "IdAttr", wantTag: "id,attr"},
+		{structType: structType, field: "Id", wantTag: "id"},
+		{structType: field.Type, field: "ValueAttr", wantTag: "value,attr"},
+		{structType: field.Type, field: "Value", wantTag: "value"},
+		{structType: field.Type, field: "CharData", wantTag: ",chardata"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := tt.structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+
+			if got := field.Tag.Get("xml"); got != tt.wantTag {
+				t.Errorf("field %s xml tag = %q, want %q", tt.field, got, tt.wantTag)
+			}
+		})
+	}
+
+	instancePtr := reflect.New(structType).Interface()
+	if err := dynamicstruct.UnmarshalXML(sample, instancePtr); err != nil {
+		t.Fatalf("UnmarshalXML() error = %v", err)
+	}
+
+	data, err := dynamicstruct.MarshalXML(instancePtr)
+	if err != nil {
+		t.Fatalf("MarshalXML() error = %v", err)
+	}
+
+	want := `<order id="42"><id>A-42</id><item value="3"><value>EUR</value>Widget</item></order>`
+	if string(data) != want {
+		t.Errorf("MarshalXML() = %s, want %s", data, want)
+	}
+}
+
+func TestFromXMLSampleTextAndNamespaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		sample     string
+		opts       []dynamicstruct.ImportOption
+		wantTags   map[string]string
+		wantValues map[string]string
+	}{
+		{
+			name:       "root_text",
+			sample:     `<note>hi</note>`,
+			wantTags:   map[string]string{"Value": ",chardata"},
+			wantValues: map[string]string{"Value": "hi"},
+		},
+		{
+			name:       "namespaced_attribute",
+			sample:     `<note xmlns:a="urn:a" a:lang="de">hallo</note>`,
+			wantTags:   map[string]string{"Lang": "urn:a lang,attr", "Value": ",chardata"},
+			wantValues: map[string]string{"Lang": "de", "Value": "hallo"},
+		},
+		{
+			name:       "same_name_in_two_namespaces",
+			sample:     `<note xmlns:a="urn:a" a:lang="de" lang="en"/>`,
+			opts:       []dynamicstruct.ImportOption{dynamicstruct.WithCollisionPolicy(dynamicstruct.CollisionSuffix)},
+			wantTags:   map[string]string{"Lang": "urn:a lang,attr", "Lang2": "lang,attr"},
+			wantValues: map[string]string{"Lang": "de", "Lang2": "en"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder, err := dynamicstruct.FromXMLSample([]byte(tt.sample), tt.opts...)
+			if err != nil {
+				t.Fatalf("FromXMLSample() error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			structType := reflect.TypeOf(instance)
+			instancePtr := reflect.New(structType).Interface()
+			for name, want := range tt.wantTags {
+				if field, _ := structType.FieldByName(name); field.Tag.Get("xml") != want {
+					t.Errorf("%s xml tag = %q, want %q", name, field.Tag.Get("xml"), want)
+				}
+			}
+
+			if err := dynamicstruct.UnmarshalXML([]byte(tt.sample), instancePtr); err != nil {
+				t.Fatalf("UnmarshalXML() error = %v", err)
+			}
+
+			for name, want := range tt.wantValues {
+				if got := reflect.ValueOf(instancePtr).Elem().FieldByName(name).String(); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestXMLWithAutoTags(t *testing.T) {
+	builder := dynamicstruct.New(dynamicstruct.WithAutoTags(dynamicstruct.CamelCase, "xml"))
+	_ = builder.AddField("XMLName", xml.Name{}, `xml:"user"`)
+	_ = builder.AddField("FirstName", "")
+	_ = builder.AddField("ID", "", `xml:"id,attr"`)
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	instancePtr := reflect.New(reflect.TypeOf(instance)).Interface()
+	reflect.ValueOf(instancePtr).Elem().FieldByName("FirstName").SetString("Alice")
+	reflect.ValueOf(instancePtr).Elem().FieldByName("ID").SetString("7")
+
+	data, err := dynamicstruct.MarshalXML(instancePtr)
+	if err != nil {
+		t.Fatalf("MarshalXML() error = %v", err)
+	}
+
+	want := `<user id="7"><firstName>Alice</firstName></user>`
+	if string(data) != want {
+		t.Errorf("MarshalXML() = %s, want %s", data, want)
+	}
+}
+
+func TestFromXMLSampleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+	}{
+		{name: "empty_document", sample: ``},
+		{name: "malformed", sample: `<a><b></a>`},
+		{name: "multiple_roots", sample: `<a/><b/>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dynamicstruct.FromXMLSample([]byte(tt.sample))
+			if !errors.Is(err, dynamicstruct.ErrInvalidSample) {
+				t.Errorf("FromXMLSample() error = %v, want %v", err, dynamicstruct.ErrInvalidSample)
+			}
+		})
+	}
+
+	t.Run("unmarshal_non_pointer", func(t *testing.T) {
+		err := dynamicstruct.UnmarshalXML([]byte(`<a/>`), struct{}{})
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("UnmarshalXML() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+
+	t.Run("namespaces_are_ignored", func(t *testing.T) {
+		builder, err := dynamicstruct.FromXMLSample([]byte(
+			`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>x</soap:Body></soap:Envelope>`,
+		))
+		if err != nil {
+			t.Fatalf("FromXMLSample() error = %v", err)
+		}
+
+		instance, _ := builder.Build()
+		if strings.Count(reflect.TypeOf(instance).String(), "xml:") != 2 {
+			t.Errorf("unexpected fields in %s", reflect.TypeOf(instance))
+		}
+	})
+}
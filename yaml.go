@@ -0,0 +1,40 @@
+package dynamicstruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+func FromYAMLSample(data []byte, opts ...ImportOption) (*Builder, error) {
+	var sample map[string]any
+	if err := yaml.Unmarshal(data, &sample); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSample, err.Error())
+	}
+
+	return fromSample(sample, "yaml", newImportConfig(opts))
+}
+
+func MarshalYAML(instance any) ([]byte, error) {
+	return yaml.Marshal(instance)
+}
+
+func UnmarshalYAML(data []byte, instancePtr any) error {
+	valueReflect := reflect.ValueOf(instancePtr)
+
+	// Check if value is a pointer and not nil
+	if valueReflect.Kind() != reflect.Ptr {
+		return ErrValueMustBePointer
+	}
+
+	if valueReflect.IsNil() {
+		return ErrValueCannotBeNil
+	}
+
+	if err := yaml.Unmarshal(data, instancePtr); err != nil {
+		return err
+	}
+
+	return transformDecoded(valueReflect)
+}
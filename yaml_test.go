@@ -0,0 +1,144 @@
+package dynamicstruct_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosmos-space/dynamicstruct"
+)
+
+func TestFromYAMLSample(t *testing.T) {
+	sample := []byte(`
+service_name: billing
+replicas: 3
+ratio: 0.75
+enabled: true
+hosts:
+  - a.example.com
+  - b.example.com
+database:
+  host: localhost
+  port: 5432
+`)
+
+	builder, err := dynamicstruct.FromYAMLSample(sample)
+	if err != nil {
+		t.Fatalf("FromYAMLSample() error = %v", err)
+	}
+
+	instance, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	structType := reflect.TypeOf(instance)
+
+	tests := []struct {
+		field    string
+		wantType reflect.Type
+		wantTag  string
+	}{
+		{field: "ServiceName", wantType: reflect.TypeOf(""), wantTag: "service_name"},
+		{field: "Replicas", wantType: reflect.TypeOf(int64(0)), wantTag: "replicas"},
+		{field: "Ratio", wantType: reflect.TypeOf(float64(0)), wantTag: "ratio"},
+		{field: "Enabled", wantType: reflect.TypeOf(false), wantTag: "enabled"},
+		{field: "Hosts", wantType: reflect.TypeOf([]string{}), wantTag: "hosts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			field, ok := structType.FieldByName(tt.field)
+			if !ok {
+				t.Fatalf("field %s not found", tt.field)
+			}
+			if field.Type != tt.wantType {
+				t.Errorf("field %s type = %v, want %v", tt.field, field.Type, tt.wantType)
+			}
+			if field.Tag.Get("yaml") != tt.wantTag {
+				t.Errorf("field %s yaml tag = %q, want %q", tt.field, field.Tag.Get("yaml"), tt.wantTag)
+			}
+		})
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		instancePtr := reflect.New(structType).Interface()
+
+		err := dynamicstruct.UnmarshalYAML(sample, instancePtr)
+		if err != nil {
+			t.Fatalf("UnmarshalYAML() error = %v", err)
+		}
+
+		port := reflect.ValueOf(instancePtr).Elem().FieldByName("Database").FieldByName("Port").Int()
+		if port != 5432 {
+			t.Errorf("Database.Port = %d, want 5432", port)
+		}
+
+		data, err := dynamicstruct.MarshalYAML(instancePtr)
+		if err != nil {
+			t.Fatalf("MarshalYAML() error = %v", err)
+		}
+
+		if !strings.Contains(string(data), "service_name: billing") {
+			t.Errorf("MarshalYAML() = %s, want service_name key", data)
+		}
+	})
+}
+
+func TestFromYAMLSampleNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		sample   string
+		wantType reflect.Type
+	}{
+		{name: "integers", sample: "values: [1, 2]\n", wantType: reflect.TypeOf([]int64{})},
+		{name: "floats", sample: "values: [1.5, 2.5]\n", wantType: reflect.TypeOf([]float64{})},
+		{name: "integer_then_float", sample: "values: [1, 2.5]\n", wantType: reflect.TypeOf([]float64{})},
+		{name: "float_then_integer", sample: "values: [2.5, 1]\n", wantType: reflect.TypeOf([]float64{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := dynamicstruct.WithInferenceOptions(dynamicstruct.InferenceOptions{Conflicts: dynamicstruct.ConflictError})
+
+			builder, err := dynamicstruct.FromYAMLSample([]byte(tt.sample), options)
+			if err != nil {
+				t.Fatalf("FromYAMLSample() error = %v", err)
+			}
+
+			instance, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			if field, _ := reflect.TypeOf(instance).FieldByName("Values"); field.Type != tt.wantType {
+				t.Errorf("Values type = %v, want %v", field.Type, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestYAMLErrors(t *testing.T) {
+	t.Run("invalid_sample", func(t *testing.T) {
+		_, err := dynamicstruct.FromYAMLSample([]byte("key: [unclosed"))
+		if !errors.Is(err, dynamicstruct.ErrInvalidSample) {
+			t.Errorf("FromYAMLSample() error = %v, want %v", err, dynamicstruct.ErrInvalidSample)
+		}
+	})
+
+	t.Run("unmarshal_non_pointer", func(t *testing.T) {
+		err := dynamicstruct.UnmarshalYAML([]byte("a: 1"), struct{}{})
+		if !errors.Is(err, dynamicstruct.ErrValueMustBePointer) {
+			t.Errorf("UnmarshalYAML() error = %v, want %v", err, dynamicstruct.ErrValueMustBePointer)
+		}
+	})
+
+	t.Run("unmarshal_nil_pointer", func(t *testing.T) {
+		var ptr *struct{}
+		err := dynamicstruct.UnmarshalYAML([]byte("a: 1"), ptr)
+		if !errors.Is(err, dynamicstruct.ErrValueCannotBeNil) {
+			t.Errorf("UnmarshalYAML() error = %v, want %v", err, dynamicstruct.ErrValueCannotBeNil)
+		}
+	})
+}